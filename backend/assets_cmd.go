@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/database"
+	"dannyswat/learnspeak/repositories"
+	"dannyswat/learnspeak/services"
+)
+
+const assetsUsage = `Usage: learnspeak assets <command> [flags]
+
+Commands:
+  process [--force]   Strip metadata, record dimensions and build variants of images
+                      uploaded before the asset library existed
+  gc [--grace-period DURATION] [--dry-run]
+                      Remove uploaded images, profile photos and recordings no content
+                      refers to
+`
+
+// runAssetsCommand handles "learnspeak assets process|gc"
+func runAssetsCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, assetsUsage)
+		return fmt.Errorf("missing assets command")
+	}
+
+	switch args[0] {
+	case "process":
+		return runAssetsProcess(cfg, args[1:])
+	case "gc":
+		return runAssetsGC(cfg, args[1:])
+	default:
+		fmt.Fprint(os.Stderr, assetsUsage)
+		return fmt.Errorf("unknown assets command %q", args[0])
+	}
+}
+
+func runAssetsProcess(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("assets process", flag.ContinueOnError)
+	force := fs.Bool("force", false, "reprocess images that are already recorded")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	assetService := services.NewAssetService(repositories.NewAssetRepository(database.DB), cfg, cfg.UploadDir)
+	result, err := assetService.Backfill(context.Background(), *force)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Processed %d images, skipped %d already recorded, %d failed\n", result.Processed, result.Skipped, result.Failed)
+	return nil
+}
+
+func runAssetsGC(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("assets gc", flag.ContinueOnError)
+	gracePeriod := fs.Duration("grace-period", cfg.AssetGCGracePeriod, "keep unused files modified more recently than this")
+	dryRun := fs.Bool("dry-run", false, "list what would be removed without deleting")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	assetService := services.NewAssetService(repositories.NewAssetRepository(database.DB), cfg, cfg.UploadDir)
+	result, err := assetService.CollectGarbage(context.Background(), services.AssetGCOptions{
+		GracePeriod: *gracePeriod,
+		DryRun:      *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	action := "REMOVED"
+	if *dryRun {
+		action = "WOULD REMOVE"
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "DIRECTORY\t%s\tSIZE\tIN USE\tKEPT\n", action)
+	for _, d := range result.Dirs {
+		fmt.Fprintf(w, "%s\t%d\t%.1f MB\t%d\t%d\n", d.Name, d.Removed, float64(d.RemovedBytes)/(1024*1024), d.InUse, d.Kept)
+	}
+	return w.Flush()
+}
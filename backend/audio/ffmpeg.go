@@ -0,0 +1,181 @@
+// Package audio transcodes, normalises and inspects recordings with ffmpeg and ffprobe
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAudio is returned for files without a decodable audio stream, or that are
+// nothing but silence
+var ErrInvalidAudio = errors.New("invalid audio")
+
+// Info describes the first audio stream of a file
+type Info struct {
+	Duration   time.Duration
+	SampleRate int
+	Channels   int
+	Codec      string
+}
+
+// Options controls Transcode. The output is always mono MP3.
+type Options struct {
+	SampleRate       int     // Hz
+	Bitrate          string  // e.g. "48k"
+	Loudness         float64 // integrated loudness target in LUFS (EBU R128); 0 skips normalisation
+	TrimSilence      bool    // remove leading and trailing silence
+	SilenceThreshold string  // level counted as silence, e.g. "-50dB"
+}
+
+// FFmpeg runs the ffmpeg and ffprobe commands
+type FFmpeg struct {
+	ffmpeg  string
+	ffprobe string
+}
+
+// NewFFmpeg returns nil if either command is empty, "off" or not on PATH
+func NewFFmpeg(ffmpegCommand, ffprobeCommand string) *FFmpeg {
+	ffmpeg, ok := lookPath(ffmpegCommand)
+	if !ok {
+		return nil
+	}
+	ffprobe, ok := lookPath(ffprobeCommand)
+	if !ok {
+		return nil
+	}
+	return &FFmpeg{ffmpeg: ffmpeg, ffprobe: ffprobe}
+}
+
+// inputFormats maps the audio extensions accepted for upload to their ffmpeg demuxers.
+// Forcing the demuxer stops an upload that is really a playlist (HLS, concat) from being
+// opened as one, and -protocol_whitelist keeps ffmpeg from fetching anything but the file.
+var inputFormats = map[string]string{
+	".mp3":  "mp3",
+	".wav":  "wav",
+	".ogg":  "ogg",
+	".m4a":  "mov",
+	".webm": "matroska",
+}
+
+// inputArgs returns the options that open path as a local file of its extension's format
+func inputArgs(path string) []string {
+	args := []string{"-protocol_whitelist", "file"}
+	if format, ok := inputFormats[strings.ToLower(filepath.Ext(path))]; ok {
+		args = append(args, "-f", format)
+	}
+	return append(args, "-i", path)
+}
+
+func lookPath(command string) (string, bool) {
+	if command == "" || command == "off" {
+		return "", false
+	}
+	path, err := exec.LookPath(command)
+	return path, err == nil
+}
+
+// Probe reads the duration and format of the first audio stream of path
+func (f *FFmpeg) Probe(ctx context.Context, path string) (*Info, error) {
+	args := []string{
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "format=duration:stream=codec_name,sample_rate,channels,duration",
+		"-of", "json",
+	}
+	cmd := exec.CommandContext(ctx, f.ffprobe, append(args, inputArgs(path)...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAudio, strings.TrimSpace(stderr.String()))
+	}
+
+	var probe struct {
+		Streams []struct {
+			CodecName  string `json:"codec_name"`
+			SampleRate string `json:"sample_rate"`
+			Channels   int    `json:"channels"`
+			Duration   string `json:"duration"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return nil, fmt.Errorf("%w: no audio stream", ErrInvalidAudio)
+	}
+
+	stream := probe.Streams[0]
+	info := &Info{Codec: stream.CodecName, Channels: stream.Channels}
+	info.SampleRate, _ = strconv.Atoi(stream.SampleRate)
+
+	// Browser recordings (WebM) often only carry a duration on the container, or none at all
+	for _, d := range []string{probe.Format.Duration, stream.Duration} {
+		if seconds, err := strconv.ParseFloat(d, 64); err == nil && seconds > 0 {
+			info.Duration = time.Duration(seconds * float64(time.Second))
+			break
+		}
+	}
+	return info, nil
+}
+
+// Transcode converts in to a mono MP3 at out, trimming silence and normalising loudness as
+// configured. Metadata and any video or cover art streams are dropped.
+func (f *FFmpeg) Transcode(ctx context.Context, in, out string, opts Options) error {
+	args := []string{"-hide_banner", "-loglevel", "error", "-nostdin", "-y"}
+	args = append(args, inputArgs(in)...)
+	args = append(args, "-vn", "-sn", "-dn", "-map_metadata", "-1")
+	if filters := filterChain(opts); filters != "" {
+		args = append(args, "-af", filters)
+	}
+	args = append(args, "-ac", "1")
+	if opts.SampleRate > 0 {
+		args = append(args, "-ar", strconv.Itoa(opts.SampleRate))
+	}
+	args = append(args, "-c:a", "libmp3lame")
+	if opts.Bitrate != "" {
+		args = append(args, "-b:a", opts.Bitrate)
+	}
+	args = append(args, "-f", "mp3", out)
+
+	cmd := exec.CommandContext(ctx, f.ffmpeg, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%s failed: %w: %s", filepath.Base(f.ffmpeg), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// filterChain builds the -af filter graph. Trailing silence is trimmed by reversing the
+// audio, trimming its start and reversing it back.
+func filterChain(opts Options) string {
+	var filters []string
+	if opts.TrimSilence {
+		threshold := opts.SilenceThreshold
+		if threshold == "" {
+			threshold = "-50dB"
+		}
+		trim := "silenceremove=start_periods=1:start_duration=0:start_silence=0.1:start_threshold=" + threshold
+		filters = append(filters, trim, "areverse", trim, "areverse")
+	}
+	if opts.Loudness != 0 {
+		filters = append(filters, fmt.Sprintf("loudnorm=I=%g:TP=-1.5:LRA=11", opts.Loudness))
+	}
+	return strings.Join(filters, ",")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/database"
+	"dannyswat/learnspeak/services"
+)
+
+const cacheUsage = `Usage: learnspeak cache <command> [flags]
+
+Commands:
+  prune [--older-than DURATION] [--dry-run]   Remove old cache files no content refers to
+`
+
+// runCacheCommand handles "learnspeak cache prune"
+func runCacheCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "prune" {
+		fmt.Fprint(os.Stderr, cacheUsage)
+		if len(args) == 0 {
+			return fmt.Errorf("missing cache command")
+		}
+		return fmt.Errorf("unknown cache command %q", args[0])
+	}
+
+	fs := flag.NewFlagSet("cache prune", flag.ContinueOnError)
+	olderThan := fs.Duration("older-than", services.DefaultCachePruneAge, "only remove files older than this, e.g. 720h")
+	dryRun := fs.Bool("dry-run", false, "list what would be removed without deleting")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	result, err := services.NewCacheService(database.DB, cfg.UploadDir).PruneCaches(services.CachePruneOptions{
+		OlderThan: *olderThan,
+		DryRun:    *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	action := "REMOVED"
+	if *dryRun {
+		action = "WOULD REMOVE"
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "CACHE\t%s\tSIZE\tIN USE\tKEPT\n", action)
+	for _, d := range result.Dirs {
+		fmt.Fprintf(w, "%s\t%d\t%.1f MB\t%d\t%d\n", d.Name, d.Removed, float64(d.RemovedBytes)/(1024*1024), d.InUse, d.Kept)
+	}
+	return w.Flush()
+}
@@ -4,6 +4,9 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
+
+	"dannyswat/learnspeak/ratelimit"
 
 	"github.com/joho/godotenv"
 )
@@ -22,6 +25,38 @@ type Config struct {
 	CORSAllowedOrigins string
 	MaxUploadSize      int64
 	UploadDir          string
+	// HTTP server
+	ServerReadTimeout     time.Duration // time to read a request, including the body
+	ServerWriteTimeout    time.Duration // time to write a response; must cover batch image generation
+	ServerIdleTimeout     time.Duration // keep-alive idle time
+	ShutdownTimeout       time.Duration // time to drain requests and background workers on shutdown
+	ContentSecurityPolicy string        // overrides the default CSP for the embedded SPA
+	// Rate limiting and quotas
+	RateLimitRedisURL       string          // shared counter store; empty keeps counters in memory
+	TrustProxyHeaders       bool            // take the client IP from X-Forwarded-For/X-Real-IP sent by a private-network proxy
+	APIRateLimit            ratelimit.Limit // per IP, all /api/v1 routes
+	LoginRateLimit          ratelimit.Limit // per IP
+	RegisterRateLimit       ratelimit.Limit // per IP
+	AIRateLimit             ratelimit.Limit // per user, TTS, translation and image generation
+	LoginMaxFailures        int             // failed logins from one IP before a username is locked for it; 0 disables lockout
+	LoginAccountMaxFailures int             // failed logins from any IP before a username is locked everywhere; 0 disables
+	LoginFailureWindow      time.Duration   // window the failures are counted in
+	LoginLockDuration       time.Duration   // how long the username stays locked for that IP
+	AIDailyImageQuota       int             // per user per UTC day; 0 means unlimited
+	AIDailyTranslationQuota int
+	AIDailyTTSQuota         int
+	// AI cost accounting, in USD
+	AIMonthlyUserBudget               float64 // estimated spend per user per calendar month (UTC); 0 means no cap
+	AIMonthlyOrgBudget                float64 // estimated spend of all users per calendar month; 0 means no cap
+	AIPriceTTSPerMillionChars         float64
+	AIPriceTranslationPerMillionChars float64
+	AIPriceImageAzure                 float64 // per image
+	AIPriceImageIdeogram              float64 // per image
+	AIPriceImageOpenAI                float64 // per image
+	// Logging and metrics
+	LogLevel     string // debug, info, warn or error
+	LogFormat    string // "json" or "text"; defaults to json in production
+	MetricsToken string // Bearer token required for /metrics when set
 	// Azure TTS Configuration
 	AzureTTSKey     string
 	AzureTTSRegion  string
@@ -39,8 +74,39 @@ type Config struct {
 	ImageCacheEnabled     bool
 	// Ideogram Configuration
 	IdeogramAPIKey string
+	// OpenAI-compatible images API (OpenAI or any server exposing /v1/images/generations)
+	OpenAIImageAPIKey  string
+	OpenAIImageBaseURL string // empty uses the official OpenAI API
+	OpenAIImageModel   string
+	// Stable Diffusion Configuration
+	StableDiffusionURL     string
+	StableDiffusionAPI     string // "webui" (AUTOMATIC1111/Forge) or "comfyui"
+	StableDiffusionSteps   int
+	StableDiffusionTimeout time.Duration
+	ComfyUICheckpoint      string // checkpoint file name for the default ComfyUI workflow
+	ComfyUIWorkflowFile    string // API-format workflow JSON replacing the default one
 	// Image Generation Provider
-	ImageGenerationProvider string // "azure" or "ideogram"
+	ImageGenerationProvider       string        // comma-separated fallback order of azure, ideogram, openai, stable-diffusion, placeholder
+	ImageProviderFailureThreshold int           // consecutive failures before a provider is skipped
+	ImageProviderCooldown         time.Duration // how long an unhealthy provider is skipped
+	// Image processing of uploads and generated images
+	ImageVariantsEnabled bool   // build thumb/medium and WebP/AVIF variants
+	ImageThumbSize       int    // longest side of the thumb variant, in pixels
+	ImageMediumSize      int    // longest side of the medium variant, in pixels
+	ImageWebPEncoder     string // cwebp command; empty or missing skips WebP variants
+	ImageAVIFEncoder     string // avifenc command; empty or missing skips AVIF variants
+	// Audio processing of uploaded recordings
+	FFmpegPath          string        // ffmpeg command; "off" or missing keeps uploads as they are
+	FFprobePath         string        // ffprobe command
+	AudioSampleRate     int           // Hz of the canonical mono MP3
+	AudioBitrate        string        // e.g. "48k"
+	AudioLoudnessTarget float64       // integrated loudness in LUFS; 0 disables normalisation
+	AudioTrimSilence    bool          // trim leading and trailing silence
+	AudioProcessTimeout time.Duration // per upload
+	// Asset library garbage collection
+	AssetGCGracePeriod time.Duration // unused uploads younger than this are kept
+	// Chinese character data
+	HanziDataDir string // Make Me a Hanzi and OpenCC files; empty uses the data embedded at build
 }
 
 var AppConfig *Config
@@ -57,6 +123,9 @@ func LoadConfig() *Config {
 	ttsCacheEnabled, _ := strconv.ParseBool(getEnv("TTS_CACHE_ENABLED", "true"))
 	translatorCacheEnabled, _ := strconv.ParseBool(getEnv("TRANSLATOR_CACHE_ENABLED", "true"))
 	imageCacheEnabled, _ := strconv.ParseBool(getEnv("IMAGE_CACHE_ENABLED", "true"))
+	trustProxyHeaders, _ := strconv.ParseBool(getEnv("TRUST_PROXY_HEADERS", "true"))
+	imageVariantsEnabled, _ := strconv.ParseBool(getEnv("IMAGE_VARIANTS_ENABLED", "true"))
+	audioTrimSilence, _ := strconv.ParseBool(getEnv("AUDIO_TRIM_SILENCE", "true"))
 
 	AppConfig = &Config{
 		Port:               getEnv("PORT", "8080"),
@@ -72,6 +141,38 @@ func LoadConfig() *Config {
 		CORSAllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", ""),
 		MaxUploadSize:      maxUploadSize,
 		UploadDir:          getEnv("UPLOAD_DIR", "./uploads"),
+		// HTTP server
+		ServerReadTimeout:     getDurationEnv("SERVER_READ_TIMEOUT", 60*time.Second),
+		ServerWriteTimeout:    getDurationEnv("SERVER_WRITE_TIMEOUT", 10*time.Minute),
+		ServerIdleTimeout:     getDurationEnv("SERVER_IDLE_TIMEOUT", 2*time.Minute),
+		ShutdownTimeout:       getDurationEnv("SHUTDOWN_TIMEOUT", 60*time.Second),
+		ContentSecurityPolicy: getEnv("CONTENT_SECURITY_POLICY", ""),
+		// Rate limiting and quotas
+		RateLimitRedisURL:       getEnv("RATE_LIMIT_REDIS_URL", ""),
+		TrustProxyHeaders:       trustProxyHeaders,
+		APIRateLimit:            getLimitEnv("API_RATE_LIMIT", "600/1m"),
+		LoginRateLimit:          getLimitEnv("LOGIN_RATE_LIMIT", "10/1m"),
+		RegisterRateLimit:       getLimitEnv("REGISTER_RATE_LIMIT", "5/1h"),
+		AIRateLimit:             getLimitEnv("AI_RATE_LIMIT", "30/1m"),
+		LoginMaxFailures:        getIntEnv("LOGIN_MAX_FAILURES", 5),
+		LoginAccountMaxFailures: getIntEnv("LOGIN_ACCOUNT_MAX_FAILURES", 50),
+		LoginFailureWindow:      getDurationEnv("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		LoginLockDuration:       getDurationEnv("LOGIN_LOCK_DURATION", 15*time.Minute),
+		AIDailyImageQuota:       getIntEnv("AI_DAILY_IMAGE_QUOTA", 50),
+		AIDailyTranslationQuota: getIntEnv("AI_DAILY_TRANSLATION_QUOTA", 2000),
+		AIDailyTTSQuota:         getIntEnv("AI_DAILY_TTS_QUOTA", 1000),
+		// AI cost accounting
+		AIMonthlyUserBudget:               getFloatEnv("AI_MONTHLY_USER_BUDGET", 0),
+		AIMonthlyOrgBudget:                getFloatEnv("AI_MONTHLY_ORG_BUDGET", 0),
+		AIPriceTTSPerMillionChars:         getFloatEnv("AI_PRICE_TTS_PER_MILLION_CHARS", 16),
+		AIPriceTranslationPerMillionChars: getFloatEnv("AI_PRICE_TRANSLATION_PER_MILLION_CHARS", 10),
+		AIPriceImageAzure:                 getFloatEnv("AI_PRICE_IMAGE_AZURE", 0.04),
+		AIPriceImageIdeogram:              getFloatEnv("AI_PRICE_IMAGE_IDEOGRAM", 0.08),
+		AIPriceImageOpenAI:                getFloatEnv("AI_PRICE_IMAGE_OPENAI", 0.04),
+		// Logging and metrics
+		LogLevel:     getEnv("LOG_LEVEL", "info"),
+		LogFormat:    getEnv("LOG_FORMAT", ""),
+		MetricsToken: getEnv("METRICS_TOKEN", ""),
 		// Azure TTS Configuration
 		AzureTTSKey:     getEnv("AZURE_TTS_KEY", ""),
 		AzureTTSRegion:  getEnv("AZURE_TTS_REGION", "eastus"),
@@ -89,8 +190,39 @@ func LoadConfig() *Config {
 		ImageCacheEnabled:     imageCacheEnabled,
 		// Ideogram Configuration
 		IdeogramAPIKey: getEnv("IDEOGRAM_API_KEY", ""),
+		// OpenAI-compatible images API
+		OpenAIImageAPIKey:  getEnv("OPENAI_IMAGE_API_KEY", ""),
+		OpenAIImageBaseURL: getEnv("OPENAI_IMAGE_BASE_URL", ""),
+		OpenAIImageModel:   getEnv("OPENAI_IMAGE_MODEL", "dall-e-3"),
+		// Stable Diffusion Configuration
+		StableDiffusionURL:     getEnv("STABLE_DIFFUSION_URL", ""),
+		StableDiffusionAPI:     getEnv("STABLE_DIFFUSION_API", "webui"),
+		StableDiffusionSteps:   getIntEnv("STABLE_DIFFUSION_STEPS", 25),
+		StableDiffusionTimeout: getDurationEnv("STABLE_DIFFUSION_TIMEOUT", 2*time.Minute),
+		ComfyUICheckpoint:      getEnv("COMFYUI_CHECKPOINT", "sd_xl_base_1.0.safetensors"),
+		ComfyUIWorkflowFile:    getEnv("COMFYUI_WORKFLOW_FILE", ""),
 		// Image Generation Provider
-		ImageGenerationProvider: getEnv("IMAGE_GENERATION_PROVIDER", "azure"),
+		ImageGenerationProvider:       getEnv("IMAGE_GENERATION_PROVIDER", "azure"),
+		ImageProviderFailureThreshold: getIntEnv("IMAGE_PROVIDER_FAILURE_THRESHOLD", 3),
+		ImageProviderCooldown:         getDurationEnv("IMAGE_PROVIDER_COOLDOWN", 2*time.Minute),
+		// Image processing
+		ImageVariantsEnabled: imageVariantsEnabled,
+		ImageThumbSize:       getIntEnv("IMAGE_THUMB_SIZE", 256),
+		ImageMediumSize:      getIntEnv("IMAGE_MEDIUM_SIZE", 768),
+		ImageWebPEncoder:     getEnv("IMAGE_WEBP_ENCODER", "cwebp"),
+		ImageAVIFEncoder:     getEnv("IMAGE_AVIF_ENCODER", "avifenc"),
+		// Audio processing
+		FFmpegPath:          getEnv("FFMPEG_PATH", "ffmpeg"),
+		FFprobePath:         getEnv("FFPROBE_PATH", "ffprobe"),
+		AudioSampleRate:     getIntEnv("AUDIO_SAMPLE_RATE", 24000),
+		AudioBitrate:        getEnv("AUDIO_BITRATE", "48k"),
+		AudioLoudnessTarget: getFloatEnv("AUDIO_LOUDNESS_TARGET", -16),
+		AudioTrimSilence:    audioTrimSilence,
+		AudioProcessTimeout: getDurationEnv("AUDIO_PROCESS_TIMEOUT", 60*time.Second),
+		// Asset library
+		AssetGCGracePeriod: getDurationEnv("ASSET_GC_GRACE_PERIOD", 24*time.Hour),
+		// Chinese character data
+		HanziDataDir: getEnv("HANZI_DATA_DIR", ""),
 	}
 
 	return AppConfig
@@ -102,3 +234,53 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getDurationEnv parses a Go duration such as "30s" or "5m"
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration for %s: %q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid integer for %s: %q, using %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+func getFloatEnv(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Invalid number for %s: %q, using %g", key, value, defaultValue)
+		return defaultValue
+	}
+	return f
+}
+
+// getLimitEnv parses a rate limit such as "10/1m"; "off" or "0" disables it
+func getLimitEnv(key, defaultValue string) ratelimit.Limit {
+	limit, err := ratelimit.ParseLimit(getEnv(key, defaultValue))
+	if err != nil {
+		log.Printf("%v for %s, using %s", err, key, defaultValue)
+		limit, _ = ratelimit.ParseLimit(defaultValue)
+	}
+	return limit
+}
@@ -0,0 +1,300 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The baseline_schema migration creates the tables as they were before versioned
+// migrations. These snapshots are frozen copies of the models at that point, so later
+// model changes don't leak into 0001: schema changes belong in their own migrations.
+// Never edit them.
+
+type baselineUser struct {
+	ID            uint    `gorm:"primaryKey"`
+	Username      string  `gorm:"uniqueIndex;size:50;not null"`
+	PasswordHash  string  `gorm:"size:255;not null"`
+	Email         string  `gorm:"uniqueIndex;size:255"`
+	Name          string  `gorm:"size:100;not null"`
+	ProfilePicURL *string `gorm:"size:500"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+
+	Roles []baselineRole `gorm:"many2many:user_roles;"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineRole struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"uniqueIndex;size:50;not null"`
+	Description string `gorm:"type:text"`
+	CreatedAt   time.Time
+
+	Users []baselineUser `gorm:"many2many:user_roles;"`
+}
+
+func (baselineRole) TableName() string { return "roles" }
+
+type baselineUserRole struct {
+	ID         uint `gorm:"primaryKey"`
+	UserID     uint `gorm:"not null;index;uniqueIndex:idx_user_role"`
+	RoleID     uint `gorm:"not null;index;uniqueIndex:idx_user_role"`
+	AssignedAt time.Time
+
+	User baselineUser `gorm:"foreignKey:UserID"`
+	Role baselineRole `gorm:"foreignKey:RoleID"`
+}
+
+func (baselineUserRole) TableName() string { return "user_roles" }
+
+type baselineLanguage struct {
+	ID         uint   `gorm:"primaryKey"`
+	Code       string `gorm:"size:10;unique;not null"`
+	Name       string `gorm:"size:100;not null"`
+	NativeName string `gorm:"size:100"`
+	Direction  string `gorm:"size:3;default:ltr"`
+	IsActive   bool   `gorm:"default:true"`
+	CreatedAt  time.Time
+}
+
+func (baselineLanguage) TableName() string { return "languages" }
+
+type baselineWord struct {
+	ID        uint   `gorm:"primaryKey"`
+	BaseWord  string `gorm:"size:255;not null"`
+	ImageURL  string `gorm:"size:500"`
+	Notes     string `gorm:"type:text"`
+	CreatedBy uint   `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Creator      baselineUser              `gorm:"foreignKey:CreatedBy"`
+	Translations []baselineWordTranslation `gorm:"foreignKey:WordID"`
+}
+
+func (baselineWord) TableName() string { return "words" }
+
+type baselineWordTranslation struct {
+	ID           uint   `gorm:"primaryKey"`
+	WordID       uint   `gorm:"not null"`
+	LanguageID   uint   `gorm:"not null"`
+	Translation  string `gorm:"type:text;not null"`
+	Romanization string `gorm:"size:255"`
+	AudioURL     string `gorm:"size:500"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Word     baselineWord     `gorm:"foreignKey:WordID"`
+	Language baselineLanguage `gorm:"foreignKey:LanguageID"`
+}
+
+func (baselineWordTranslation) TableName() string { return "word_translations" }
+
+type baselineTopic struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:200;not null"`
+	Description string `gorm:"type:text"`
+	Level       string `gorm:"size:20;not null"`
+	LanguageID  uint   `gorm:"not null"`
+	CreatedBy   uint   `gorm:"not null"`
+	IsPublic    bool   `gorm:"default:false;not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Language baselineLanguage    `gorm:"foreignKey:LanguageID"`
+	Creator  baselineUser        `gorm:"foreignKey:CreatedBy"`
+	Words    []baselineTopicWord `gorm:"foreignKey:TopicID"`
+}
+
+func (baselineTopic) TableName() string { return "topics" }
+
+type baselineTopicWord struct {
+	ID            uint `gorm:"primaryKey"`
+	TopicID       uint `gorm:"not null"`
+	WordID        uint `gorm:"not null"`
+	SequenceOrder int  `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+
+	Topic baselineTopic `gorm:"foreignKey:TopicID"`
+	Word  baselineWord  `gorm:"foreignKey:WordID"`
+}
+
+func (baselineTopicWord) TableName() string { return "topic_words" }
+
+type baselineJourney struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:200;not null"`
+	Description string `gorm:"type:text"`
+	LanguageID  uint   `gorm:"not null"`
+	CreatedBy   uint   `gorm:"not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Language baselineLanguage       `gorm:"foreignKey:LanguageID"`
+	Creator  baselineUser           `gorm:"foreignKey:CreatedBy"`
+	Topics   []baselineJourneyTopic `gorm:"foreignKey:JourneyID"`
+}
+
+func (baselineJourney) TableName() string { return "journeys" }
+
+type baselineJourneyTopic struct {
+	ID            uint `gorm:"primaryKey"`
+	JourneyID     uint `gorm:"not null"`
+	TopicID       uint `gorm:"not null"`
+	SequenceOrder int  `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+
+	Journey baselineJourney `gorm:"foreignKey:JourneyID"`
+	Topic   baselineTopic   `gorm:"foreignKey:TopicID"`
+}
+
+func (baselineJourneyTopic) TableName() string { return "journey_topics" }
+
+type baselineJourneyInvitation struct {
+	ID              uint   `gorm:"primaryKey"`
+	JourneyID       uint   `gorm:"not null;index"`
+	InvitationToken string `gorm:"size:64;uniqueIndex;not null"`
+	CreatedBy       uint   `gorm:"not null"`
+	ExpiresAt       *time.Time
+	MaxUses         *int
+	CurrentUses     int  `gorm:"default:0;not null"`
+	IsActive        bool `gorm:"default:true;not null"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+
+	Journey baselineJourney `gorm:"foreignKey:JourneyID"`
+	Creator baselineUser    `gorm:"foreignKey:CreatedBy"`
+}
+
+func (baselineJourneyInvitation) TableName() string { return "journey_invitations" }
+
+type baselineQuizQuestion struct {
+	ID            uint    `gorm:"primaryKey"`
+	TopicID       uint    `gorm:"not null;index"`
+	WordID        *uint   `gorm:"index"`
+	QuestionType  string  `gorm:"not null;type:varchar(50)"`
+	QuestionText  string  `gorm:"not null;type:text"`
+	AudioURL      *string `gorm:"type:varchar(255)"`
+	ImageURL      *string `gorm:"type:varchar(255)"`
+	CorrectAnswer string  `gorm:"not null;type:varchar(255)"`
+	OptionA       string  `gorm:"not null;type:varchar(255)"`
+	OptionB       string  `gorm:"not null;type:varchar(255)"`
+	OptionC       string  `gorm:"not null;type:varchar(255)"`
+	OptionD       string  `gorm:"not null;type:varchar(255)"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	Topic *baselineTopic `gorm:"foreignKey:TopicID"`
+	Word  *baselineWord  `gorm:"foreignKey:WordID"`
+}
+
+func (baselineQuizQuestion) TableName() string { return "topic_quizzes" }
+
+type baselineConversation struct {
+	ID               uint   `gorm:"primaryKey"`
+	Title            string `gorm:"size:200;not null"`
+	Description      string `gorm:"type:text"`
+	Context          string `gorm:"type:text"`
+	LanguageID       uint   `gorm:"not null"`
+	DifficultyLevel  string `gorm:"size:20;not null"`
+	ScenarioAudioURL string `gorm:"size:500"`
+	ScenarioImageURL string `gorm:"size:500"`
+	CreatedBy        uint   `gorm:"not null"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+
+	Language baselineLanguage           `gorm:"foreignKey:LanguageID"`
+	Creator  baselineUser               `gorm:"foreignKey:CreatedBy"`
+	Lines    []baselineConversationLine `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE"`
+}
+
+func (baselineConversation) TableName() string { return "conversations" }
+
+type baselineConversationLine struct {
+	ID             uint   `gorm:"primaryKey"`
+	ConversationID uint   `gorm:"not null"`
+	SequenceOrder  int    `gorm:"not null"`
+	SpeakerRole    string `gorm:"size:100;not null"`
+	EnglishText    string `gorm:"type:text;not null"`
+	TargetText     string `gorm:"type:text;not null"`
+	Romanization   string `gorm:"type:text"`
+	AudioURL       string `gorm:"size:500"`
+	ImageURL       string `gorm:"size:500"`
+	WordID         *uint
+	IsLearnerLine  bool `gorm:"default:false"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	Conversation baselineConversation `gorm:"foreignKey:ConversationID"`
+	Word         *baselineWord        `gorm:"foreignKey:WordID"`
+}
+
+func (baselineConversationLine) TableName() string { return "conversation_lines" }
+
+type baselineTopicConversation struct {
+	ID             uint `gorm:"primaryKey"`
+	TopicID        uint `gorm:"not null"`
+	ConversationID uint `gorm:"not null"`
+	SequenceOrder  int  `gorm:"not null;default:0"`
+	CreatedAt      time.Time
+
+	Topic        baselineTopic        `gorm:"foreignKey:TopicID"`
+	Conversation baselineConversation `gorm:"foreignKey:ConversationID"`
+}
+
+func (baselineTopicConversation) TableName() string { return "topic_conversations" }
+
+type baselineUserJourney struct {
+	ID          uint      `gorm:"primaryKey"`
+	UserID      uint      `gorm:"not null;index"`
+	JourneyID   uint      `gorm:"not null;index"`
+	AssignedBy  uint      `gorm:"not null"`
+	Status      string    `gorm:"size:20;not null;default:'assigned';index"`
+	AssignedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	User           baselineUser    `gorm:"foreignKey:UserID"`
+	Journey        baselineJourney `gorm:"foreignKey:JourneyID"`
+	AssignedByUser baselineUser    `gorm:"foreignKey:AssignedBy"`
+}
+
+func (baselineUserJourney) TableName() string { return "user_journeys" }
+
+type baselineUserProgress struct {
+	ID               uint     `gorm:"primaryKey"`
+	UserID           uint     `gorm:"not null;index"`
+	TopicID          *uint    `gorm:"index"`
+	JourneyID        *uint    `gorm:"index"`
+	ActivityType     string   `gorm:"size:50;not null;index"`
+	Completed        bool     `gorm:"default:false;index"`
+	Score            *float64 `gorm:"type:decimal(5,2)"`
+	MaxScore         *float64 `gorm:"type:decimal(5,2)"`
+	TimeSpentSeconds int      `gorm:"default:0"`
+	CompletedAt      *time.Time
+	CreatedAt        time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+
+	User    baselineUser     `gorm:"foreignKey:UserID"`
+	Topic   *baselineTopic   `gorm:"foreignKey:TopicID"`
+	Journey *baselineJourney `gorm:"foreignKey:JourneyID"`
+}
+
+func (baselineUserProgress) TableName() string { return "user_progress" }
+
+type baselineUserBookmark struct {
+	ID        uint `gorm:"primaryKey"`
+	UserID    uint `gorm:"not null"`
+	WordID    *uint
+	TopicID   *uint
+	CreatedAt time.Time
+
+	User  baselineUser   `gorm:"foreignKey:UserID"`
+	Word  *baselineWord  `gorm:"foreignKey:WordID"`
+	Topic *baselineTopic `gorm:"foreignKey:TopicID"`
+}
+
+func (baselineUserBookmark) TableName() string { return "user_bookmarks" }
@@ -3,7 +3,8 @@ package database
 import (
 	"dannyswat/learnspeak/config"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -25,15 +26,29 @@ func Connect(cfg *config.Config) error {
 	)
 
 	var err error
+	// SQL statements are logged at debug level; slow queries and errors as warnings
+	logLevel := logger.Warn
+	if cfg.LogLevel == "debug" {
+		logLevel = logger.Info
+	}
+
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: logger.NewSlogLogger(slog.Default(), logger.Config{
+			SlowThreshold:             200 * time.Millisecond,
+			LogLevel:                  logLevel,
+			IgnoreRecordNotFoundError: true,
+		}),
 	})
 
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Println("Database connection established successfully")
+	if err := registerQueryMetrics(DB); err != nil {
+		return err
+	}
+
+	slog.Info("Database connection established")
 	return nil
 }
 
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"encoding/json"
+
+	"dannyswat/learnspeak/models"
+)
+
+// defaultLanguages are the languages seeded on a new database, with their TTS voices,
+// romanization, translator codes and fonts. Admins manage them through the languages API
+// afterwards.
+func defaultLanguages() []models.Language {
+	return []models.Language{
+		{
+			Code: "en", Name: "English", NativeName: "English", Direction: "ltr", IsActive: true,
+			DefaultVoice: "en-US-JennyNeural",
+			Voices: voices(
+				models.LanguageVoice{Voice: "en-US-JennyNeural", Name: "Jenny (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "en-US-GuyNeural", Name: "Guy (Male)", Gender: "Male"},
+				models.LanguageVoice{Voice: "en-US-AriaNeural", Name: "Aria (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "en-US-DavisNeural", Name: "Davis (Male)", Gender: "Male"},
+			),
+		},
+		{
+			Code: "zh-HK", Name: "Cantonese (Traditional)", NativeName: "廣東話（繁體）", Direction: "ltr", IsActive: true,
+			DefaultVoice: "zh-HK-HiuMaanNeural",
+			Voices: voices(
+				models.LanguageVoice{Voice: "zh-HK-HiuMaanNeural", Name: "HiuMaan (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "zh-HK-WanLungNeural", Name: "WanLung (Male)", Gender: "Male"},
+				models.LanguageVoice{Voice: "zh-HK-HiuGaaiNeural", Name: "HiuGaai (Female)", Gender: "Female"},
+			),
+			RomanizationScheme: "jyutping",
+			TranslatorCode:     "zh-Hant",
+			Script:             "hk",
+			FontFamily:         `"Noto Sans HK", "PingFang HK", "Microsoft JhengHei", sans-serif`,
+		},
+		{
+			Code: "zh-CN", Name: "Mandarin (Simplified)", NativeName: "普通话（简体）", Direction: "ltr", IsActive: true,
+			DefaultVoice: "zh-CN-XiaoxiaoNeural",
+			Voices: voices(
+				models.LanguageVoice{Voice: "zh-CN-XiaoxiaoNeural", Name: "Xiaoxiao (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "zh-CN-YunyangNeural", Name: "Yunyang (Male)", Gender: "Male"},
+				models.LanguageVoice{Voice: "zh-CN-XiaoyiNeural", Name: "Xiaoyi (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "zh-CN-YunjianNeural", Name: "Yunjian (Male)", Gender: "Male"},
+			),
+			RomanizationScheme: "pinyin",
+			TranslatorCode:     "zh-Hans",
+			Script:             "s",
+			FontFamily:         `"Noto Sans SC", "PingFang SC", "Microsoft YaHei", sans-serif`,
+		},
+		{
+			Code: "es", Name: "Spanish", NativeName: "Español", Direction: "ltr", IsActive: true,
+			DefaultVoice: "es-ES-ElviraNeural",
+			Voices: voices(
+				models.LanguageVoice{Voice: "es-ES-ElviraNeural", Name: "Elvira (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "es-ES-AlvaroNeural", Name: "Alvaro (Male)", Gender: "Male"},
+				models.LanguageVoice{Voice: "es-ES-AbrilNeural", Name: "Abril (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "es-ES-ArnoldNeural", Name: "Arnold (Male)", Gender: "Male"},
+			),
+		},
+		{
+			Code: "fr", Name: "French", NativeName: "Français", Direction: "ltr", IsActive: true,
+			DefaultVoice: "fr-FR-DeniseNeural",
+			Voices: voices(
+				models.LanguageVoice{Voice: "fr-FR-DeniseNeural", Name: "Denise (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "fr-FR-HenriNeural", Name: "Henri (Male)", Gender: "Male"},
+				models.LanguageVoice{Voice: "fr-FR-BrigitteNeural", Name: "Brigitte (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "fr-FR-AlainNeural", Name: "Alain (Male)", Gender: "Male"},
+			),
+		},
+		{
+			Code: "ja", Name: "Japanese", NativeName: "日本語", Direction: "ltr", IsActive: true,
+			DefaultVoice: "ja-JP-NanamiNeural",
+			Voices: voices(
+				models.LanguageVoice{Voice: "ja-JP-NanamiNeural", Name: "Nanami (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "ja-JP-KeitaNeural", Name: "Keita (Male)", Gender: "Male"},
+				models.LanguageVoice{Voice: "ja-JP-AoiNeural", Name: "Aoi (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "ja-JP-DaichiNeural", Name: "Daichi (Male)", Gender: "Male"},
+			),
+			RomanizationScheme: "hepburn",
+			FontFamily:         `"Noto Sans JP", "Hiragino Sans", "Yu Gothic", sans-serif`,
+		},
+		{
+			Code: "ko", Name: "Korean", NativeName: "한국어", Direction: "ltr", IsActive: true,
+			DefaultVoice: "ko-KR-SunHiNeural",
+			Voices: voices(
+				models.LanguageVoice{Voice: "ko-KR-SunHiNeural", Name: "SunHi (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "ko-KR-InJoonNeural", Name: "InJoon (Male)", Gender: "Male"},
+				models.LanguageVoice{Voice: "ko-KR-JiMinNeural", Name: "JiMin (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "ko-KR-BongJinNeural", Name: "BongJin (Male)", Gender: "Male"},
+			),
+			RomanizationScheme: "revised",
+			FontFamily:         `"Noto Sans KR", "Apple SD Gothic Neo", "Malgun Gothic", sans-serif`,
+		},
+		{
+			Code: "vi", Name: "Vietnamese", NativeName: "Tiếng Việt", Direction: "ltr", IsActive: true,
+			DefaultVoice: "vi-VN-HoaiMyNeural",
+			Voices: voices(
+				models.LanguageVoice{Voice: "vi-VN-HoaiMyNeural", Name: "HoaiMy (Female)", Gender: "Female"},
+				models.LanguageVoice{Voice: "vi-VN-NamMinhNeural", Name: "NamMinh (Male)", Gender: "Male"},
+			),
+		},
+	}
+}
+
+func voices(list ...models.LanguageVoice) string {
+	data, err := json.Marshal(list)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"dannyswat/learnspeak/metrics"
+
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// registerQueryMetrics times every GORM statement and records it in metrics.DBQueryDuration
+func registerQueryMetrics(db *gorm.DB) error {
+	cb := db.Callback()
+	registrations := []struct {
+		name string
+		err  error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register("metrics:before_create", startQueryTimer)},
+		{"create", cb.Create().After("gorm:create").Register("metrics:after_create", observeQuery("create"))},
+		{"query", cb.Query().Before("gorm:query").Register("metrics:before_query", startQueryTimer)},
+		{"query", cb.Query().After("gorm:query").Register("metrics:after_query", observeQuery("query"))},
+		{"update", cb.Update().Before("gorm:update").Register("metrics:before_update", startQueryTimer)},
+		{"update", cb.Update().After("gorm:update").Register("metrics:after_update", observeQuery("update"))},
+		{"delete", cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startQueryTimer)},
+		{"delete", cb.Delete().After("gorm:delete").Register("metrics:after_delete", observeQuery("delete"))},
+		{"row", cb.Row().Before("gorm:row").Register("metrics:before_row", startQueryTimer)},
+		{"row", cb.Row().After("gorm:row").Register("metrics:after_row", observeQuery("row"))},
+		{"raw", cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startQueryTimer)},
+		{"raw", cb.Raw().After("gorm:raw").Register("metrics:after_raw", observeQuery("raw"))},
+	}
+
+	for _, r := range registrations {
+		if r.err != nil {
+			return fmt.Errorf("failed to register %s query metrics: %w", r.name, r.err)
+		}
+	}
+	return nil
+}
+
+func startQueryTimer(tx *gorm.DB) {
+	tx.InstanceSet(queryStartKey, time.Now())
+}
+
+func observeQuery(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		start, ok := tx.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		table := tx.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		metrics.DBQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start.(time.Time)).Seconds())
+	}
+}
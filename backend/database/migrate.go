@@ -4,65 +4,38 @@ import (
 	"dannyswat/learnspeak/models"
 	"dannyswat/learnspeak/utils"
 	"fmt"
-	"log"
-	"os"
-	"path/filepath"
-	"sort"
+	"log/slog"
 )
 
-// Migrate runs database migrations
+// Migrate applies all pending versioned migrations and seeds essential data
 func Migrate() error {
-	log.Println("Running database migrations...")
-
-	// Use GORM AutoMigrate for all models
-	log.Println("Creating/updating database schema...")
-	err := DB.AutoMigrate(
-		// Core models
-		&models.User{},
-		&models.Role{},
-		&models.UserRole{},
-
-		// Content models
-		&models.Language{},
-		&models.Word{},
-		&models.WordTranslation{},
-		&models.Topic{},
-		&models.TopicWord{},
-		&models.Journey{},
-		&models.JourneyTopic{},
-		&models.JourneyInvitation{},
-		&models.QuizQuestion{},
-
-		// Conversation models
-		&models.Conversation{},
-		&models.ConversationLine{},
-		&models.TopicConversation{},
-
-		// User progress models
-		&models.UserJourney{},
-		&models.UserProgress{},
-		&models.UserBookmark{},
-	)
+	slog.Info("Running database migrations")
+
+	applied, err := Registry.Up(DB, "")
 	if err != nil {
-		return fmt.Errorf("failed to auto-migrate models: %w", err)
+		return err
 	}
+	slog.Info("Database migrations applied", "count", len(applied))
 
 	// Seed essential data
-	log.Println("Seeding essential data (roles, languages)...")
+	slog.Info("Seeding essential data (roles, languages)")
 	if err := seedEssentialData(); err != nil {
 		return fmt.Errorf("failed to seed essential data: %w", err)
 	}
 
-	// Run SQL migrations for functions, triggers, and views
-	log.Println("Running SQL migrations (functions, triggers, views)...")
-	if err := runSQLMigrations(); err != nil {
-		return fmt.Errorf("failed to run SQL migrations: %w", err)
-	}
-
-	log.Println("Migrations completed successfully")
+	slog.Info("Migrations completed")
 	return nil
 }
 
+// Seed creates the default roles, languages and admin user. Existing rows are left untouched.
+func Seed() error {
+	slog.Info("Seeding essential data (roles, languages)")
+	if err := seedEssentialData(); err != nil {
+		return fmt.Errorf("failed to seed essential data: %w", err)
+	}
+	return SeedAdminUser()
+}
+
 // seedEssentialData seeds roles and languages using GORM
 func seedEssentialData() error {
 	// Seed roles
@@ -80,97 +53,27 @@ func seedEssentialData() error {
 			if err := DB.Create(&role).Error; err != nil {
 				return fmt.Errorf("failed to seed role %s: %w", role.Name, err)
 			}
-			log.Printf("Created role: %s", role.Name)
+			slog.Info("Created role", "role", role.Name)
 		} else {
-			log.Printf("Role already exists: %s", role.Name)
+			slog.Debug("Role already exists", "role", role.Name)
 		}
 	}
 
-	// Seed languages
-	languages := []models.Language{
-		{Code: "en", Name: "English", NativeName: "English", Direction: "ltr", IsActive: true},
-		{Code: "zh-HK", Name: "Cantonese (Traditional)", NativeName: "廣東話（繁體）", Direction: "ltr", IsActive: true},
-		{Code: "zh-CN", Name: "Mandarin (Simplified)", NativeName: "普通话（简体）", Direction: "ltr", IsActive: true},
-		{Code: "es", Name: "Spanish", NativeName: "Español", Direction: "ltr", IsActive: true},
-		{Code: "fr", Name: "French", NativeName: "Français", Direction: "ltr", IsActive: true},
-		{Code: "ja", Name: "Japanese", NativeName: "日本語", Direction: "ltr", IsActive: true},
-		{Code: "ko", Name: "Korean", NativeName: "한국어", Direction: "ltr", IsActive: true},
-		{Code: "vi", Name: "Vietnamese", NativeName: "Tiếng Việt", Direction: "ltr", IsActive: true},
+	// Seed languages into an empty table only, so languages an admin deleted stay deleted
+	var languageCount int64
+	if err := DB.Model(&models.Language{}).Count(&languageCount).Error; err != nil {
+		return fmt.Errorf("failed to count languages: %w", err)
 	}
-
-	for _, language := range languages {
-		var existing models.Language
-		result := DB.Where("code = ?", language.Code).First(&existing)
-		if result.Error != nil {
-			// Language doesn't exist, create it
-			if err := DB.Create(&language).Error; err != nil {
-				return fmt.Errorf("failed to seed language %s: %w", language.Code, err)
-			}
-			log.Printf("Created language: %s (%s)", language.Name, language.Code)
-		} else {
-			log.Printf("Language already exists: %s (%s)", language.Name, language.Code)
-		}
-	}
-
-	return nil
-}
-
-// runSQLMigrations executes SQL files from functions, triggers, and views directories
-func runSQLMigrations() error {
-	dbPath := filepath.Join("database")
-
-	// Order matters: functions -> triggers
-	directories := []string{"functions", "triggers"}
-
-	for _, dir := range directories {
-		dirPath := filepath.Join(dbPath, dir)
-		if err := executeSQLFilesInDirectory(dirPath); err != nil {
-			return fmt.Errorf("failed to execute SQL files in %s: %w", dir, err)
-		}
-	}
-
-	return nil
-}
-
-// executeSQLFilesInDirectory executes all .sql files in a directory in sorted order
-func executeSQLFilesInDirectory(dirPath string) error {
-	// Check if directory exists
-	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-		log.Printf("Directory %s does not exist, skipping", dirPath)
-		return nil
-	}
-
-	// Get all .sql files
-	files, err := filepath.Glob(filepath.Join(dirPath, "*.sql"))
-	if err != nil {
-		return fmt.Errorf("failed to read directory %s: %w", dirPath, err)
-	}
-
-	if len(files) == 0 {
-		log.Printf("No SQL files found in %s", dirPath)
+	if languageCount > 0 {
+		slog.Debug("Languages already exist", "count", languageCount)
 		return nil
 	}
 
-	// Sort files to ensure consistent execution order
-	sort.Strings(files)
-
-	// Execute each file
-	for _, file := range files {
-		fileName := filepath.Base(file)
-		log.Printf("Executing SQL file: %s", fileName)
-
-		content, err := os.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("failed to read file %s: %w", fileName, err)
-		}
-
-		// Execute SQL (CREATE OR REPLACE makes it idempotent)
-		if err := DB.Exec(string(content)).Error; err != nil {
-			// Log warning but continue - SQL uses CREATE OR REPLACE for idempotency
-			log.Printf("Warning: SQL file %s execution had issue (may be normal): %v", fileName, err)
-		} else {
-			log.Printf("Successfully executed: %s", fileName)
+	for _, language := range defaultLanguages() {
+		if err := DB.Create(&language).Error; err != nil {
+			return fmt.Errorf("failed to seed language %s: %w", language.Code, err)
 		}
+		slog.Info("Created language", "language", language.Code, "name", language.Name)
 	}
 
 	return nil
@@ -202,7 +105,7 @@ func SeedAdminUser() error {
 	// Check if admin user already exists
 	var existingUser models.User
 	if err := DB.Where("username = ?", "admin").First(&existingUser).Error; err == nil {
-		log.Println("Admin user already exists. Skipping admin user creation.")
+		slog.Debug("Admin user already exists, skipping admin user creation")
 		return nil
 	}
 
@@ -231,12 +134,8 @@ func SeedAdminUser() error {
 		return fmt.Errorf("failed to create admin user: %w", err)
 	}
 
-	log.Println("========================================")
-	log.Println("✓ Admin user created successfully")
-	log.Println("  Username: admin")
-	log.Println("  Password: PleaseChange")
-	log.Println("  IMPORTANT: Please change this password immediately after first login!")
-	log.Println("========================================")
+	slog.Warn("Default admin user created, change this password immediately after first login",
+		"username", "admin", "password", "PleaseChange")
 
 	return nil
 }
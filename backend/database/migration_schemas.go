@@ -0,0 +1,544 @@
+package database
+
+import "time"
+
+// Frozen copies of the models each Go migration from 0003 on creates or alters, as they
+// were when the migration was added, so later model changes don't leak into it. The
+// suffix is the migration version. Never edit them: schema changes belong in their own
+// migrations.
+
+// Tables a migration only references through foreign keys. AutoMigrate also migrates
+// referenced tables, so these carry nothing but the primary key to leave them untouched.
+
+type userRef struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (userRef) TableName() string { return "users" }
+
+type languageRef struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (languageRef) TableName() string { return "languages" }
+
+type journeyRef struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (journeyRef) TableName() string { return "journeys" }
+
+type topicRef struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (topicRef) TableName() string { return "topics" }
+
+// 0003 placement_tests
+
+type placementTest0003 struct {
+	ID                 uint   `gorm:"primaryKey"`
+	UserID             uint   `gorm:"not null;index"`
+	LanguageID         uint   `gorm:"not null;index"`
+	JourneyID          *uint  `gorm:"index"`
+	Status             string `gorm:"size:20;not null;default:'in_progress';index"`
+	CurrentLevel       string `gorm:"size:20;not null"`
+	EstimatedLevel     string `gorm:"size:20"`
+	RecommendedTopicID *uint
+	PendingQuestionID  *uint
+	CompletedAt        *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+
+	User             userRef               `gorm:"foreignKey:UserID"`
+	Language         languageRef           `gorm:"foreignKey:LanguageID"`
+	Journey          *journeyRef           `gorm:"foreignKey:JourneyID"`
+	RecommendedTopic *topicRef             `gorm:"foreignKey:RecommendedTopicID"`
+	Answers          []placementAnswer0003 `gorm:"foreignKey:PlacementTestID;constraint:OnDelete:CASCADE"`
+}
+
+func (placementTest0003) TableName() string { return "placement_tests" }
+
+type placementAnswer0003 struct {
+	ID              uint   `gorm:"primaryKey"`
+	PlacementTestID uint   `gorm:"not null;index"`
+	QuestionID      uint   `gorm:"not null"`
+	TopicID         uint   `gorm:"not null"`
+	Level           string `gorm:"size:20;not null"`
+	Answer          string `gorm:"size:1;not null"`
+	IsCorrect       bool   `gorm:"not null"`
+	CreatedAt       time.Time
+}
+
+func (placementAnswer0003) TableName() string { return "placement_answers" }
+
+// 0005 ai_daily_usage
+
+type aiDailyUsage0005 struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_ai_daily_usage_user_date_category"`
+	UsageDate time.Time `gorm:"type:date;not null;uniqueIndex:idx_ai_daily_usage_user_date_category;index"`
+	Category  string    `gorm:"size:20;not null;uniqueIndex:idx_ai_daily_usage_user_date_category"`
+	Count     int       `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	User userRef `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (aiDailyUsage0005) TableName() string { return "ai_daily_usage" }
+
+// 0006 ai_usage_ledger
+
+type aiUsage0006 struct {
+	ID            uint      `gorm:"primaryKey"`
+	UserID        *uint     `gorm:"index:idx_ai_usage_user_created"`
+	Provider      string    `gorm:"size:50;not null"`
+	Operation     string    `gorm:"size:50;not null"`
+	Characters    int       `gorm:"not null;default:0"`
+	Images        int       `gorm:"not null;default:0"`
+	EstimatedCost float64   `gorm:"type:numeric(12,6);not null;default:0"`
+	CacheHit      bool      `gorm:"not null;default:false"`
+	CreatedAt     time.Time `gorm:"index:idx_ai_usage_user_created;index"`
+
+	User *userRef `gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+}
+
+func (aiUsage0006) TableName() string { return "ai_usage" }
+
+// 0007 assets
+
+type asset0007 struct {
+	ID        uint   `gorm:"primaryKey"`
+	URL       string `gorm:"size:500;not null;uniqueIndex"`
+	Source    string `gorm:"size:20;not null;index"`
+	Format    string `gorm:"size:10;not null"`
+	Width     int    `gorm:"not null"`
+	Height    int    `gorm:"not null"`
+	Bytes     int64  `gorm:"not null"`
+	Blurhash  string `gorm:"size:100"`
+	SHA256    string `gorm:"size:64;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Variants []assetVariant0007 `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+}
+
+func (asset0007) TableName() string { return "assets" }
+
+type assetVariant0007 struct {
+	ID      uint   `gorm:"primaryKey"`
+	AssetID uint   `gorm:"not null;uniqueIndex:idx_asset_variants_asset_name_format"`
+	Name    string `gorm:"size:20;not null;uniqueIndex:idx_asset_variants_asset_name_format"`
+	Format  string `gorm:"size:10;not null;uniqueIndex:idx_asset_variants_asset_name_format"`
+	URL     string `gorm:"size:500;not null"`
+	Width   int    `gorm:"not null"`
+	Height  int    `gorm:"not null"`
+	Bytes   int64  `gorm:"not null"`
+}
+
+func (assetVariant0007) TableName() string { return "asset_variants" }
+
+// 0008 audio_assets
+
+type asset0008 struct {
+	ID         uint   `gorm:"primaryKey"`
+	URL        string `gorm:"size:500;not null;uniqueIndex"`
+	Kind       string `gorm:"size:10;not null;default:'image'"`
+	Source     string `gorm:"size:20;not null;index"`
+	Format     string `gorm:"size:10;not null"`
+	Width      int    `gorm:"not null"`
+	Height     int    `gorm:"not null"`
+	Bytes      int64  `gorm:"not null"`
+	Blurhash   string `gorm:"size:100"`
+	DurationMs int64  `gorm:"not null;default:0"`
+	SampleRate int    `gorm:"not null;default:0"`
+	Channels   int    `gorm:"not null;default:0"`
+	SHA256     string `gorm:"size:64;index"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	Variants []assetVariant0007 `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+}
+
+func (asset0008) TableName() string { return "assets" }
+
+// 0009 asset_library
+
+type asset0009 struct {
+	ID           uint   `gorm:"primaryKey"`
+	URL          string `gorm:"size:500;not null;uniqueIndex"`
+	Kind         string `gorm:"size:10;not null;default:'image'"`
+	Source       string `gorm:"size:20;not null;index"`
+	Format       string `gorm:"size:10;not null"`
+	Width        int    `gorm:"not null"`
+	Height       int    `gorm:"not null"`
+	Bytes        int64  `gorm:"not null"`
+	Blurhash     string `gorm:"size:100"`
+	DurationMs   int64  `gorm:"not null;default:0"`
+	SampleRate   int    `gorm:"not null;default:0"`
+	Channels     int    `gorm:"not null;default:0"`
+	SHA256       string `gorm:"size:64;index"`
+	OriginalName string `gorm:"size:255"`
+	UploadedBy   *uint  `gorm:"index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Uploader *userRef           `gorm:"foreignKey:UploadedBy;constraint:OnDelete:SET NULL"`
+	Variants []assetVariant0007 `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+	Tags     []assetTag0009     `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+}
+
+func (asset0009) TableName() string { return "assets" }
+
+type assetTag0009 struct {
+	ID      uint   `gorm:"primaryKey"`
+	AssetID uint   `gorm:"not null;uniqueIndex:idx_asset_tags_asset_tag"`
+	Tag     string `gorm:"size:50;not null;uniqueIndex:idx_asset_tags_asset_tag;index"`
+}
+
+func (assetTag0009) TableName() string { return "asset_tags" }
+
+// 0010 image_search
+
+type asset0010 struct {
+	ID           uint   `gorm:"primaryKey"`
+	URL          string `gorm:"size:500;not null;uniqueIndex"`
+	Kind         string `gorm:"size:10;not null;default:'image'"`
+	Source       string `gorm:"size:20;not null;index"`
+	Format       string `gorm:"size:10;not null"`
+	Width        int    `gorm:"not null"`
+	Height       int    `gorm:"not null"`
+	Bytes        int64  `gorm:"not null"`
+	Blurhash     string `gorm:"size:100"`
+	DurationMs   int64  `gorm:"not null;default:0"`
+	SampleRate   int    `gorm:"not null;default:0"`
+	Channels     int    `gorm:"not null;default:0"`
+	SHA256       string `gorm:"size:64;index"`
+	OriginalName string `gorm:"size:255"`
+	Word         string `gorm:"size:255;not null;default:''"`
+	Translation  string `gorm:"size:255;not null;default:''"`
+	Prompt       string `gorm:"type:text;not null;default:''"`
+	UploadedBy   *uint  `gorm:"index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Uploader *userRef           `gorm:"foreignKey:UploadedBy;constraint:OnDelete:SET NULL"`
+	Variants []assetVariant0007 `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+	Tags     []assetTag0009     `gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+}
+
+func (asset0010) TableName() string { return "assets" }
+
+// 0011 revisions
+
+type revision0011 struct {
+	ID           uint   `gorm:"primaryKey"`
+	EntityType   string `gorm:"size:20;not null;uniqueIndex:idx_revisions_entity_version"`
+	EntityID     uint   `gorm:"not null;uniqueIndex:idx_revisions_entity_version"`
+	Version      int    `gorm:"not null;uniqueIndex:idx_revisions_entity_version"`
+	Action       string `gorm:"size:20;not null;index"`
+	Snapshot     string `gorm:"type:jsonb;not null"`
+	Changes      string `gorm:"type:jsonb;not null;default:'[]'"`
+	RestoredFrom *uint
+	UserID       *uint     `gorm:"index"`
+	CreatedAt    time.Time `gorm:"index"`
+
+	User *userRef `gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+}
+
+func (revision0011) TableName() string { return "revisions" }
+
+// 0012 audit_log
+
+type auditEvent0012 struct {
+	ID         uint      `gorm:"primaryKey"`
+	Action     string    `gorm:"size:50;not null;index"`
+	ActorID    *uint     `gorm:"index"`
+	TargetType string    `gorm:"size:30;index:idx_audit_events_target"`
+	TargetID   *uint     `gorm:"index:idx_audit_events_target"`
+	Details    string    `gorm:"type:jsonb;not null;default:'{}'"`
+	IP         string    `gorm:"size:45;index"`
+	UserAgent  string    `gorm:"size:255"`
+	RequestID  string    `gorm:"size:64"`
+	CreatedAt  time.Time `gorm:"not null;index"`
+}
+
+func (auditEvent0012) TableName() string { return "audit_events" }
+
+// 0013 content_review
+
+type topic0013 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:200;not null"`
+	Description string `gorm:"type:text"`
+	Level       string `gorm:"size:20;not null"`
+	LanguageID  uint   `gorm:"not null"`
+	CreatedBy   uint   `gorm:"not null"`
+	IsPublic    bool   `gorm:"default:false;not null"`
+	Status      string `gorm:"size:20;not null;default:draft;index"`
+	DraftOf     *uint  `gorm:"index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Language languageRef `gorm:"foreignKey:LanguageID"`
+	Creator  userRef     `gorm:"foreignKey:CreatedBy"`
+}
+
+func (topic0013) TableName() string { return "topics" }
+
+type journey0013 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:200;not null"`
+	Description string `gorm:"type:text"`
+	LanguageID  uint   `gorm:"not null"`
+	CreatedBy   uint   `gorm:"not null"`
+	Status      string `gorm:"size:20;not null;default:draft;index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Language languageRef `gorm:"foreignKey:LanguageID"`
+	Creator  userRef     `gorm:"foreignKey:CreatedBy"`
+}
+
+func (journey0013) TableName() string { return "journeys" }
+
+type contentReview0013 struct {
+	ID           uint   `gorm:"primaryKey"`
+	ContentType  string `gorm:"size:20;not null;index:idx_content_reviews_content"`
+	ContentID    uint   `gorm:"not null;index:idx_content_reviews_content"`
+	Status       string `gorm:"size:20;not null;default:pending;index"`
+	Note         string `gorm:"type:text"`
+	SubmittedBy  uint   `gorm:"not null;index"`
+	ReviewerID   *uint  `gorm:"index"`
+	DecisionNote string `gorm:"type:text"`
+	DecidedBy    *uint
+	DecidedAt    *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Submitter userRef             `gorm:"foreignKey:SubmittedBy"`
+	Reviewer  *userRef            `gorm:"foreignKey:ReviewerID;constraint:OnDelete:SET NULL"`
+	Decider   *userRef            `gorm:"foreignKey:DecidedBy;constraint:OnDelete:SET NULL"`
+	Comments  []reviewComment0013 `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE"`
+}
+
+func (contentReview0013) TableName() string { return "content_reviews" }
+
+type reviewComment0013 struct {
+	ID        uint   `gorm:"primaryKey"`
+	ReviewID  uint   `gorm:"not null;index"`
+	UserID    uint   `gorm:"not null"`
+	Body      string `gorm:"type:text;not null"`
+	CreatedAt time.Time
+
+	User userRef `gorm:"foreignKey:UserID"`
+}
+
+func (reviewComment0013) TableName() string { return "review_comments" }
+
+// 0014 marketplace
+
+type journey0014 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:200;not null"`
+	Description string `gorm:"type:text"`
+	LanguageID  uint   `gorm:"not null"`
+	CreatedBy   uint   `gorm:"not null"`
+	IsPublic    bool   `gorm:"default:false;not null"`
+	Status      string `gorm:"size:20;not null;default:draft;index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Language languageRef `gorm:"foreignKey:LanguageID"`
+	Creator  userRef     `gorm:"foreignKey:CreatedBy"`
+}
+
+func (journey0014) TableName() string { return "journeys" }
+
+type conversation0014 struct {
+	ID               uint   `gorm:"primaryKey"`
+	Title            string `gorm:"size:200;not null"`
+	Description      string `gorm:"type:text"`
+	Context          string `gorm:"type:text"`
+	LanguageID       uint   `gorm:"not null"`
+	DifficultyLevel  string `gorm:"size:20;not null"`
+	ScenarioAudioURL string `gorm:"size:500"`
+	ScenarioImageURL string `gorm:"size:500"`
+	CreatedBy        uint   `gorm:"not null"`
+	IsPublic         bool   `gorm:"default:false;not null"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+
+	Language languageRef `gorm:"foreignKey:LanguageID"`
+	Creator  userRef     `gorm:"foreignKey:CreatedBy"`
+}
+
+func (conversation0014) TableName() string { return "conversations" }
+
+type contentRating0014 struct {
+	ID          uint   `gorm:"primaryKey"`
+	ContentType string `gorm:"size:20;not null;uniqueIndex:idx_content_ratings_user"`
+	ContentID   uint   `gorm:"not null;uniqueIndex:idx_content_ratings_user"`
+	UserID      uint   `gorm:"not null;uniqueIndex:idx_content_ratings_user"`
+	Rating      int    `gorm:"not null"`
+	Comment     string `gorm:"type:text"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	User userRef `gorm:"foreignKey:UserID"`
+}
+
+func (contentRating0014) TableName() string { return "content_ratings" }
+
+type contentClone0014 struct {
+	ID             uint   `gorm:"primaryKey"`
+	ContentType    string `gorm:"size:20;not null;index:idx_content_clones_source;index:idx_content_clones_clone"`
+	SourceID       uint   `gorm:"not null;index:idx_content_clones_source"`
+	SourceName     string `gorm:"size:200;not null"`
+	SourceAuthorID uint   `gorm:"not null;index"`
+	CloneID        uint   `gorm:"not null;index:idx_content_clones_clone"`
+	ClonedBy       uint   `gorm:"not null;index"`
+	CreatedAt      time.Time
+}
+
+func (contentClone0014) TableName() string { return "content_clones" }
+
+// 0015 word_details
+
+type word0015 struct {
+	ID           uint   `gorm:"primaryKey"`
+	BaseWord     string `gorm:"size:255;not null"`
+	ImageURL     string `gorm:"size:500"`
+	Notes        string `gorm:"type:text"`
+	PartOfSpeech string `gorm:"size:20"`
+	CreatedBy    uint   `gorm:"not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Creator      userRef               `gorm:"foreignKey:CreatedBy"`
+	Translations []wordTranslation0015 `gorm:"foreignKey:WordID"`
+	Relations    []wordRelation0015    `gorm:"foreignKey:WordID"`
+}
+
+func (word0015) TableName() string { return "words" }
+
+type wordTranslation0015 struct {
+	ID           uint   `gorm:"primaryKey"`
+	WordID       uint   `gorm:"not null"`
+	LanguageID   uint   `gorm:"not null"`
+	Translation  string `gorm:"type:text;not null"`
+	Romanization string `gorm:"size:255"`
+	AudioURL     string `gorm:"size:500"`
+	MeasureWord  string `gorm:"size:50"`
+	Gender       string `gorm:"size:20"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Word     word0015          `gorm:"foreignKey:WordID"`
+	Language languageRef       `gorm:"foreignKey:LanguageID"`
+	Examples []wordExample0015 `gorm:"foreignKey:TranslationID"`
+}
+
+func (wordTranslation0015) TableName() string { return "word_translations" }
+
+type wordExample0015 struct {
+	ID            uint   `gorm:"primaryKey"`
+	TranslationID uint   `gorm:"not null;index"`
+	Sentence      string `gorm:"type:text;not null"`
+	Romanization  string `gorm:"size:500"`
+	Meaning       string `gorm:"type:text"`
+	AudioURL      string `gorm:"size:500"`
+	SequenceOrder int    `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (wordExample0015) TableName() string { return "word_examples" }
+
+type wordRelation0015 struct {
+	ID            uint   `gorm:"primaryKey"`
+	WordID        uint   `gorm:"not null;uniqueIndex:idx_word_relations_pair"`
+	RelatedWordID uint   `gorm:"not null;uniqueIndex:idx_word_relations_pair;index"`
+	RelationType  string `gorm:"size:20;not null"`
+	CreatedAt     time.Time
+
+	RelatedWord *word0015 `gorm:"foreignKey:RelatedWordID"`
+}
+
+func (wordRelation0015) TableName() string { return "word_relations" }
+
+// 0016 language_settings
+
+type language0016 struct {
+	ID                 uint   `gorm:"primaryKey"`
+	Code               string `gorm:"size:10;unique;not null"`
+	Name               string `gorm:"size:100;not null"`
+	NativeName         string `gorm:"size:100"`
+	Direction          string `gorm:"size:3;default:ltr"`
+	IsActive           bool   `gorm:"default:true"`
+	DefaultVoice       string `gorm:"size:100"`
+	Voices             string `gorm:"type:jsonb;not null;default:'[]'"`
+	RomanizationScheme string `gorm:"size:50"`
+	TranslatorCode     string `gorm:"size:20"`
+	FontFamily         string `gorm:"size:255"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (language0016) TableName() string { return "languages" }
+
+// languageSettings0016 are the voices, translator codes and fonts that used to be
+// hard-coded, moved onto the seeded languages
+var languageSettings0016 = []language0016{
+	{
+		Code:         "en",
+		DefaultVoice: "en-US-JennyNeural",
+		Voices:       `[{"voice":"en-US-JennyNeural","name":"Jenny (Female)","gender":"Female"},{"voice":"en-US-GuyNeural","name":"Guy (Male)","gender":"Male"},{"voice":"en-US-AriaNeural","name":"Aria (Female)","gender":"Female"},{"voice":"en-US-DavisNeural","name":"Davis (Male)","gender":"Male"}]`,
+	},
+	{
+		Code:               "zh-HK",
+		DefaultVoice:       "zh-HK-HiuMaanNeural",
+		Voices:             `[{"voice":"zh-HK-HiuMaanNeural","name":"HiuMaan (Female)","gender":"Female"},{"voice":"zh-HK-WanLungNeural","name":"WanLung (Male)","gender":"Male"},{"voice":"zh-HK-HiuGaaiNeural","name":"HiuGaai (Female)","gender":"Female"}]`,
+		RomanizationScheme: "jyutping",
+		TranslatorCode:     "zh-Hant",
+		FontFamily:         `"Noto Sans HK", "PingFang HK", "Microsoft JhengHei", sans-serif`,
+	},
+	{
+		Code:               "zh-CN",
+		DefaultVoice:       "zh-CN-XiaoxiaoNeural",
+		Voices:             `[{"voice":"zh-CN-XiaoxiaoNeural","name":"Xiaoxiao (Female)","gender":"Female"},{"voice":"zh-CN-YunyangNeural","name":"Yunyang (Male)","gender":"Male"},{"voice":"zh-CN-XiaoyiNeural","name":"Xiaoyi (Female)","gender":"Female"},{"voice":"zh-CN-YunjianNeural","name":"Yunjian (Male)","gender":"Male"}]`,
+		RomanizationScheme: "pinyin",
+		TranslatorCode:     "zh-Hans",
+		FontFamily:         `"Noto Sans SC", "PingFang SC", "Microsoft YaHei", sans-serif`,
+	},
+	{
+		Code:         "es",
+		DefaultVoice: "es-ES-ElviraNeural",
+		Voices:       `[{"voice":"es-ES-ElviraNeural","name":"Elvira (Female)","gender":"Female"},{"voice":"es-ES-AlvaroNeural","name":"Alvaro (Male)","gender":"Male"},{"voice":"es-ES-AbrilNeural","name":"Abril (Female)","gender":"Female"},{"voice":"es-ES-ArnoldNeural","name":"Arnold (Male)","gender":"Male"}]`,
+	},
+	{
+		Code:         "fr",
+		DefaultVoice: "fr-FR-DeniseNeural",
+		Voices:       `[{"voice":"fr-FR-DeniseNeural","name":"Denise (Female)","gender":"Female"},{"voice":"fr-FR-HenriNeural","name":"Henri (Male)","gender":"Male"},{"voice":"fr-FR-BrigitteNeural","name":"Brigitte (Female)","gender":"Female"},{"voice":"fr-FR-AlainNeural","name":"Alain (Male)","gender":"Male"}]`,
+	},
+	{
+		Code:               "ja",
+		DefaultVoice:       "ja-JP-NanamiNeural",
+		Voices:             `[{"voice":"ja-JP-NanamiNeural","name":"Nanami (Female)","gender":"Female"},{"voice":"ja-JP-KeitaNeural","name":"Keita (Male)","gender":"Male"},{"voice":"ja-JP-AoiNeural","name":"Aoi (Female)","gender":"Female"},{"voice":"ja-JP-DaichiNeural","name":"Daichi (Male)","gender":"Male"}]`,
+		RomanizationScheme: "hepburn",
+		FontFamily:         `"Noto Sans JP", "Hiragino Sans", "Yu Gothic", sans-serif`,
+	},
+	{
+		Code:               "ko",
+		DefaultVoice:       "ko-KR-SunHiNeural",
+		Voices:             `[{"voice":"ko-KR-SunHiNeural","name":"SunHi (Female)","gender":"Female"},{"voice":"ko-KR-InJoonNeural","name":"InJoon (Male)","gender":"Male"},{"voice":"ko-KR-JiMinNeural","name":"JiMin (Female)","gender":"Female"},{"voice":"ko-KR-BongJinNeural","name":"BongJin (Male)","gender":"Male"}]`,
+		RomanizationScheme: "revised",
+		FontFamily:         `"Noto Sans KR", "Apple SD Gothic Neo", "Malgun Gothic", sans-serif`,
+	},
+	{
+		Code:         "vi",
+		DefaultVoice: "vi-VN-HoaiMyNeural",
+		Voices:       `[{"voice":"vi-VN-HoaiMyNeural","name":"HoaiMy (Female)","gender":"Female"},{"voice":"vi-VN-NamMinhNeural","name":"NamMinh (Male)","gender":"Male"}]`,
+	},
+}
@@ -0,0 +1,449 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// sqlFiles embeds the SQL sources so migrations don't depend on the working directory
+//
+//go:embed functions/*.sql triggers/*.sql migrations/*.sql
+var sqlFiles embed.FS
+
+// funcMigration is a migration implemented in Go
+type funcMigration struct {
+	version string
+	name    string
+	up      func(tx *gorm.DB) error
+	down    func(tx *gorm.DB) error
+}
+
+func (m funcMigration) Version() string        { return m.version }
+func (m funcMigration) Name() string           { return m.name }
+func (m funcMigration) Up(tx *gorm.DB) error   { return m.up(tx) }
+func (m funcMigration) Down(tx *gorm.DB) error { return m.down(tx) }
+
+// sqlMigration is a migration loaded from migrations/NNNN_name.up.sql and .down.sql
+type sqlMigration struct {
+	version  string
+	name     string
+	upFile   string
+	downFile string
+}
+
+func (m sqlMigration) Version() string { return m.version }
+func (m sqlMigration) Name() string    { return m.name }
+
+func (m sqlMigration) Up(tx *gorm.DB) error {
+	return execSQLFile(tx, m.upFile)
+}
+
+func (m sqlMigration) Down(tx *gorm.DB) error {
+	if m.downFile == "" {
+		return fmt.Errorf("migration %s has no down script", m.version)
+	}
+	return execSQLFile(tx, m.downFile)
+}
+
+// baselineModels are the tables of the original schema, in dependency order
+var baselineModels = []interface{}{
+	// Core models
+	&baselineUser{},
+	&baselineRole{},
+	&baselineUserRole{},
+
+	// Content models
+	&baselineLanguage{},
+	&baselineWord{},
+	&baselineWordTranslation{},
+	&baselineTopic{},
+	&baselineTopicWord{},
+	&baselineJourney{},
+	&baselineJourneyTopic{},
+	&baselineJourneyInvitation{},
+	&baselineQuizQuestion{},
+
+	// Conversation models
+	&baselineConversation{},
+	&baselineConversationLine{},
+	&baselineTopicConversation{},
+
+	// User progress models
+	&baselineUserJourney{},
+	&baselineUserProgress{},
+	&baselineUserBookmark{},
+}
+
+// baselineTriggers are dropped, with their tables, when the functions migration is rolled back
+var baselineTriggers = []struct{ name, table string }{
+	{"update_users_updated_at", "users"},
+	{"update_words_updated_at", "words"},
+	{"update_word_translations_updated_at", "word_translations"},
+	{"update_topics_updated_at", "topics"},
+	{"update_journeys_updated_at", "journeys"},
+	{"update_topic_quizzes_updated_at", "topic_quizzes"},
+	{"update_journey_status_trigger", "user_progress"},
+	{"journey_topic_reset_completion_trigger", "journey_topics"},
+	{"update_journey_invitations_updated_at", "journey_invitations"},
+}
+
+func init() {
+	// Versions 0001-0003 capture the schema that used to be created on every boot.
+	// They are idempotent, so existing databases adopt them without changes.
+	Registry.Register(funcMigration{
+		version: "0001",
+		name:    "baseline_schema",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(baselineModels...)
+		},
+		down: func(tx *gorm.DB) error {
+			for i := len(baselineModels) - 1; i >= 0; i-- {
+				if err := tx.Migrator().DropTable(baselineModels[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0002",
+		name:    "functions_and_triggers",
+		up: func(tx *gorm.DB) error {
+			// Order matters: functions -> triggers
+			for _, dir := range []string{"functions", "triggers"} {
+				files, err := fs.Glob(sqlFiles, dir+"/*.sql")
+				if err != nil {
+					return err
+				}
+				for _, file := range files {
+					if err := execSQLFile(tx, file); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		down: func(tx *gorm.DB) error {
+			for _, trigger := range baselineTriggers {
+				if err := tx.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", trigger.name, trigger.table)).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Exec(`
+				DROP FUNCTION IF EXISTS reset_journey_completion_on_new_topic();
+				DROP FUNCTION IF EXISTS update_journey_status();
+				DROP FUNCTION IF EXISTS update_updated_at_column();
+			`).Error
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0003",
+		name:    "placement_tests",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&placementTest0003{}, &placementAnswer0003{})
+		},
+		down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&placementAnswer0003{}, &placementTest0003{})
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0005",
+		name:    "ai_daily_usage",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&aiDailyUsage0005{})
+		},
+		down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&aiDailyUsage0005{})
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0006",
+		name:    "ai_usage_ledger",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&aiUsage0006{})
+		},
+		down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&aiUsage0006{})
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0007",
+		name:    "assets",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&asset0007{}, &assetVariant0007{})
+		},
+		down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&assetVariant0007{}, &asset0007{})
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0008",
+		name:    "audio_assets",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&asset0008{})
+		},
+		down: func(tx *gorm.DB) error {
+			if err := tx.Where("kind = ?", "audio").Delete(&asset0008{}).Error; err != nil {
+				return err
+			}
+			for _, column := range []string{"Kind", "DurationMs", "SampleRate", "Channels"} {
+				if err := tx.Migrator().DropColumn(&asset0008{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0009",
+		name:    "asset_library",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&asset0009{}, &assetTag0009{})
+		},
+		down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&assetTag0009{}); err != nil {
+				return err
+			}
+			for _, column := range []string{"OriginalName", "UploadedBy"} {
+				if err := tx.Migrator().DropColumn(&asset0009{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0010",
+		name:    "image_search",
+		up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&asset0010{}); err != nil {
+				return err
+			}
+			// Same expressions as the image search in repositories/asset_repository.go
+			return tx.Exec(`
+				CREATE INDEX IF NOT EXISTS idx_assets_search_fts ON assets
+					USING GIN (to_tsvector('simple', learnspeak_search_text(word || ' ' || translation || ' ' || prompt || ' ' || coalesce(original_name, ''))));
+				CREATE INDEX IF NOT EXISTS idx_assets_word_trgm ON assets
+					USING GIN (learnspeak_search_text(word) gin_trgm_ops);
+				CREATE INDEX IF NOT EXISTS idx_assets_translation_trgm ON assets
+					USING GIN (learnspeak_search_text(translation) gin_trgm_ops);
+			`).Error
+		},
+		down: func(tx *gorm.DB) error {
+			if err := tx.Exec(`
+				DROP INDEX IF EXISTS idx_assets_search_fts;
+				DROP INDEX IF EXISTS idx_assets_word_trgm;
+				DROP INDEX IF EXISTS idx_assets_translation_trgm;
+			`).Error; err != nil {
+				return err
+			}
+			for _, column := range []string{"Word", "Translation", "Prompt"} {
+				if err := tx.Migrator().DropColumn(&asset0010{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0011",
+		name:    "revisions",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&revision0011{})
+		},
+		down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&revision0011{})
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0012",
+		name:    "audit_log",
+		up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&auditEvent0012{}); err != nil {
+				return err
+			}
+			return tx.Exec(`
+				CREATE OR REPLACE FUNCTION learnspeak_audit_events_append_only() RETURNS trigger AS $$
+				BEGIN
+					RAISE EXCEPTION 'audit_events is append-only';
+				END;
+				$$ LANGUAGE plpgsql;
+
+				DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+				CREATE TRIGGER audit_events_append_only
+					BEFORE UPDATE OR DELETE ON audit_events
+					FOR EACH ROW EXECUTE FUNCTION learnspeak_audit_events_append_only();
+
+				DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+				CREATE TRIGGER audit_events_no_truncate
+					BEFORE TRUNCATE ON audit_events
+					FOR EACH STATEMENT EXECUTE FUNCTION learnspeak_audit_events_append_only();
+			`).Error
+		},
+		down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&auditEvent0012{}); err != nil {
+				return err
+			}
+			return tx.Exec(`DROP FUNCTION IF EXISTS learnspeak_audit_events_append_only()`).Error
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0013",
+		name:    "content_review",
+		up: func(tx *gorm.DB) error {
+			// Content learners could already see stays published: existing rows take the
+			// column default, which then becomes draft for new content
+			if err := tx.Exec(`
+				ALTER TABLE topics ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'published';
+				ALTER TABLE topics ALTER COLUMN status SET DEFAULT 'draft';
+				ALTER TABLE journeys ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'published';
+				ALTER TABLE journeys ALTER COLUMN status SET DEFAULT 'draft';
+			`).Error; err != nil {
+				return err
+			}
+			if err := tx.AutoMigrate(&topic0013{}, &journey0013{}, &contentReview0013{}, &reviewComment0013{}); err != nil {
+				return err
+			}
+			// Every row predates the review workflow, so none is a real draft. Publish rows
+			// that got the draft default anyway because their status column already existed.
+			return tx.Exec(`
+				UPDATE topics SET status = 'published' WHERE status = 'draft' AND draft_of IS NULL;
+				UPDATE journeys SET status = 'published' WHERE status = 'draft';
+			`).Error
+		},
+		down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&reviewComment0013{}, &contentReview0013{}); err != nil {
+				return err
+			}
+			// Draft copies are kept and become ordinary topics
+			for _, column := range []string{"Status", "DraftOf"} {
+				if err := tx.Migrator().DropColumn(&topic0013{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().DropColumn(&journey0013{}, "Status")
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0014",
+		name:    "marketplace",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&journey0014{}, &conversation0014{}, &contentRating0014{}, &contentClone0014{})
+		},
+		down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&contentClone0014{}, &contentRating0014{}); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&conversation0014{}, "IsPublic"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&journey0014{}, "IsPublic")
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0015",
+		name:    "word_details",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&word0015{}, &wordTranslation0015{}, &wordExample0015{}, &wordRelation0015{})
+		},
+		down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&wordRelation0015{}, &wordExample0015{}); err != nil {
+				return err
+			}
+			for _, column := range []string{"MeasureWord", "Gender"} {
+				if err := tx.Migrator().DropColumn(&wordTranslation0015{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().DropColumn(&word0015{}, "PartOfSpeech")
+		},
+	})
+
+	Registry.Register(funcMigration{
+		version: "0016",
+		name:    "language_settings",
+		up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&language0016{}); err != nil {
+				return err
+			}
+			for _, language := range languageSettings0016 {
+				if err := tx.Model(&language0016{}).Where("code = ?", language.Code).Updates(map[string]interface{}{
+					"default_voice":       language.DefaultVoice,
+					"voices":              language.Voices,
+					"romanization_scheme": language.RomanizationScheme,
+					"translator_code":     language.TranslatorCode,
+					"font_family":         language.FontFamily,
+				}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		down: func(tx *gorm.DB) error {
+			for _, column := range []string{"DefaultVoice", "Voices", "RomanizationScheme", "TranslatorCode", "FontFamily", "UpdatedAt"} {
+				if err := tx.Migrator().DropColumn(&language0016{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	registerSQLMigrations()
+}
+
+// registerSQLMigrations registers every migrations/NNNN_name.up.sql with its optional .down.sql
+func registerSQLMigrations() {
+	files, err := fs.Glob(sqlFiles, "migrations/*.up.sql")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, file := range files {
+		base := strings.TrimSuffix(path.Base(file), ".up.sql")
+		version, name, ok := strings.Cut(base, "_")
+		if !ok {
+			panic(fmt.Sprintf("invalid migration file name %s: expected NNNN_name.up.sql", file))
+		}
+
+		m := sqlMigration{
+			version: version,
+			name:    name,
+			upFile:  file,
+		}
+		downFile := path.Join(path.Dir(file), base+".down.sql")
+		if _, err := fs.Stat(sqlFiles, downFile); err == nil {
+			m.downFile = downFile
+		}
+		Registry.Register(m)
+	}
+}
+
+func execSQLFile(tx *gorm.DB, file string) error {
+	content, err := sqlFiles.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if err := tx.Exec(string(content)).Error; err != nil {
+		return fmt.Errorf("failed to execute %s: %w", file, err)
+	}
+	return nil
+}
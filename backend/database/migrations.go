@@ -2,12 +2,19 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"sort"
+	"time"
+
+	"dannyswat/learnspeak/models"
 
 	"gorm.io/gorm"
 )
 
+// migrationLockKey is the Postgres advisory lock held while migrating, so replicas
+// starting at the same time apply migrations one after another instead of racing
+const migrationLockKey int64 = 7_305_874_526_091_337
+
 // Migration interface that all migrations must implement
 type Migration interface {
 	// Up runs the migration
@@ -20,6 +27,17 @@ type Migration interface {
 	Name() string
 }
 
+// MigrationStatus describes whether a migration version has been applied
+type MigrationStatus struct {
+	Version   string
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Missing is set for versions recorded in schema_migrations that are not registered
+	// in this build, e.g. after running a newer release against the same database
+	Missing bool
+}
+
 // MigrationRegistry holds all registered migrations
 type MigrationRegistry struct {
 	migrations []Migration
@@ -34,6 +52,11 @@ func NewMigrationRegistry() *MigrationRegistry {
 
 // Register adds a migration to the registry
 func (r *MigrationRegistry) Register(m Migration) {
+	for _, existing := range r.migrations {
+		if existing.Version() == m.Version() {
+			panic(fmt.Sprintf("duplicate migration version %s (%s, %s)", m.Version(), existing.Name(), m.Name()))
+		}
+	}
 	r.migrations = append(r.migrations, m)
 }
 
@@ -46,75 +69,198 @@ func (r *MigrationRegistry) GetMigrations() []Migration {
 	return r.migrations
 }
 
-// RunMigrations executes all registered migrations
-func (r *MigrationRegistry) RunMigrations(db *gorm.DB) error {
-	log.Println("Running database migrations...")
+// Up applies pending migrations in version order, up to and including target
+// (all of them when target is empty). Each migration runs in its own transaction
+// together with its schema_migrations record.
+func (r *MigrationRegistry) Up(db *gorm.DB, target string) ([]Migration, error) {
+	var applied []Migration
 
-	migrations := r.GetMigrations()
-	for _, migration := range migrations {
-		log.Printf("Running migration %s: %s", migration.Version(), migration.Name())
+	err := withMigrationLock(db, func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
 
-		// Run in transaction
-		err := db.Transaction(func(tx *gorm.DB) error {
-			return migration.Up(tx)
-		})
+		if target != "" && r.find(target) == nil {
+			return fmt.Errorf("unknown migration version %s", target)
+		}
 
-		if err != nil {
-			// Check if it's an "already exists" error - this is okay
-			errMsg := err.Error()
-			if containsAny(errMsg, []string{"already exists", "duplicate key"}) {
-				log.Printf("Migration %s already applied (skipping)", migration.Version())
+		for _, migration := range r.GetMigrations() {
+			if target != "" && migration.Version() > target {
+				break
+			}
+			if _, ok := done[migration.Version()]; ok {
 				continue
 			}
-			return fmt.Errorf("failed to execute migration %s: %w", migration.Version(), err)
+
+			slog.Info("Applying migration", "version", migration.Version(), "name", migration.Name())
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&models.SchemaMigration{
+					Version:   migration.Version(),
+					Name:      migration.Name(),
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %s: %w", migration.Version(), err)
+			}
+
+			slog.Info("Applied migration", "version", migration.Version())
+			applied = append(applied, migration)
 		}
 
-		log.Printf("Successfully applied migration: %s", migration.Version())
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the most recently applied migrations, newest first
+func (r *MigrationRegistry) Down(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1")
 	}
 
-	log.Println("Migrations completed successfully")
-	return nil
+	var rolledBack []Migration
+
+	err := withMigrationLock(db, func(conn *gorm.DB) error {
+		var records []models.SchemaMigration
+		if err := conn.Order("version DESC").Limit(steps).Find(&records).Error; err != nil {
+			return fmt.Errorf("failed to read schema_migrations: %w", err)
+		}
+
+		for _, record := range records {
+			migration := r.find(record.Version)
+			if migration == nil {
+				return fmt.Errorf("migration %s (%s) is not registered in this build and cannot be rolled back", record.Version, record.Name)
+			}
+
+			slog.Info("Rolling back migration", "version", migration.Version(), "name", migration.Name())
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&models.SchemaMigration{}, "version = ?", record.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %s: %w", migration.Version(), err)
+			}
+
+			slog.Info("Rolled back migration", "version", migration.Version())
+			rolledBack = append(rolledBack, migration)
+		}
+
+		return nil
+	})
+
+	return rolledBack, err
 }
 
-// RollbackMigrations rolls back all migrations in reverse order
-func (r *MigrationRegistry) RollbackMigrations(db *gorm.DB) error {
-	log.Println("Rolling back database migrations...")
+// Status lists every registered migration and whether it has been applied
+func (r *MigrationRegistry) Status(db *gorm.DB) ([]MigrationStatus, error) {
+	if err := db.AutoMigrate(&models.SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	done, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
 
-	migrations := r.GetMigrations()
-	// Reverse order for rollback
-	for i := len(migrations) - 1; i >= 0; i-- {
-		migration := migrations[i]
-		log.Printf("Rolling back migration %s: %s", migration.Version(), migration.Name())
+	statuses := make([]MigrationStatus, 0, len(r.migrations))
+	for _, migration := range r.GetMigrations() {
+		status := MigrationStatus{
+			Version: migration.Version(),
+			Name:    migration.Name(),
+		}
+		if record, ok := done[migration.Version()]; ok {
+			status.Applied = true
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+			delete(done, migration.Version())
+		}
+		statuses = append(statuses, status)
+	}
 
-		err := db.Transaction(func(tx *gorm.DB) error {
-			return migration.Down(tx)
+	for _, record := range done {
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Missing:   true,
 		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
 
-		if err != nil {
-			log.Printf("Warning: Failed to rollback migration %s: %v", migration.Version(), err)
-			// Continue with other rollbacks
-			continue
-		}
+	return statuses, nil
+}
 
-		log.Printf("Successfully rolled back migration: %s", migration.Version())
+// Pending returns the registered migrations that have not been applied, in version order.
+// Unlike Up it neither takes the migration lock nor creates schema_migrations.
+func (r *MigrationRegistry) Pending(db *gorm.DB) ([]Migration, error) {
+	done, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Println("Rollback completed")
+	var pending []Migration
+	for _, migration := range r.GetMigrations() {
+		if _, ok := done[migration.Version()]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+func (r *MigrationRegistry) find(version string) Migration {
+	for _, migration := range r.migrations {
+		if migration.Version() == version {
+			return migration
+		}
+	}
 	return nil
 }
 
-// containsAny checks if string contains any of the substrings
-func containsAny(s string, substrs []string) bool {
-	for _, substr := range substrs {
-		if len(s) >= len(substr) {
-			for i := 0; i <= len(s)-len(substr); i++ {
-				if s[i:i+len(substr)] == substr {
-					return true
-				}
+// withMigrationLock runs fn on a single pooled connection holding the migration advisory lock.
+// Session-level advisory locks belong to a connection, so everything must run on conn.
+func withMigrationLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		slog.Info("Acquiring migration lock")
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey).Error; err != nil {
+				slog.Warn("Failed to release migration lock", "error", err)
 			}
+		}()
+
+		if err := conn.AutoMigrate(&models.SchemaMigration{}); err != nil {
+			return fmt.Errorf("failed to create schema_migrations table: %w", err)
 		}
+
+		return fn(conn)
+	})
+}
+
+// appliedVersions returns the applied migrations keyed by version
+func appliedVersions(db *gorm.DB) (map[string]models.SchemaMigration, error) {
+	var records []models.SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	done := make(map[string]models.SchemaMigration, len(records))
+	for _, record := range records {
+		done[record.Version] = record
 	}
-	return false
+	return done, nil
 }
 
 // Global migration registry
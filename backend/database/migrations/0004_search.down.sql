@@ -0,0 +1,18 @@
+-- Remove full-text and fuzzy search support
+-- The pg_trgm and unaccent extensions are left installed
+DROP INDEX IF EXISTS idx_words_search_fts;
+DROP INDEX IF EXISTS idx_word_translations_search_fts;
+DROP INDEX IF EXISTS idx_word_translations_romanization_fts;
+DROP INDEX IF EXISTS idx_topics_search_fts;
+DROP INDEX IF EXISTS idx_conversations_search_fts;
+DROP INDEX IF EXISTS idx_conversation_lines_search_fts;
+DROP INDEX IF EXISTS idx_conversation_lines_romanization_fts;
+DROP INDEX IF EXISTS idx_words_base_word_trgm;
+DROP INDEX IF EXISTS idx_word_translations_translation_trgm;
+DROP INDEX IF EXISTS idx_word_translations_romanization_trgm;
+DROP INDEX IF EXISTS idx_topics_name_trgm;
+DROP INDEX IF EXISTS idx_conversations_title_trgm;
+
+DROP FUNCTION IF EXISTS learnspeak_strip_tones(text);
+DROP FUNCTION IF EXISTS learnspeak_search_text(text);
+DROP FUNCTION IF EXISTS learnspeak_unaccent(text);
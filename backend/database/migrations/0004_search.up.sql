@@ -0,0 +1,57 @@
+-- Full-text and fuzzy search support
+-- Requires the pg_trgm and unaccent extensions (bundled with PostgreSQL contrib)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE; pinning the dictionary makes it safe to use in indexes
+CREATE OR REPLACE FUNCTION learnspeak_unaccent(t text)
+RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent'::regdictionary, t);
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- Normalise text for searching: lowercase, strip accents and put spaces around
+-- CJK characters (kana, hanzi/kanji, hangul) so each becomes its own token
+CREATE OR REPLACE FUNCTION learnspeak_search_text(t text)
+RETURNS text AS $$
+    SELECT lower(learnspeak_unaccent(regexp_replace(
+        coalesce(t, ''),
+        '([\u3040-\u30ff\u3400-\u4dbf\u4e00-\u9fff\uf900-\ufaff\uac00-\ud7af])',
+        ' \1 ',
+        'g'
+    )));
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Normalise romanization so it matches without tones: removes tone marks
+-- (pinyin "nǐ hǎo") and tone numbers (jyutping "nei5 hou2")
+CREATE OR REPLACE FUNCTION learnspeak_strip_tones(t text)
+RETURNS text AS $$
+    SELECT lower(regexp_replace(learnspeak_unaccent(coalesce(t, '')), '([[:alpha:]])[1-6]', '\1', 'g'));
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Full-text indexes
+CREATE INDEX IF NOT EXISTS idx_words_search_fts ON words
+    USING GIN (to_tsvector('simple', learnspeak_search_text(base_word || ' ' || coalesce(notes, ''))));
+CREATE INDEX IF NOT EXISTS idx_word_translations_search_fts ON word_translations
+    USING GIN (to_tsvector('simple', learnspeak_search_text(translation)));
+CREATE INDEX IF NOT EXISTS idx_word_translations_romanization_fts ON word_translations
+    USING GIN (to_tsvector('simple', learnspeak_strip_tones(romanization)));
+CREATE INDEX IF NOT EXISTS idx_topics_search_fts ON topics
+    USING GIN (to_tsvector('simple', learnspeak_search_text(name || ' ' || coalesce(description, ''))));
+CREATE INDEX IF NOT EXISTS idx_conversations_search_fts ON conversations
+    USING GIN (to_tsvector('simple', learnspeak_search_text(title || ' ' || coalesce(description, '') || ' ' || coalesce(context, ''))));
+CREATE INDEX IF NOT EXISTS idx_conversation_lines_search_fts ON conversation_lines
+    USING GIN (to_tsvector('simple', learnspeak_search_text(english_text || ' ' || target_text)));
+CREATE INDEX IF NOT EXISTS idx_conversation_lines_romanization_fts ON conversation_lines
+    USING GIN (to_tsvector('simple', learnspeak_strip_tones(romanization)));
+
+-- Trigram indexes for typo-tolerant matching on short fields
+CREATE INDEX IF NOT EXISTS idx_words_base_word_trgm ON words
+    USING GIN (learnspeak_search_text(base_word) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_word_translations_translation_trgm ON word_translations
+    USING GIN (learnspeak_search_text(translation) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_word_translations_romanization_trgm ON word_translations
+    USING GIN (learnspeak_strip_tones(romanization) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_topics_name_trgm ON topics
+    USING GIN (learnspeak_search_text(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_conversations_title_trgm ON conversations
+    USING GIN (learnspeak_search_text(title) gin_trgm_ops);
@@ -0,0 +1,6 @@
+-- Chinese character set of each language, read by script conversion and derived translations
+ALTER TABLE languages ADD COLUMN IF NOT EXISTS script varchar(10);
+
+UPDATE languages SET script = 'hk' WHERE code = 'zh-HK' AND script IS NULL;
+UPDATE languages SET script = 't' WHERE code = 'zh-TW' AND script IS NULL;
+UPDATE languages SET script = 's' WHERE code = 'zh-CN' AND script IS NULL;
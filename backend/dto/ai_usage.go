@@ -0,0 +1,56 @@
+package dto
+
+// AIUserUsage is one user's paid AI calls on a day, by category (image, translation, tts)
+type AIUserUsage struct {
+	UserID   uint           `json:"userId"`
+	Username string         `json:"username"`
+	Name     string         `json:"name"`
+	Usage    map[string]int `json:"usage"`
+}
+
+// AIUsageReportResponse lists every user's AI usage on a UTC day against the daily quotas
+type AIUsageReportResponse struct {
+	Date   string         `json:"date"`   // YYYY-MM-DD, UTC
+	Limits map[string]int `json:"limits"` // daily quota per category; 0 means unlimited
+	Users  []AIUserUsage  `json:"users"`
+}
+
+// AIUsageBreakdown aggregates one provider operation in the ai_usage ledger
+type AIUsageBreakdown struct {
+	Provider      string  `json:"provider"`
+	Operation     string  `json:"operation"`
+	Calls         int64   `json:"calls"` // billed provider calls
+	CacheHits     int64   `json:"cacheHits"`
+	Characters    int64   `json:"characters"`
+	Images        int64   `json:"images"`
+	EstimatedCost float64 `json:"estimatedCost"` // USD
+}
+
+// AIUserCost is one user's estimated AI spend in a month
+type AIUserCost struct {
+	UserID        *uint   `json:"userId"` // null for CLI and background jobs
+	Username      string  `json:"username"`
+	Name          string  `json:"name"`
+	Calls         int64   `json:"calls"`
+	CacheHits     int64   `json:"cacheHits"`
+	EstimatedCost float64 `json:"estimatedCost"`
+}
+
+// AIMonthlyUsageReport is the organisation-wide AI spend for a calendar month (UTC)
+type AIMonthlyUsageReport struct {
+	Month         string             `json:"month"` // YYYY-MM
+	EstimatedCost float64            `json:"estimatedCost"`
+	Budget        float64            `json:"budget"`     // organisation cap; 0 means none
+	UserBudget    float64            `json:"userBudget"` // per-user cap; 0 means none
+	Breakdown     []AIUsageBreakdown `json:"breakdown"`
+	Users         []AIUserCost       `json:"users"`
+}
+
+// AIUserMonthlyUsageReport is one user's AI spend for a calendar month (UTC)
+type AIUserMonthlyUsageReport struct {
+	Month         string             `json:"month"`
+	UserID        uint               `json:"userId"`
+	EstimatedCost float64            `json:"estimatedCost"`
+	Budget        float64            `json:"budget"` // 0 means none
+	Breakdown     []AIUsageBreakdown `json:"breakdown"`
+}
@@ -0,0 +1,97 @@
+package dto
+
+import "time"
+
+// AssetResponse is an image or recording in the asset library with its metadata and variants
+type AssetResponse struct {
+	ID         uint                   `json:"id"`
+	URL        string                 `json:"url"`
+	Kind       string                 `json:"kind"` // image or audio
+	Source     string                 `json:"source"`
+	Format     string                 `json:"format"`
+	Width      int                    `json:"width"`
+	Height     int                    `json:"height"`
+	Bytes      int64                  `json:"bytes"`
+	Blurhash   string                 `json:"blurhash,omitempty"`
+	DurationMs int64                  `json:"durationMs,omitempty"`
+	SampleRate int                    `json:"sampleRate,omitempty"`
+	Channels   int                    `json:"channels,omitempty"`
+	Variants   []AssetVariantResponse `json:"variants"`
+
+	// Library
+	OriginalName string    `json:"originalName,omitempty"`
+	Word         string    `json:"word,omitempty"` // generated images: the word, translation and prompt they were made for
+	Translation  string    `json:"translation,omitempty"`
+	Prompt       string    `json:"prompt,omitempty"`
+	UploadedBy   *uint     `json:"uploadedBy,omitempty"`
+	UploaderName string    `json:"uploaderName,omitempty"`
+	Tags         []string  `json:"tags"`
+	RefCount     *int      `json:"refCount,omitempty"` // content rows using the file; set in listings and on GET /assets/:id
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// AssetVariantResponse is a resized or re-encoded copy of an asset. Variants are built in
+// the background, so a freshly uploaded image may not have them yet.
+type AssetVariantResponse struct {
+	Name   string `json:"name"`   // thumb, medium or full
+	Format string `json:"format"` // jpeg, png, webp or avif
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// AssetListResponse represents a paginated list of assets
+type AssetListResponse struct {
+	Assets     []AssetResponse `json:"assets"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	TotalPages int             `json:"totalPages"`
+}
+
+// AssetFilterParams represents query parameters for searching the asset library
+type AssetFilterParams struct {
+	Source     string `query:"source"` // upload, profile, generated or audio
+	Kind       string `query:"kind"`   // image or audio
+	Query      string `query:"q"`      // original file name, URL or tag
+	Tag        string `query:"tag"`
+	UploadedBy uint   `query:"uploadedBy"`
+	Unused     bool   `query:"unused"` // only assets no content refers to
+	Page       int    `query:"page"`
+	PageSize   int    `query:"pageSize"`
+}
+
+// UpdateAssetTagsRequest replaces an asset's tags
+type UpdateAssetTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// AssetReferenceResponse is a content row that uses an asset
+type AssetReferenceResponse struct {
+	Type  string `json:"type"`  // word, word_translation, word_example, conversation, conversation_line, quiz_question, user or revision
+	Field string `json:"field"` // e.g. imageUrl
+	ID    uint   `json:"id"`
+}
+
+// AssetReferencesResponse lists where an asset is used
+type AssetReferencesResponse struct {
+	AssetID    uint                     `json:"assetId"`
+	URL        string                   `json:"url"`
+	References []AssetReferenceResponse `json:"references"`
+}
+
+// AssetGCResponse reports a garbage collection run over the asset library directories
+type AssetGCResponse struct {
+	DryRun bool                 `json:"dryRun"`
+	Dirs   []AssetGCDirResponse `json:"dirs"`
+}
+
+// AssetGCDirResponse is the outcome for one upload directory
+type AssetGCDirResponse struct {
+	Name         string `json:"name"`
+	Removed      int    `json:"removed"`
+	RemovedBytes int64  `json:"removedBytes"`
+	InUse        int    `json:"inUse"`
+	Kept         int    `json:"kept"` // unused but within the grace period
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEventResponse is one entry in the audit log
+type AuditEventResponse struct {
+	ID            uint            `json:"id"`
+	Action        string          `json:"action"`
+	ActorID       *uint           `json:"actorId,omitempty"` // absent for anonymous requests and the CLI
+	ActorUsername string          `json:"actorUsername,omitempty"`
+	TargetType    string          `json:"targetType,omitempty"` // user, journey or invitation
+	TargetID      *uint           `json:"targetId,omitempty"`
+	Details       json.RawMessage `json:"details,omitempty"`
+	IP            string          `json:"ip,omitempty"`
+	UserAgent     string          `json:"userAgent,omitempty"`
+	RequestID     string          `json:"requestId,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// AuditEventListResponse represents a paginated list of audit events, newest first
+type AuditEventListResponse struct {
+	Events     []AuditEventResponse `json:"events"`
+	Total      int64                `json:"total"`
+	Page       int                  `json:"page"`
+	PageSize   int                  `json:"pageSize"`
+	TotalPages int                  `json:"totalPages"`
+}
+
+// AuditFilterParams represents query parameters for searching and exporting the audit log
+type AuditFilterParams struct {
+	Action     string `query:"action"` // one or more actions, comma-separated
+	ActorID    uint   `query:"actorId"`
+	TargetType string `query:"targetType"`
+	TargetID   uint   `query:"targetId"`
+	IP         string `query:"ip"`
+	From       string `query:"from"` // YYYY-MM-DD or RFC 3339, inclusive
+	To         string `query:"to"`   // YYYY-MM-DD (inclusive) or RFC 3339 (exclusive)
+	Page       int    `query:"page"`
+	PageSize   int    `query:"pageSize"`
+}
@@ -0,0 +1,89 @@
+package dto
+
+// CharacterEtymology explains how a character is formed
+type CharacterEtymology struct {
+	Type     string `json:"type"` // ideographic, pictographic or pictophonetic
+	Hint     string `json:"hint,omitempty"`
+	Phonetic string `json:"phonetic,omitempty"`
+	Semantic string `json:"semantic,omitempty"`
+}
+
+// CharacterInfo represents a Chinese character without its stroke data
+type CharacterInfo struct {
+	Character     string              `json:"character"`
+	Definition    string              `json:"definition,omitempty"`
+	Pinyin        []string            `json:"pinyin"`
+	Radical       string              `json:"radical,omitempty"`
+	Decomposition string              `json:"decomposition,omitempty"`
+	Etymology     *CharacterEtymology `json:"etymology,omitempty"`
+	StrokeCount   int                 `json:"strokeCount"` // 0 without stroke data
+	Traditional   []string            `json:"traditional"`
+	Simplified    []string            `json:"simplified"`
+}
+
+// CharacterResponse represents a Chinese character with its stroke order. Strokes are SVG
+// paths on a 1024x1024 grid with the y axis pointing up, and medians are the points along
+// each stroke.
+type CharacterResponse struct {
+	CharacterInfo
+	Strokes []string   `json:"strokes"`
+	Medians [][][2]int `json:"medians"`
+}
+
+// CharacterListResponse represents the characters of a text
+type CharacterListResponse struct {
+	Characters []CharacterInfo `json:"characters"`
+	Missing    []string        `json:"missing"` // characters without any data
+}
+
+// HandwritingPracticeResponse represents the characters to practise writing in a topic
+type HandwritingPracticeResponse struct {
+	TopicID         uint                `json:"topicId"`
+	TopicName       string              `json:"topicName"`
+	Characters      []PracticeCharacter `json:"characters"`
+	Attempts        int                 `json:"attempts"`
+	BestScore       *float64            `json:"bestScore,omitempty"`
+	LastPracticedAt string              `json:"lastPracticedAt,omitempty"`
+}
+
+// PracticeCharacter represents a character of a topic with the words it appears in
+type PracticeCharacter struct {
+	CharacterInfo
+	Words []PracticeWordInfo `json:"words"`
+}
+
+// PracticeWordInfo represents a word containing a practice character
+type PracticeWordInfo struct {
+	WordID      uint   `json:"wordId"`
+	BaseWord    string `json:"baseWord"`
+	Translation string `json:"translation"`
+}
+
+// HandwritingAttemptRequest represents a handwriting practice attempt
+type HandwritingAttemptRequest struct {
+	JourneyID        *uint                   `json:"journeyId"`
+	TimeSpentSeconds int                     `json:"timeSpentSeconds" validate:"min=0"`
+	Characters       []HandwritingCharResult `json:"characters" validate:"required,min=1,max=100,dive"`
+}
+
+// HandwritingCharResult represents how one character was written
+type HandwritingCharResult struct {
+	Character string `json:"character" validate:"required"`
+	Mistakes  int    `json:"mistakes" validate:"min=0"` // strokes drawn wrong before getting them right
+}
+
+// HandwritingAttemptResponse represents the scored attempt
+type HandwritingAttemptResponse struct {
+	ProgressID uint                   `json:"progressId"`
+	Score      float64                `json:"score"` // percentage of strokes written without a mistake
+	Passed     bool                   `json:"passed"`
+	Characters []HandwritingCharScore `json:"characters"`
+}
+
+// HandwritingCharScore represents the score of one character in an attempt
+type HandwritingCharScore struct {
+	Character   string  `json:"character"`
+	StrokeCount int     `json:"strokeCount"`
+	Mistakes    int     `json:"mistakes"`
+	Accuracy    float64 `json:"accuracy"` // percentage
+}
@@ -12,6 +12,7 @@ type CreateConversationRequest struct {
 	ScenarioAudioURL string                          `json:"scenarioAudioUrl" validate:"omitempty,max=500"`
 	ScenarioImageURL string                          `json:"scenarioImageUrl" validate:"omitempty,max=500"`
 	TopicID          *uint                           `json:"topicId" validate:"omitempty"`
+	IsPublic         bool                            `json:"isPublic"`
 	Lines            []CreateConversationLineRequest `json:"lines" validate:"required,min=1,dive"`
 }
 
@@ -24,6 +25,7 @@ type UpdateConversationRequest struct {
 	DifficultyLevel  string `json:"difficultyLevel" validate:"omitempty,oneof=beginner intermediate advanced"`
 	ScenarioAudioURL string `json:"scenarioAudioUrl" validate:"omitempty,max=500"`
 	ScenarioImageURL string `json:"scenarioImageUrl" validate:"omitempty,max=500"`
+	IsPublic         *bool  `json:"isPublic"`
 }
 
 // CreateConversationLineRequest represents a line in a conversation
@@ -68,6 +70,7 @@ type ConversationResponse struct {
 	ScenarioAudioURL string                     `json:"scenarioAudioUrl"`
 	ScenarioImageURL string                     `json:"scenarioImageUrl"`
 	Lines            []ConversationLineResponse `json:"lines"`
+	IsPublic         bool                       `json:"isPublic"`
 	CreatedBy        uint                       `json:"createdBy"`
 	CreatedAt        time.Time                  `json:"createdAt"`
 	UpdatedAt        time.Time                  `json:"updatedAt"`
@@ -0,0 +1,137 @@
+package dto
+
+// CoursePackageFormatVersion is the version of the course package layout written by the exporter
+const CoursePackageFormatVersion = 1
+
+// CoursePackage is the content of package.json inside an exported course zip.
+// Records keep their source IDs so references between them can be remapped on import.
+// Media URLs are kept as-is; files bundled in the zip are listed in Media.
+type CoursePackage struct {
+	FormatVersion int                   `json:"formatVersion"`
+	ExportedAt    string                `json:"exportedAt"`
+	Journey       PackageJourney        `json:"journey"`
+	Topics        []PackageTopic        `json:"topics"`
+	Words         []PackageWord         `json:"words"`
+	Conversations []PackageConversation `json:"conversations"`
+	Media         []PackageMedia        `json:"media"`
+}
+
+// PackageJourney represents the exported journey
+type PackageJourney struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	LanguageCode string `json:"languageCode"`
+	CreatedBy    string `json:"createdBy"` // username of the original creator
+	TopicIDs     []uint `json:"topicIds"`  // source topic IDs in journey order
+}
+
+// PackageTopic represents an exported topic with its quizzes
+type PackageTopic struct {
+	SourceID        uint          `json:"sourceId"`
+	Name            string        `json:"name"`
+	Description     string        `json:"description"`
+	Level           string        `json:"level"`
+	LanguageCode    string        `json:"languageCode"`
+	IsPublic        bool          `json:"isPublic"`
+	CreatedBy       string        `json:"createdBy"`
+	WordIDs         []uint        `json:"wordIds"`         // source word IDs in topic order
+	ConversationIDs []uint        `json:"conversationIds"` // source conversation IDs in topic order
+	Quizzes         []PackageQuiz `json:"quizzes"`
+}
+
+// PackageWord represents an exported word with its translations
+type PackageWord struct {
+	SourceID     uint                  `json:"sourceId"`
+	BaseWord     string                `json:"baseWord"`
+	ImageURL     string                `json:"imageUrl,omitempty"`
+	Notes        string                `json:"notes,omitempty"`
+	PartOfSpeech string                `json:"partOfSpeech,omitempty"`
+	CreatedBy    string                `json:"createdBy"`
+	Translations []PackageTranslation  `json:"translations"`
+	Relations    []PackageWordRelation `json:"relations,omitempty"` // to other words in the package
+}
+
+// PackageTranslation represents an exported word translation
+type PackageTranslation struct {
+	LanguageCode string           `json:"languageCode"`
+	Translation  string           `json:"translation"`
+	Romanization string           `json:"romanization,omitempty"`
+	AudioURL     string           `json:"audioUrl,omitempty"`
+	MeasureWord  string           `json:"measureWord,omitempty"`
+	Gender       string           `json:"gender,omitempty"`
+	Examples     []PackageExample `json:"examples,omitempty"`
+}
+
+// PackageExample represents an exported example sentence
+type PackageExample struct {
+	Sentence     string `json:"sentence"`
+	Romanization string `json:"romanization,omitempty"`
+	Meaning      string `json:"meaning,omitempty"`
+	AudioURL     string `json:"audioUrl,omitempty"`
+}
+
+// PackageWordRelation represents an exported link between two words
+type PackageWordRelation struct {
+	WordID       uint   `json:"wordId"` // source word ID
+	RelationType string `json:"relationType"`
+}
+
+// PackageQuiz represents an exported quiz question
+type PackageQuiz struct {
+	WordID        *uint   `json:"wordId,omitempty"` // source word ID
+	QuestionType  string  `json:"questionType"`
+	QuestionText  string  `json:"questionText"`
+	AudioURL      *string `json:"audioUrl,omitempty"`
+	ImageURL      *string `json:"imageUrl,omitempty"`
+	CorrectAnswer string  `json:"correctAnswer"`
+	OptionA       string  `json:"optionA"`
+	OptionB       string  `json:"optionB"`
+	OptionC       string  `json:"optionC"`
+	OptionD       string  `json:"optionD"`
+}
+
+// PackageConversation represents an exported conversation with its lines
+type PackageConversation struct {
+	SourceID         uint                      `json:"sourceId"`
+	Title            string                    `json:"title"`
+	Description      string                    `json:"description,omitempty"`
+	Context          string                    `json:"context,omitempty"`
+	LanguageCode     string                    `json:"languageCode"`
+	DifficultyLevel  string                    `json:"difficultyLevel"`
+	ScenarioAudioURL string                    `json:"scenarioAudioUrl,omitempty"`
+	ScenarioImageURL string                    `json:"scenarioImageUrl,omitempty"`
+	CreatedBy        string                    `json:"createdBy"`
+	Lines            []PackageConversationLine `json:"lines"`
+}
+
+// PackageConversationLine represents an exported conversation line
+type PackageConversationLine struct {
+	SequenceOrder int    `json:"sequenceOrder"`
+	SpeakerRole   string `json:"speakerRole"`
+	EnglishText   string `json:"englishText"`
+	TargetText    string `json:"targetText"`
+	Romanization  string `json:"romanization,omitempty"`
+	AudioURL      string `json:"audioUrl,omitempty"`
+	ImageURL      string `json:"imageUrl,omitempty"`
+	WordID        *uint  `json:"wordId,omitempty"` // source word ID
+	IsLearnerLine bool   `json:"isLearnerLine"`
+}
+
+// PackageMedia maps a media URL used by the content to a file bundled in the zip
+type PackageMedia struct {
+	URL    string `json:"url"`    // URL as referenced by the content, e.g. /uploads/image/abc.png
+	Path   string `json:"path"`   // path inside the zip, e.g. media/<sha256>.png
+	SHA256 string `json:"sha256"` // content hash of the file
+}
+
+// CoursePackageImportResult summarises a course package import
+type CoursePackageImportResult struct {
+	JourneyID            uint     `json:"journeyId"`
+	TopicsCreated        int      `json:"topicsCreated"`
+	WordsCreated         int      `json:"wordsCreated"`
+	WordsReused          int      `json:"wordsReused"`
+	QuizzesCreated       int      `json:"quizzesCreated"`
+	ConversationsCreated int      `json:"conversationsCreated"`
+	MediaImported        int      `json:"mediaImported"`
+	Warnings             []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// Health statuses. A component is "unconfigured" when an optional provider has no credentials.
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusDegraded     = "degraded"
+	HealthStatusFail         = "fail"
+	HealthStatusUnconfigured = "unconfigured"
+)
+
+// HealthComponent is the result of checking one dependency
+type HealthComponent struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"` // a failing required component makes the instance not ready
+	LatencyMs float64 `json:"latencyMs"`
+	Message   string  `json:"message,omitempty"`
+}
+
+// HealthResponse is returned by /livez and /readyz
+type HealthResponse struct {
+	Status     string            `json:"status"` // ok, degraded (optional component unavailable) or fail
+	Components []HealthComponent `json:"components,omitempty"`
+	CheckedAt  time.Time         `json:"checkedAt"`
+}
@@ -6,6 +6,7 @@ type CreateJourneyRequest struct {
 	Description  string `json:"description" validate:"omitempty,max=1000"`
 	LanguageCode string `json:"languageCode" validate:"required"`
 	TopicIDs     []uint `json:"topicIds" validate:"omitempty"`
+	IsPublic     bool   `json:"isPublic"`
 }
 
 // UpdateJourneyRequest represents the request to update a journey
@@ -14,6 +15,7 @@ type UpdateJourneyRequest struct {
 	Description  *string `json:"description" validate:"omitempty,max=1000"`
 	LanguageCode *string `json:"languageCode" validate:"omitempty"`
 	TopicIDs     *[]uint `json:"topicIds" validate:"omitempty"`
+	IsPublic     *bool   `json:"isPublic"`
 }
 
 // ReorderJourneyTopicsRequest represents the request to reorder topics in a journey
@@ -39,6 +41,8 @@ type JourneyResponse struct {
 	ID              uint               `json:"id"`
 	Name            string             `json:"name"`
 	Description     string             `json:"description"`
+	IsPublic        bool               `json:"isPublic"`
+	Status          string             `json:"status"` // draft, in_review, published or archived
 	Language        *LanguageInfo      `json:"language,omitempty"`
 	CreatedBy       *CreatorInfo       `json:"createdBy,omitempty"`
 	TopicCount      int                `json:"topicCount"`
@@ -64,6 +68,7 @@ type JourneyFilterParams struct {
 	Search        string `query:"search"`
 	LanguageCode  string `query:"languageCode"`
 	CreatedBy     uint   `query:"createdBy"`
+	Status        string `query:"status"`
 	Page          int    `query:"page"`
 	PageSize      int    `query:"pageSize"`
 	IncludeTopics bool   `query:"includeTopics"`
@@ -0,0 +1,56 @@
+package dto
+
+// LanguageVoice represents an alternate TTS voice of a language
+type LanguageVoice struct {
+	Voice  string `json:"voice" validate:"required,max=100"` // Azure TTS voice, e.g. zh-HK-WanLungNeural
+	Name   string `json:"name" validate:"omitempty,max=100"` // display name, e.g. WanLung (Male)
+	Gender string `json:"gender" validate:"omitempty,oneof=Female Male Neutral"`
+}
+
+// CreateLanguageRequest represents the request to add a language
+type CreateLanguageRequest struct {
+	Code               string          `json:"code" validate:"required,max=10"` // BCP-47, e.g. th or pt-BR
+	Name               string          `json:"name" validate:"required,max=100"`
+	NativeName         string          `json:"nativeName" validate:"omitempty,max=100"`
+	Direction          string          `json:"direction" validate:"omitempty,oneof=ltr rtl"` // default ltr
+	IsActive           *bool           `json:"isActive"`                                     // default true
+	DefaultVoice       string          `json:"defaultVoice" validate:"omitempty,max=100"`
+	Voices             []LanguageVoice `json:"voices" validate:"omitempty,max=20,dive"`
+	RomanizationScheme string          `json:"romanizationScheme" validate:"omitempty,max=50"`
+	TranslatorCode     string          `json:"translatorCode" validate:"omitempty,max=20"`
+	FontFamily         string          `json:"fontFamily" validate:"omitempty,max=255"`
+	Script             string          `json:"script" validate:"omitempty,oneof=s t hk"` // Chinese languages only: Simplified, Traditional or Hong Kong
+}
+
+// UpdateLanguageRequest represents the request to update a language; omitted fields are kept
+type UpdateLanguageRequest struct {
+	Code               *string         `json:"code" validate:"omitempty,max=10"`
+	Name               *string         `json:"name" validate:"omitempty,min=1,max=100"`
+	NativeName         *string         `json:"nativeName" validate:"omitempty,max=100"`
+	Direction          *string         `json:"direction" validate:"omitempty,oneof=ltr rtl"`
+	IsActive           *bool           `json:"isActive"`
+	DefaultVoice       *string         `json:"defaultVoice" validate:"omitempty,max=100"`
+	Voices             []LanguageVoice `json:"voices" validate:"omitempty,max=20,dive"` // replaces all voices when present; [] removes them
+	RomanizationScheme *string         `json:"romanizationScheme" validate:"omitempty,max=50"`
+	TranslatorCode     *string         `json:"translatorCode" validate:"omitempty,max=20"`
+	FontFamily         *string         `json:"fontFamily" validate:"omitempty,max=255"`
+	Script             *string         `json:"script" validate:"omitempty,oneof=s t hk"` // "" makes the language non-Chinese
+}
+
+// LanguageResponse represents a language with its settings
+type LanguageResponse struct {
+	ID                 uint            `json:"id"`
+	Code               string          `json:"code"`
+	Name               string          `json:"name"`
+	NativeName         string          `json:"nativeName"`
+	Direction          string          `json:"direction"`
+	IsActive           bool            `json:"isActive"`
+	DefaultVoice       string          `json:"defaultVoice"`
+	Voices             []LanguageVoice `json:"voices"`
+	RomanizationScheme string          `json:"romanizationScheme"`
+	TranslatorCode     string          `json:"translatorCode"` // Azure Translator code, the language code unless set
+	FontFamily         string          `json:"fontFamily"`
+	Script             string          `json:"script"`
+	CreatedAt          string          `json:"createdAt"`
+	UpdatedAt          string          `json:"updatedAt"`
+}
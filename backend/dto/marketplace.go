@@ -0,0 +1,113 @@
+package dto
+
+// MarketplaceFilterParams represents query parameters for browsing the marketplace
+type MarketplaceFilterParams struct {
+	ContentType  string `query:"contentType"` // topic, journey or conversation
+	Search       string `query:"search"`
+	LanguageCode string `query:"languageCode"`
+	Level        string `query:"level"` // topics and conversations only
+	CreatedBy    uint   `query:"createdBy"`
+	Mine         bool   `query:"mine"` // own content, public or not
+	Sort         string `query:"sort"` // newest (default), rating or clones
+	Page         int    `query:"page"`
+	PageSize     int    `query:"pageSize"`
+}
+
+// MarketplaceItemResponse represents a shared topic, journey or conversation
+type MarketplaceItemResponse struct {
+	ContentType   string        `json:"contentType"`
+	ID            uint          `json:"id"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	Level         string        `json:"level,omitempty"`
+	Language      *LanguageInfo `json:"language,omitempty"`
+	Author        *CreatorInfo  `json:"author,omitempty"`
+	ItemCount     int           `json:"itemCount"` // words in a topic, topics in a journey, lines in a conversation
+	AverageRating float64       `json:"averageRating"`
+	RatingCount   int64         `json:"ratingCount"`
+	CloneCount    int64         `json:"cloneCount"`
+	IsListed      bool          `json:"isListed"`
+	MyRating      *int          `json:"myRating,omitempty"`
+	CreatedAt     string        `json:"createdAt"`
+	UpdatedAt     string        `json:"updatedAt"`
+}
+
+// MarketplaceListResponse represents paginated marketplace content
+type MarketplaceListResponse struct {
+	Items      []MarketplaceItemResponse `json:"items"`
+	Total      int64                     `json:"total"`
+	Page       int                       `json:"page"`
+	PageSize   int                       `json:"pageSize"`
+	TotalPages int                       `json:"totalPages"`
+}
+
+// RateContentRequest represents a rating of someone else's content
+type RateContentRequest struct {
+	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Comment string `json:"comment" validate:"omitempty,max=1000"`
+}
+
+// ContentRatingResponse represents a rating
+type ContentRatingResponse struct {
+	ID        uint         `json:"id"`
+	User      *CreatorInfo `json:"user,omitempty"`
+	Rating    int          `json:"rating"`
+	Comment   string       `json:"comment,omitempty"`
+	CreatedAt string       `json:"createdAt"`
+	UpdatedAt string       `json:"updatedAt"`
+}
+
+// ContentRatingListResponse represents paginated ratings of content
+type ContentRatingListResponse struct {
+	Ratings       []ContentRatingResponse `json:"ratings"`
+	AverageRating float64                 `json:"averageRating"`
+	Total         int64                   `json:"total"`
+	Page          int                     `json:"page"`
+	PageSize      int                     `json:"pageSize"`
+	TotalPages    int                     `json:"totalPages"`
+}
+
+// CloneAttribution identifies the content a clone was copied from
+type CloneAttribution struct {
+	SourceID   uint         `json:"sourceId"`
+	SourceName string       `json:"sourceName"` // when cloned
+	Author     *CreatorInfo `json:"author,omitempty"`
+	ClonedAt   string       `json:"clonedAt"`
+}
+
+// CloneResponse represents the result of cloning content into the user's library
+type CloneResponse struct {
+	ContentType          string           `json:"contentType"`
+	ID                   uint             `json:"id"` // the new copy
+	Name                 string           `json:"name"`
+	ClonedFrom           CloneAttribution `json:"clonedFrom"`
+	TopicsCreated        int              `json:"topicsCreated"`
+	WordsCreated         int              `json:"wordsCreated"`
+	QuizzesCreated       int              `json:"quizzesCreated"`
+	ConversationsCreated int              `json:"conversationsCreated"`
+}
+
+// ClonedContentResponse represents content in the user's library that was cloned
+type ClonedContentResponse struct {
+	ContentType string           `json:"contentType"`
+	ID          uint             `json:"id"`
+	Name        string           `json:"name"` // empty once the copy is deleted
+	ClonedFrom  CloneAttribution `json:"clonedFrom"`
+}
+
+// ClonedContentListResponse represents the user's clones, newest first
+type ClonedContentListResponse struct {
+	Clones     []ClonedContentResponse `json:"clones"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	PageSize   int                     `json:"pageSize"`
+	TotalPages int                     `json:"totalPages"`
+}
+
+// ClonedContentFilterParams represents query parameters for listing the user's clones
+type ClonedContentFilterParams struct {
+	ContentType string `query:"contentType"`
+	CloneID     uint   `query:"cloneId"` // attribution of one copy
+	Page        int    `query:"page"`
+	PageSize    int    `query:"pageSize"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+// StartPlacementTestRequest represents the request to start a placement test
+type StartPlacementTestRequest struct {
+	LanguageCode string `json:"languageCode" validate:"required"`
+	JourneyID    *uint  `json:"journeyId"` // Optional: sample questions from this journey and recommend a topic in it
+}
+
+// PlacementAnswerRequest represents an answer to the current placement question
+type PlacementAnswerRequest struct {
+	QuestionID uint   `json:"questionId" validate:"required"`
+	Answer     string `json:"answer" validate:"required,oneof=a b c d"`
+}
+
+// PlacementLevelResult summarises the answers given at one level tier
+type PlacementLevelResult struct {
+	Level    string  `json:"level"`
+	Answered int     `json:"answered"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"` // percentage
+}
+
+// PlacementTopicInfo represents the recommended starting topic
+type PlacementTopicInfo struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+// PlacementTestResponse represents the state of a placement test
+type PlacementTestResponse struct {
+	ID                uint                     `json:"id"`
+	Status            string                   `json:"status"`
+	Language          *LanguageInfo            `json:"language,omitempty"`
+	JourneyID         *uint                    `json:"journeyId,omitempty"`
+	CurrentLevel      string                   `json:"currentLevel"`
+	QuestionsAnswered int                      `json:"questionsAnswered"`
+	CorrectAnswers    int                      `json:"correctAnswers"`
+	MaxQuestions      int                      `json:"maxQuestions"`
+	LastAnswerCorrect *bool                    `json:"lastAnswerCorrect,omitempty"`
+	NextQuestion      *QuizQuestionForPractice `json:"nextQuestion,omitempty"`
+	EstimatedLevel    string                   `json:"estimatedLevel,omitempty"` // highest mastered level, empty if none
+	LevelResults      []PlacementLevelResult   `json:"levelResults"`
+	RecommendedTopic  *PlacementTopicInfo      `json:"recommendedTopic,omitempty"`
+	CompletedTopicIDs []uint                   `json:"completedTopicIds,omitempty"` // topics pre-marked as completed when applied
+	CreatedAt         string                   `json:"createdAt"`
+	CompletedAt       *string                  `json:"completedAt,omitempty"`
+}
@@ -16,10 +16,18 @@ type SuccessResponse struct {
 
 // ImageItem represents a single uploaded image
 type ImageItem struct {
-	Filename string `json:"filename"`
-	URL      string `json:"url"`
-	Size     int64  `json:"size"`
-	ModTime  int64  `json:"modTime"`
+	Filename     string `json:"filename"`
+	URL          string `json:"url"`
+	Size         int64  `json:"size"`
+	ModTime      int64  `json:"modTime"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	Format       string `json:"format,omitempty"`
+	Blurhash     string `json:"blurhash,omitempty"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+	AssetID      uint   `json:"assetId,omitempty"`
+	Word         string `json:"word,omitempty"` // generated images: what they were generated for
+	Translation  string `json:"translation,omitempty"`
 }
 
 // ImageListResponse represents a paginated list of images
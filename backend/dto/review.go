@@ -0,0 +1,71 @@
+package dto
+
+// SubmitReviewRequest asks for a draft topic or journey to be reviewed for publishing
+type SubmitReviewRequest struct {
+	ContentType string `json:"contentType" validate:"required,oneof=topic journey"`
+	ContentID   uint   `json:"contentId" validate:"required"`
+	ReviewerID  *uint  `json:"reviewerId"` // optional; any other teacher can pick the review up
+	Note        string `json:"note" validate:"omitempty,max=1000"`
+}
+
+// AssignReviewerRequest represents the request to assign a review to a teacher
+type AssignReviewerRequest struct {
+	ReviewerID uint `json:"reviewerId" validate:"required"`
+}
+
+// ReviewDecisionRequest represents the note left when approving or requesting changes
+type ReviewDecisionRequest struct {
+	Note string `json:"note" validate:"omitempty,max=1000"`
+}
+
+// ReviewCommentRequest represents the request to comment on a review
+type ReviewCommentRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=2000"`
+}
+
+// ReviewCommentResponse represents a comment on a review
+type ReviewCommentResponse struct {
+	ID        uint         `json:"id"`
+	User      *CreatorInfo `json:"user,omitempty"`
+	Body      string       `json:"body"`
+	CreatedAt string       `json:"createdAt"`
+}
+
+// ReviewResponse represents a review of a topic or journey
+type ReviewResponse struct {
+	ID           uint                    `json:"id"`
+	ContentType  string                  `json:"contentType"` // topic or journey
+	ContentID    uint                    `json:"contentId"`
+	ContentName  string                  `json:"contentName"`
+	DraftOf      *uint                   `json:"draftOf,omitempty"` // set while a topic draft copy is under review
+	Status       string                  `json:"status"`            // pending, approved, changes_requested or withdrawn
+	Note         string                  `json:"note,omitempty"`
+	SubmittedBy  *CreatorInfo            `json:"submittedBy,omitempty"`
+	Reviewer     *CreatorInfo            `json:"reviewer,omitempty"`
+	DecisionNote string                  `json:"decisionNote,omitempty"`
+	DecidedBy    *CreatorInfo            `json:"decidedBy,omitempty"`
+	DecidedAt    *string                 `json:"decidedAt,omitempty"`
+	Comments     []ReviewCommentResponse `json:"comments,omitempty"`
+	CreatedAt    string                  `json:"createdAt"`
+	UpdatedAt    string                  `json:"updatedAt"`
+}
+
+// ReviewListResponse represents paginated review list
+type ReviewListResponse struct {
+	Reviews    []ReviewResponse `json:"reviews"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"pageSize"`
+	TotalPages int              `json:"totalPages"`
+}
+
+// ReviewFilterParams represents query parameters for filtering reviews
+type ReviewFilterParams struct {
+	ContentType string `query:"contentType"`
+	ContentID   uint   `query:"contentId"`
+	Status      string `query:"status"`
+	ReviewerID  uint   `query:"reviewerId"`
+	SubmittedBy uint   `query:"submittedBy"`
+	Page        int    `query:"page"`
+	PageSize    int    `query:"pageSize"`
+}
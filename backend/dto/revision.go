@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RevisionResponse is one recorded change to a word, topic, conversation or quiz question
+type RevisionResponse struct {
+	ID           uint             `json:"id"`
+	EntityType   string           `json:"entityType"` // word, topic, conversation or quiz_question
+	EntityID     uint             `json:"entityId"`
+	Version      int              `json:"version"`
+	Action       string           `json:"action"` // baseline, create, update, delete or restore
+	Changes      []RevisionChange `json:"changes"`
+	Snapshot     json.RawMessage  `json:"snapshot,omitempty"` // full state; only on GET /revisions/:id and restores
+	RestoredFrom *uint            `json:"restoredFrom,omitempty"`
+	UserID       *uint            `json:"userId,omitempty"`
+	UserName     string           `json:"userName,omitempty"`
+	CreatedAt    time.Time        `json:"createdAt"`
+}
+
+// RevisionChange is one field that differs from the previous state. Fields of translations
+// and conversation lines are keyed by their ID, e.g. "translations[12].audioUrl"; Old is
+// null for added values and New is null for removed ones.
+type RevisionChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// RevisionListResponse represents a paginated list of revisions, newest first
+type RevisionListResponse struct {
+	Revisions  []RevisionResponse `json:"revisions"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"pageSize"`
+	TotalPages int                `json:"totalPages"`
+}
+
+// RevisionFilterParams represents query parameters for listing revisions
+type RevisionFilterParams struct {
+	EntityType string `query:"entityType"` // word, topic, conversation or quiz_question
+	EntityID   uint   `query:"entityId"`
+	Action     string `query:"action"` // e.g. delete, to find deleted content to restore
+	UserID     uint   `query:"userId"`
+	Page       int    `query:"page"`
+	PageSize   int    `query:"pageSize"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+// ConvertScriptRequest represents text to convert between Chinese scripts
+type ConvertScriptRequest struct {
+	Text       string `json:"text" validate:"required,max=10000"`
+	Conversion string `json:"conversion" validate:"required,oneof=s2t t2s s2hk hk2s t2hk hk2t"`
+	Pinyin     bool   `json:"pinyin"` // also spell the converted text in pinyin
+}
+
+// ConvertScriptResponse represents converted text
+type ConvertScriptResponse struct {
+	Text       string `json:"text"`
+	Conversion string `json:"conversion"`
+	Converted  string `json:"converted"`
+	Pinyin     string `json:"pinyin,omitempty"`
+}
+
+// DeriveTranslationRequest derives a word's translation in one Chinese language from its
+// translation in another. Both languages need a script setting.
+type DeriveTranslationRequest struct {
+	FromLanguageCode string `json:"fromLanguageCode" validate:"omitempty,max=10"` // default zh-HK
+	ToLanguageCode   string `json:"toLanguageCode" validate:"omitempty,max=10"`   // default zh-CN
+	Overwrite        bool   `json:"overwrite"`                                    // replace an existing translation
+}
@@ -0,0 +1,49 @@
+package dto
+
+// SearchParams represents query parameters for the unified search
+type SearchParams struct {
+	Query      string `query:"q"`
+	Type       string `query:"type"`  // word, topic or conversation
+	Level      string `query:"level"` // beginner, intermediate or advanced
+	LanguageID uint   `query:"languageId"`
+	Page       int    `query:"page"`
+	PageSize   int    `query:"pageSize"`
+}
+
+// SearchResult is a single ranked search hit
+type SearchResult struct {
+	Type         string        `json:"type"` // word, topic or conversation
+	ID           uint          `json:"id"`
+	Title        string        `json:"title"`
+	Snippet      string        `json:"snippet,omitempty"`
+	Level        string        `json:"level,omitempty"`
+	Language     *LanguageInfo `json:"language,omitempty"`
+	MatchedField string        `json:"matchedField"`
+	Score        float64       `json:"score"`
+}
+
+// SearchFacet counts the matches for one facet value
+type SearchFacet struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets groups match counts by type, level and language.
+// Facets count every match of the query, before the type, level and language filters.
+type SearchFacets struct {
+	Types     []SearchFacet `json:"types"`
+	Levels    []SearchFacet `json:"levels"`
+	Languages []SearchFacet `json:"languages"`
+}
+
+// SearchResponse represents a page of search results with facets
+type SearchResponse struct {
+	Query      string         `json:"query"`
+	Results    []SearchResult `json:"results"`
+	Facets     SearchFacets   `json:"facets"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"pageSize"`
+	TotalPages int            `json:"totalPages"`
+}
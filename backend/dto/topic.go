@@ -32,6 +32,8 @@ type TopicResponse struct {
 	Description       string          `json:"description"`
 	Level             string          `json:"level"`
 	IsPublic          bool            `json:"isPublic"`
+	Status            string          `json:"status"`            // draft, in_review, published or archived
+	DraftOf           *uint           `json:"draftOf,omitempty"` // published topic this draft copy replaces
 	Language          *LanguageInfo   `json:"language,omitempty"`
 	CreatedBy         *CreatorInfo    `json:"createdBy,omitempty"`
 	WordCount         int             `json:"wordCount"`
@@ -71,6 +73,7 @@ type TopicFilterParams struct {
 	LanguageCode string `query:"languageCode"`
 	CreatedBy    uint   `query:"createdBy"`
 	IsPublic     *bool  `query:"isPublic"`
+	Status       string `query:"status"`
 	Page         int    `query:"page"`
 	PageSize     int    `query:"pageSize"`
 	IncludeWords bool   `query:"includeWords"`
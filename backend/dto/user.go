@@ -33,6 +33,9 @@ type UpdateUserRequest struct {
 	Name          *string `json:"name" validate:"omitempty,min=1,max=100"`
 	Email         *string `json:"email" validate:"omitempty,email"`
 	ProfilePicURL *string `json:"profilePicUrl" validate:"omitempty,max=500"`
+
+	// Roles replaces the user's roles when present (admin only)
+	Roles []string `json:"roles,omitempty" validate:"omitempty,min=1,dive,oneof=learner teacher admin"`
 }
 
 // CreateUserRequest represents the request to create a new user (admin only)
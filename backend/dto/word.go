@@ -5,56 +5,101 @@ type CreateWordRequest struct {
 	BaseWord     string                   `json:"baseWord" validate:"required,min=1,max=255"`
 	ImageURL     string                   `json:"imageUrl" validate:"omitempty,max=500"`
 	Notes        string                   `json:"notes" validate:"omitempty,max=5000"`
+	PartOfSpeech string                   `json:"partOfSpeech" validate:"omitempty,oneof=noun verb adjective adverb pronoun preposition conjunction interjection particle classifier numeral phrase"`
 	Translations []CreateTranslationInput `json:"translations" validate:"required,min=1,dive"`
+	Relations    []WordRelationInput      `json:"relations" validate:"omitempty,dive"`
 }
 
 // CreateTranslationInput represents translation data for word creation
 type CreateTranslationInput struct {
-	LanguageID   uint   `json:"languageId" validate:"required,min=1"`
-	Translation  string `json:"translation" validate:"required,min=1"`
-	Romanization string `json:"romanization" validate:"omitempty,max=255"`
+	LanguageID   uint           `json:"languageId" validate:"required,min=1"`
+	Translation  string         `json:"translation" validate:"required,min=1"`
+	Romanization string         `json:"romanization" validate:"omitempty,max=255"`
+	AudioURL     string         `json:"audioUrl" validate:"omitempty,max=500"`
+	MeasureWord  string         `json:"measureWord" validate:"omitempty,max=50"`
+	Gender       string         `json:"gender" validate:"omitempty,oneof=masculine feminine neuter common"`
+	Examples     []ExampleInput `json:"examples" validate:"omitempty,max=20,dive"`
+}
+
+// ExampleInput represents an example sentence of a translation
+type ExampleInput struct {
+	Sentence     string `json:"sentence" validate:"required,min=1,max=1000"`
+	Romanization string `json:"romanization" validate:"omitempty,max=500"`
+	Meaning      string `json:"meaning" validate:"omitempty,max=1000"`
 	AudioURL     string `json:"audioUrl" validate:"omitempty,max=500"`
 }
 
+// WordRelationInput links a word to a related word
+type WordRelationInput struct {
+	RelatedWordID uint   `json:"relatedWordId" validate:"required,min=1"`
+	RelationType  string `json:"relationType" validate:"required,oneof=synonym antonym related"`
+}
+
 // UpdateWordRequest represents the request to update a word
 type UpdateWordRequest struct {
 	BaseWord     *string                  `json:"baseWord" validate:"omitempty,min=1,max=255"`
 	ImageURL     *string                  `json:"imageUrl" validate:"omitempty,max=500"`
 	Notes        *string                  `json:"notes" validate:"omitempty,max=5000"`
+	PartOfSpeech *string                  `json:"partOfSpeech" validate:"omitempty,oneof=noun verb adjective adverb pronoun preposition conjunction interjection particle classifier numeral phrase"`
 	Translations []UpdateTranslationInput `json:"translations" validate:"omitempty,dive"`
+	Relations    []WordRelationInput      `json:"relations" validate:"omitempty,dive"` // replaces all links when present; [] removes them
 }
 
 // UpdateTranslationInput represents translation data for word update
 type UpdateTranslationInput struct {
-	ID           *uint   `json:"id" validate:"omitempty,min=1"`
-	LanguageID   uint    `json:"languageId" validate:"required,min=1"`
-	Translation  string  `json:"translation" validate:"required,min=1"`
-	Romanization *string `json:"romanization" validate:"omitempty,max=255"`
-	AudioURL     *string `json:"audioUrl" validate:"omitempty,max=500"`
+	ID           *uint          `json:"id" validate:"omitempty,min=1"`
+	LanguageID   uint           `json:"languageId" validate:"required,min=1"`
+	Translation  string         `json:"translation" validate:"required,min=1"`
+	Romanization *string        `json:"romanization" validate:"omitempty,max=255"`
+	AudioURL     *string        `json:"audioUrl" validate:"omitempty,max=500"`
+	MeasureWord  *string        `json:"measureWord" validate:"omitempty,max=50"`
+	Gender       *string        `json:"gender" validate:"omitempty,oneof=masculine feminine neuter common"`
+	Examples     []ExampleInput `json:"examples" validate:"omitempty,max=20,dive"` // replaces all examples when present; [] removes them
 }
 
 // WordResponse represents a word with its translations
 type WordResponse struct {
-	ID           uint                  `json:"id"`
-	BaseWord     string                `json:"baseWord"`
-	ImageURL     string                `json:"imageUrl"`
-	Notes        string                `json:"notes"`
-	CreatedBy    uint                  `json:"createdBy"`
-	CreatedAt    string                `json:"createdAt"`
-	UpdatedAt    string                `json:"updatedAt"`
-	Creator      *CreatorInfo          `json:"creator,omitempty"`
-	Translations []TranslationResponse `json:"translations"`
+	ID           uint                   `json:"id"`
+	BaseWord     string                 `json:"baseWord"`
+	ImageURL     string                 `json:"imageUrl"`
+	Notes        string                 `json:"notes"`
+	PartOfSpeech string                 `json:"partOfSpeech"`
+	CreatedBy    uint                   `json:"createdBy"`
+	CreatedAt    string                 `json:"createdAt"`
+	UpdatedAt    string                 `json:"updatedAt"`
+	Creator      *CreatorInfo           `json:"creator,omitempty"`
+	Translations []TranslationResponse  `json:"translations"`
+	Relations    []WordRelationResponse `json:"relations"`
 }
 
 // TranslationResponse represents a translation in response
 type TranslationResponse struct {
-	ID           uint          `json:"id"`
-	WordID       uint          `json:"wordId"`
-	LanguageID   uint          `json:"languageId"`
-	Translation  string        `json:"translation"`
-	Romanization string        `json:"romanization"`
-	AudioURL     string        `json:"audioUrl"`
-	Language     *LanguageInfo `json:"language,omitempty"`
+	ID           uint              `json:"id"`
+	WordID       uint              `json:"wordId"`
+	LanguageID   uint              `json:"languageId"`
+	Translation  string            `json:"translation"`
+	Romanization string            `json:"romanization"`
+	AudioURL     string            `json:"audioUrl"`
+	MeasureWord  string            `json:"measureWord"`
+	Gender       string            `json:"gender"`
+	Examples     []ExampleResponse `json:"examples"`
+	Language     *LanguageInfo     `json:"language,omitempty"`
+}
+
+// ExampleResponse represents an example sentence in response
+type ExampleResponse struct {
+	ID           uint   `json:"id"`
+	Sentence     string `json:"sentence"`
+	Romanization string `json:"romanization"`
+	Meaning      string `json:"meaning"`
+	AudioURL     string `json:"audioUrl"`
+}
+
+// WordRelationResponse represents a related word in response
+type WordRelationResponse struct {
+	WordID       uint   `json:"wordId"`
+	BaseWord     string `json:"baseWord"`
+	RelationType string `json:"relationType"`
 }
 
 // LanguageInfo represents basic language information
@@ -92,7 +137,8 @@ type WordFilterParams struct {
 
 // UploadResponse represents file upload response
 type UploadResponse struct {
-	URL      string `json:"url"`
-	Filename string `json:"filename"`
-	Size     int64  `json:"size"`
+	URL      string         `json:"url"`
+	Filename string         `json:"filename"`
+	Size     int64          `json:"size"`
+	Asset    *AssetResponse `json:"asset,omitempty"` // images, and audio when ffmpeg is installed
 }
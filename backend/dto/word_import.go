@@ -0,0 +1,55 @@
+package dto
+
+// WordImportMapping maps spreadsheet column headers to word fields.
+// When no mapping is supplied the headers produced by the word export are used:
+// "id", "baseWord", "notes", "imageUrl", "<code> translation" and "<code> romanization".
+type WordImportMapping struct {
+	ID           string                         `json:"id,omitempty"` // optional column with existing word IDs
+	BaseWord     string                         `json:"baseWord"`
+	Notes        string                         `json:"notes,omitempty"`
+	ImageURL     string                         `json:"imageUrl,omitempty"`
+	Translations []WordImportTranslationMapping `json:"translations"`
+}
+
+// WordImportTranslationMapping maps the columns holding one language's translation
+type WordImportTranslationMapping struct {
+	LanguageCode string `json:"languageCode"`
+	Translation  string `json:"translation"`
+	Romanization string `json:"romanization,omitempty"`
+}
+
+// WordImportRowError describes a validation problem with a single row
+type WordImportRowError struct {
+	Row     int    `json:"row"` // 1-based row number in the file, including the header row
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// WordImportRowResult describes what happened (or would happen, in a dry run) to a row
+type WordImportRowResult struct {
+	Row      int    `json:"row"`
+	BaseWord string `json:"baseWord"`
+	WordID   uint   `json:"wordId,omitempty"` // zero for words that would be created in a dry run
+	Action   string `json:"action"`           // created, updated, unchanged or skipped
+}
+
+// WordImportResult summarises a bulk word import
+type WordImportResult struct {
+	DryRun    bool                  `json:"dryRun"`
+	TotalRows int                   `json:"totalRows"`
+	Created   int                   `json:"created"`
+	Updated   int                   `json:"updated"`
+	Unchanged int                   `json:"unchanged"`
+	Skipped   int                   `json:"skipped"`
+	Rows      []WordImportRowResult `json:"rows"`
+	Errors    []WordImportRowError  `json:"errors,omitempty"`
+}
+
+// WordExportParams represents query parameters for exporting a filtered word list
+type WordExportParams struct {
+	Search     string `query:"search"`
+	LanguageID uint   `query:"languageId"`
+	CreatedBy  uint   `query:"createdBy"`
+	Format     string `query:"format"`    // csv (default) or xlsx
+	Languages  string `query:"languages"` // comma-separated language codes; defaults to all languages used
+}
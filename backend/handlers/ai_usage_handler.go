@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/ratelimit"
+	"dannyswat/learnspeak/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AIUsageHandler struct {
+	quotaService *services.AIQuotaService
+	usageService *services.AIUsageService
+}
+
+func NewAIUsageHandler(quotaService *services.AIQuotaService, usageService *services.AIUsageService) *AIUsageHandler {
+	return &AIUsageHandler{
+		quotaService: quotaService,
+		usageService: usageService,
+	}
+}
+
+// GetUsage godoc
+// @Summary Get AI usage
+// @Description List every user's paid AI calls (image, translation, tts) on a UTC day, with the daily quotas
+// @Tags admin
+// @Produce json
+// @Param date query string false "Day as YYYY-MM-DD (default today, UTC)"
+// @Success 200 {object} dto.AIUsageReportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/usage [get]
+func (h *AIUsageHandler) GetUsage(c echo.Context) error {
+	date := time.Now()
+	if value := c.QueryParam("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "date must be in YYYY-MM-DD format",
+			})
+		}
+		date = parsed
+	}
+
+	report, err := h.quotaService.GetUsageReport(date)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to load AI usage",
+		})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// GetMonthlyUsage godoc
+// @Summary Get monthly AI spend
+// @Description Organisation-wide estimated AI cost for a calendar month (UTC), by provider operation and by user
+// @Tags admin
+// @Produce json
+// @Param month query string false "Month as YYYY-MM (default current month)"
+// @Success 200 {object} dto.AIMonthlyUsageReport
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/usage/monthly [get]
+func (h *AIUsageHandler) GetMonthlyUsage(c echo.Context) error {
+	month, err := parseMonthParam(c.QueryParam("month"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "month must be in YYYY-MM format",
+		})
+	}
+
+	report, err := h.usageService.GetMonthlyReport(month)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to load AI usage",
+		})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// GetUserMonthlyUsage godoc
+// @Summary Get a user's monthly AI spend
+// @Description Estimated AI cost of one user for a calendar month (UTC), by provider operation
+// @Tags admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Param month query string false "Month as YYYY-MM (default current month)"
+// @Success 200 {object} dto.AIUserMonthlyUsageReport
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/usage/monthly/users/{id} [get]
+func (h *AIUsageHandler) GetUserMonthlyUsage(c echo.Context) error {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid user ID",
+		})
+	}
+	return h.userMonthlyUsage(c, uint(userID))
+}
+
+// GetMyMonthlyUsage godoc
+// @Summary Get my monthly AI spend
+// @Description Estimated AI cost of the current user for a calendar month (UTC), with the budget cap
+// @Tags ai
+// @Produce json
+// @Param month query string false "Month as YYYY-MM (default current month)"
+// @Success 200 {object} dto.AIUserMonthlyUsageReport
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /usage/monthly [get]
+func (h *AIUsageHandler) GetMyMonthlyUsage(c echo.Context) error {
+	return h.userMonthlyUsage(c, c.Get("userId").(uint))
+}
+
+func (h *AIUsageHandler) userMonthlyUsage(c echo.Context, userID uint) error {
+	month, err := parseMonthParam(c.QueryParam("month"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "month must be in YYYY-MM format",
+		})
+	}
+
+	report, err := h.usageService.GetUserMonthlyReport(userID, month)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to load AI usage",
+		})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// parseMonthParam parses ?month=YYYY-MM, defaulting to the current month
+func parseMonthParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	return time.Parse("2006-01", value)
+}
+
+// isAILimitError reports whether err is an exhausted daily quota or monthly budget
+func isAILimitError(err error) bool {
+	var quotaErr *services.QuotaExceededError
+	var budgetErr *services.BudgetExceededError
+	return errors.As(err, &quotaErr) || errors.As(err, &budgetErr)
+}
+
+// aiLimitErrorResponse writes the response for an exhausted quota or budget, or a failure
+// to check one. Both are 429s: quotas reset at midnight UTC, budgets on the 1st of the month.
+func aiLimitErrorResponse(c echo.Context, err error) error {
+	var budgetErr *services.BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		c.Response().Header().Set("Retry-After", ratelimit.RetryAfterHeader(time.Until(budgetErr.ResetAt)))
+		return c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+			Error:   "budget_exceeded",
+			Message: "Monthly AI budget exhausted",
+			Details: map[string]string{
+				"scope":  budgetErr.Scope,
+				"budget": strconv.FormatFloat(budgetErr.Budget, 'f', 2, 64),
+				"spent":  strconv.FormatFloat(budgetErr.Spent, 'f', 2, 64),
+			},
+		})
+	}
+
+	var quotaErr *services.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		now := time.Now().UTC()
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		c.Response().Header().Set("Retry-After", ratelimit.RetryAfterHeader(midnight.Sub(now)))
+		return c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+			Error:   "quota_exceeded",
+			Message: "Daily " + quotaErr.Category + " quota exceeded",
+			Details: map[string]string{
+				"category": quotaErr.Category,
+				"limit":    strconv.Itoa(quotaErr.Limit),
+			},
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "internal_error",
+		Message: "Failed to check AI quota",
+	})
+}
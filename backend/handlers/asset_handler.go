@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/services"
+)
+
+// maxAssetTags and maxAssetTagLength bound the tags of one asset
+const (
+	maxAssetTags      = 20
+	maxAssetTagLength = 50
+)
+
+type AssetHandler struct {
+	assetService  *services.AssetService
+	gcGracePeriod time.Duration
+}
+
+func NewAssetHandler(assetService *services.AssetService, gcGracePeriod time.Duration) *AssetHandler {
+	return &AssetHandler{assetService: assetService, gcGracePeriod: gcGracePeriod}
+}
+
+// ListAssets godoc
+// @Summary Search the asset library
+// @Description List images and recordings in the asset library with their metadata, tags, variants and reference counts, newest first
+// @Tags assets
+// @Produce json
+// @Security BearerAuth
+// @Param source query string false "upload, profile, generated or audio"
+// @Param kind query string false "image or audio"
+// @Param q query string false "Matches the original file name, URL or a tag"
+// @Param tag query string false "Exact tag"
+// @Param uploadedBy query int false "Uploader user ID"
+// @Param unused query bool false "Only assets no content refers to"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Items per page (default: 20, max 100)"
+// @Success 200 {object} dto.AssetListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/v1/assets [get]
+func (h *AssetHandler) ListAssets(c echo.Context) error {
+	var params dto.AssetFilterParams
+	if err := c.Bind(&params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	switch params.Source {
+	case "", models.AssetSourceUpload, models.AssetSourceProfile, models.AssetSourceGenerated, models.AssetSourceAudio:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid source. Allowed: upload, profile, generated, audio")
+	}
+	switch params.Kind {
+	case "", models.AssetKindImage, models.AssetKindAudio:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid kind. Allowed: image, audio")
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 || params.PageSize > 100 {
+		params.PageSize = 20
+	}
+
+	result, err := h.assetService.ListAssets(&params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list assets")
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetAsset godoc
+// @Summary Get an asset
+// @Tags assets
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Asset ID"
+// @Success 200 {object} dto.AssetResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/assets/{id} [get]
+func (h *AssetHandler) GetAsset(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid asset ID")
+	}
+
+	asset, err := h.assetService.GetAsset(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Asset not found")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get asset")
+	}
+	return c.JSON(http.StatusOK, asset)
+}
+
+// GetAssetReferences godoc
+// @Summary List where an asset is used
+// @Tags assets
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Asset ID"
+// @Success 200 {object} dto.AssetReferencesResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/assets/{id}/references [get]
+func (h *AssetHandler) GetAssetReferences(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid asset ID")
+	}
+
+	refs, err := h.assetService.GetReferences(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Asset not found")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get asset references")
+	}
+	return c.JSON(http.StatusOK, refs)
+}
+
+// UpdateAssetTags godoc
+// @Summary Replace an asset's tags
+// @Description Tags are trimmed, lower-cased and deduplicated
+// @Tags assets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Asset ID"
+// @Param request body dto.UpdateAssetTagsRequest true "Tags"
+// @Success 200 {object} dto.AssetResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/assets/{id}/tags [put]
+func (h *AssetHandler) UpdateAssetTags(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid asset ID")
+	}
+
+	var req dto.UpdateAssetTagsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if len(req.Tags) > maxAssetTags {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("At most %d tags are allowed", maxAssetTags))
+	}
+	for _, tag := range req.Tags {
+		if utf8.RuneCountInString(tag) > maxAssetTagLength {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Tags must be at most %d characters", maxAssetTagLength))
+		}
+	}
+
+	asset, err := h.assetService.SetTags(uint(id), req.Tags)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Asset not found")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update tags")
+	}
+	return c.JSON(http.StatusOK, asset)
+}
+
+// CollectGarbage godoc
+// @Summary Remove unused uploaded files
+// @Description Remove uploaded images, profile photos and recordings that no content refers to and that are older than the grace period (ASSET_GC_GRACE_PERIOD)
+// @Tags assets
+// @Produce json
+// @Security BearerAuth
+// @Param dryRun query bool false "Report what would be removed without deleting"
+// @Success 200 {object} dto.AssetGCResponse
+// @Router /api/v1/admin/assets/gc [post]
+func (h *AssetHandler) CollectGarbage(c echo.Context) error {
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dryRun"))
+
+	result, err := h.assetService.CollectGarbage(c.Request().Context(), services.AssetGCOptions{
+		GracePeriod: h.gcGracePeriod,
+		DryRun:      dryRun,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to collect unused files")
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListEvents godoc
+// @Summary Search the audit log
+// @Description Logins, failed logins, user, role, journey assignment and invitation changes, newest first
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Param action query string false "Comma-separated actions, e.g. auth.login_failed,auth.login_locked"
+// @Param actorId query int false "User who performed the action"
+// @Param targetType query string false "user, journey or invitation"
+// @Param targetId query int false "ID of the user, journey or invitation"
+// @Param ip query string false "Client IP address"
+// @Param from query string false "Start date (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "End date (YYYY-MM-DD, inclusive, or RFC 3339)"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Items per page (default: 50, max 200)"
+// @Success 200 {object} dto.AuditEventListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/v1/admin/audit [get]
+func (h *AuditHandler) ListEvents(c echo.Context) error {
+	var params dto.AuditFilterParams
+	if err := c.Bind(&params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 || params.PageSize > 200 {
+		params.PageSize = 50
+	}
+
+	result, err := h.auditService.ListEvents(&params)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAuditFilter) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list audit events")
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// ExportEvents godoc
+// @Summary Export the audit log
+// @Description Download the audit events matching the filter as CSV, oldest first
+// @Tags audit
+// @Produce text/csv
+// @Security BearerAuth
+// @Param action query string false "Comma-separated actions"
+// @Param actorId query int false "User who performed the action"
+// @Param targetType query string false "user, journey or invitation"
+// @Param targetId query int false "ID of the user, journey or invitation"
+// @Param ip query string false "Client IP address"
+// @Param from query string false "Start date (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "End date (YYYY-MM-DD, inclusive, or RFC 3339)"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/v1/admin/audit/export [get]
+func (h *AuditHandler) ExportEvents(c echo.Context) error {
+	var params dto.AuditFilterParams
+	if err := c.Bind(&params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	// Stream the export; the log can be far larger than one response should buffer
+	filename := "audit-" + time.Now().UTC().Format("20060102-150405") + ".csv"
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+	if err := h.auditService.ExportCSV(&params, res); err != nil {
+		if res.Committed {
+			// Too late for an error response; the client gets a truncated file
+			slog.ErrorContext(c.Request().Context(), "Audit export failed", "error", err)
+			return nil
+		}
+		res.Header().Del(echo.HeaderContentDisposition)
+		if errors.Is(err, services.ErrInvalidAuditFilter) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export audit events")
+	}
+	return nil
+}
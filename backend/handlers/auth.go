@@ -4,8 +4,11 @@ import (
 	"dannyswat/learnspeak/database"
 	"dannyswat/learnspeak/dto"
 	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/ratelimit"
+	"dannyswat/learnspeak/services"
 	"dannyswat/learnspeak/utils"
 	"net/http"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -14,6 +17,48 @@ import (
 
 var validate = validator.New()
 
+// loginLockout locks usernames after repeated failed logins from one IP; nil disables it
+var loginLockout *ratelimit.Lockout
+
+// SetLoginLockout configures the lockout applied by Login
+func SetLoginLockout(lockout *ratelimit.Lockout) {
+	loginLockout = lockout
+}
+
+// auditLog records logins, registrations and password changes; nil disables it
+var auditLog *services.AuditService
+
+// SetAuditLog configures the audit log written by the auth handlers
+func SetAuditLog(audit *services.AuditService) {
+	auditLog = audit
+}
+
+// auditLoginFailure records a rejected login. userID is nil for unknown usernames and
+// lockedFor is non-zero when the attempt locked the username or hit an existing lock.
+func auditLoginFailure(c echo.Context, username, reason string, userID *uint, lockedFor time.Duration) {
+	action := models.AuditActionLoginFailed
+	details := map[string]interface{}{"username": username, "reason": reason}
+	if lockedFor > 0 {
+		action = models.AuditActionLoginLocked
+		details["lockedForSeconds"] = int(lockedFor.Seconds())
+	}
+	entry := services.AuditEntry{Action: action, ActorID: userID, Details: details}
+	if userID != nil {
+		entry.TargetType = models.AuditTargetUser
+		entry.TargetID = *userID
+	}
+	auditLog.Record(c.Request().Context(), entry)
+}
+
+// accountLocked responds to a login attempt on a locked username
+func accountLocked(c echo.Context, retryAfter time.Duration) error {
+	c.Response().Header().Set("Retry-After", ratelimit.RetryAfterHeader(retryAfter))
+	return c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+		Error:   "account_locked",
+		Message: "Too many failed login attempts, please try again later",
+	})
+}
+
 // Register handles user registration
 func Register(c echo.Context) error {
 	var req dto.RegisterRequest
@@ -96,6 +141,14 @@ func Register(c echo.Context) error {
 	// Load user with roles
 	database.DB.Preload("Roles").First(&user, user.ID)
 
+	auditLog.Record(c.Request().Context(), services.AuditEntry{
+		Action:     models.AuditActionRegister,
+		ActorID:    &user.ID,
+		TargetType: models.AuditTargetUser,
+		TargetID:   user.ID,
+		Details:    map[string]interface{}{"username": user.Username, "email": user.Email},
+	})
+
 	// Generate token
 	token, err := utils.GenerateToken(&user)
 	if err != nil {
@@ -144,10 +197,22 @@ func Login(c echo.Context) error {
 		})
 	}
 
+	ctx := c.Request().Context()
+	if lockedFor := loginLockout.LockedFor(ctx, req.Username, c.RealIP()); lockedFor > 0 {
+		auditLoginFailure(c, req.Username, "locked", nil, lockedFor)
+		return accountLocked(c, lockedFor)
+	}
+
 	// Find user
 	var user models.User
 	if err := database.DB.Preload("Roles").Where("username = ?", req.Username).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
+			// Unknown usernames count too, so lockouts don't reveal which accounts exist
+			lockedFor := loginLockout.RecordFailure(ctx, req.Username, c.RealIP())
+			auditLoginFailure(c, req.Username, "unknown_user", nil, lockedFor)
+			if lockedFor > 0 {
+				return accountLocked(c, lockedFor)
+			}
 			return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
 				Error:   "unauthorized",
 				Message: "Invalid credentials",
@@ -161,11 +226,23 @@ func Login(c echo.Context) error {
 
 	// Check password
 	if !utils.CheckPassword(user.PasswordHash, req.Password) {
+		lockedFor := loginLockout.RecordFailure(ctx, req.Username, c.RealIP())
+		auditLoginFailure(c, req.Username, "bad_password", &user.ID, lockedFor)
+		if lockedFor > 0 {
+			return accountLocked(c, lockedFor)
+		}
 		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
 			Error:   "unauthorized",
 			Message: "Invalid credentials",
 		})
 	}
+	loginLockout.Reset(ctx, req.Username, c.RealIP())
+	auditLog.Record(ctx, services.AuditEntry{
+		Action:     models.AuditActionLogin,
+		ActorID:    &user.ID,
+		TargetType: models.AuditTargetUser,
+		TargetID:   user.ID,
+	})
 
 	// Generate token
 	token, err := utils.GenerateToken(&user)
@@ -271,6 +348,12 @@ func ChangePassword(c echo.Context) error {
 		})
 	}
 
+	auditLog.Record(c.Request().Context(), services.AuditEntry{
+		Action:     models.AuditActionPasswordChange,
+		TargetType: models.AuditTargetUser,
+		TargetID:   user.ID,
+	})
+
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Password changed successfully",
 	})
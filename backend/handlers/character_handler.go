@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type CharacterHandler struct {
+	characterService services.CharacterService
+}
+
+func NewCharacterHandler(characterService services.CharacterService) *CharacterHandler {
+	return &CharacterHandler{characterService: characterService}
+}
+
+// LookupCharacters godoc
+// @Summary Look up the characters of a text
+// @Description Definition, pinyin, radical, decomposition, stroke count and traditional/simplified variants of each distinct Chinese character in a text
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param text query string true "Text, up to 100 distinct characters"
+// @Success 200 {object} dto.CharacterListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/characters [get]
+func (h *CharacterHandler) LookupCharacters(c echo.Context) error {
+	text := c.QueryParam("text")
+	if text == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "text is required")
+	}
+
+	result, err := h.characterService.LookupText(text)
+	if err != nil {
+		return characterError(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetCharacter godoc
+// @Summary Get a character with its stroke order
+// @Description Character data with SVG stroke paths and medians in writing order, for animating and checking handwriting
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param char path string true "A single Chinese character, URL-encoded"
+// @Success 200 {object} dto.CharacterResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/characters/{char} [get]
+func (h *CharacterHandler) GetCharacter(c echo.Context) error {
+	char, err := url.PathUnescape(c.Param("char"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid character")
+	}
+
+	result, err := h.characterService.GetCharacter(char)
+	if err != nil {
+		return characterError(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetHandwritingPractice godoc
+// @Summary Get a topic's handwriting practice
+// @Description The Chinese characters of a topic's words in its language, in word order, with your attempts and best score
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Topic ID"
+// @Success 200 {object} dto.HandwritingPracticeResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/topics/{id}/handwriting [get]
+func (h *CharacterHandler) GetHandwritingPractice(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid topic ID")
+	}
+
+	result, err := h.characterService.GetPractice(uint(id), canViewUnpublished(c), userID)
+	if err != nil {
+		return characterError(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// RecordHandwritingAttempt godoc
+// @Summary Record a handwriting attempt
+// @Description Score an attempt by the share of strokes written without a mistake and record it as progress on the topic; 70% or more passes
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Topic ID"
+// @Param request body dto.HandwritingAttemptRequest true "Mistakes per character"
+// @Success 201 {object} dto.HandwritingAttemptResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/topics/{id}/handwriting/attempts [post]
+func (h *CharacterHandler) RecordHandwritingAttempt(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid topic ID")
+	}
+
+	var req dto.HandwritingAttemptRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	result, err := h.characterService.RecordAttempt(uint(id), &req, canViewUnpublished(c), userID)
+	if err != nil {
+		return characterError(err)
+	}
+	return c.JSON(http.StatusCreated, result)
+}
+
+// characterError maps character errors to HTTP statuses
+func characterError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrCharacterDataUnavailable):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, services.ErrCharacterNotFound), errors.Is(err, services.ErrPracticeTopicNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -42,6 +43,12 @@ func (h *ConversationHandler) CreateConversation(c echo.Context) error {
 	// Create conversation
 	conversation, err := h.conversationService.CreateConversation(&req, userID)
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTopicNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrContentLocked):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
@@ -168,9 +175,9 @@ func (h *ConversationHandler) GetConversationsByTopic(c echo.Context) error {
 	}
 
 	// Get conversations
-	conversations, err := h.conversationService.GetConversationsByTopic(uint(topicID))
+	conversations, err := h.conversationService.GetConversationsByTopic(uint(topicID), canViewUnpublished(c))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(topicErrorStatus(err), err.Error())
 	}
 
 	return c.JSON(http.StatusOK, conversations)
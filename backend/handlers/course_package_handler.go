@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type CoursePackageHandler struct {
+	packageService *services.CoursePackageService
+}
+
+func NewCoursePackageHandler(packageService *services.CoursePackageService) *CoursePackageHandler {
+	return &CoursePackageHandler{
+		packageService: packageService,
+	}
+}
+
+// ExportJourney godoc
+// @Summary Export a journey as a course package
+// @Description Download a zip archive with the journey, its topics, words, quizzes, conversations and media
+// @Tags journeys
+// @Produce application/zip
+// @Param id path int true "Journey ID"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /journeys/{id}/export [get]
+func (h *CoursePackageHandler) ExportJourney(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid journey ID",
+			Error:   err.Error(),
+		})
+	}
+
+	// Build the archive in a temp file so errors can still be reported as JSON
+	tmp, err := os.CreateTemp("", "learnspeak-export-*.zip")
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "Failed to create export file",
+			Error:   err.Error(),
+		})
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := h.packageService.ExportJourney(uint(id), tmp); err != nil {
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "Failed to export journey",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.Attachment(tmp.Name(), fmt.Sprintf("journey-%d.zip", id))
+}
+
+// ImportJourney godoc
+// @Summary Import a course package
+// @Description Import a course package zip as a new journey owned by the current user
+// @Tags journeys
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Course package zip"
+// @Param preserveCreators formData bool false "Keep original creators when their usernames exist (admin only)"
+// @Success 201 {object} dto.CoursePackageImportResult
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /journeys/import [post]
+func (h *CoursePackageHandler) ImportJourney(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+			Error:   "unauthorized",
+		})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "No file provided",
+			Error:   err.Error(),
+		})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "Failed to open uploaded file",
+			Error:   err.Error(),
+		})
+	}
+	defer src.Close()
+
+	opts := services.ImportOptions{
+		PreserveCreators: c.FormValue("preserveCreators") == "true",
+	}
+
+	// Only admins may attribute imported content to other users
+	if opts.PreserveCreators && !hasRole(c, "admin") {
+		return c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: "Only administrators can preserve original creators",
+			Error:   "forbidden",
+		})
+	}
+
+	result, err := h.packageService.ImportJourney(c.Request().Context(), src, file.Size, userID, opts)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Failed to import course package",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// hasRole checks whether the authenticated user has the given role
+func hasRole(c echo.Context, role string) bool {
+	roles, _ := c.Get("roles").([]string)
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type FlashcardExportHandler struct {
+	exportService *services.FlashcardExportService
+}
+
+func NewFlashcardExportHandler(exportService *services.FlashcardExportService) *FlashcardExportHandler {
+	return &FlashcardExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportTopicFlashcards godoc
+// @Summary Export topic flashcards
+// @Description Download a topic's flashcards as an Anki package with embedded media, or as Quizlet TSV
+// @Tags flashcards
+// @Produce application/octet-stream
+// @Param id path int true "Topic ID"
+// @Param format query string false "apkg (default) or tsv"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /topics/{id}/flashcards/export [get]
+func (h *FlashcardExportHandler) ExportTopicFlashcards(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid topic ID",
+			Error:   err.Error(),
+		})
+	}
+
+	return h.sendExport(c, func(f *os.File) (string, error) {
+		return h.exportService.ExportTopic(uint(id), c.QueryParam("format"), canViewUnpublished(c), f)
+	})
+}
+
+// ExportBookmarks godoc
+// @Summary Export bookmarked words
+// @Description Download the current user's bookmarked words as an Anki package with embedded media, or as Quizlet TSV
+// @Tags flashcards
+// @Produce application/octet-stream
+// @Param format query string false "apkg (default) or tsv"
+// @Param languageId query int false "Only include translations in this language"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /bookmarks/export [get]
+func (h *FlashcardExportHandler) ExportBookmarks(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+			Error:   "unauthorized",
+		})
+	}
+
+	var languageID uint64
+	if raw := c.QueryParam("languageId"); raw != "" {
+		var err error
+		languageID, err = strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Message: "Invalid language ID",
+				Error:   err.Error(),
+			})
+		}
+	}
+
+	return h.sendExport(c, func(f *os.File) (string, error) {
+		return h.exportService.ExportBookmarks(userID, uint(languageID), c.QueryParam("format"), f)
+	})
+}
+
+// sendExport builds the export in a temp file so errors can still be reported as JSON
+func (h *FlashcardExportHandler) sendExport(c echo.Context, write func(f *os.File) (string, error)) error {
+	tmp, err := os.CreateTemp("", "learnspeak-flashcards-*")
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "Failed to create export file",
+			Error:   err.Error(),
+		})
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	filename, err := write(tmp)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrTopicNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, dto.ErrorResponse{
+			Message: "Failed to export flashcards",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.Attachment(tmp.Name(), filename)
+}
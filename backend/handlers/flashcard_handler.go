@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"dannyswat/learnspeak/dto"
 	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/services"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -25,9 +27,9 @@ func (h *FlashcardHandler) GetTopicFlashcards(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid topic ID"})
 	}
 
-	// Check if topic exists
+	// Check if topic exists and the user may see it
 	var topic models.Topic
-	if err := h.db.First(&topic, topicID).Error; err != nil {
+	if err := h.db.First(&topic, topicID).Error; err != nil || !services.TopicVisible(&topic, canViewUnpublished(c)) {
 		return c.JSON(http.StatusNotFound, map[string]string{"message": "Topic not found"})
 	}
 
@@ -37,6 +39,8 @@ func (h *FlashcardHandler) GetTopicFlashcards(c echo.Context) error {
 		Order("sequence_order ASC").
 		Preload("Word.Translations").
 		Preload("Word.Translations.Language").
+		Preload("Word.Translations.Examples", orderExamples).
+		Preload("Word.Relations.RelatedWord").
 		Find(&topicWords).Error; err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to load flashcards"})
 	}
@@ -56,12 +60,14 @@ func (h *FlashcardHandler) GetTopicFlashcards(c echo.Context) error {
 
 	// Format response
 	type FlashcardResponse struct {
-		ID           uint                     `json:"id"`
-		BaseWord     string                   `json:"baseWord"`
-		ImageURL     string                   `json:"imageUrl,omitempty"`
-		Notes        string                   `json:"notes,omitempty"`
-		Translations []models.WordTranslation `json:"translations"`
-		IsBookmarked bool                     `json:"isBookmarked"`
+		ID           uint                       `json:"id"`
+		BaseWord     string                     `json:"baseWord"`
+		ImageURL     string                     `json:"imageUrl,omitempty"`
+		Notes        string                     `json:"notes,omitempty"`
+		PartOfSpeech string                     `json:"partOfSpeech,omitempty"`
+		Translations []models.WordTranslation   `json:"translations"`
+		Relations    []dto.WordRelationResponse `json:"relations"`
+		IsBookmarked bool                       `json:"isBookmarked"`
 	}
 
 	flashcards := make([]FlashcardResponse, 0, len(topicWords))
@@ -71,7 +77,9 @@ func (h *FlashcardHandler) GetTopicFlashcards(c echo.Context) error {
 			BaseWord:     tw.Word.BaseWord,
 			ImageURL:     tw.Word.ImageURL,
 			Notes:        tw.Word.Notes,
+			PartOfSpeech: tw.Word.PartOfSpeech,
 			Translations: tw.Word.Translations,
+			Relations:    services.ToWordRelationResponses(tw.Word.Relations),
 			IsBookmarked: bookmarkedWordIDs[tw.Word.ID],
 		})
 	}
@@ -225,6 +233,7 @@ func (h *FlashcardHandler) GetBookmarkedWords(c echo.Context) error {
 	if err := h.db.Where("user_id = ? AND word_id IS NOT NULL", userID).
 		Preload("Word.Translations").
 		Preload("Word.Translations.Language").
+		Preload("Word.Translations.Examples", orderExamples).
 		Find(&bookmarks).Error; err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to load bookmarks"})
 	}
@@ -233,6 +242,7 @@ func (h *FlashcardHandler) GetBookmarkedWords(c echo.Context) error {
 		ID           uint                     `json:"id"`
 		BaseWord     string                   `json:"baseWord"`
 		ImageURL     string                   `json:"imageUrl,omitempty"`
+		PartOfSpeech string                   `json:"partOfSpeech,omitempty"`
 		Translations []models.WordTranslation `json:"translations"`
 		BookmarkedAt string                   `json:"bookmarkedAt"`
 	}
@@ -244,6 +254,7 @@ func (h *FlashcardHandler) GetBookmarkedWords(c echo.Context) error {
 				ID:           b.Word.ID,
 				BaseWord:     b.Word.BaseWord,
 				ImageURL:     b.Word.ImageURL,
+				PartOfSpeech: b.Word.PartOfSpeech,
 				Translations: b.Word.Translations,
 				BookmarkedAt: b.CreatedAt.Format("2006-01-02T15:04:05Z"),
 			})
@@ -256,6 +267,11 @@ func (h *FlashcardHandler) GetBookmarkedWords(c echo.Context) error {
 	})
 }
 
+// orderExamples preloads example sentences in their order
+func orderExamples(db *gorm.DB) *gorm.DB {
+	return db.Order("sequence_order ASC, id ASC")
+}
+
 func uintPtr(u uint) *uint {
 	return &u
 }
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type HealthHandler struct {
+	healthService *services.HealthService
+}
+
+func NewHealthHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Livez godoc
+// @Summary Liveness probe
+// @Description Reports that the process is running. Does not check dependencies, so a database outage doesn't cause restarts.
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c echo.Context) error {
+	return c.JSON(http.StatusOK, dto.HealthResponse{
+		Status:    dto.HealthStatusOK,
+		CheckedAt: time.Now().UTC(),
+	})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Checks the database, migration version, upload directory and AI providers, each with its latency. Returns 503 when a required component fails; unconfigured providers only degrade the status.
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse
+// @Failure 503 {object} dto.HealthResponse
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	result := h.healthService.Readiness(c.Request().Context())
+
+	status := http.StatusOK
+	if result.Status == dto.HealthStatusFail {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, result)
+}
@@ -5,13 +5,15 @@ import (
 	"net/http"
 
 	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
 	"dannyswat/learnspeak/services"
 
 	"github.com/labstack/echo/v4"
 )
 
 type ImageGenerationHandler struct {
-	service *services.ImageGenerationService
+	service      *services.ImageGenerationService
+	quotaService *services.AIQuotaService
 }
 
 type GenerateImageRequest struct {
@@ -24,10 +26,11 @@ type GenerateImageRequest struct {
 }
 
 type GenerateImageResponse struct {
-	URL       string `json:"url"`
-	LocalPath string `json:"local_path"`
-	Prompt    string `json:"prompt"`
-	Cached    bool   `json:"cached"`
+	URL       string             `json:"url"`
+	LocalPath string             `json:"local_path"`
+	Prompt    string             `json:"prompt"`
+	Cached    bool               `json:"cached"`
+	Asset     *dto.AssetResponse `json:"asset,omitempty"`
 }
 
 type BatchGenerateImageRequest struct {
@@ -44,9 +47,10 @@ type BatchGenerateResult struct {
 	Error string                 `json:"error,omitempty"`
 }
 
-func NewImageGenerationHandler(service *services.ImageGenerationService) *ImageGenerationHandler {
+func NewImageGenerationHandler(service *services.ImageGenerationService, quotaService *services.AIQuotaService) *ImageGenerationHandler {
 	return &ImageGenerationHandler{
-		service: service,
+		service:      service,
+		quotaService: quotaService,
 	}
 }
 
@@ -77,8 +81,14 @@ func (h *ImageGenerationHandler) GenerateImage(c echo.Context) error {
 		})
 	}
 
+	ctx := c.Request().Context()
+	reservation, err := h.quotaService.Reserve(ctx, c.Get("userId").(uint), models.AIUsageImage, 1)
+	if err != nil {
+		return aiLimitErrorResponse(c, err)
+	}
+
 	// Generate image
-	result, err := h.service.GenerateImage(c.Request().Context(), services.ImageGeneratorOptions{
+	result, err := h.service.GenerateImage(ctx, services.ImageGeneratorOptions{
 		Word:         req.Word,
 		Translation:  req.Translation,
 		Size:         req.Size,
@@ -86,6 +96,12 @@ func (h *ImageGenerationHandler) GenerateImage(c echo.Context) error {
 		Style:        req.Style,
 		CustomPrompt: req.CustomPrompt,
 	})
+	if err != nil || result.Cached {
+		h.quotaService.Release(ctx, reservation, 1)
+	}
+	if isAILimitError(err) {
+		return aiLimitErrorResponse(c, err)
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error: fmt.Sprintf("Failed to generate image: %v", err),
@@ -97,6 +113,7 @@ func (h *ImageGenerationHandler) GenerateImage(c echo.Context) error {
 		LocalPath: result.LocalPath,
 		Prompt:    result.Prompt,
 		Cached:    result.Cached,
+		Asset:     result.Asset,
 	})
 }
 
@@ -140,15 +157,23 @@ func (h *ImageGenerationHandler) BatchGenerateImages(c echo.Context) error {
 		})
 	}
 
+	// Reserve the whole batch up front so a batch can't run past the quota halfway through
+	ctx := c.Request().Context()
+	reservation, err := h.quotaService.Reserve(ctx, c.Get("userId").(uint), models.AIUsageImage, len(req.Words))
+	if err != nil {
+		return aiLimitErrorResponse(c, err)
+	}
+
 	// Generate images for each word
 	results := make([]BatchGenerateResult, len(req.Words))
+	unused := 0
 	for i, wordReq := range req.Words {
 		result := BatchGenerateResult{
 			Word: wordReq.Word,
 		}
 
 		// Generate image
-		img, err := h.service.GenerateImage(c.Request().Context(), services.ImageGeneratorOptions{
+		img, err := h.service.GenerateImage(ctx, services.ImageGeneratorOptions{
 			Word:         wordReq.Word,
 			Translation:  wordReq.Translation,
 			Size:         wordReq.Size,
@@ -157,6 +182,9 @@ func (h *ImageGenerationHandler) BatchGenerateImages(c echo.Context) error {
 			CustomPrompt: wordReq.CustomPrompt,
 		})
 
+		if err != nil || img.Cached {
+			unused++
+		}
 		if err != nil {
 			result.Error = err.Error()
 		} else {
@@ -165,12 +193,15 @@ func (h *ImageGenerationHandler) BatchGenerateImages(c echo.Context) error {
 				LocalPath: img.LocalPath,
 				Prompt:    img.Prompt,
 				Cached:    img.Cached,
+				Asset:     img.Asset,
 			}
 		}
 
 		results[i] = result
 	}
 
+	h.quotaService.Release(ctx, reservation, unused)
+
 	return c.JSON(http.StatusOK, BatchGenerateImageResponse{
 		Images: results,
 	})
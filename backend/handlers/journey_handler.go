@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
 
 	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
 	"dannyswat/learnspeak/services"
 )
 
@@ -104,6 +106,14 @@ func (h *JourneyHandler) GetJourney(c echo.Context) error {
 		})
 	}
 
+	// Learners only ever see published journeys
+	if journey.Status != models.ContentStatusPublished && !canViewUnpublished(c) {
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "Journey not found",
+			Error:   "journey not found",
+		})
+	}
+
 	return c.JSON(http.StatusOK, journey)
 }
 
@@ -162,6 +172,9 @@ func (h *JourneyHandler) UpdateJourney(c echo.Context) error {
 	journey, err := h.journeyService.UpdateJourney(uint(id), &req, userID)
 	if err != nil {
 		statusCode := http.StatusBadRequest
+		if errors.Is(err, services.ErrContentLocked) {
+			statusCode = http.StatusConflict
+		}
 
 		return c.JSON(statusCode, dto.ErrorResponse{
 			Message: "Failed to update journey",
@@ -327,6 +340,9 @@ func (h *JourneyHandler) ReorderTopics(c echo.Context) error {
 	// Reorder topics
 	if err := h.journeyService.ReorderTopics(uint(id), topicIDs, userID); err != nil {
 		statusCode := http.StatusBadRequest
+		if errors.Is(err, services.ErrContentLocked) {
+			statusCode = http.StatusConflict
+		}
 		return c.JSON(statusCode, dto.ErrorResponse{
 			Message: "Failed to reorder topics",
 			Error:   err.Error(),
@@ -389,7 +405,7 @@ func (h *JourneyHandler) AssignJourney(c echo.Context) error {
 	}
 
 	// Assign journey
-	response, err := h.journeyService.AssignJourney(uint(id), req.UserIDs, userID)
+	response, err := h.journeyService.AssignJourney(c.Request().Context(), uint(id), req.UserIDs, userID)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Message: "Failed to assign journey",
@@ -441,7 +457,7 @@ func (h *JourneyHandler) UnassignJourney(c echo.Context) error {
 	}
 
 	// Unassign journey
-	if err := h.journeyService.UnassignJourney(uint(id), req.UserIDs); err != nil {
+	if err := h.journeyService.UnassignJourney(c.Request().Context(), uint(id), req.UserIDs); err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Message: "Failed to unassign journey",
 			Error:   err.Error(),
@@ -658,7 +674,7 @@ func (h *JourneyHandler) GenerateInvitation(c echo.Context) error {
 	}
 
 	// Generate invitation
-	invitation, err := h.journeyService.GenerateInvitation(uint(id), &req, userID)
+	invitation, err := h.journeyService.GenerateInvitation(c.Request().Context(), uint(id), &req, userID)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Message: "Failed to generate invitation",
@@ -728,15 +744,20 @@ func (h *JourneyHandler) AcceptInvitation(c echo.Context) error {
 	}
 
 	// Accept invitation
-	if err := h.journeyService.AcceptInvitation(token, userID); err != nil {
+	journeyID, err := h.journeyService.AcceptInvitation(c.Request().Context(), token, userID)
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Message: "Failed to accept invitation",
 			Error:   err.Error(),
 		})
 	}
 
+	// The client can offer a placement test for the journey instead of starting at the first topic
 	return c.JSON(http.StatusOK, dto.SuccessResponse{
 		Message: "Successfully joined the journey!",
+		Data: map[string]interface{}{
+			"journeyId": journeyID,
+		},
 	})
 }
 
@@ -812,7 +833,7 @@ func (h *JourneyHandler) DeactivateInvitation(c echo.Context) error {
 		})
 	}
 
-	if err := h.journeyService.DeactivateInvitation(uint(invitationID), uint(id), userID); err != nil {
+	if err := h.journeyService.DeactivateInvitation(c.Request().Context(), uint(invitationID), uint(id), userID); err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Message: "Failed to deactivate invitation",
 			Error:   err.Error(),
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
+	"dannyswat/learnspeak/dto"
 	"dannyswat/learnspeak/services"
 
 	"github.com/labstack/echo/v4"
@@ -20,10 +23,10 @@ func NewLanguageHandler(languageService services.LanguageService) *LanguageHandl
 
 // GetLanguages handles GET /api/languages
 // @Summary Get all active languages
-// @Description Get a list of all active languages
+// @Description Get a list of all active languages with their voices, romanization scheme and font hints
 // @Tags languages
 // @Produce json
-// @Success 200 {array} models.Language
+// @Success 200 {array} dto.LanguageResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/languages [get]
 func (h *LanguageHandler) GetLanguages(c echo.Context) error {
@@ -34,3 +37,143 @@ func (h *LanguageHandler) GetLanguages(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, languages)
 }
+
+// ListAllLanguages handles GET /api/admin/languages
+// @Summary List all languages
+// @Description List every language, inactive ones included
+// @Tags languages
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.LanguageResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/admin/languages [get]
+func (h *LanguageHandler) ListAllLanguages(c echo.Context) error {
+	languages, err := h.languageService.ListAll()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch languages")
+	}
+
+	return c.JSON(http.StatusOK, languages)
+}
+
+// GetLanguage handles GET /api/admin/languages/:id
+// @Summary Get a language
+// @Tags languages
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Language ID"
+// @Success 200 {object} dto.LanguageResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/admin/languages/{id} [get]
+func (h *LanguageHandler) GetLanguage(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid language ID")
+	}
+
+	language, err := h.languageService.GetLanguage(uint(id))
+	if err != nil {
+		return languageError(err)
+	}
+
+	return c.JSON(http.StatusOK, language)
+}
+
+// CreateLanguage handles POST /api/admin/languages
+// @Summary Add a language
+// @Description Add a language with its TTS voices, romanization scheme, translator code, direction and font hints
+// @Tags languages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateLanguageRequest true "Language"
+// @Success 201 {object} dto.LanguageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/admin/languages [post]
+func (h *LanguageHandler) CreateLanguage(c echo.Context) error {
+	var req dto.CreateLanguageRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	language, err := h.languageService.CreateLanguage(c.Request().Context(), &req)
+	if err != nil {
+		return languageError(err)
+	}
+
+	return c.JSON(http.StatusCreated, language)
+}
+
+// UpdateLanguage handles PUT /api/admin/languages/:id
+// @Summary Update a language
+// @Description Update a language and its settings; omitted fields are kept, and voices are replaced as a list
+// @Tags languages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Language ID"
+// @Param request body dto.UpdateLanguageRequest true "Language"
+// @Success 200 {object} dto.LanguageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/admin/languages/{id} [put]
+func (h *LanguageHandler) UpdateLanguage(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid language ID")
+	}
+
+	var req dto.UpdateLanguageRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	language, err := h.languageService.UpdateLanguage(c.Request().Context(), uint(id), &req)
+	if err != nil {
+		return languageError(err)
+	}
+
+	return c.JSON(http.StatusOK, language)
+}
+
+// DeleteLanguage handles DELETE /api/admin/languages/:id
+// @Summary Delete a language
+// @Description Delete a language no word, topic, journey, conversation or placement test is in; deactivate it otherwise
+// @Tags languages
+// @Security BearerAuth
+// @Param id path int true "Language ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/admin/languages/{id} [delete]
+func (h *LanguageHandler) DeleteLanguage(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid language ID")
+	}
+
+	if err := h.languageService.DeleteLanguage(c.Request().Context(), uint(id)); err != nil {
+		return languageError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// languageError maps language errors to HTTP statuses
+func languageError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrLanguageNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrLanguageExists), errors.Is(err, services.ErrLanguageInUse):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+}
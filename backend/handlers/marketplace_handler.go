@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type MarketplaceHandler struct {
+	marketplaceService services.MarketplaceService
+}
+
+func NewMarketplaceHandler(marketplaceService services.MarketplaceService) *MarketplaceHandler {
+	return &MarketplaceHandler{marketplaceService: marketplaceService}
+}
+
+// ListContent godoc
+// @Summary Browse the marketplace
+// @Description Public topics, journeys or conversations shared by teachers, with ratings and clone counts
+// @Tags marketplace
+// @Produce json
+// @Security BearerAuth
+// @Param contentType query string true "topic, journey or conversation"
+// @Param search query string false "Search name and description"
+// @Param languageCode query string false "Language code"
+// @Param level query string false "beginner, intermediate or advanced (topics and conversations)"
+// @Param createdBy query int false "Author"
+// @Param mine query bool false "Own content, including content that isn't public"
+// @Param sort query string false "newest (default), rating or clones"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Items per page (default: 20, max 100)"
+// @Success 200 {object} dto.MarketplaceListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/v1/marketplace [get]
+func (h *MarketplaceHandler) ListContent(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	var params dto.MarketplaceFilterParams
+	if err := c.Bind(&params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+	switch params.Sort {
+	case "", "newest", "rating", "clones":
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid sort. Allowed: newest, rating, clones")
+	}
+	params.Page, params.PageSize = marketplacePage(params.Page, params.PageSize)
+
+	result, err := h.marketplaceService.ListContent(&params, userID)
+	if err != nil {
+		return marketplaceError(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetContent godoc
+// @Summary Get marketplace content
+// @Description A public topic, journey or conversation, or your own, with your rating of it
+// @Tags marketplace
+// @Produce json
+// @Security BearerAuth
+// @Param contentType path string true "topic, journey or conversation"
+// @Param id path int true "Content ID"
+// @Success 200 {object} dto.MarketplaceItemResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/marketplace/{contentType}/{id} [get]
+func (h *MarketplaceHandler) GetContent(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid content ID")
+	}
+
+	item, err := h.marketplaceService.GetContent(c.Param("contentType"), uint(id), userID)
+	if err != nil {
+		return marketplaceError(err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// ListRatings godoc
+// @Summary List ratings
+// @Description Ratings of public content, or of your own, most recently changed first
+// @Tags marketplace
+// @Produce json
+// @Security BearerAuth
+// @Param contentType path string true "topic, journey or conversation"
+// @Param id path int true "Content ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Items per page (default: 20, max 100)"
+// @Success 200 {object} dto.ContentRatingListResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/marketplace/{contentType}/{id}/ratings [get]
+func (h *MarketplaceHandler) ListRatings(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid content ID")
+	}
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+	page, pageSize = marketplacePage(page, pageSize)
+
+	result, err := h.marketplaceService.ListRatings(c.Param("contentType"), uint(id), page, pageSize, userID)
+	if err != nil {
+		return marketplaceError(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// RateContent godoc
+// @Summary Rate content
+// @Description Rate someone else's public content from 1 to 5; rating again replaces your earlier rating
+// @Tags marketplace
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param contentType path string true "topic, journey or conversation"
+// @Param id path int true "Content ID"
+// @Param request body dto.RateContentRequest true "Rating"
+// @Success 200 {object} dto.ContentRatingResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/marketplace/{contentType}/{id}/rating [put]
+func (h *MarketplaceHandler) RateContent(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid content ID")
+	}
+
+	var req dto.RateContentRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	rating, err := h.marketplaceService.RateContent(c.Param("contentType"), uint(id), &req, userID)
+	if err != nil {
+		return marketplaceError(err)
+	}
+	return c.JSON(http.StatusOK, rating)
+}
+
+// DeleteRating godoc
+// @Summary Remove your rating
+// @Tags marketplace
+// @Security BearerAuth
+// @Param contentType path string true "topic, journey or conversation"
+// @Param id path int true "Content ID"
+// @Success 204
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/marketplace/{contentType}/{id}/rating [delete]
+func (h *MarketplaceHandler) DeleteRating(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid content ID")
+	}
+
+	if err := h.marketplaceService.DeleteRating(c.Param("contentType"), uint(id), userID); err != nil {
+		return marketplaceError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CloneContent godoc
+// @Summary Clone content into your library
+// @Description Deep-copy a public topic, journey or conversation, with its words, translations, quiz questions and media references, as private drafts you own
+// @Tags marketplace
+// @Produce json
+// @Security BearerAuth
+// @Param contentType path string true "topic, journey or conversation"
+// @Param id path int true "Content ID"
+// @Success 201 {object} dto.CloneResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/marketplace/{contentType}/{id}/clone [post]
+func (h *MarketplaceHandler) CloneContent(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid content ID")
+	}
+
+	result, err := h.marketplaceService.CloneContent(c.Param("contentType"), uint(id), userID)
+	if err != nil {
+		return marketplaceError(err)
+	}
+	return c.JSON(http.StatusCreated, result)
+}
+
+// ListClones godoc
+// @Summary List your clones
+// @Description Content you cloned, with attribution to its source and author, newest first
+// @Tags marketplace
+// @Produce json
+// @Security BearerAuth
+// @Param contentType query string false "topic, journey or conversation"
+// @Param cloneId query int false "Your copy's ID, for its attribution"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Items per page (default: 20, max 100)"
+// @Success 200 {object} dto.ClonedContentListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/v1/marketplace/clones [get]
+func (h *MarketplaceHandler) ListClones(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	var params dto.ClonedContentFilterParams
+	if err := c.Bind(&params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+	params.Page, params.PageSize = marketplacePage(params.Page, params.PageSize)
+
+	result, err := h.marketplaceService.ListClones(&params, userID)
+	if err != nil {
+		return marketplaceError(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// marketplacePage applies the default page and page size
+func marketplacePage(page, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+// marketplaceError maps marketplace errors to HTTP statuses
+func marketplaceError(err error) error {
+	if errors.Is(err, services.ErrNotInMarketplace) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+}
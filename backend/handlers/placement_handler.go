@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type PlacementHandler struct {
+	placementService services.PlacementService
+}
+
+func NewPlacementHandler(placementService services.PlacementService) *PlacementHandler {
+	return &PlacementHandler{
+		placementService: placementService,
+	}
+}
+
+// StartPlacementTest godoc
+// @Summary Start a placement test
+// @Description Start an adaptive placement test for a language, optionally scoped to a journey
+// @Tags placement
+// @Accept json
+// @Produce json
+// @Param request body dto.StartPlacementTestRequest true "Placement test options"
+// @Success 201 {object} dto.PlacementTestResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /placement-tests [post]
+func (h *PlacementHandler) StartPlacementTest(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+			Error:   "unauthorized",
+		})
+	}
+
+	var req dto.StartPlacementTestRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+	}
+
+	test, err := h.placementService.StartTest(&req, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Failed to start placement test",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, test)
+}
+
+// GetPlacementTest godoc
+// @Summary Get a placement test
+// @Description Get the current state or result of a placement test
+// @Tags placement
+// @Produce json
+// @Param id path int true "Placement test ID"
+// @Success 200 {object} dto.PlacementTestResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /placement-tests/{id} [get]
+func (h *PlacementHandler) GetPlacementTest(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+			Error:   "unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid placement test ID",
+			Error:   err.Error(),
+		})
+	}
+
+	test, err := h.placementService.GetTest(uint(id), userID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "Placement test not found",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, test)
+}
+
+// SubmitPlacementAnswer godoc
+// @Summary Answer a placement question
+// @Description Submit the answer to the current question and receive the next question or the result
+// @Tags placement
+// @Accept json
+// @Produce json
+// @Param id path int true "Placement test ID"
+// @Param request body dto.PlacementAnswerRequest true "Answer"
+// @Success 200 {object} dto.PlacementTestResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /placement-tests/{id}/answers [post]
+func (h *PlacementHandler) SubmitPlacementAnswer(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+			Error:   "unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid placement test ID",
+			Error:   err.Error(),
+		})
+	}
+
+	var req dto.PlacementAnswerRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Validation failed",
+			Error:   err.Error(),
+		})
+	}
+
+	test, err := h.placementService.SubmitAnswer(uint(id), &req, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Failed to submit answer",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, test)
+}
+
+// ApplyPlacementResult godoc
+// @Summary Apply a placement result to the journey
+// @Description Mark the journey topics below the recommended starting topic as completed
+// @Tags placement
+// @Produce json
+// @Param id path int true "Placement test ID"
+// @Success 200 {object} dto.PlacementTestResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /placement-tests/{id}/apply [post]
+func (h *PlacementHandler) ApplyPlacementResult(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+			Error:   "unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid placement test ID",
+			Error:   err.Error(),
+		})
+	}
+
+	test, err := h.placementService.ApplyResult(uint(id), userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Failed to apply placement result",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, test)
+}
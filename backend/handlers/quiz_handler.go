@@ -3,6 +3,7 @@ package handlers
 import (
 	"dannyswat/learnspeak/dto"
 	"dannyswat/learnspeak/services"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -22,6 +23,13 @@ func NewQuizHandler(quizService *services.QuizService) *QuizHandler {
 // CreateQuestion creates a new quiz question
 // POST /api/v1/quiz
 func (h *QuizHandler) CreateQuestion(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+		})
+	}
+
 	var req dto.CreateQuizQuestionRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -35,9 +43,9 @@ func (h *QuizHandler) CreateQuestion(c echo.Context) error {
 		})
 	}
 
-	question, err := h.quizService.CreateQuestion(&req)
+	question, err := h.quizService.CreateQuestion(&req, userID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		return c.JSON(topicErrorStatus(err), dto.ErrorResponse{
 			Message: err.Error(),
 		})
 	}
@@ -73,6 +81,13 @@ func (h *QuizHandler) GetQuestion(c echo.Context) error {
 // UpdateQuestion updates a quiz question
 // PUT /api/v1/quiz/:id
 func (h *QuizHandler) UpdateQuestion(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+		})
+	}
+
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -93,9 +108,9 @@ func (h *QuizHandler) UpdateQuestion(c echo.Context) error {
 		})
 	}
 
-	question, err := h.quizService.UpdateQuestion(uint(id), &req)
+	question, err := h.quizService.UpdateQuestion(uint(id), &req, userID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		return c.JSON(topicErrorStatus(err), dto.ErrorResponse{
 			Message: err.Error(),
 		})
 	}
@@ -109,6 +124,13 @@ func (h *QuizHandler) UpdateQuestion(c echo.Context) error {
 // DeleteQuestion deletes a quiz question
 // DELETE /api/v1/quiz/:id
 func (h *QuizHandler) DeleteQuestion(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+		})
+	}
+
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -116,8 +138,8 @@ func (h *QuizHandler) DeleteQuestion(c echo.Context) error {
 		})
 	}
 
-	if err := h.quizService.DeleteQuestion(uint(id)); err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+	if err := h.quizService.DeleteQuestion(uint(id), userID); err != nil {
+		return c.JSON(topicErrorStatus(err), dto.ErrorResponse{
 			Message: err.Error(),
 		})
 	}
@@ -191,9 +213,9 @@ func (h *QuizHandler) GetTopicQuizForPractice(c echo.Context) error {
 
 	shuffle := c.QueryParam("shuffle") == "true"
 
-	questions, err := h.quizService.GetTopicQuestionsForPractice(uint(topicID), shuffle)
+	questions, err := h.quizService.GetTopicQuestionsForPractice(uint(topicID), shuffle, canViewUnpublished(c))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		return c.JSON(topicErrorStatus(err), dto.ErrorResponse{
 			Message: err.Error(),
 		})
 	}
@@ -231,9 +253,9 @@ func (h *QuizHandler) SubmitQuiz(c echo.Context) error {
 		})
 	}
 
-	result, err := h.quizService.SubmitQuiz(userID, &req)
+	result, err := h.quizService.SubmitQuiz(c.Request().Context(), userID, &req, canViewUnpublished(c))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		return c.JSON(topicErrorStatus(err), dto.ErrorResponse{
 			Message: err.Error(),
 		})
 	}
@@ -243,3 +265,15 @@ func (h *QuizHandler) SubmitQuiz(c echo.Context) error {
 		Data:    result,
 	})
 }
+
+// topicErrorStatus maps a topic that doesn't exist or isn't visible to 404, and changes to
+// a topic that isn't a draft to 409
+func topicErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTopicNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrContentLocked):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
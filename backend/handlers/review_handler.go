@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/services"
+)
+
+type ReviewHandler struct {
+	reviewService services.ReviewService
+}
+
+func NewReviewHandler(reviewService services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService}
+}
+
+// SubmitReview godoc
+// @Summary Submit content for review
+// @Description Move a draft topic or journey to in_review, optionally assigning a reviewer (creator or admin)
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.SubmitReviewRequest true "Content to review"
+// @Success 201 {object} dto.ReviewResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/v1/reviews [post]
+func (h *ReviewHandler) SubmitReview(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	var req dto.SubmitReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	review, err := h.reviewService.SubmitForReview(&req, userID, hasRole(c, "admin"))
+	if err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(http.StatusCreated, review)
+}
+
+// ListReviews godoc
+// @Summary List reviews
+// @Description Reviews of topics and journeys, newest first
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param contentType query string false "topic or journey"
+// @Param contentId query int false "Topic or journey ID"
+// @Param status query string false "pending, approved, changes_requested or withdrawn"
+// @Param reviewerId query int false "Assigned reviewer"
+// @Param submittedBy query int false "Submitter"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Items per page (default: 20, max 100)"
+// @Success 200 {object} dto.ReviewListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/v1/reviews [get]
+func (h *ReviewHandler) ListReviews(c echo.Context) error {
+	var params dto.ReviewFilterParams
+	if err := c.Bind(&params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	switch params.ContentType {
+	case "", models.ReviewContentTopic, models.ReviewContentJourney:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid contentType. Allowed: topic, journey")
+	}
+	switch params.Status {
+	case "", models.ReviewStatusPending, models.ReviewStatusApproved, models.ReviewStatusChangesRequested, models.ReviewStatusWithdrawn:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid status. Allowed: pending, approved, changes_requested, withdrawn")
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 || params.PageSize > 100 {
+		params.PageSize = 20
+	}
+
+	result, err := h.reviewService.ListReviews(&params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list reviews")
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetReview godoc
+// @Summary Get a review
+// @Description A review with its comments
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} dto.ReviewResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/reviews/{id} [get]
+func (h *ReviewHandler) GetReview(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	review, err := h.reviewService.GetReview(uint(id))
+	if err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+// AssignReviewer godoc
+// @Summary Assign a reviewer
+// @Description Set the reviewer of a pending review (submitter or admin; teachers can claim an unassigned review)
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param request body dto.AssignReviewerRequest true "Reviewer"
+// @Success 200 {object} dto.ReviewResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/v1/reviews/{id}/reviewer [put]
+func (h *ReviewHandler) AssignReviewer(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	var req dto.AssignReviewerRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	review, err := h.reviewService.AssignReviewer(uint(id), req.ReviewerID, userID, hasRole(c, "admin"))
+	if err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+// AddComment godoc
+// @Summary Comment on a review
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param request body dto.ReviewCommentRequest true "Comment"
+// @Success 201 {object} dto.ReviewCommentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/reviews/{id}/comments [post]
+func (h *ReviewHandler) AddComment(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	var req dto.ReviewCommentRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	comment, err := h.reviewService.AddComment(uint(id), req.Body, userID)
+	if err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(http.StatusCreated, comment)
+}
+
+// Approve godoc
+// @Summary Approve a review
+// @Description Publish the content under review; an approved topic draft copy replaces its published topic (assigned reviewer or admin)
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param request body dto.ReviewDecisionRequest false "Decision note"
+// @Success 200 {object} dto.ReviewResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/v1/reviews/{id}/approve [post]
+func (h *ReviewHandler) Approve(c echo.Context) error {
+	return h.decide(c, h.reviewService.Approve)
+}
+
+// RequestChanges godoc
+// @Summary Request changes
+// @Description Return the content under review to draft with a note (assigned reviewer or admin)
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param request body dto.ReviewDecisionRequest true "What needs to change"
+// @Success 200 {object} dto.ReviewResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/v1/reviews/{id}/request-changes [post]
+func (h *ReviewHandler) RequestChanges(c echo.Context) error {
+	return h.decide(c, h.reviewService.RequestChanges)
+}
+
+// Withdraw godoc
+// @Summary Withdraw a review
+// @Description Close a pending review and return the content to draft (submitter or admin)
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} dto.ReviewResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/v1/reviews/{id}/withdraw [post]
+func (h *ReviewHandler) Withdraw(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	review, err := h.reviewService.Withdraw(uint(id), userID, hasRole(c, "admin"))
+	if err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+// ArchiveTopic handles POST /api/v1/topics/:id/archive
+func (h *ReviewHandler) ArchiveTopic(c echo.Context) error {
+	return h.setArchived(c, models.ReviewContentTopic, h.reviewService.Archive)
+}
+
+// UnarchiveTopic handles POST /api/v1/topics/:id/unarchive
+func (h *ReviewHandler) UnarchiveTopic(c echo.Context) error {
+	return h.setArchived(c, models.ReviewContentTopic, h.reviewService.Unarchive)
+}
+
+// ArchiveJourney handles POST /api/v1/journeys/:id/archive
+func (h *ReviewHandler) ArchiveJourney(c echo.Context) error {
+	return h.setArchived(c, models.ReviewContentJourney, h.reviewService.Archive)
+}
+
+// UnarchiveJourney handles POST /api/v1/journeys/:id/unarchive
+func (h *ReviewHandler) UnarchiveJourney(c echo.Context) error {
+	return h.setArchived(c, models.ReviewContentJourney, h.reviewService.Unarchive)
+}
+
+// decide runs an approve or request-changes decision
+func (h *ReviewHandler) decide(c echo.Context, decision func(id uint, note string, userID uint, isAdmin bool) (*dto.ReviewResponse, error)) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid review ID")
+	}
+
+	var req dto.ReviewDecisionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	review, err := decision(uint(id), req.Note, userID, hasRole(c, "admin"))
+	if err != nil {
+		return reviewError(err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+// setArchived archives or unarchives a topic or journey
+func (h *ReviewHandler) setArchived(c echo.Context, contentType string, change func(contentType string, id uint, userID uint, isAdmin bool) error) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid "+contentType+" ID")
+	}
+
+	if err := change(contentType, uint(id), userID, hasRole(c, "admin")); err != nil {
+		return reviewError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// reviewError maps workflow errors to HTTP statuses
+func reviewError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrReviewNotFound), errors.Is(err, services.ErrContentNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrReviewForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrReviewConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+}
+
+// canViewUnpublished reports whether the user may see drafts and content under review
+func canViewUnpublished(c echo.Context) bool {
+	return hasRole(c, "teacher") || hasRole(c, "admin")
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/services"
+)
+
+type RevisionHandler struct {
+	revisionService services.RevisionService
+}
+
+func NewRevisionHandler(revisionService services.RevisionService) *RevisionHandler {
+	return &RevisionHandler{revisionService: revisionService}
+}
+
+// ListRevisions godoc
+// @Summary List revision history
+// @Description Who changed which words, topics, conversations and quiz questions, when, and which fields, newest first
+// @Tags revisions
+// @Produce json
+// @Security BearerAuth
+// @Param entityType query string false "word, topic, conversation or quiz_question"
+// @Param entityId query int false "ID of the word, topic, conversation or quiz question"
+// @Param action query string false "baseline, create, update, delete or restore"
+// @Param userId query int false "User who made the change"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Items per page (default: 20, max 100)"
+// @Success 200 {object} dto.RevisionListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/v1/revisions [get]
+func (h *RevisionHandler) ListRevisions(c echo.Context) error {
+	var params dto.RevisionFilterParams
+	if err := c.Bind(&params); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	switch params.EntityType {
+	case "", models.RevisionEntityWord, models.RevisionEntityTopic, models.RevisionEntityConversation, models.RevisionEntityQuizQuestion:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid entityType. Allowed: word, topic, conversation, quiz_question")
+	}
+	switch params.Action {
+	case "", models.RevisionActionBaseline, models.RevisionActionCreate, models.RevisionActionUpdate, models.RevisionActionDelete, models.RevisionActionRestore:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid action. Allowed: baseline, create, update, delete, restore")
+	}
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.PageSize <= 0 || params.PageSize > 100 {
+		params.PageSize = 20
+	}
+
+	result, err := h.revisionService.ListRevisions(&params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list revisions")
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetRevision godoc
+// @Summary Get a revision
+// @Description A revision with its field-level changes and the full recorded state
+// @Tags revisions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Revision ID"
+// @Success 200 {object} dto.RevisionResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/revisions/{id} [get]
+func (h *RevisionHandler) GetRevision(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid revision ID")
+	}
+
+	revision, err := h.revisionService.GetRevision(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrRevisionNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get revision")
+	}
+	return c.JSON(http.StatusOK, revision)
+}
+
+// RestoreRevision godoc
+// @Summary Restore a revision
+// @Description Put a word, topic, conversation or quiz question back to the state recorded in a revision, recreating it if it was deleted. The restore is recorded as a new revision.
+// @Tags revisions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Revision ID"
+// @Success 200 {object} dto.RevisionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/revisions/{id}/restore [post]
+func (h *RevisionHandler) RestoreRevision(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid revision ID")
+	}
+
+	revision, err := h.revisionService.RestoreRevision(uint(id), userID, hasRole(c, "admin"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRevisionNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrRestoreUnauthorized), errors.Is(err, services.ErrRelatedWordForbidden):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		case errors.Is(err, services.ErrContentLocked):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, revision)
+}
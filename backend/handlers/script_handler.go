@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type ScriptHandler struct {
+	scriptService services.ScriptService
+}
+
+func NewScriptHandler(scriptService services.ScriptService) *ScriptHandler {
+	return &ScriptHandler{scriptService: scriptService}
+}
+
+// ConvertScript godoc
+// @Summary Convert between Chinese scripts
+// @Description Convert text between Simplified, Traditional and Hong Kong Chinese with the OpenCC tables, optionally spelling the result in pinyin
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ConvertScriptRequest true "Text and conversion: s2t, t2s, s2hk, hk2s, t2hk or hk2t"
+// @Success 200 {object} dto.ConvertScriptResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/convert [post]
+func (h *ScriptHandler) ConvertScript(c echo.Context) error {
+	var req dto.ConvertScriptRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	result, err := h.scriptService.Convert(&req)
+	if err != nil {
+		return characterError(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
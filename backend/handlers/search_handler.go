@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type SearchHandler struct {
+	searchService services.SearchService
+}
+
+func NewSearchHandler(searchService services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search godoc
+// @Summary Search content
+// @Description Ranked full-text and fuzzy search across words, translations, topics and conversations, with facets by type, level and language. Romanization matches without tones.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search text"
+// @Param type query string false "Filter by type (word, topic, conversation)"
+// @Param level query string false "Filter by level (beginner, intermediate, advanced)"
+// @Param languageId query int false "Filter by language ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Page size (default: 20, max: 100)"
+// @Success 200 {object} dto.SearchResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /search [get]
+func (h *SearchHandler) Search(c echo.Context) error {
+	var params dto.SearchParams
+	if err := c.Bind(&params); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+	}
+
+	// Teachers and admins can also find private topics and unpublished conversations
+	includePrivate := hasRole(c, "teacher") || hasRole(c, "admin")
+
+	results, err := h.searchService.Search(&params, includePrivate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Search failed",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
 	"dannyswat/learnspeak/services"
 
 	"github.com/labstack/echo/v4"
@@ -65,6 +67,11 @@ func (h *TopicHandler) GetTopic(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
 
+	// Learners only ever see published topics
+	if topic.Status != models.ContentStatusPublished && !canViewUnpublished(c) {
+		return echo.NewHTTPError(http.StatusNotFound, "topic not found")
+	}
+
 	return c.JSON(http.StatusOK, topic)
 }
 
@@ -99,6 +106,9 @@ func (h *TopicHandler) UpdateTopic(c echo.Context) error {
 		if err.Error() == "topic not found" {
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, services.ErrContentLocked) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
@@ -125,6 +135,9 @@ func (h *TopicHandler) DeleteTopic(c echo.Context) error {
 		if err.Error() == "topic not found" {
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, services.ErrContentLocked) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
@@ -179,12 +192,47 @@ func (h *TopicHandler) ReorderWords(c echo.Context) error {
 		if err.Error() == "topic not found" {
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, services.ErrContentLocked) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "Words reordered successfully"})
 }
 
+// CreateDraft handles POST /api/topics/:id/draft - returns the editable draft copy of a
+// published topic, creating it if needed
+func (h *TopicHandler) CreateDraft(c echo.Context) error {
+	// Get user ID from context (set by JWT middleware)
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	// Parse ID
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid topic ID")
+	}
+
+	draft, created, err := h.topicService.CreateDraft(uint(id), userID)
+	if err != nil {
+		if err.Error() == "topic not found" {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, services.ErrContentLocked) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if created {
+		return c.JSON(http.StatusCreated, draft)
+	}
+	return c.JSON(http.StatusOK, draft)
+}
+
 // AddWordsToTopic adds words to an existing topic
 // POST /api/v1/topics/:id/words
 func (h *TopicHandler) AddWordsToTopic(c echo.Context) error {
@@ -217,6 +265,9 @@ func (h *TopicHandler) AddWordsToTopic(c echo.Context) error {
 		if err.Error() == "topic not found" {
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, services.ErrContentLocked) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
@@ -231,9 +282,10 @@ func (h *TopicHandler) ListPublicTopics(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
 	}
 
-	// Force isPublic to true
+	// Force isPublic to true and only show published topics
 	isPublic := true
 	params.IsPublic = &isPublic
+	params.Status = models.ContentStatusPublished
 
 	// Get public topics
 	response, err := h.topicService.ListTopics(&params)
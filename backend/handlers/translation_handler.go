@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
 	"dannyswat/learnspeak/services"
 
 	"github.com/labstack/echo/v4"
@@ -11,11 +14,15 @@ import (
 
 type TranslationHandler struct {
 	translationService *services.TranslationService
+	quotaService       *services.AIQuotaService
+	scriptService      services.ScriptService
 }
 
-func NewTranslationHandler(translationService *services.TranslationService) *TranslationHandler {
+func NewTranslationHandler(translationService *services.TranslationService, quotaService *services.AIQuotaService, scriptService services.ScriptService) *TranslationHandler {
 	return &TranslationHandler{
 		translationService: translationService,
+		quotaService:       quotaService,
+		scriptService:      scriptService,
 	}
 }
 
@@ -35,15 +42,34 @@ func (h *TranslationHandler) Translate(c echo.Context) error {
 			Error: "Text is required",
 		})
 	}
+	if err := h.checkConversion(req.Convert); err != nil {
+		return conversionErrorResponse(c, err)
+	}
+
+	ctx := c.Request().Context()
+	reservation, err := h.quotaService.Reserve(ctx, c.Get("userId").(uint), models.AIUsageTranslation, 1)
+	if err != nil {
+		return aiLimitErrorResponse(c, err)
+	}
 
 	// Translate
-	result, err := h.translationService.Translate(&req)
+	result, err := h.translationService.Translate(ctx, &req)
+	if err != nil || result.Cached {
+		h.quotaService.Release(ctx, reservation, 1)
+	}
+	if isAILimitError(err) {
+		return aiLimitErrorResponse(c, err)
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error: "Failed to translate: " + err.Error(),
 		})
 	}
 
+	if err := h.convertResult(result, req.Convert); err != nil {
+		return conversionErrorResponse(c, err)
+	}
+
 	return c.JSON(http.StatusOK, result)
 }
 
@@ -63,14 +89,78 @@ func (h *TranslationHandler) BatchTranslate(c echo.Context) error {
 			Error: "At least one text is required",
 		})
 	}
+	if err := h.checkConversion(req.Convert); err != nil {
+		return conversionErrorResponse(c, err)
+	}
+
+	// Reserve one call per non-empty text, then refund the ones served from cache
+	texts := 0
+	for _, text := range req.Texts {
+		if strings.TrimSpace(text) != "" {
+			texts++
+		}
+	}
+	ctx := c.Request().Context()
+	reservation, err := h.quotaService.Reserve(ctx, c.Get("userId").(uint), models.AIUsageTranslation, texts)
+	if err != nil {
+		return aiLimitErrorResponse(c, err)
+	}
 
 	// Translate batch
-	result, err := h.translationService.BatchTranslate(&req)
+	result, err := h.translationService.BatchTranslate(ctx, &req)
+	if err != nil {
+		h.quotaService.Release(ctx, reservation, texts)
+	} else {
+		h.quotaService.Release(ctx, reservation, result.Cached)
+	}
+	if isAILimitError(err) {
+		return aiLimitErrorResponse(c, err)
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error: "Failed to translate batch: " + err.Error(),
 		})
 	}
 
+	for i := range result.Results {
+		if err := h.convertResult(&result.Results[i], req.Convert); err != nil {
+			return conversionErrorResponse(c, err)
+		}
+	}
+
 	return c.JSON(http.StatusOK, result)
 }
+
+// checkConversion verifies a requested script conversion before any quota is spent
+func (h *TranslationHandler) checkConversion(conversion string) error {
+	if conversion == "" {
+		return nil
+	}
+	_, err := h.scriptService.ConvertText("", conversion)
+	return err
+}
+
+// convertResult converts a translation and its alternatives to the requested Chinese script
+func (h *TranslationHandler) convertResult(result *services.TranslationResult, conversion string) error {
+	if conversion == "" {
+		return nil
+	}
+	var err error
+	if result.Translation, err = h.scriptService.ConvertText(result.Translation, conversion); err != nil {
+		return err
+	}
+	for i, alternative := range result.Alternatives {
+		if result.Alternatives[i], err = h.scriptService.ConvertText(alternative, conversion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func conversionErrorResponse(c echo.Context, err error) error {
+	status := http.StatusBadRequest
+	if errors.Is(err, services.ErrCharacterDataUnavailable) {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, dto.ErrorResponse{Error: err.Error()})
+}
@@ -1,21 +1,25 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
 	"dannyswat/learnspeak/services"
 
 	"github.com/labstack/echo/v4"
 )
 
 type TTSHandler struct {
-	ttsService *services.TTSService
+	ttsService   *services.TTSService
+	quotaService *services.AIQuotaService
 }
 
-func NewTTSHandler(ttsService *services.TTSService) *TTSHandler {
+func NewTTSHandler(ttsService *services.TTSService, quotaService *services.AIQuotaService) *TTSHandler {
 	return &TTSHandler{
-		ttsService: ttsService,
+		ttsService:   ttsService,
+		quotaService: quotaService,
 	}
 }
 
@@ -41,8 +45,21 @@ func (h *TTSHandler) GenerateTTS(c echo.Context) error {
 		req.Language = "zh-HK"
 	}
 
+	ctx := c.Request().Context()
+	reservation, err := h.quotaService.Reserve(ctx, c.Get("userId").(uint), models.AIUsageTTS, 1)
+	if err != nil {
+		return aiLimitErrorResponse(c, err)
+	}
+
 	// Generate audio
-	response, err := h.ttsService.GenerateAudio(&req)
+	response, err := h.ttsService.GenerateAudio(ctx, &req)
+	if err != nil || response.Cached {
+		// Cached audio and failures don't count against the quota
+		h.quotaService.Release(ctx, reservation, 1)
+	}
+	if isAILimitError(err) {
+		return aiLimitErrorResponse(c, err)
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error: "Failed to generate audio: " + err.Error(),
@@ -52,7 +69,7 @@ func (h *TTSHandler) GenerateTTS(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
-// DeleteCachedAudio deletes a specific cached audio file
+// DeleteCachedAudio deletes a specific cached audio file, unless content still uses it
 // DELETE /api/tts/cache
 func (h *TTSHandler) DeleteCachedAudio(c echo.Context) error {
 	audioURL := c.QueryParam("url")
@@ -63,7 +80,13 @@ func (h *TTSHandler) DeleteCachedAudio(c echo.Context) error {
 	}
 
 	// Delete the cached audio file
-	if err := h.ttsService.DeleteCachedAudio(audioURL); err != nil {
+	err := h.ttsService.DeleteCachedAudio(audioURL)
+	if errors.Is(err, services.ErrAssetInUse) {
+		return c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error: "Cached audio is still used by other content",
+		})
+	}
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error: "Failed to delete cached audio: " + err.Error(),
 		})
@@ -3,41 +3,50 @@ package handlers
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"dannyswat/learnspeak/audio"
 	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/imaging"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/services"
 
 	"github.com/labstack/echo/v4"
 )
 
 type FileUploadHandler struct {
-	uploadDir string
-	maxSize   int64
+	uploadDir    string
+	maxSize      int64
+	assetService *services.AssetService
 }
 
-func NewFileUploadHandler(uploadDir string, maxSizeMB int64) *FileUploadHandler {
+func NewFileUploadHandler(uploadDir string, maxSizeMB int64, assetService *services.AssetService) *FileUploadHandler {
 	// Create upload directory if it doesn't exist
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
 		panic(fmt.Sprintf("Failed to create upload directory: %v", err))
 	}
 
 	return &FileUploadHandler{
-		uploadDir: uploadDir,
-		maxSize:   maxSizeMB * 1024 * 1024, // Convert MB to bytes
+		uploadDir:    uploadDir,
+		maxSize:      maxSizeMB * 1024 * 1024, // Convert MB to bytes
+		assetService: assetService,
 	}
 }
 
 // UploadAudio handles POST /api/upload/audio
 // @Summary Upload audio file
-// @Description Upload an audio file for word pronunciation
+// @Description Upload an audio file for word pronunciation. When ffmpeg is installed it is transcoded to mono MP3 with silence trimmed and loudness normalised, so the returned URL ends in .mp3.
 // @Tags upload
 // @Accept multipart/form-data
 // @Produce json
@@ -51,7 +60,7 @@ func (h *FileUploadHandler) UploadAudio(c echo.Context) error {
 
 // UploadImage handles POST /api/upload/image
 // @Summary Upload image file
-// @Description Upload an image file for word illustration
+// @Description Upload an image file for word illustration. Metadata is stripped and the response includes the image's dimensions; resized and WebP/AVIF variants are built in the background.
 // @Tags upload
 // @Accept multipart/form-data
 // @Produce json
@@ -148,14 +157,60 @@ func (h *FileUploadHandler) uploadFile(c echo.Context, fileType string, allowedE
 		Size:     written,
 	}
 
+	// Uploads are checked by decoding them and recorded in the asset library
+	dst.Close()
+	if err := h.processUpload(c, fileType, destPath, file.Filename, &response); err != nil {
+		os.Remove(destPath)
+		return err
+	}
+
 	return c.JSON(http.StatusOK, response)
 }
 
-// ListImages handles GET /api/upload/images?page=1&pageSize=20
+// processUpload strips image metadata, or transcodes audio to the canonical MP3, and adds
+// the resulting asset to response. A file already in the asset library is replaced by it.
+func (h *FileUploadHandler) processUpload(c echo.Context, fileType, destPath, originalName string, response *dto.UploadResponse) error {
+	ctx := c.Request().Context()
+
+	var asset *dto.AssetResponse
+	var err error
+	switch fileType {
+	case "image", "profile":
+		source, _ := services.AssetSourceForURL(response.URL)
+		asset, err = h.assetService.ProcessImageAsync(ctx, response.URL, source, originalName)
+	case "audio":
+		asset, err = h.assetService.ProcessAudio(ctx, response.URL, originalName)
+	default:
+		return nil
+	}
+
+	if errors.Is(err, imaging.ErrInvalidImage) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid image file")
+	}
+	if errors.Is(err, audio.ErrInvalidAudio) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or silent audio file")
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to process upload", "type", fileType, "path", destPath, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process file")
+	}
+	if asset == nil { // audio without ffmpeg is kept as uploaded
+		return nil
+	}
+
+	response.URL = asset.URL
+	response.Filename = path.Base(asset.URL)
+	response.Size = asset.Bytes
+	response.Asset = asset
+	return nil
+}
+
+// ListImages handles GET /api/upload/images?page=1&pageSize=20&q=apple
 // @Summary List uploaded images
-// @Description List all uploaded images with pagination
+// @Description List all uploaded images with pagination, with dimensions, blurhash and thumbnail for images in the asset library. With q, images in the library are searched by the word, translation or prompt they were generated for, the words that use them, their file name and tags, best match first.
 // @Tags upload
 // @Produce json
+// @Param q query string false "Search text"
 // @Param page query int false "Page number (default: 1)"
 // @Param pageSize query int false "Items per page (default: 20)"
 // @Success 200 {object} dto.ImageListResponse
@@ -176,6 +231,10 @@ func (h *FileUploadHandler) ListImages(c echo.Context) error {
 		}
 	}
 
+	if q := strings.TrimSpace(c.QueryParam("q")); q != "" {
+		return h.searchImages(c, q, page, pageSize)
+	}
+
 	// Read images from both image and image-cache directories
 	type imageFileWithDir struct {
 		entry os.DirEntry
@@ -194,7 +253,7 @@ func (h *FileUploadHandler) ListImages(c echo.Context) error {
 	// Add files from image directory
 	if err == nil {
 		for _, entry := range entries {
-			if !entry.IsDir() {
+			if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 				imageFilesWithDir = append(imageFilesWithDir, imageFileWithDir{entry, "image"})
 			}
 		}
@@ -210,7 +269,7 @@ func (h *FileUploadHandler) ListImages(c echo.Context) error {
 	// Add files from image-cache directory
 	if err == nil {
 		for _, entry := range cacheEntries {
-			if !entry.IsDir() {
+			if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 				imageFilesWithDir = append(imageFilesWithDir, imageFileWithDir{entry, "image-cache"})
 			}
 		}
@@ -254,16 +313,31 @@ func (h *FileUploadHandler) ListImages(c echo.Context) error {
 
 	// Build response
 	images := make([]dto.ImageItem, len(pageImages))
+	urls := make([]string, len(pageImages))
 	for i, fileWithDir := range pageImages {
 		info, _ := fileWithDir.entry.Info()
+		urls[i] = fmt.Sprintf("/uploads/%s/%s", fileWithDir.dir, fileWithDir.entry.Name())
 		images[i] = dto.ImageItem{
 			Filename: fileWithDir.entry.Name(),
-			URL:      fmt.Sprintf("/uploads/%s/%s", fileWithDir.dir, fileWithDir.entry.Name()),
+			URL:      urls[i],
 			Size:     info.Size(),
 			ModTime:  info.ModTime().Unix(),
 		}
 	}
 
+	// Add dimensions and thumbnails of images recorded in the asset library
+	assets, err := h.assetService.GetAssetsByURLs(urls)
+	if err != nil {
+		slog.WarnContext(c.Request().Context(), "Failed to load image assets", "error", err)
+	}
+	for i := range images {
+		asset, ok := assets[images[i].URL]
+		if !ok {
+			continue
+		}
+		addAssetDetails(&images[i], asset)
+	}
+
 	return c.JSON(http.StatusOK, dto.ImageListResponse{
 		Images: images,
 		Total:  total,
@@ -272,6 +346,63 @@ func (h *FileUploadHandler) ListImages(c echo.Context) error {
 	})
 }
 
+// searchImages lists the images in the asset library matching q, best match first
+func (h *FileUploadHandler) searchImages(c echo.Context, q string, page, pageSize int) error {
+	result, err := h.assetService.SearchImages(q, page, pageSize)
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Failed to search images", "query", q, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search images")
+	}
+
+	images := make([]dto.ImageItem, len(result.Assets))
+	for i := range result.Assets {
+		asset := &result.Assets[i]
+		images[i] = dto.ImageItem{
+			Filename: path.Base(asset.URL),
+			URL:      asset.URL,
+			Size:     asset.Bytes,
+			ModTime:  asset.CreatedAt.Unix(),
+		}
+		addAssetDetails(&images[i], asset)
+	}
+
+	return c.JSON(http.StatusOK, dto.ImageListResponse{
+		Images: images,
+		Total:  int(result.Total),
+		Page:   page,
+		Pages:  result.TotalPages,
+	})
+}
+
+// addAssetDetails copies the metadata of an image's asset onto its list item
+func addAssetDetails(item *dto.ImageItem, asset *dto.AssetResponse) {
+	item.AssetID = asset.ID
+	item.Width = asset.Width
+	item.Height = asset.Height
+	item.Format = asset.Format
+	item.Blurhash = asset.Blurhash
+	item.ThumbnailURL = thumbnailURL(asset)
+	item.Word = asset.Word
+	item.Translation = asset.Translation
+}
+
+// thumbnailURL picks the asset's thumb variant, preferring WebP, or "" if it has none
+func thumbnailURL(asset *dto.AssetResponse) string {
+	url := ""
+	for _, v := range asset.Variants {
+		if v.Name != models.AssetVariantThumb {
+			continue
+		}
+		if v.Format == imaging.FormatWebP {
+			return v.URL
+		}
+		if url == "" {
+			url = v.URL
+		}
+	}
+	return url
+}
+
 // contains checks if a slice contains a string
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
@@ -195,7 +196,7 @@ func (h *UserHandler) SearchUsers(c echo.Context) error {
 
 // UpdateUser godoc
 // @Summary Update user information
-// @Description Update user profile information
+// @Description Update user profile information. Users can update themselves; admins can update anyone and change roles.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -226,9 +227,9 @@ func (h *UserHandler) UpdateUser(c echo.Context) error {
 		})
 	}
 
-	// Check if user is updating their own profile
-	// TODO: Add admin role check to allow admins to update any user
-	if currentUserID != uint(id) {
+	// Users can update their own profile; admins can update anyone
+	isAdmin := hasRole(c, "admin")
+	if currentUserID != uint(id) && !isAdmin {
 		return c.JSON(http.StatusForbidden, dto.ErrorResponse{
 			Message: "You can only update your own profile",
 			Error:   "forbidden",
@@ -252,8 +253,24 @@ func (h *UserHandler) UpdateUser(c echo.Context) error {
 		})
 	}
 
+	// Only admins change roles, and not their own admin role so they can't lock themselves out
+	if len(req.Roles) > 0 {
+		if !isAdmin {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Message: "Only administrators can change roles",
+				Error:   "forbidden",
+			})
+		}
+		if currentUserID == uint(id) && !slices.Contains(req.Roles, "admin") {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Message: "Cannot remove your own admin role",
+				Error:   "bad_request",
+			})
+		}
+	}
+
 	// Update user
-	user, err := h.userService.UpdateUser(uint(id), &req)
+	user, err := h.userService.UpdateUser(c.Request().Context(), uint(id), &req)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Message: "Failed to update user",
@@ -294,7 +311,7 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 	}
 
 	// Create user
-	user, err := h.userService.CreateUser(&req)
+	user, err := h.userService.CreateUser(c.Request().Context(), &req)
 	if err != nil {
 		statusCode := http.StatusBadRequest
 		if err.Error() == "username already exists" || err.Error() == "email already exists" {
@@ -339,7 +356,7 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 	}
 
 	// Delete user
-	if err := h.userService.DeleteUser(uint(id)); err != nil {
+	if err := h.userService.DeleteUser(c.Request().Context(), uint(id)); err != nil {
 		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
 			Message: "Failed to delete user",
 			Error:   err.Error(),
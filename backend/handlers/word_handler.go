@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -30,6 +31,7 @@ func NewWordHandler(wordService services.WordService) *WordHandler {
 // @Success 201 {object} dto.WordResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Router /api/words [post]
 func (h *WordHandler) CreateWord(c echo.Context) error {
 	// Get user ID from context (set by JWT middleware)
@@ -50,8 +52,11 @@ func (h *WordHandler) CreateWord(c echo.Context) error {
 	}
 
 	// Create word
-	word, err := h.wordService.CreateWord(&req, userID)
+	word, err := h.wordService.CreateWord(&req, userID, hasRole(c, "admin"))
 	if err != nil {
+		if errors.Is(err, services.ErrRelatedWordForbidden) {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
@@ -94,6 +99,7 @@ func (h *WordHandler) GetWord(c echo.Context) error {
 // @Success 200 {object} dto.WordResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Router /api/words/{id} [put]
 func (h *WordHandler) UpdateWord(c echo.Context) error {
@@ -121,10 +127,13 @@ func (h *WordHandler) UpdateWord(c echo.Context) error {
 	}
 
 	// Update word
-	word, err := h.wordService.UpdateWord(uint(id), &req, userID)
+	word, err := h.wordService.UpdateWord(uint(id), &req, userID, hasRole(c, "admin"))
 	if err != nil {
-		if err.Error() == "word not found" {
+		switch {
+		case err.Error() == "word not found":
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrRelatedWordForbidden):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
 		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -140,6 +149,7 @@ func (h *WordHandler) UpdateWord(c echo.Context) error {
 // @Param id path int true "Word ID"
 // @Success 204 "No Content"
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Router /api/words/{id} [delete]
 func (h *WordHandler) DeleteWord(c echo.Context) error {
@@ -156,10 +166,13 @@ func (h *WordHandler) DeleteWord(c echo.Context) error {
 	}
 
 	// Delete word
-	err = h.wordService.DeleteWord(uint(id), userID)
+	err = h.wordService.DeleteWord(uint(id), userID, hasRole(c, "admin"))
 	if err != nil {
-		if err.Error() == "word not found" {
+		switch {
+		case err.Error() == "word not found":
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrRelatedWordForbidden):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
 		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -167,6 +180,60 @@ func (h *WordHandler) DeleteWord(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// DeriveTranslation handles POST /api/words/:id/translations/derive
+// @Summary Derive a translation in another Chinese script
+// @Description Convert a word's translation, with its measure word and example sentences, between two Chinese languages by their script settings (zh-HK to zh-CN by default), copying the romanization when both use the same scheme and deriving pinyin otherwise
+// @Tags words
+// @Accept json
+// @Produce json
+// @Param id path int true "Word ID"
+// @Param request body dto.DeriveTranslationRequest true "Languages to convert between"
+// @Success 200 {object} dto.WordResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/words/{id}/translations/derive [post]
+func (h *WordHandler) DeriveTranslation(c echo.Context) error {
+	// Get user ID from context (set by JWT middleware)
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "User ID not found in context")
+	}
+
+	// Parse ID
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid word ID")
+	}
+
+	// Parse request
+	var req dto.DeriveTranslationRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	word, err := h.wordService.DeriveTranslation(uint(id), &req, userID)
+	if err != nil {
+		switch {
+		case err.Error() == "word not found":
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrTranslationExists):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		case errors.Is(err, services.ErrCharacterDataUnavailable):
+			return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, word)
+}
+
 // ListWords handles GET /api/words
 // @Summary List words
 // @Description List words with filtering and pagination
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/services"
+)
+
+type WordImportHandler struct {
+	importService *services.WordImportService
+}
+
+func NewWordImportHandler(importService *services.WordImportService) *WordImportHandler {
+	return &WordImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportWords godoc
+// @Summary Bulk import words
+// @Description Create or update words and translations from a CSV or XLSX file. Rows are matched by ID or base word, so re-importing a file is safe.
+// @Tags words
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param mapping formData string false "JSON column mapping (dto.WordImportMapping); defaults to the export headers"
+// @Param dryRun formData bool false "Validate and report without saving"
+// @Success 200 {object} dto.WordImportResult
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /words/import [post]
+func (h *WordImportHandler) ImportWords(c echo.Context) error {
+	userID, ok := c.Get("userId").(uint)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "User ID not found in context",
+			Error:   "unauthorized",
+		})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "No file provided",
+			Error:   err.Error(),
+		})
+	}
+
+	var mapping *dto.WordImportMapping
+	if raw := c.FormValue("mapping"); raw != "" {
+		mapping = &dto.WordImportMapping{}
+		if err := json.Unmarshal([]byte(raw), mapping); err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Message: "Invalid column mapping",
+				Error:   err.Error(),
+			})
+		}
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "Failed to open uploaded file",
+			Error:   err.Error(),
+		})
+	}
+	defer src.Close()
+
+	dryRun := c.FormValue("dryRun") == "true"
+	result, err := h.importService.ImportWords(src, file.Size, file.Filename, mapping, userID, dryRun)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Failed to import words",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ExportWords godoc
+// @Summary Export words
+// @Description Download the words matching the filter as CSV or XLSX, in the layout accepted by the import
+// @Tags words
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param search query string false "Search term"
+// @Param languageId query int false "Filter by language ID"
+// @Param createdBy query int false "Filter by creator user ID"
+// @Param format query string false "csv (default) or xlsx"
+// @Param languages query string false "Comma-separated language codes to include as columns"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /words/export [get]
+func (h *WordImportHandler) ExportWords(c echo.Context) error {
+	var params dto.WordExportParams
+	if err := c.Bind(&params); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := h.importService.ExportWords(&params, &buf); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "Failed to export words",
+			Error:   err.Error(),
+		})
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	filename := "words.csv"
+	if strings.EqualFold(params.Format, services.WordImportFormatXLSX) {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		filename = "words.xlsx"
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
@@ -0,0 +1,140 @@
+package hanzi
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Conversion converts text from one Chinese script to another. The names and tables are
+// OpenCC's: s is simplified, t is traditional and hk is traditional with Hong Kong
+// character forms.
+type Conversion string
+
+const (
+	SimplifiedToTraditional Conversion = "s2t"
+	TraditionalToSimplified Conversion = "t2s"
+	SimplifiedToHongKong    Conversion = "s2hk"
+	HongKongToSimplified    Conversion = "hk2s"
+	TraditionalToHongKong   Conversion = "t2hk"
+	HongKongToTraditional   Conversion = "hk2t"
+)
+
+// conversionStep converts by the longest match in its phrase table, which is optional, or
+// its character table
+type conversionStep struct {
+	phrases    string
+	characters string
+}
+
+var conversions = map[Conversion][]conversionStep{
+	SimplifiedToTraditional: {{stPhrasesFile, traditionalFile}},
+	TraditionalToSimplified: {{tsPhrasesFile, simplifiedFile}},
+	SimplifiedToHongKong:    {{stPhrasesFile, traditionalFile}, {"", hkVariantsFile}},
+	HongKongToSimplified:    {{hkRevPhrasesFile, hkVariantsRev}, {tsPhrasesFile, simplifiedFile}},
+	TraditionalToHongKong:   {{"", hkVariantsFile}},
+	HongKongToTraditional:   {{hkRevPhrasesFile, hkVariantsRev}},
+}
+
+// Valid reports whether c is a known conversion
+func (c Conversion) Valid() bool {
+	_, ok := conversions[c]
+	return ok
+}
+
+// Convert converts text the way OpenCC's configuration of the same name does: each step
+// takes the longest phrase or character found in its tables, from left to right, and
+// replaces it with its first candidate. Text without an entry is kept.
+func (d *Dataset) Convert(text string, conversion Conversion) (string, error) {
+	steps, ok := conversions[conversion]
+	if !ok {
+		return "", fmt.Errorf("unknown conversion %q", conversion)
+	}
+
+	for _, step := range steps {
+		characters, ok := d.tables[step.characters]
+		if !ok {
+			return "", fmt.Errorf("%w (%s is missing)", ErrNoData, step.characters)
+		}
+		tables := []*table{characters}
+		if phrases, ok := d.tables[step.phrases]; ok {
+			tables = []*table{phrases, characters}
+		}
+		text = convertStep(text, tables)
+	}
+	return text, nil
+}
+
+func convertStep(text string, tables []*table) string {
+	maxLen := 0
+	for _, t := range tables {
+		maxLen = max(maxLen, t.maxLen)
+	}
+
+	runes := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+	for i := 0; i < len(runes); {
+		matched := false
+		for n := min(maxLen, len(runes)-i); n > 0 && !matched; n-- {
+			key := string(runes[i : i+n])
+			for _, t := range tables {
+				if candidates, ok := t.entries[key]; ok {
+					b.WriteString(candidates[0])
+					i += n
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			b.WriteRune(runes[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// fullWidthPunctuation maps Chinese punctuation to its ASCII form in pinyin
+var fullWidthPunctuation = strings.NewReplacer(
+	"，", ",", "。", ".", "、", ",", "？", "?", "！", "!", "：", ":", "；", ";",
+	"（", "(", "）", ")", "「", "\"", "」", "\"", "『", "'", "』", "'", "“", "\"", "”", "\"",
+)
+
+// Pinyin spells text in pinyin with tone marks, one syllable per character, taking each
+// character's first reading in the dictionary. Readings that depend on the word, such as
+// 行 in 银行, may need correcting. Other text is kept, and punctuation follows the
+// previous syllable.
+func (d *Dataset) Pinyin(text string) (string, error) {
+	if len(d.dictionary) == 0 {
+		return "", fmt.Errorf("%w (%s is missing)", ErrNoData, dictionaryFile)
+	}
+
+	var words []string
+	var pending strings.Builder
+	flush := func() {
+		if pending.Len() > 0 {
+			words = append(words, pending.String())
+			pending.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case IsHan(r):
+			flush()
+			reading := string(r)
+			if entry, ok := d.dictionary[r]; ok && len(entry.Pinyin) > 0 {
+				reading = entry.Pinyin[0]
+			}
+			words = append(words, reading)
+		case unicode.IsSpace(r):
+			flush()
+		case unicode.IsPunct(r) && pending.Len() == 0 && len(words) > 0:
+			words[len(words)-1] += fullWidthPunctuation.Replace(string(r))
+		default:
+			pending.WriteRune(r)
+		}
+	}
+	flush()
+	return strings.Join(words, " "), nil
+}
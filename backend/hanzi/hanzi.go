@@ -0,0 +1,332 @@
+// Package hanzi serves per-character data for Chinese: definitions, pinyin, radicals and
+// decompositions and stroke order from Make Me a Hanzi, and traditional/simplified variants
+// and script conversion from OpenCC. The data files are embedded from data/ at build time (see
+// setup-hanzi-data.sh) or read from a directory at runtime.
+package hanzi
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed data
+var embedded embed.FS
+
+// Data files, in the layout of their upstream projects
+const (
+	dictionaryFile   = "dictionary.txt"           // Make Me a Hanzi, one JSON object per line
+	graphicsFile     = "graphics.txt"             // Make Me a Hanzi, one JSON object per line
+	traditionalFile  = "STCharacters.txt"         // OpenCC, simplified -> traditional
+	simplifiedFile   = "TSCharacters.txt"         // OpenCC, traditional -> simplified
+	stPhrasesFile    = "STPhrases.txt"            // OpenCC, simplified -> traditional phrases
+	tsPhrasesFile    = "TSPhrases.txt"            // OpenCC, traditional -> simplified phrases
+	hkVariantsFile   = "HKVariants.txt"           // OpenCC, traditional -> Hong Kong variants
+	hkRevPhrasesFile = "HKVariantsRevPhrases.txt" // OpenCC, Hong Kong -> traditional phrases
+
+	// hkVariantsRev is HKVariants.txt reversed, which OpenCC generates at build time
+	hkVariantsRev = "HKVariantsRev"
+)
+
+// openCCFiles are the OpenCC tables read by Load
+var openCCFiles = []string{traditionalFile, simplifiedFile, stPhrasesFile, tsPhrasesFile, hkVariantsFile, hkRevPhrasesFile}
+
+// ErrNoData is returned when none of the data files are installed
+var ErrNoData = errors.New("character data is not installed; run setup-hanzi-data.sh and rebuild, or set HANZI_DATA_DIR")
+
+// Etymology explains how a character is formed
+type Etymology struct {
+	Type     string `json:"type"` // ideographic, pictographic or pictophonetic
+	Hint     string `json:"hint,omitempty"`
+	Phonetic string `json:"phonetic,omitempty"` // pictophonetic characters
+	Semantic string `json:"semantic,omitempty"`
+}
+
+// Character is everything known about one character. Fields are empty when the
+// character is missing from a data file.
+type Character struct {
+	Character     string
+	Definition    string
+	Pinyin        []string
+	Radical       string
+	Decomposition string // ideographic description sequence, e.g. ⿰氵每
+	Etymology     *Etymology
+	StrokeCount   int // 0 without stroke data
+	// Strokes are SVG paths in writing order on a 1024x1024 grid with the y axis pointing
+	// up; Medians are the points along each stroke, for animating and checking handwriting
+	Strokes     []string
+	Medians     [][][2]int
+	Traditional []string // as listed by OpenCC, may include the character itself
+	Simplified  []string
+}
+
+type dictionaryEntry struct {
+	Character     string     `json:"character"`
+	Definition    string     `json:"definition"`
+	Pinyin        []string   `json:"pinyin"`
+	Decomposition string     `json:"decomposition"`
+	Etymology     *Etymology `json:"etymology"`
+	Radical       string     `json:"radical"`
+}
+
+type graphicsEntry struct {
+	Character string     `json:"character"`
+	Strokes   []string   `json:"strokes"`
+	Medians   [][][2]int `json:"medians"`
+}
+
+// table is an OpenCC dictionary: keys of one or more characters, each with its candidates,
+// the preferred one first
+type table struct {
+	entries map[string][]string
+	maxLen  int // longest key in runes
+}
+
+func (t *table) add(key string, candidates []string) {
+	t.entries[key] = candidates
+	if n := utf8.RuneCountInString(key); n > t.maxLen {
+		t.maxLen = n
+	}
+}
+
+// Dataset is loaded character data
+type Dataset struct {
+	dictionary map[rune]*dictionaryEntry
+	graphics   map[rune][]byte   // raw lines, decoded on lookup
+	tables     map[string]*table // OpenCC tables by file name
+}
+
+// Load reads whichever data files exist in fsys. Missing files leave their part of the
+// data empty; ErrNoData is returned when there are none.
+func Load(fsys fs.FS) (*Dataset, error) {
+	d := &Dataset{
+		dictionary: make(map[rune]*dictionaryEntry),
+		graphics:   make(map[rune][]byte),
+		tables:     make(map[string]*table),
+	}
+
+	found := 0
+	load := func(name string, parse func([]byte) error) error {
+		data, err := fs.ReadFile(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found++
+		if err := parse(data); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+
+	if err := load(dictionaryFile, d.parseDictionary); err != nil {
+		return nil, err
+	}
+	if err := load(graphicsFile, d.parseGraphics); err != nil {
+		return nil, err
+	}
+	for _, name := range openCCFiles {
+		if err := load(name, d.tableParser(name)); err != nil {
+			return nil, err
+		}
+	}
+	if found == 0 {
+		return nil, ErrNoData
+	}
+
+	if variants, ok := d.tables[hkVariantsFile]; ok {
+		rev := &table{entries: make(map[string][]string)}
+		for from, candidates := range variants.entries {
+			for _, to := range candidates {
+				if _, ok := rev.entries[to]; !ok && to != from {
+					rev.add(to, []string{from})
+				}
+			}
+		}
+		d.tables[hkVariantsRev] = rev
+	}
+	return d, nil
+}
+
+func (d *Dataset) parseDictionary(data []byte) error {
+	return eachLine(data, func(line []byte) error {
+		var entry dictionaryEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		if r, ok := singleRune(entry.Character); ok {
+			d.dictionary[r] = &entry
+		}
+		return nil
+	})
+}
+
+func (d *Dataset) parseGraphics(data []byte) error {
+	return eachLine(data, func(line []byte) error {
+		var entry struct {
+			Character string `json:"character"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		if r, ok := singleRune(entry.Character); ok {
+			d.graphics[r] = line
+		}
+		return nil
+	})
+}
+
+// tableParser reads an OpenCC table: a character or phrase, a tab, and its candidates
+// separated by spaces
+func (d *Dataset) tableParser(name string) func([]byte) error {
+	return func(data []byte) error {
+		t := &table{entries: make(map[string][]string)}
+		err := eachLine(data, func(line []byte) error {
+			from, to, ok := strings.Cut(string(line), "\t")
+			if candidates := strings.Fields(to); ok && from != "" && len(candidates) > 0 {
+				t.add(from, candidates)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		d.tables[name] = t
+		return nil
+	}
+}
+
+func eachLine(data []byte, fn func(line []byte) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		// The scanner reuses its buffer, and graphics lines are kept
+		if err := fn(bytes.Clone(line)); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func singleRune(s string) (rune, bool) {
+	r, size := utf8.DecodeRuneInString(s)
+	return r, r != utf8.RuneError && size == len(s)
+}
+
+// HasStrokes reports whether the dataset includes stroke order data
+func (d *Dataset) HasStrokes() bool {
+	return len(d.graphics) > 0
+}
+
+// Lookup returns the data for a character, or false if no data file has it.
+// withStrokes also decodes the stroke paths and medians.
+func (d *Dataset) Lookup(char rune, withStrokes bool) (*Character, bool) {
+	c := &Character{Character: string(char)}
+	found := false
+
+	if entry, ok := d.dictionary[char]; ok {
+		found = true
+		c.Definition = entry.Definition
+		c.Pinyin = entry.Pinyin
+		c.Radical = entry.Radical
+		c.Decomposition = entry.Decomposition
+		c.Etymology = entry.Etymology
+	}
+	if line, ok := d.graphics[char]; ok {
+		found = true
+		var entry graphicsEntry
+		if err := json.Unmarshal(line, &entry); err == nil {
+			c.StrokeCount = len(entry.Strokes)
+			if withStrokes {
+				c.Strokes = entry.Strokes
+				c.Medians = entry.Medians
+			}
+		}
+	}
+	if variants, ok := d.variants(traditionalFile, char); ok {
+		found = true
+		c.Traditional = variants
+	}
+	if variants, ok := d.variants(simplifiedFile, char); ok {
+		found = true
+		c.Simplified = variants
+	}
+	return c, found
+}
+
+func (d *Dataset) variants(name string, char rune) ([]string, bool) {
+	t, ok := d.tables[name]
+	if !ok {
+		return nil, false
+	}
+	variants, ok := t.entries[string(char)]
+	return variants, ok
+}
+
+// IsHan reports whether r is a Chinese character
+func IsHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// HanCharacters returns the distinct Chinese characters of text in order of appearance
+func HanCharacters(text string) []rune {
+	seen := make(map[rune]bool)
+	var chars []rune
+	for _, r := range text {
+		if IsHan(r) && !seen[r] {
+			seen[r] = true
+			chars = append(chars, r)
+		}
+	}
+	return chars
+}
+
+// Source loads a dataset on first use, from dir or, when dir is empty, from the data
+// embedded at build time
+type Source struct {
+	dir  string
+	once sync.Once
+	data *Dataset
+	err  error
+}
+
+// NewSource returns a source reading dir, or the embedded data if dir is empty
+func NewSource(dir string) *Source {
+	return &Source{dir: dir}
+}
+
+// Dataset returns the loaded dataset, loading it on the first call
+func (s *Source) Dataset() (*Dataset, error) {
+	s.once.Do(func() {
+		var fsys fs.FS
+		if s.dir != "" {
+			fsys = os.DirFS(s.dir)
+		} else {
+			sub, err := fs.Sub(embedded, "data")
+			if err != nil {
+				s.err = err
+				return
+			}
+			fsys = sub
+		}
+		s.data, s.err = Load(fsys)
+	})
+	return s.data, s.err
+}
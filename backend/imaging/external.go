@@ -0,0 +1,77 @@
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// ExternalEncoder encodes images with a command-line encoder, for formats the Go standard
+// library can't write. The image is handed over as a temporary PNG.
+type ExternalEncoder struct {
+	Format string
+	path   string
+	args   func(in, out string) []string
+}
+
+// NewWebPEncoder returns a WebP encoder running libwebp's cwebp, or nil if command is
+// empty, "off" or not on PATH
+func NewWebPEncoder(command string) *ExternalEncoder {
+	return newExternalEncoder(FormatWebP, command, func(in, out string) []string {
+		return []string{"-quiet", "-q", "80", "-metadata", "none", in, "-o", out}
+	})
+}
+
+// NewAVIFEncoder returns an AVIF encoder running libavif's avifenc, or nil if command is
+// empty, "off" or not on PATH
+func NewAVIFEncoder(command string) *ExternalEncoder {
+	return newExternalEncoder(FormatAVIF, command, func(in, out string) []string {
+		return []string{"--speed", "6", "--min", "20", "--max", "40", in, out}
+	})
+}
+
+func newExternalEncoder(format, command string, args func(in, out string) []string) *ExternalEncoder {
+	if command == "" || command == "off" {
+		return nil
+	}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return nil
+	}
+	return &ExternalEncoder{Format: format, path: path, args: args}
+}
+
+// Encode encodes img, killing the encoder if ctx ends first
+func (e *ExternalEncoder) Encode(ctx context.Context, img image.Image) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "learnspeak-encode-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in.png")
+	out := filepath.Join(dir, "out."+e.Format)
+
+	f, err := os.Create(in)
+	if err != nil {
+		return nil, err
+	}
+	// Fast compression: the file only lives until the encoder has read it
+	err = (&png.Encoder{CompressionLevel: png.BestSpeed}).Encode(f, img)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write encoder input: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, e.path, e.args(in, out)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", filepath.Base(e.path), err, output)
+	}
+	return os.ReadFile(out)
+}
@@ -0,0 +1,124 @@
+// Package imaging decodes, cleans, resizes and re-encodes images for the asset pipeline
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register decoders
+	"image/jpeg"
+	"image/png"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Formats produced by the pipeline
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+	FormatWebP = "webp"
+	FormatAVIF = "avif"
+)
+
+// ErrInvalidImage is returned by Decode for data that isn't a supported, intact image
+var ErrInvalidImage = errors.New("invalid image")
+
+// MaxPixels caps the dimensions Decode accepts. The header is checked before decoding, so a
+// small file declaring a huge canvas can't make the decoder allocate gigabytes.
+const MaxPixels = 40_000_000
+
+// Decoded is an image decoded with its EXIF orientation already applied
+type Decoded struct {
+	Image       image.Image
+	Format      string // jpeg, png, gif or webp
+	Orientation int    // EXIF orientation of the source, 1 when absent
+}
+
+// Decode decodes a JPEG, PNG, GIF or WebP image and rotates it upright
+func Decode(data []byte) (*Decoded, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, fmt.Errorf("%w: unsupported dimensions %dx%d (at most %d pixels)", ErrInvalidImage, config.Width, config.Height, MaxPixels)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	orientation := 1
+	if format == FormatJPEG {
+		orientation = jpegOrientation(data)
+		img = applyOrientation(img, orientation)
+	}
+
+	return &Decoded{Image: img, Format: format, Orientation: orientation}, nil
+}
+
+// Fit scales img down so neither side exceeds maxSize, keeping the aspect ratio.
+// Images that already fit are returned as is.
+func Fit(img image.Image, maxSize int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSize && h <= maxSize {
+		return img
+	}
+
+	if w >= h {
+		h = max(1, h*maxSize/w)
+		w = maxSize
+	} else {
+		w = max(1, w*maxSize/h)
+		h = maxSize
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// IsOpaque reports whether img has no transparent pixels
+func IsOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// Encode encodes img as JPEG or PNG. Metadata is never written.
+func Encode(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatJPEG:
+		err = jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: 85})
+	case FormatPNG:
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// flatten composites img over white, for formats and algorithms without transparency
+func flatten(img image.Image) image.Image {
+	if IsOpaque(img) {
+		return img
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/database"
+	"dannyswat/learnspeak/repositories"
+	"dannyswat/learnspeak/services"
+)
+
+// runExportJourneyCommand handles "learnspeak export-journey --id N [--out FILE]"
+func runExportJourneyCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("export-journey", flag.ContinueOnError)
+	id := fs.Uint("id", 0, "journey ID (required)")
+	out := fs.String("out", "", "output file (default journey-<id>.zip)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return fmt.Errorf("--id is required")
+	}
+	if *out == "" {
+		*out = fmt.Sprintf("journey-%d.zip", *id)
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+
+	err = newCoursePackageService(cfg).ExportJourney(*id, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*out)
+		return err
+	}
+
+	fmt.Printf("Exported journey %d to %s\n", *id, *out)
+	return nil
+}
+
+// runImportJourneyCommand handles "learnspeak import-journey --file FILE --user USERNAME"
+func runImportJourneyCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import-journey", flag.ContinueOnError)
+	file := fs.String("file", "", "course package zip (required)")
+	username := fs.String("user", "admin", "user who owns the imported content")
+	preserveCreators := fs.Bool("preserve-creators", false, "keep original creators that exist on this instance")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("--file is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	user, err := repositories.NewUserRepository(database.DB).GetByUsername(*username)
+	if err != nil {
+		return fmt.Errorf("user %q not found", *username)
+	}
+
+	result, err := newCoursePackageService(cfg).ImportJourney(context.Background(), f, info.Size(), user.ID, services.ImportOptions{
+		PreserveCreators: *preserveCreators,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported journey %d: %d topics, %d words created, %d words reused, %d quizzes, %d conversations, %d media files\n",
+		result.JourneyID, result.TopicsCreated, result.WordsCreated, result.WordsReused,
+		result.QuizzesCreated, result.ConversationsCreated, result.MediaImported)
+	for _, warning := range result.Warnings {
+		fmt.Printf("warning: %s\n", warning)
+	}
+	return nil
+}
+
+// newCoursePackageService creates the course package service on the connected database
+func newCoursePackageService(cfg *config.Config) *services.CoursePackageService {
+	db := database.DB
+	revisions := services.NewRevisionService(
+		repositories.NewRevisionRepository(db),
+		repositories.NewWordRepository(db),
+		repositories.NewTopicRepository(db),
+		repositories.NewConversationRepository(db),
+		repositories.NewQuizRepository(db),
+	)
+	assets := services.NewAssetService(repositories.NewAssetRepository(db), cfg, cfg.UploadDir)
+	return services.NewCoursePackageService(db, cfg.UploadDir, revisions, assets)
+}
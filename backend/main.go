@@ -2,104 +2,77 @@ package main
 
 import (
 	"dannyswat/learnspeak/config"
-	"dannyswat/learnspeak/database"
-	"dannyswat/learnspeak/routes"
 	"dannyswat/learnspeak/utils"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"os"
-	"strconv"
-
-	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
 )
 
+const usage = `Usage: learnspeak [command] [flags]
+
+Commands:
+  serve                      Migrate, seed and start the API server (default)
+  migrate up|down|status     Manage database migrations
+  seed                       Create default roles, languages and the admin user
+  create-user                Create a user with a role
+  reset-password             Set a new password for a user
+  cache prune                Remove unused TTS, image and translation cache files
+  assets process|gc          Record files missing from the asset library, or remove unused uploads
+  export-journey             Export a journey as a course package
+  import-journey             Import a course package as a new journey
+  regenerate-tts             Regenerate TTS audio for a topic
+
+Run "learnspeak <command> -h" for the flags of a command.
+`
+
 func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
-	log.Printf("Starting LearnSpeak API Server on port %s (Environment: %s)\n", cfg.Port, cfg.Environment)
-
-	// Connect to database
-	if err := database.Connect(cfg); err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-	}
-	defer database.Close()
+	utils.SetupLogger(cfg.Environment, cfg.LogLevel, cfg.LogFormat)
 
-	// Run migrations
-	if err := database.Migrate(); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	command, args := "serve", []string{}
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
 	}
 
-	// Seed default roles
-	if err := database.SeedDefaultRoles(); err != nil {
-		log.Fatalf("Failed to seed default roles: %v", err)
+	var err error
+	switch command {
+	case "serve":
+		err = runServeCommand(cfg, args)
+	case "migrate":
+		err = runMigrateCommand(cfg, args)
+	case "seed":
+		err = runSeedCommand(cfg, args)
+	case "create-user":
+		err = runCreateUserCommand(cfg, args)
+	case "reset-password":
+		err = runResetPasswordCommand(cfg, args)
+	case "cache":
+		err = runCacheCommand(cfg, args)
+	case "assets":
+		err = runAssetsCommand(cfg, args)
+	case "export-journey":
+		err = runExportJourneyCommand(cfg, args)
+	case "import-journey":
+		err = runImportJourneyCommand(cfg, args)
+	case "regenerate-tts":
+		err = runRegenerateTTSCommand(cfg, args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		slog.Error("Unknown command", "command", command)
+		os.Exit(2)
 	}
 
-	// Seed admin user
-	if err := database.SeedAdminUser(); err != nil {
-		log.Fatalf("Failed to seed admin user: %v", err)
-	}
-
-	// Initialize Echo
-	e := echo.New()
-
-	// Register custom validator
-	e.Validator = utils.NewValidator()
-
-	// Middleware
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
-
-	// CORS middleware - only needed for development with separate frontend server
-	if cfg.CORSAllowedOrigins != "" {
-		origins := []string{}
-		for _, origin := range splitAndTrim(cfg.CORSAllowedOrigins, ",") {
-			if origin != "" {
-				origins = append(origins, origin)
-			}
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
 		}
-		if len(origins) > 0 {
-			e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-				AllowOrigins: origins,
-				AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE, echo.PATCH},
-				AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
-			}))
-			log.Printf("CORS enabled for origins: %v\n", origins)
-		}
-	}
-
-	// Request size limit
-	e.Use(middleware.BodyLimit(strconv.FormatInt(cfg.MaxUploadSize, 10)))
-
-	// Create uploads directory
-	uploadsDir := "./uploads"
-	if err := os.MkdirAll(uploadsDir, 0755); err != nil {
-		log.Printf("Warning: Failed to create uploads directory: %v", err)
-	}
-
-	// Serve uploaded files
-	e.Static("/uploads", uploadsDir)
-
-	// Setup routes
-	routes.SetupRoutes(e, cfg, uploadsDir)
-
-	// Serve static files from frontend build (production)
-	// The frontend build should be placed in ./frontend
-	staticDir := "./frontend"
-	if _, err := os.Stat(staticDir); err == nil {
-		e.Use(middleware.StaticWithConfig(middleware.StaticConfig{
-			Root:   staticDir,
-			Index:  "index.html",
-			Browse: false,
-			HTML5:  true, // Support client-side routing
-		}))
-		log.Printf("Serving static files from %s\n", staticDir)
-	} else {
-		log.Printf("Static files directory not found: %s (running in API-only mode)\n", staticDir)
-	}
-
-	// Start server
-	if err := e.Start(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		slog.Error("Command failed", "command", command, "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -0,0 +1,77 @@
+// Package metrics defines the Prometheus metrics exposed on /metrics
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// HTTPRequestDuration records request latency by route template, so /words/1 and /words/2 share a series
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "learnspeak_http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration records GORM statement timings
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "learnspeak_db_query_duration_seconds",
+		Help:    "Database query latency by operation and table.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	// CacheLookups counts hits and misses of the TTS, translation and image caches
+	CacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "learnspeak_cache_lookups_total",
+		Help: "Cache lookups by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	// ProviderCalls counts calls to external AI providers
+	ProviderCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "learnspeak_provider_calls_total",
+		Help: "External provider calls by provider, operation and status (success or failure).",
+	}, []string{"provider", "operation", "status"})
+
+	// ProviderCallDuration records external provider latency
+	ProviderCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "learnspeak_provider_call_duration_seconds",
+		Help:    "External provider call latency by provider and operation.",
+		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"provider", "operation"})
+)
+
+// Cache names used as the "cache" label
+const (
+	CacheTTS         = "tts"
+	CacheTranslation = "translation"
+	CacheImage       = "image"
+)
+
+// ObserveCacheLookup records a cache hit or miss
+func ObserveCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveProviderCall records the outcome and latency of an external provider call started at start
+func ObserveProviderCall(provider, operation string, start time.Time, err error) {
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	ProviderCalls.WithLabelValues(provider, operation, status).Inc()
+	ProviderCallDuration.WithLabelValues(provider, operation).Observe(time.Since(start).Seconds())
+}
+
+// Handler serves the registered metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -43,6 +43,10 @@ func JWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 		c.Set("username", claims.Username)
 		c.Set("roles", claims.Roles)
 
+		// Services only see the request context, e.g. to attribute AI usage
+		req := c.Request()
+		c.SetRequest(req.WithContext(utils.ContextWithUserID(req.Context(), claims.UserID)))
+
 		return next(c)
 	}
 }
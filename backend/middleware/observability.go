@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/metrics"
+	"dannyswat/learnspeak/utils"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// RequestID assigns an X-Request-ID (or keeps the caller's) and stores it in the request
+// context, so services logging with that context include it
+func RequestID() echo.MiddlewareFunc {
+	return echomiddleware.RequestIDWithConfig(echomiddleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, id string) {
+			req := c.Request()
+			c.SetRequest(req.WithContext(utils.ContextWithRequestID(req.Context(), id)))
+		},
+	})
+}
+
+// ClientInfo stores the client IP (as resolved by the server's IP extractor) and user agent
+// in the request context for the audit log
+func ClientInfo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(utils.ContextWithClient(req.Context(), utils.Client{
+				IP:        c.RealIP(),
+				UserAgent: req.UserAgent(),
+			})))
+			return next(c)
+		}
+	}
+}
+
+// RequestLogger logs one structured line per request. Server errors are logged at error level.
+func RequestLogger() echo.MiddlewareFunc {
+	return echomiddleware.RequestLoggerWithConfig(echomiddleware.RequestLoggerConfig{
+		LogMethod:    true,
+		LogURI:       true,
+		LogRoutePath: true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogRemoteIP:  true,
+		LogError:     true,
+		HandleError:  true,
+		LogValuesFunc: func(c echo.Context, v echomiddleware.RequestLoggerValues) error {
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("uri", v.URI),
+				slog.String("route", v.RoutePath),
+				slog.Int("status", v.Status),
+				slog.Float64("latency_ms", float64(v.Latency.Microseconds())/1000),
+				slog.String("remote_ip", v.RemoteIP),
+			}
+			if userID, ok := c.Get("userId").(uint); ok {
+				attrs = append(attrs, slog.Uint64("user_id", uint64(userID)))
+			}
+
+			level := slog.LevelInfo
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+				if v.Status >= http.StatusInternalServerError {
+					level = slog.LevelError
+				}
+			}
+			slog.LogAttrs(c.Request().Context(), level, "request", attrs...)
+			return nil
+		},
+	})
+}
+
+// Metrics records request latency by route template
+func Metrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// Let Echo write the error response so the recorded status is the one sent
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			metrics.HTTPRequestDuration.
+				WithLabelValues(c.Request().Method, route, strconv.Itoa(c.Response().Status)).
+				Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
+
+// MetricsAuth requires "Authorization: Bearer <token>" when token is set
+func MetricsAuth(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token == "" {
+				return next(c)
+			}
+			expected := "Bearer " + token
+			if subtle.ConstantTimeCompare([]byte(c.Request().Header.Get("Authorization")), []byte(expected)) != 1 {
+				return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+					Error:   "unauthorized",
+					Message: "Invalid metrics token",
+				})
+			}
+			return next(c)
+		}
+	}
+}
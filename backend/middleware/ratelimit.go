@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitByIP limits requests per client IP. scope separates counters of different
+// limits, e.g. "login" and "register".
+func RateLimitByIP(limiter *ratelimit.Limiter, scope string, limit ratelimit.Limit) echo.MiddlewareFunc {
+	return rateLimit(limiter, limit, func(c echo.Context) string {
+		return scope + ":ip:" + c.RealIP()
+	})
+}
+
+// RateLimitByUser limits requests per authenticated user, falling back to the client IP.
+// It must run after JWTMiddleware.
+func RateLimitByUser(limiter *ratelimit.Limiter, scope string, limit ratelimit.Limit) echo.MiddlewareFunc {
+	return rateLimit(limiter, limit, func(c echo.Context) string {
+		if userID, ok := c.Get("userId").(uint); ok {
+			return scope + ":user:" + strconv.FormatUint(uint64(userID), 10)
+		}
+		return scope + ":ip:" + c.RealIP()
+	})
+}
+
+func rateLimit(limiter *ratelimit.Limiter, limit ratelimit.Limit, key func(c echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if limiter == nil || !limit.Enabled() {
+			return next
+		}
+		return func(c echo.Context) error {
+			result := limiter.Allow(c.Request().Context(), key(c), limit)
+
+			header := c.Response().Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			if !result.Allowed {
+				header.Set("Retry-After", ratelimit.RetryAfterHeader(result.RetryAfter()))
+				return c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+					Error:   "rate_limited",
+					Message: "Too many requests, please try again later",
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// ProxyIPExtractor returns the client IP from X-Forwarded-For or X-Real-IP, but only
+// when the direct peer is a loopback or private address such as a local reverse proxy.
+// Clients connecting directly can't spoof their IP to dodge rate limits.
+func ProxyIPExtractor() echo.IPExtractor {
+	fromXFF := echo.ExtractIPFromXFFHeader()
+	fromRealIP := echo.ExtractIPFromRealIPHeader()
+	return func(req *http.Request) string {
+		if req.Header.Get(echo.HeaderXForwardedFor) != "" {
+			return fromXFF(req)
+		}
+		return fromRealIP(req)
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// DefaultContentSecurityPolicy fits the SPA served from ./frontend: bundled scripts only,
+// Google Fonts, images from AI providers over https, and recorded audio as blob: URLs
+const DefaultContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self'; " +
+	"style-src 'self' 'unsafe-inline' https://fonts.googleapis.com; " +
+	"font-src 'self' data: https://fonts.gstatic.com; " +
+	"img-src 'self' data: blob: https:; " +
+	"media-src 'self' data: blob: https:; " +
+	"connect-src 'self'; " +
+	"object-src 'none'; " +
+	"base-uri 'self'; " +
+	"form-action 'self'; " +
+	"frame-ancestors 'none'"
+
+// SecurityHeaders sets CSP, frame, content-type and referrer headers on every response.
+// HSTS is only sent over HTTPS (directly or via X-Forwarded-Proto), so local HTTP keeps working.
+func SecurityHeaders(contentSecurityPolicy string, enableHSTS bool) echo.MiddlewareFunc {
+	if contentSecurityPolicy == "" {
+		contentSecurityPolicy = DefaultContentSecurityPolicy
+	}
+
+	hstsMaxAge := 0
+	if enableHSTS {
+		hstsMaxAge = 31536000 // one year
+	}
+
+	secure := echomiddleware.SecureWithConfig(echomiddleware.SecureConfig{
+		XSSProtection:         "0", // legacy filter; CSP replaces it and it can introduce leaks
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         "DENY",
+		HSTSMaxAge:            hstsMaxAge,
+		ContentSecurityPolicy: contentSecurityPolicy,
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		h := secure(next)
+		return func(c echo.Context) error {
+			// The microphone is used for pronunciation recording
+			c.Response().Header().Set("Permissions-Policy", "camera=(), geolocation=(), microphone=(self)")
+			return h(c)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/database"
+)
+
+const migrateUsage = `Usage: learnspeak migrate <command> [flags]
+
+Commands:
+  up [--to VERSION]   Apply pending migrations (up to VERSION if given)
+  down [--steps N]    Roll back the last N applied migrations (default 1)
+  status              List migrations and whether they are applied
+`
+
+// runMigrateCommand handles "learnspeak migrate up|down|status"
+func runMigrateCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return fmt.Errorf("missing migrate command")
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	switch args[0] {
+	case "up":
+		fs := flag.NewFlagSet("migrate up", flag.ContinueOnError)
+		to := fs.String("to", "", "apply migrations up to and including this version")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		applied, err := database.Registry.Up(database.DB, *to)
+		for _, m := range applied {
+			fmt.Printf("applied  %s %s\n", m.Version(), m.Name())
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+		return nil
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		rolledBack, err := database.Registry.Down(database.DB, *steps)
+		for _, m := range rolledBack {
+			fmt.Printf("reverted %s %s\n", m.Version(), m.Name())
+		}
+		if err != nil {
+			return err
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("No applied migrations")
+		}
+		return nil
+
+	case "status":
+		statuses, err := database.Registry.Status(database.DB)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", ""
+			if s.Applied {
+				state = "applied"
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Missing {
+				state = "applied (unknown to this build)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
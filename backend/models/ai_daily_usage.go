@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AIDailyUsage counts a user's paid AI calls of one category on one UTC day.
+// Rows are only ever incremented or refunded, never created per call.
+type AIDailyUsage struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"userId" gorm:"not null;uniqueIndex:idx_ai_daily_usage_user_date_category"`
+	UsageDate time.Time `json:"usageDate" gorm:"type:date;not null;uniqueIndex:idx_ai_daily_usage_user_date_category;index"`
+	Category  string    `json:"category" gorm:"size:20;not null;uniqueIndex:idx_ai_daily_usage_user_date_category"` // image, translation, tts
+	Count     int       `json:"count" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Relations
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for AIDailyUsage
+func (AIDailyUsage) TableName() string {
+	return "ai_daily_usage"
+}
+
+// AI quota categories
+const (
+	AIUsageImage       = "image"
+	AIUsageTranslation = "translation"
+	AIUsageTTS         = "tts"
+)
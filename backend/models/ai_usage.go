@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// AIUsage is one call to a paid AI provider, or a cache hit that avoided one
+type AIUsage struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	UserID        *uint     `json:"userId" gorm:"index:idx_ai_usage_user_created"` // nil for CLI and background jobs
+	Provider      string    `json:"provider" gorm:"size:50;not null"`              // azure-tts, azure-translator, azure-openai, ideogram
+	Operation     string    `json:"operation" gorm:"size:50;not null"`             // synthesize, translate, dictionary, image
+	Characters    int       `json:"characters" gorm:"not null;default:0"`
+	Images        int       `json:"images" gorm:"not null;default:0"`
+	EstimatedCost float64   `json:"estimatedCost" gorm:"type:numeric(12,6);not null;default:0"` // USD; 0 for cache hits
+	CacheHit      bool      `json:"cacheHit" gorm:"not null;default:false"`
+	CreatedAt     time.Time `json:"createdAt" gorm:"index:idx_ai_usage_user_created;index"`
+
+	// Relations
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+}
+
+// TableName specifies the table name for AIUsage
+func (AIUsage) TableName() string {
+	return "ai_usage"
+}
+
+// AI providers and operations recorded in the usage ledger
+const (
+	AIProviderAzureTTS        = "azure-tts"
+	AIProviderAzureTranslator = "azure-translator"
+	AIProviderAzureOpenAI     = "azure-openai"
+	AIProviderIdeogram        = "ideogram"
+	AIProviderOpenAI          = "openai"
+	AIProviderStableDiffusion = "stable-diffusion"
+	AIProviderPlaceholder     = "placeholder"
+
+	AIOperationSynthesize = "synthesize"
+	AIOperationTranslate  = "translate"
+	AIOperationDictionary = "dictionary"
+	AIOperationImage      = "image"
+)
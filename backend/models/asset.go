@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// Asset is an image or audio file stored under the upload directory, with its metadata and variants
+type Asset struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	URL          string    `json:"url" gorm:"size:500;not null;uniqueIndex"`     // /uploads/... path the file is served from
+	Kind         string    `json:"kind" gorm:"size:10;not null;default:'image'"` // image or audio
+	Source       string    `json:"source" gorm:"size:20;not null;index"`         // upload, profile, generated or audio
+	Format       string    `json:"format" gorm:"size:10;not null"`               // jpeg, png, gif, webp or mp3
+	Width        int       `json:"width" gorm:"not null"`
+	Height       int       `json:"height" gorm:"not null"`
+	Bytes        int64     `json:"bytes" gorm:"not null"`
+	Blurhash     string    `json:"blurhash" gorm:"size:100"`
+	DurationMs   int64     `json:"durationMs" gorm:"not null;default:0"` // audio only
+	SampleRate   int       `json:"sampleRate" gorm:"not null;default:0"` // audio only, Hz
+	Channels     int       `json:"channels" gorm:"not null;default:0"`   // audio only
+	SHA256       string    `json:"sha256" gorm:"size:64;index"`
+	OriginalName string    `json:"originalName" gorm:"size:255"`                    // file name as uploaded
+	Word         string    `json:"word" gorm:"size:255;not null;default:''"`        // what a generated image depicts
+	Translation  string    `json:"translation" gorm:"size:255;not null;default:''"` // of Word
+	Prompt       string    `json:"prompt" gorm:"type:text;not null;default:''"`     // sent to the image provider
+	UploadedBy   *uint     `json:"uploadedBy" gorm:"index"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+
+	// Relations
+	Uploader *User          `json:"uploader,omitempty" gorm:"foreignKey:UploadedBy;constraint:OnDelete:SET NULL"`
+	Variants []AssetVariant `json:"variants,omitempty" gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+	Tags     []AssetTag     `json:"tags,omitempty" gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for Asset
+func (Asset) TableName() string {
+	return "assets"
+}
+
+// AssetVariant is a resized or re-encoded copy of an asset
+type AssetVariant struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	AssetID uint   `json:"assetId" gorm:"not null;uniqueIndex:idx_asset_variants_asset_name_format"`
+	Name    string `json:"name" gorm:"size:20;not null;uniqueIndex:idx_asset_variants_asset_name_format"`   // thumb, medium or full
+	Format  string `json:"format" gorm:"size:10;not null;uniqueIndex:idx_asset_variants_asset_name_format"` // jpeg, png, webp or avif
+	URL     string `json:"url" gorm:"size:500;not null"`
+	Width   int    `json:"width" gorm:"not null"`
+	Height  int    `json:"height" gorm:"not null"`
+	Bytes   int64  `json:"bytes" gorm:"not null"`
+}
+
+// TableName specifies the table name for AssetVariant
+func (AssetVariant) TableName() string {
+	return "asset_variants"
+}
+
+// AssetTag is a teacher-assigned label used to find an asset in the library
+type AssetTag struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	AssetID uint   `json:"assetId" gorm:"not null;uniqueIndex:idx_asset_tags_asset_tag"`
+	Tag     string `json:"tag" gorm:"size:50;not null;uniqueIndex:idx_asset_tags_asset_tag;index"` // lower case
+}
+
+// TableName specifies the table name for AssetTag
+func (AssetTag) TableName() string {
+	return "asset_tags"
+}
+
+// Asset kinds
+const (
+	AssetKindImage = "image"
+	AssetKindAudio = "audio"
+)
+
+// Asset sources, from the upload directory the file lives in
+const (
+	AssetSourceUpload    = "upload"    // uploads/image
+	AssetSourceProfile   = "profile"   // uploads/profile
+	AssetSourceGenerated = "generated" // uploads/image-cache
+	AssetSourceAudio     = "audio"     // uploads/audio
+)
+
+// Asset variant names
+const (
+	AssetVariantThumb  = "thumb"
+	AssetVariantMedium = "medium"
+	AssetVariantFull   = "full" // original size, re-encoded
+)
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// AuditEvent is one entry in the append-only audit log of administrative and security
+// events. Rows are never updated or deleted; a database trigger rejects both.
+type AuditEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Action     string    `json:"action" gorm:"size:50;not null;index"`                    // e.g. auth.login_failed, user.roles_change
+	ActorID    *uint     `json:"actorId" gorm:"index"`                                    // nil for anonymous requests and the CLI
+	TargetType string    `json:"targetType" gorm:"size:30;index:idx_audit_events_target"` // user, journey, invitation or language
+	TargetID   *uint     `json:"targetId" gorm:"index:idx_audit_events_target"`
+	Details    string    `json:"details" gorm:"type:jsonb;not null;default:'{}'"`
+	IP         string    `json:"ip" gorm:"size:45;index"`
+	UserAgent  string    `json:"userAgent" gorm:"size:255"`
+	RequestID  string    `json:"requestId" gorm:"size:64"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"not null;index"`
+
+	// ActorUsername is joined from users when listing; there is no foreign key, so
+	// entries outlive the accounts they mention
+	ActorUsername string `json:"actorUsername" gorm:"->;-:migration"`
+}
+
+// TableName specifies the table name for AuditEvent
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// Audit actions
+const (
+	AuditActionLogin          = "auth.login"
+	AuditActionLoginFailed    = "auth.login_failed"
+	AuditActionLoginLocked    = "auth.login_locked"
+	AuditActionRegister       = "auth.register"
+	AuditActionPasswordChange = "auth.password_change"
+
+	AuditActionUserCreate        = "user.create"
+	AuditActionUserUpdate        = "user.update"
+	AuditActionUserRolesChange   = "user.roles_change"
+	AuditActionUserDelete        = "user.delete"
+	AuditActionUserPasswordReset = "user.password_reset"
+
+	AuditActionJourneyAssign   = "journey.assign"
+	AuditActionJourneyUnassign = "journey.unassign"
+
+	AuditActionInvitationCreate     = "invitation.create"
+	AuditActionInvitationDeactivate = "invitation.deactivate"
+	AuditActionInvitationAccept     = "invitation.accept"
+
+	AuditActionLanguageCreate = "language.create"
+	AuditActionLanguageUpdate = "language.update"
+	AuditActionLanguageDelete = "language.delete"
+)
+
+// Audit target types
+const (
+	AuditTargetUser       = "user"
+	AuditTargetJourney    = "journey"
+	AuditTargetInvitation = "invitation"
+	AuditTargetLanguage   = "language"
+)
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Publishing status of topics and journeys. Learners only see published content.
+const (
+	ContentStatusDraft     = "draft"
+	ContentStatusInReview  = "in_review"
+	ContentStatusPublished = "published"
+	ContentStatusArchived  = "archived"
+)
+
+// Content that goes through review
+const (
+	ReviewContentTopic   = "topic"
+	ReviewContentJourney = "journey"
+)
+
+// Review outcomes
+const (
+	ReviewStatusPending          = "pending"
+	ReviewStatusApproved         = "approved"
+	ReviewStatusChangesRequested = "changes_requested"
+	ReviewStatusWithdrawn        = "withdrawn"
+)
+
+// ContentReview is a request to publish a draft topic or journey. A pending review holds
+// its content in_review until a reviewer approves it or requests changes.
+type ContentReview struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	ContentType  string     `json:"contentType" gorm:"size:20;not null;index:idx_content_reviews_content"` // topic or journey
+	ContentID    uint       `json:"contentId" gorm:"not null;index:idx_content_reviews_content"`
+	Status       string     `json:"status" gorm:"size:20;not null;default:pending;index"`
+	Note         string     `json:"note" gorm:"type:text"` // from the submitter
+	SubmittedBy  uint       `json:"submittedBy" gorm:"not null;index"`
+	ReviewerID   *uint      `json:"reviewerId" gorm:"index"`
+	DecisionNote string     `json:"decisionNote" gorm:"type:text"`
+	DecidedBy    *uint      `json:"decidedBy"`
+	DecidedAt    *time.Time `json:"decidedAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+
+	// Relations
+	Submitter User            `json:"submitter,omitempty" gorm:"foreignKey:SubmittedBy"`
+	Reviewer  *User           `json:"reviewer,omitempty" gorm:"foreignKey:ReviewerID;constraint:OnDelete:SET NULL"`
+	Decider   *User           `json:"decider,omitempty" gorm:"foreignKey:DecidedBy;constraint:OnDelete:SET NULL"`
+	Comments  []ReviewComment `json:"comments,omitempty" gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE"`
+}
+
+// ReviewComment is a note left on a review by the submitter or a reviewer
+type ReviewComment struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ReviewID  uint      `json:"reviewId" gorm:"not null;index"`
+	UserID    uint      `json:"userId" gorm:"not null"`
+	Body      string    `json:"body" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Relations
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for ContentReview
+func (ContentReview) TableName() string {
+	return "content_reviews"
+}
+
+// TableName specifies the table name for ReviewComment
+func (ReviewComment) TableName() string {
+	return "review_comments"
+}
@@ -13,6 +13,7 @@ type Conversation struct {
 	ScenarioAudioURL string    `json:"scenarioAudioUrl" gorm:"size:500"`
 	ScenarioImageURL string    `json:"scenarioImageUrl" gorm:"size:500"`
 	CreatedBy        uint      `json:"createdBy" gorm:"not null"`
+	IsPublic         bool      `json:"isPublic" gorm:"default:false;not null"` // listed in the marketplace
 	CreatedAt        time.Time `json:"createdAt"`
 	UpdatedAt        time.Time `json:"updatedAt"`
 
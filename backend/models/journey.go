@@ -9,6 +9,8 @@ type Journey struct {
 	Description string    `json:"description" gorm:"type:text"`
 	LanguageID  uint      `json:"languageId" gorm:"not null"`
 	CreatedBy   uint      `json:"createdBy" gorm:"not null"`
+	IsPublic    bool      `json:"isPublic" gorm:"default:false;not null"`             // listed in the marketplace once published
+	Status      string    `json:"status" gorm:"size:20;not null;default:draft;index"` // draft, in_review, published or archived
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 
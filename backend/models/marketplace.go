@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Content that teachers can share in the marketplace
+const (
+	MarketplaceTopic        = "topic"
+	MarketplaceJourney      = "journey"
+	MarketplaceConversation = "conversation"
+)
+
+// ContentRating is a teacher's 1-5 star rating of someone else's public content
+type ContentRating struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ContentType string    `json:"contentType" gorm:"size:20;not null;uniqueIndex:idx_content_ratings_user"` // topic, journey or conversation
+	ContentID   uint      `json:"contentId" gorm:"not null;uniqueIndex:idx_content_ratings_user"`
+	UserID      uint      `json:"userId" gorm:"not null;uniqueIndex:idx_content_ratings_user"`
+	Rating      int       `json:"rating" gorm:"not null"`
+	Comment     string    `json:"comment" gorm:"type:text"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	// Relations
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// ContentClone records that a topic, journey or conversation was copied into a teacher's
+// library. It has no foreign keys, so the attribution outlives a deleted source.
+type ContentClone struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	ContentType    string    `json:"contentType" gorm:"size:20;not null;index:idx_content_clones_source;index:idx_content_clones_clone"`
+	SourceID       uint      `json:"sourceId" gorm:"not null;index:idx_content_clones_source"`
+	SourceName     string    `json:"sourceName" gorm:"size:200;not null"` // name or title when cloned
+	SourceAuthorID uint      `json:"sourceAuthorId" gorm:"not null;index"`
+	CloneID        uint      `json:"cloneId" gorm:"not null;index:idx_content_clones_clone"`
+	ClonedBy       uint      `json:"clonedBy" gorm:"not null;index"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// TableName specifies the table name for ContentRating
+func (ContentRating) TableName() string {
+	return "content_ratings"
+}
+
+// TableName specifies the table name for ContentClone
+func (ContentClone) TableName() string {
+	return "content_clones"
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// PlacementTest represents an adaptive placement test session for a language
+type PlacementTest struct {
+	ID                 uint       `json:"id" gorm:"primaryKey"`
+	UserID             uint       `json:"userId" gorm:"not null;index"`
+	LanguageID         uint       `json:"languageId" gorm:"not null;index"`
+	JourneyID          *uint      `json:"journeyId" gorm:"index"`
+	Status             string     `json:"status" gorm:"size:20;not null;default:'in_progress';index"` // in_progress, completed, applied
+	CurrentLevel       string     `json:"currentLevel" gorm:"size:20;not null"`
+	EstimatedLevel     string     `json:"estimatedLevel" gorm:"size:20"`
+	RecommendedTopicID *uint      `json:"recommendedTopicId"`
+	PendingQuestionID  *uint      `json:"-"`
+	CompletedAt        *time.Time `json:"completedAt"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+
+	// Relations
+	User             User              `json:"-" gorm:"foreignKey:UserID"`
+	Language         Language          `json:"language,omitempty" gorm:"foreignKey:LanguageID"`
+	Journey          *Journey          `json:"journey,omitempty" gorm:"foreignKey:JourneyID"`
+	RecommendedTopic *Topic            `json:"recommendedTopic,omitempty" gorm:"foreignKey:RecommendedTopicID"`
+	Answers          []PlacementAnswer `json:"answers,omitempty" gorm:"foreignKey:PlacementTestID;constraint:OnDelete:CASCADE"`
+}
+
+// PlacementAnswer represents a single graded answer within a placement test
+type PlacementAnswer struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	PlacementTestID uint      `json:"placementTestId" gorm:"not null;index"`
+	QuestionID      uint      `json:"questionId" gorm:"not null"`
+	TopicID         uint      `json:"topicId" gorm:"not null"`
+	Level           string    `json:"level" gorm:"size:20;not null"`
+	Answer          string    `json:"answer" gorm:"size:1;not null"`
+	IsCorrect       bool      `json:"isCorrect" gorm:"not null"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// TableName specifies the table name for PlacementTest
+func (PlacementTest) TableName() string {
+	return "placement_tests"
+}
+
+// TableName specifies the table name for PlacementAnswer
+func (PlacementAnswer) TableName() string {
+	return "placement_answers"
+}
+
+// Placement test statuses
+const (
+	PlacementStatusInProgress = "in_progress"
+	PlacementStatusCompleted  = "completed"
+	PlacementStatusApplied    = "applied"
+)
+
+// Topic levels in ascending order of difficulty
+const (
+	LevelBeginner     = "beginner"
+	LevelIntermediate = "intermediate"
+	LevelAdvanced     = "advanced"
+)
+
+// TopicLevels lists the topic level tiers from easiest to hardest
+var TopicLevels = []string{LevelBeginner, LevelIntermediate, LevelAdvanced}
+
+// LevelRank returns the position of a level in TopicLevels, or -1 if unknown
+func LevelRank(level string) int {
+	for i, l := range TopicLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Revision is one recorded change to a word, topic, conversation or quiz question: the
+// full state after the change and the fields that changed
+type Revision struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	EntityType   string    `json:"entityType" gorm:"size:20;not null;uniqueIndex:idx_revisions_entity_version"` // word, topic, conversation or quiz_question
+	EntityID     uint      `json:"entityId" gorm:"not null;uniqueIndex:idx_revisions_entity_version"`
+	Version      int       `json:"version" gorm:"not null;uniqueIndex:idx_revisions_entity_version"` // 1, 2, ... per entity
+	Action       string    `json:"action" gorm:"size:20;not null;index"`                             // baseline, create, update, delete or restore
+	Snapshot     string    `json:"snapshot" gorm:"type:jsonb;not null"`                              // state after the change; before it for deletes
+	Changes      string    `json:"changes" gorm:"type:jsonb;not null;default:'[]'"`                  // field-level diff against the previous state
+	RestoredFrom *uint     `json:"restoredFrom"`                                                     // revision a restore went back to
+	UserID       *uint     `json:"userId" gorm:"index"`                                              // nil for baselines
+	CreatedAt    time.Time `json:"createdAt" gorm:"index"`
+
+	// Relations
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+}
+
+// TableName specifies the table name for Revision
+func (Revision) TableName() string {
+	return "revisions"
+}
+
+// Entities with revision history
+const (
+	RevisionEntityWord         = "word"
+	RevisionEntityTopic        = "topic"
+	RevisionEntityConversation = "conversation"
+	RevisionEntityQuizQuestion = "quiz_question"
+)
+
+// Revision actions. A baseline records the state of content that pre-dates revision
+// history, just before its first tracked change.
+const (
+	RevisionActionBaseline = "baseline"
+	RevisionActionCreate   = "create"
+	RevisionActionUpdate   = "update"
+	RevisionActionDelete   = "delete"
+	RevisionActionRestore  = "restore"
+)
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// SchemaMigration records a database migration version that has been applied
+type SchemaMigration struct {
+	Version   string    `json:"version" gorm:"primaryKey;size:50"`
+	Name      string    `json:"name" gorm:"size:200;not null"`
+	AppliedAt time.Time `json:"appliedAt" gorm:"not null"`
+}
+
+// TableName specifies the table name for SchemaMigration
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
@@ -10,7 +10,9 @@ type Topic struct {
 	Level       string    `json:"level" gorm:"size:20;not null"` // beginner, intermediate, advanced
 	LanguageID  uint      `json:"languageId" gorm:"not null"`
 	CreatedBy   uint      `json:"createdBy" gorm:"not null"`
-	IsPublic    bool      `json:"isPublic" gorm:"default:false;not null"`
+	IsPublic    bool      `json:"isPublic" gorm:"default:false;not null"`             // listed in the public catalog once published
+	Status      string    `json:"status" gorm:"size:20;not null;default:draft;index"` // draft, in_review, published or archived
+	DraftOf     *uint     `json:"draftOf,omitempty" gorm:"index"`                     // published topic this draft copy replaces when approved
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 
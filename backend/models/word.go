@@ -6,28 +6,46 @@ import (
 
 // Word represents a base vocabulary word (language-agnostic)
 type Word struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	BaseWord  string    `json:"baseWord" gorm:"size:255;not null"`
-	ImageURL  string    `json:"imageUrl" gorm:"size:500"`
-	Notes     string    `json:"notes" gorm:"type:text"`
-	CreatedBy uint      `json:"createdBy" gorm:"not null"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	BaseWord     string    `json:"baseWord" gorm:"size:255;not null"`
+	ImageURL     string    `json:"imageUrl" gorm:"size:500"`
+	Notes        string    `json:"notes" gorm:"type:text"`
+	PartOfSpeech string    `json:"partOfSpeech" gorm:"size:20"` // noun, verb, adjective, ...
+	CreatedBy    uint      `json:"createdBy" gorm:"not null"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
 
 	// Relations
 	Creator      User              `json:"creator,omitempty" gorm:"foreignKey:CreatedBy"`
 	Translations []WordTranslation `json:"translations,omitempty" gorm:"foreignKey:WordID"`
+	Relations    []WordRelation    `json:"relations,omitempty" gorm:"foreignKey:WordID"`
 }
 
 // Language represents a supported language
 type Language struct {
-	ID         uint      `json:"id" gorm:"primaryKey"`
-	Code       string    `json:"code" gorm:"size:10;unique;not null"`
-	Name       string    `json:"name" gorm:"size:100;not null"`
-	NativeName string    `json:"nativeName" gorm:"size:100"`
-	Direction  string    `json:"direction" gorm:"size:3;default:ltr"`
-	IsActive   bool      `json:"isActive" gorm:"default:true"`
-	CreatedAt  time.Time `json:"createdAt"`
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	Code       string `json:"code" gorm:"size:10;unique;not null"`
+	Name       string `json:"name" gorm:"size:100;not null"`
+	NativeName string `json:"nativeName" gorm:"size:100"`
+	Direction  string `json:"direction" gorm:"size:3;default:ltr"`
+	IsActive   bool   `json:"isActive" gorm:"default:true"`
+
+	// Settings read by TTS, translation and the frontend
+	DefaultVoice       string    `json:"defaultVoice" gorm:"size:100"`                   // Azure TTS voice, e.g. zh-HK-HiuMaanNeural
+	Voices             string    `json:"voices" gorm:"type:jsonb;not null;default:'[]'"` // alternate voices, a JSON array of {voice, name, gender}
+	RomanizationScheme string    `json:"romanizationScheme" gorm:"size:50"`              // e.g. jyutping, pinyin, hepburn
+	TranslatorCode     string    `json:"translatorCode" gorm:"size:20"`                  // Azure Translator code when it differs from Code, e.g. zh-Hant
+	FontFamily         string    `json:"fontFamily" gorm:"size:255"`                     // CSS font stack for the script
+	Script             string    `json:"script" gorm:"size:10"`                          // Chinese character set for script conversion: s, t or hk
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+// LanguageVoice is an alternate TTS voice of a language
+type LanguageVoice struct {
+	Voice  string `json:"voice"`  // Azure TTS voice, e.g. zh-HK-WanLungNeural
+	Name   string `json:"name"`   // display name, e.g. WanLung (Male)
+	Gender string `json:"gender"` // Female, Male or Neutral
 }
 
 // WordTranslation represents language-specific translation
@@ -38,12 +56,47 @@ type WordTranslation struct {
 	Translation  string    `json:"translation" gorm:"type:text;not null"`
 	Romanization string    `json:"romanization" gorm:"size:255"`
 	AudioURL     string    `json:"audioUrl" gorm:"size:500"`
+	MeasureWord  string    `json:"measureWord" gorm:"size:50"` // classifier for Chinese nouns, e.g. 隻 for 狗
+	Gender       string    `json:"gender" gorm:"size:20"`      // masculine, feminine, neuter or common
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 
 	// Relations
-	Word     Word     `json:"word,omitempty" gorm:"foreignKey:WordID"`
-	Language Language `json:"language,omitempty" gorm:"foreignKey:LanguageID"`
+	Word     Word          `json:"word,omitempty" gorm:"foreignKey:WordID"`
+	Language Language      `json:"language,omitempty" gorm:"foreignKey:LanguageID"`
+	Examples []WordExample `json:"examples,omitempty" gorm:"foreignKey:TranslationID"`
+}
+
+// WordExample is an example sentence using a translation, with its own audio
+type WordExample struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	TranslationID uint      `json:"translationId" gorm:"not null;index"`
+	Sentence      string    `json:"sentence" gorm:"type:text;not null"` // in the translation's language
+	Romanization  string    `json:"romanization" gorm:"size:500"`
+	Meaning       string    `json:"meaning" gorm:"type:text"` // the sentence in English
+	AudioURL      string    `json:"audioUrl" gorm:"size:500"`
+	SequenceOrder int       `json:"sequenceOrder" gorm:"not null;default:0"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Relation types between words. Each is symmetric, so a link is stored in both directions.
+const (
+	WordRelationSynonym = "synonym"
+	WordRelationAntonym = "antonym"
+	WordRelationRelated = "related"
+)
+
+// WordRelation links a word to a related word
+type WordRelation struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	WordID        uint      `json:"wordId" gorm:"not null;uniqueIndex:idx_word_relations_pair"`
+	RelatedWordID uint      `json:"relatedWordId" gorm:"not null;uniqueIndex:idx_word_relations_pair;index"`
+	RelationType  string    `json:"relationType" gorm:"size:20;not null"`
+	CreatedAt     time.Time `json:"createdAt"`
+
+	// Relations
+	RelatedWord *Word `json:"relatedWord,omitempty" gorm:"foreignKey:RelatedWordID"`
 }
 
 // TableName specifies the table name for Word
@@ -60,3 +113,13 @@ func (Language) TableName() string {
 func (WordTranslation) TableName() string {
 	return "word_translations"
 }
+
+// TableName specifies the table name for WordExample
+func (WordExample) TableName() string {
+	return "word_examples"
+}
+
+// TableName specifies the table name for WordRelation
+func (WordRelation) TableName() string {
+	return "word_relations"
+}
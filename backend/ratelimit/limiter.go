@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests hits per Window. A zero Limit disables limiting.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// Enabled reports whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// ParseLimit parses "<requests>/<window>", e.g. "10/1m" or "5/1h". "", "0" and "off"
+// disable the limit.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" || strings.EqualFold(s, "off") {
+		return Limit{}, nil
+	}
+
+	requests, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected <requests>/<window>", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: requests must be a non-negative integer", s)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: window must be a positive duration", s)
+	}
+	return Limit{Requests: n, Window: d}, nil
+}
+
+// Result describes the state of a key after a hit
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// RetryAfter is how long the caller should wait before the window resets
+func (r Result) RetryAfter() time.Duration {
+	d := time.Until(r.ResetAt)
+	if d < time.Second {
+		return time.Second
+	}
+	return d
+}
+
+// RetryAfterHeader formats d as a Retry-After value, rounded up to whole seconds
+func RetryAfterHeader(d time.Duration) string {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.FormatInt(seconds, 10)
+}
+
+// Limiter applies limits to keys in a Store
+type Limiter struct {
+	store Store
+}
+
+// NewLimiter creates a limiter backed by store
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{store: store}
+}
+
+// Store returns the limiter's backing store
+func (l *Limiter) Store() Store {
+	return l.store
+}
+
+// Allow records a hit on key and reports whether it is within limit. If the store is
+// unavailable the hit is allowed, so a Redis outage doesn't take the API down with it.
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) Result {
+	if !limit.Enabled() {
+		return Result{Allowed: true}
+	}
+
+	count, resetAt, err := l.store.Incr(ctx, "rl:"+key, limit.Window)
+	if err != nil {
+		slog.WarnContext(ctx, "Rate limit store unavailable, allowing request", "key", key, "error", err)
+		return Result{Allowed: true, Limit: limit.Requests, Remaining: limit.Requests}
+	}
+
+	remaining := limit.Requests - int(count)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{
+		Allowed:   count <= int64(limit.Requests),
+		Limit:     limit.Requests,
+		Remaining: remaining,
+		ResetAt:   resetAt,
+	}
+}
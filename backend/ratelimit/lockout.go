@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Lockout locks an account for Duration after failed logins within Window. Failures are
+// counted per username and client IP, so a few bad passwords sent from one address can't
+// lock the owner out from another. A second, higher threshold counts failures for the
+// username across all addresses, so guessing from rotating IPs is still stopped.
+type Lockout struct {
+	store              Store
+	maxFailures        int
+	accountMaxFailures int
+	window             time.Duration
+	duration           time.Duration
+}
+
+// NewLockout creates a login lockout. maxFailures of 0 disables it; accountMaxFailures of
+// 0 disables only the lock across addresses.
+func NewLockout(store Store, maxFailures, accountMaxFailures int, window, duration time.Duration) *Lockout {
+	return &Lockout{
+		store:              store,
+		maxFailures:        maxFailures,
+		accountMaxFailures: accountMaxFailures,
+		window:             window,
+		duration:           duration,
+	}
+}
+
+func (l *Lockout) enabled() bool {
+	return l != nil && l.maxFailures > 0 && l.window > 0 && l.duration > 0
+}
+
+// LockedFor returns how long username remains locked for logins from ip, or 0 if it isn't
+func (l *Lockout) LockedFor(ctx context.Context, username, ip string) time.Duration {
+	if !l.enabled() {
+		return 0
+	}
+	var lockedFor time.Duration
+	for _, key := range []string{lockKey(username, ip), accountLockKey(username)} {
+		count, resetAt, err := l.store.Get(ctx, key)
+		if err != nil {
+			slog.WarnContext(ctx, "Lockout store unavailable", "error", err)
+			return 0
+		}
+		if d := time.Until(resetAt); count > 0 && d > lockedFor {
+			lockedFor = d
+		}
+	}
+	return lockedFor
+}
+
+// RecordFailure counts a failed login from ip and returns the lock duration if this
+// failure locked the account for that address, or for every address, or 0
+func (l *Lockout) RecordFailure(ctx context.Context, username, ip string) time.Duration {
+	if !l.enabled() {
+		return 0
+	}
+
+	if l.accountMaxFailures > 0 && l.lockAfter(ctx, accountFailuresKey(username), accountLockKey(username), l.accountMaxFailures) {
+		slog.WarnContext(ctx, "Account locked after repeated login failures from several addresses", "username", username, "ip", ip, "duration", l.duration.String())
+		return l.duration
+	}
+	if l.lockAfter(ctx, failuresKey(username, ip), lockKey(username, ip), l.maxFailures) {
+		slog.WarnContext(ctx, "Account locked after repeated login failures", "username", username, "ip", ip, "duration", l.duration.String())
+		return l.duration
+	}
+	return 0
+}
+
+// lockAfter counts a failure under failures and sets lock once maxFailures is reached
+func (l *Lockout) lockAfter(ctx context.Context, failures, lock string, maxFailures int) bool {
+	count, _, err := l.store.Incr(ctx, failures, l.window)
+	if err != nil {
+		slog.WarnContext(ctx, "Lockout store unavailable", "error", err)
+		return false
+	}
+	if count < int64(maxFailures) {
+		return false
+	}
+
+	if _, _, err := l.store.Incr(ctx, lock, l.duration); err != nil {
+		slog.WarnContext(ctx, "Lockout store unavailable", "error", err)
+		return false
+	}
+	if err := l.store.Reset(ctx, failures); err != nil {
+		slog.WarnContext(ctx, "Failed to reset login failures", "error", err)
+	}
+	return true
+}
+
+// Reset clears the failure count from ip after a successful login. The count across
+// addresses only expires, so logging in doesn't reset an attacker's progress.
+func (l *Lockout) Reset(ctx context.Context, username, ip string) {
+	if !l.enabled() {
+		return
+	}
+	if err := l.store.Reset(ctx, failuresKey(username, ip)); err != nil {
+		slog.WarnContext(ctx, "Failed to reset login failures", "error", err)
+	}
+}
+
+// Usernames are case-folded so "Alice" and "alice" share a counter
+func failuresKey(username, ip string) string {
+	return "login-failures:" + ip + ":" + strings.ToLower(username)
+}
+
+func lockKey(username, ip string) string {
+	return "login-lock:" + ip + ":" + strings.ToLower(username)
+}
+
+func accountFailuresKey(username string) string {
+	return "login-failures:*:" + strings.ToLower(username)
+}
+
+func accountLockKey(username string) string {
+	return "login-lock:*:" + strings.ToLower(username)
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// incrScript increments a counter and sets its expiry on the first hit, atomically, so a
+// crash between the two commands can't leave a counter that never expires
+var incrScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return {count, redis.call('PTTL', KEYS[1])}
+`)
+
+// RedisStore is a Store shared by every instance connected to the same Redis
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore connects to the Redis server at url (redis://[:password@]host:port/db).
+// Keys are namespaced with prefix.
+func NewRedisStore(ctx context.Context, url, prefix string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return &RedisStore{client: client, prefix: prefix}, nil
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	res, err := incrScript.Run(ctx, s.client, []string{s.prefix + key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return res[0], ttlToResetAt(time.Duration(res[1]) * time.Millisecond), nil
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (int64, time.Time, error) {
+	pipe := s.client.Pipeline()
+	get := pipe.Get(ctx, s.prefix+key)
+	ttl := pipe.PTTL(ctx, s.prefix+key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, time.Time{}, err
+	}
+
+	count, err := get.Int64()
+	if err == redis.Nil {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return count, ttlToResetAt(ttl.Val()), nil
+}
+
+func (s *RedisStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
+
+// Close closes the Redis connection pool
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// ttlToResetAt converts a PTTL reply; negative values mean the key has no expiry or is gone
+func ttlToResetAt(ttl time.Duration) time.Time {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return time.Now().Add(ttl)
+}
@@ -0,0 +1,102 @@
+// Package ratelimit counts requests and login failures in fixed windows, backed by
+// process memory or Redis so limits can be shared between replicas
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps counters that expire a fixed time after their first hit
+type Store interface {
+	// Incr adds one hit to key. The first hit starts a window of the given length; the
+	// returned count and reset time describe the current window.
+	Incr(ctx context.Context, key string, window time.Duration) (int64, time.Time, error)
+	// Get returns key's count without adding a hit. An expired or unknown key has count 0.
+	Get(ctx context.Context, key string) (int64, time.Time, error)
+	// Reset deletes key
+	Reset(ctx context.Context, key string) error
+	// Close releases the store's resources
+	Close() error
+}
+
+type memoryEntry struct {
+	count   int64
+	resetAt time.Time
+}
+
+// MemoryStore is a Store for a single instance. Counters are lost on restart.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// NewMemoryStore creates an in-memory store that drops expired counters every minute
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+		stop:    make(chan struct{}),
+	}
+	go s.janitor(time.Minute)
+	return s
+}
+
+func (s *MemoryStore) Incr(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !now.Before(e.resetAt) {
+		e = &memoryEntry{resetAt: now.Add(window)}
+		s.entries[key] = e
+	}
+	e.count++
+	return e.count, e.resetAt, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (int64, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !time.Now().Before(e.resetAt) {
+		return 0, time.Time{}, nil
+	}
+	return e.count, e.resetAt, nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// Close stops the janitor
+func (s *MemoryStore) Close() error {
+	s.once.Do(func() { close(s.stop) })
+	return nil
+}
+
+func (s *MemoryStore) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, e := range s.entries {
+				if !now.Before(e.resetAt) {
+					delete(s.entries, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
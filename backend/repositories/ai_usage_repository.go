@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"sort"
+	"time"
+
+	"dannyswat/learnspeak/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AIUserUsage is one user's usage counts for a day, keyed by category
+type AIUserUsage struct {
+	UserID   uint
+	Username string
+	Name     string
+	Counts   map[string]int
+}
+
+// AIUsageSummary aggregates ledger entries of one provider operation
+type AIUsageSummary struct {
+	Provider      string
+	Operation     string
+	Calls         int64 // provider calls, excluding cache hits
+	CacheHits     int64
+	Characters    int64
+	Images        int64
+	EstimatedCost float64
+}
+
+// AIUserCost aggregates one user's ledger entries. UserID is nil for usage without a user.
+type AIUserCost struct {
+	UserID        *uint
+	Username      string
+	Name          string
+	Calls         int64
+	CacheHits     int64
+	EstimatedCost float64
+}
+
+type AIUsageRepository interface {
+	// Reserve adds n to the user's count for date and category if the result stays within
+	// limit (0 means unlimited). It reports whether the reservation was made.
+	Reserve(userID uint, date time.Time, category string, n, limit int) (bool, error)
+	// Release refunds n previously reserved calls, e.g. cache hits or failed requests
+	Release(userID uint, date time.Time, category string, n int) error
+	// ListByDate returns every user with usage on date, most active first
+	ListByDate(date time.Time) ([]AIUserUsage, error)
+
+	// CreateEntry appends to the ai_usage ledger
+	CreateEntry(entry *models.AIUsage) error
+	// SumCost returns the estimated cost in [from, to), for one user or everyone when userID is nil
+	SumCost(from, to time.Time, userID *uint) (float64, error)
+	// SummarizeByOperation groups [from, to) by provider and operation, for one user or everyone
+	SummarizeByOperation(from, to time.Time, userID *uint) ([]AIUsageSummary, error)
+	// SummarizeByUser groups [from, to) by user, highest cost first
+	SummarizeByUser(from, to time.Time) ([]AIUserCost, error)
+}
+
+type aiUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewAIUsageRepository(db *gorm.DB) AIUsageRepository {
+	return &aiUsageRepository{db: db}
+}
+
+// Reserve uses a conditional UPDATE so concurrent requests can't overshoot the limit
+func (r *aiUsageRepository) Reserve(userID uint, date time.Time, category string, n, limit int) (bool, error) {
+	reserved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		row := models.AIDailyUsage{UserID: userID, UsageDate: date, Category: category}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+			return err
+		}
+
+		query := tx.Model(&models.AIDailyUsage{}).
+			Where("user_id = ? AND usage_date = ? AND category = ?", userID, date, category)
+		if limit > 0 {
+			query = query.Where("count + ? <= ?", n, limit)
+		}
+		result := query.Updates(map[string]interface{}{
+			"count":      gorm.Expr("count + ?", n),
+			"updated_at": time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		reserved = result.RowsAffected > 0
+		return nil
+	})
+	return reserved, err
+}
+
+// Release decrements the count, never below zero
+func (r *aiUsageRepository) Release(userID uint, date time.Time, category string, n int) error {
+	return r.db.Model(&models.AIDailyUsage{}).
+		Where("user_id = ? AND usage_date = ? AND category = ?", userID, date, category).
+		Updates(map[string]interface{}{
+			"count":      gorm.Expr("GREATEST(count - ?, 0)", n),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *aiUsageRepository) ListByDate(date time.Time) ([]AIUserUsage, error) {
+	var rows []struct {
+		UserID   uint
+		Username string
+		Name     string
+		Category string
+		Count    int
+	}
+	err := r.db.Table("ai_daily_usage u").
+		Select("u.user_id, users.username, users.name, u.category, u.count").
+		Joins("JOIN users ON users.id = u.user_id").
+		Where("u.usage_date = ? AND u.count > 0", date).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uint]*AIUserUsage)
+	totals := make(map[uint]int)
+	var order []uint
+	for _, row := range rows {
+		usage, ok := byUser[row.UserID]
+		if !ok {
+			usage = &AIUserUsage{
+				UserID:   row.UserID,
+				Username: row.Username,
+				Name:     row.Name,
+				Counts:   make(map[string]int),
+			}
+			byUser[row.UserID] = usage
+			order = append(order, row.UserID)
+		}
+		usage.Counts[row.Category] = row.Count
+		totals[row.UserID] += row.Count
+	}
+
+	result := make([]AIUserUsage, 0, len(order))
+	for _, id := range order {
+		result = append(result, *byUser[id])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return totals[result[i].UserID] > totals[result[j].UserID]
+	})
+	return result, nil
+}
+
+func (r *aiUsageRepository) CreateEntry(entry *models.AIUsage) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *aiUsageRepository) SumCost(from, to time.Time, userID *uint) (float64, error) {
+	var total float64
+	query := r.db.Model(&models.AIUsage{}).
+		Select("COALESCE(SUM(estimated_cost), 0)").
+		Where("created_at >= ? AND created_at < ?", from, to)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	err := query.Scan(&total).Error
+	return total, err
+}
+
+func (r *aiUsageRepository) SummarizeByOperation(from, to time.Time, userID *uint) ([]AIUsageSummary, error) {
+	var summaries []AIUsageSummary
+	query := r.db.Model(&models.AIUsage{}).
+		Select(`provider, operation,
+			COUNT(*) FILTER (WHERE NOT cache_hit) AS calls,
+			COUNT(*) FILTER (WHERE cache_hit) AS cache_hits,
+			COALESCE(SUM(characters), 0) AS characters,
+			COALESCE(SUM(images), 0) AS images,
+			COALESCE(SUM(estimated_cost), 0) AS estimated_cost`).
+		Where("created_at >= ? AND created_at < ?", from, to)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	err := query.Group("provider, operation").Order("provider, operation").Scan(&summaries).Error
+	return summaries, err
+}
+
+func (r *aiUsageRepository) SummarizeByUser(from, to time.Time) ([]AIUserCost, error) {
+	var costs []AIUserCost
+	err := r.db.Table("ai_usage").
+		Select(`ai_usage.user_id, COALESCE(users.username, '') AS username, COALESCE(users.name, '') AS name,
+			COUNT(*) FILTER (WHERE NOT ai_usage.cache_hit) AS calls,
+			COUNT(*) FILTER (WHERE ai_usage.cache_hit) AS cache_hits,
+			COALESCE(SUM(ai_usage.estimated_cost), 0) AS estimated_cost`).
+		Joins("LEFT JOIN users ON users.id = ai_usage.user_id").
+		Where("ai_usage.created_at >= ? AND ai_usage.created_at < ?", from, to).
+		Group("ai_usage.user_id, users.username, users.name").
+		Order("estimated_cost DESC").
+		Scan(&costs).Error
+	return costs, err
+}
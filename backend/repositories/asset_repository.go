@@ -0,0 +1,391 @@
+package repositories
+
+import (
+	"strings"
+
+	"dannyswat/learnspeak/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssetFilter narrows an asset library listing. Zero fields match every asset.
+type AssetFilter struct {
+	Source     string
+	Kind       string
+	Query      string // matches the original file name, URL or a tag
+	Tag        string
+	UploadedBy uint
+	Unused     bool // only assets no content refers to
+}
+
+// AssetReference is a content row that refers to a file by URL
+type AssetReference struct {
+	Type  string // word, word_translation, word_example, conversation, conversation_line, quiz_question, user or revision
+	Field string
+	ID    uint
+}
+
+// contentFileColumns are the columns that refer to uploaded files. Any new column holding an
+// /uploads/ URL must be added here, or its files are reported unused and garbage collected.
+var contentFileColumns = []struct {
+	table, column, refType, field string
+}{
+	{"words", "image_url", "word", "imageUrl"},
+	{"word_translations", "audio_url", "word_translation", "audioUrl"},
+	{"word_examples", "audio_url", "word_example", "audioUrl"},
+	{"conversations", "scenario_audio_url", "conversation", "scenarioAudioUrl"},
+	{"conversations", "scenario_image_url", "conversation", "scenarioImageUrl"},
+	{"conversation_lines", "audio_url", "conversation_line", "audioUrl"},
+	{"conversation_lines", "image_url", "conversation_line", "imageUrl"},
+	{"topic_quizzes", "audio_url", "quiz_question", "audioUrl"},
+	{"topic_quizzes", "image_url", "quiz_question", "imageUrl"},
+	{"users", "profile_pic_url", "user", "profilePicUrl"},
+}
+
+// revisionFileRefsSQL selects the uploaded files that revision snapshots refer to, so files
+// that only old versions use are kept for as long as those versions can be restored
+const revisionFileRefsSQL = "SELECT 'revision' AS type, 'snapshot' AS field, id," +
+	" split_part((regexp_matches(snapshot::text, '\"(/uploads/[^\"]+)\"', 'g'))[1], chr(63), 1) AS url" +
+	" FROM revisions WHERE snapshot::text LIKE '%\"/uploads/%'"
+
+// contentFileRefsSQL selects (type, field, id, url) for every content reference to an uploaded
+// file. Query strings such as cache busters are dropped from the URL, and soft-deleted rows
+// and revisions are included since they can be restored. The '?' is written as chr(63) so
+// GORM doesn't bind it as a placeholder.
+var contentFileRefsSQL = func() string {
+	parts := make([]string, len(contentFileColumns), len(contentFileColumns)+1)
+	for i, c := range contentFileColumns {
+		parts[i] = "SELECT '" + c.refType + "' AS type, '" + c.field + "' AS field, id, split_part(" + c.column + ", chr(63), 1) AS url" +
+			" FROM " + c.table + " WHERE " + c.column + " LIKE '/uploads/%'"
+	}
+	parts = append(parts, revisionFileRefsSQL)
+	return strings.Join(parts, " UNION ALL ")
+}()
+
+type AssetRepository interface {
+	// Upsert creates the asset for its URL, or updates the existing one when the file was replaced
+	Upsert(asset *models.Asset) error
+	// ReplaceVariants swaps the asset's variants for the given ones
+	ReplaceVariants(assetID uint, variants []models.AssetVariant) error
+	GetByID(id uint) (*models.Asset, error)
+	GetByURL(url string) (*models.Asset, error)
+	// GetByURLs returns the assets of the given URLs that exist, keyed by URL
+	GetByURLs(urls []string) (map[string]*models.Asset, error)
+	// FindByHash returns the oldest asset of source with the given content hash
+	FindByHash(source, sha256 string) (*models.Asset, error)
+	// List returns the assets matching filter, newest first
+	List(filter AssetFilter, page, pageSize int) ([]models.Asset, int64, error)
+	// ListURLs returns the URL of every asset of source
+	ListURLs(source string) ([]string, error)
+	// SearchImages returns the images of sources matching query, best match first. Images match
+	// on the word, translation and prompt they were generated for, their file name and tags,
+	// and the words and translations of the words that use them.
+	SearchImages(query string, sources []string, page, pageSize int) ([]models.Asset, int64, error)
+	// SetTags replaces the asset's tags
+	SetTags(assetID uint, tags []string) error
+	// Describe records what the image at url depicts
+	Describe(url, word, translation, prompt string) error
+	DeleteByURL(url string) error
+
+	// RefCounts returns how many content rows refer to each of urls; unused URLs are absent
+	RefCounts(urls []string) (map[string]int, error)
+	// References lists the content rows that refer to url
+	References(url string) ([]AssetReference, error)
+	// IsReferenced reports whether any content row refers to url
+	IsReferenced(url string) (bool, error)
+	// ReferencedURLs returns every uploaded file URL that content refers to
+	ReferencedURLs() (map[string]bool, error)
+}
+
+type assetRepository struct {
+	db *gorm.DB
+}
+
+func NewAssetRepository(db *gorm.DB) AssetRepository {
+	return &assetRepository{db: db}
+}
+
+func (r *assetRepository) Upsert(asset *models.Asset) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url"}},
+		DoUpdates: clause.AssignmentColumns([]string{"kind", "source", "format", "width", "height", "bytes", "blurhash", "duration_ms", "sample_rate", "channels", "sha256", "updated_at"}),
+	}).Omit(clause.Associations).Create(asset).Error
+}
+
+func (r *assetRepository) ReplaceVariants(assetID uint, variants []models.AssetVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("asset_id = ?", assetID).Delete(&models.AssetVariant{}).Error; err != nil {
+			return err
+		}
+		if len(variants) == 0 {
+			return nil
+		}
+		for i := range variants {
+			variants[i].AssetID = assetID
+		}
+		return tx.Create(&variants).Error
+	})
+}
+
+func (r *assetRepository) GetByID(id uint) (*models.Asset, error) {
+	var asset models.Asset
+	err := r.withDetails(r.db).First(&asset, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+func (r *assetRepository) GetByURL(url string) (*models.Asset, error) {
+	var asset models.Asset
+	err := r.withDetails(r.db).Where("url = ?", url).First(&asset).Error
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+func (r *assetRepository) GetByURLs(urls []string) (map[string]*models.Asset, error) {
+	result := make(map[string]*models.Asset, len(urls))
+	if len(urls) == 0 {
+		return result, nil
+	}
+
+	var assets []models.Asset
+	if err := r.withDetails(r.db).Where("url IN ?", urls).Find(&assets).Error; err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		result[assets[i].URL] = &assets[i]
+	}
+	return result, nil
+}
+
+func (r *assetRepository) FindByHash(source, sha256 string) (*models.Asset, error) {
+	var asset models.Asset
+	err := r.withDetails(r.db).
+		Where("source = ? AND sha256 = ?", source, sha256).
+		Order("id ASC").
+		First(&asset).Error
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+func (r *assetRepository) List(filter AssetFilter, page, pageSize int) ([]models.Asset, int64, error) {
+	var assets []models.Asset
+	var total int64
+
+	query := r.db.Model(&models.Asset{})
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Query != "" {
+		pattern := "%" + filter.Query + "%"
+		query = query.Where("original_name ILIKE ? OR url ILIKE ? OR EXISTS (SELECT 1 FROM asset_tags WHERE asset_tags.asset_id = assets.id AND asset_tags.tag ILIKE ?)",
+			pattern, pattern, pattern)
+	}
+	if filter.Tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM asset_tags WHERE asset_tags.asset_id = assets.id AND asset_tags.tag = ?)", strings.ToLower(filter.Tag))
+	}
+	if filter.UploadedBy > 0 {
+		query = query.Where("uploaded_by = ?", filter.UploadedBy)
+	}
+	if filter.Unused {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM (" + contentFileRefsSQL + ") refs WHERE refs.url = assets.url)")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := r.withDetails(query).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(pageSize).
+		Find(&assets).Error
+	return assets, total, err
+}
+
+// imageSearchSQL scores every image matching @query, one row per match. The asset expressions
+// mirror the indexes created by migration 0010; words are matched like the unified search.
+const imageSearchSQL = `
+WITH p AS (
+	SELECT
+		learnspeak_search_text(@query) AS q,
+		plainto_tsquery('simple', learnspeak_search_text(@query)) AS tsq
+),
+images AS (
+	SELECT * FROM assets WHERE kind = 'image' AND source IN @sources
+)
+SELECT a.id,
+	ts_rank(to_tsvector('simple', learnspeak_search_text(a.word || ' ' || a.translation || ' ' || a.prompt || ' ' || coalesce(a.original_name, ''))), p.tsq)
+		+ 2 * greatest(similarity(learnspeak_search_text(a.word), p.q), similarity(learnspeak_search_text(a.translation), p.q)) AS score
+FROM images a CROSS JOIN p
+WHERE to_tsvector('simple', learnspeak_search_text(a.word || ' ' || a.translation || ' ' || a.prompt || ' ' || coalesce(a.original_name, ''))) @@ p.tsq
+	OR learnspeak_search_text(a.word) % p.q
+	OR learnspeak_search_text(a.translation) % p.q
+
+UNION ALL
+
+SELECT a.id, 1.5
+FROM images a JOIN asset_tags t ON t.asset_id = a.id CROSS JOIN p
+WHERE learnspeak_search_text(t.tag) = p.q
+
+UNION ALL
+
+SELECT a.id, 1 + similarity(learnspeak_search_text(w.base_word), p.q)
+FROM images a JOIN words w ON split_part(w.image_url, chr(63), 1) = a.url CROSS JOIN p
+WHERE to_tsvector('simple', learnspeak_search_text(w.base_word)) @@ p.tsq
+	OR learnspeak_search_text(w.base_word) % p.q
+
+UNION ALL
+
+SELECT a.id, 1 + similarity(learnspeak_search_text(wt.translation), p.q)
+FROM images a JOIN words w ON split_part(w.image_url, chr(63), 1) = a.url
+	JOIN word_translations wt ON wt.word_id = w.id CROSS JOIN p
+WHERE to_tsvector('simple', learnspeak_search_text(wt.translation)) @@ p.tsq
+	OR learnspeak_search_text(wt.translation) % p.q
+`
+
+func (r *assetRepository) SearchImages(query string, sources []string, page, pageSize int) ([]models.Asset, int64, error) {
+	args := map[string]interface{}{"query": query, "sources": sources}
+	ranked := "SELECT id, MAX(score) AS score FROM (" + imageSearchSQL + ") hits GROUP BY id"
+
+	var total int64
+	if err := r.db.Raw("SELECT COUNT(*) FROM ("+ranked+") ranked", args).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var ids []uint
+	args["limit"] = pageSize
+	args["offset"] = (page - 1) * pageSize
+	err := r.db.Raw("SELECT id FROM ("+ranked+") ranked ORDER BY score DESC, id DESC LIMIT @limit OFFSET @offset", args).
+		Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, total, err
+	}
+
+	var found []models.Asset
+	if err := r.withDetails(r.db).Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint]models.Asset, len(found))
+	for _, asset := range found {
+		byID[asset.ID] = asset
+	}
+	assets := make([]models.Asset, 0, len(ids))
+	for _, id := range ids {
+		if asset, ok := byID[id]; ok {
+			assets = append(assets, asset)
+		}
+	}
+	return assets, total, nil
+}
+
+func (r *assetRepository) ListURLs(source string) ([]string, error) {
+	var urls []string
+	err := r.db.Model(&models.Asset{}).Where("source = ?", source).Pluck("url", &urls).Error
+	return urls, err
+}
+
+func (r *assetRepository) SetTags(assetID uint, tags []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("asset_id = ?", assetID).Delete(&models.AssetTag{}).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		rows := make([]models.AssetTag, len(tags))
+		for i, tag := range tags {
+			rows[i] = models.AssetTag{AssetID: assetID, Tag: tag}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *assetRepository) Describe(url, word, translation, prompt string) error {
+	return r.db.Model(&models.Asset{}).Where("url = ?", url).Updates(map[string]interface{}{
+		"word":        word,
+		"translation": translation,
+		"prompt":      prompt,
+	}).Error
+}
+
+func (r *assetRepository) DeleteByURL(url string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		assetIDs := tx.Model(&models.Asset{}).Select("id").Where("url = ?", url)
+		if err := tx.Where("asset_id IN (?)", assetIDs).Delete(&models.AssetVariant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("asset_id IN (?)", assetIDs).Delete(&models.AssetTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("url = ?", url).Delete(&models.Asset{}).Error
+	})
+}
+
+func (r *assetRepository) RefCounts(urls []string) (map[string]int, error) {
+	result := make(map[string]int, len(urls))
+	if len(urls) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		URL   string
+		Count int
+	}
+	err := r.db.Raw("SELECT url, COUNT(*) AS count FROM ("+contentFileRefsSQL+") refs WHERE url IN ? GROUP BY url", urls).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.URL] = row.Count
+	}
+	return result, nil
+}
+
+func (r *assetRepository) References(url string) ([]AssetReference, error) {
+	var refs []AssetReference
+	err := r.db.Raw("SELECT type, field, id FROM ("+contentFileRefsSQL+") refs WHERE url = ? ORDER BY type, id", url).
+		Scan(&refs).Error
+	return refs, err
+}
+
+func (r *assetRepository) IsReferenced(url string) (bool, error) {
+	var exists bool
+	err := r.db.Raw("SELECT EXISTS (SELECT 1 FROM ("+contentFileRefsSQL+") refs WHERE url = ?)", url).
+		Scan(&exists).Error
+	return exists, err
+}
+
+func (r *assetRepository) ReferencedURLs() (map[string]bool, error) {
+	var urls []string
+	if err := r.db.Raw("SELECT DISTINCT url FROM (" + contentFileRefsSQL + ") refs").Scan(&urls).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		result[url] = true
+	}
+	return result, nil
+}
+
+// withDetails loads the variants, tags and uploader shown with an asset
+func (r *assetRepository) withDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("Variants", variantOrder).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tag ASC") }).
+		Preload("Uploader")
+}
+
+// variantOrder lists variants smallest first
+func variantOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("width ASC, format ASC")
+}
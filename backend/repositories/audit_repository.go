@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"time"
+
+	"dannyswat/learnspeak/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditFilter narrows an audit log query; zero values match everything
+type AuditFilter struct {
+	Actions    []string
+	ActorID    uint
+	TargetType string
+	TargetID   uint
+	IP         string
+	From       time.Time // inclusive
+	To         time.Time // exclusive
+}
+
+type AuditRepository interface {
+	Create(event *models.AuditEvent) error
+	List(filter AuditFilter, page, pageSize int) ([]models.AuditEvent, int64, error)
+	Each(filter AuditFilter, batchSize int, fn func([]models.AuditEvent) error) error
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Create appends an event to the audit log
+func (r *auditRepository) Create(event *models.AuditEvent) error {
+	return r.db.Create(event).Error
+}
+
+// List retrieves events newest first
+func (r *auditRepository) List(filter AuditFilter, page, pageSize int) ([]models.AuditEvent, int64, error) {
+	var events []models.AuditEvent
+	var total int64
+
+	if err := r.filtered(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.withActor(r.filtered(filter)).
+		Order("audit_events.created_at DESC, audit_events.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// Each streams matching events oldest first, batchSize at a time
+func (r *auditRepository) Each(filter AuditFilter, batchSize int, fn func([]models.AuditEvent) error) error {
+	var lastID uint
+	for {
+		var batch []models.AuditEvent
+		err := r.withActor(r.filtered(filter)).
+			Where("audit_events.id > ?", lastID).
+			Order("audit_events.id ASC").
+			Limit(batchSize).
+			Find(&batch).Error
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+// filtered applies filter to a query on audit_events
+func (r *auditRepository) filtered(filter AuditFilter) *gorm.DB {
+	query := r.db.Model(&models.AuditEvent{})
+	if len(filter.Actions) > 0 {
+		query = query.Where("audit_events.action IN ?", filter.Actions)
+	}
+	if filter.ActorID > 0 {
+		query = query.Where("audit_events.actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("audit_events.target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID > 0 {
+		query = query.Where("audit_events.target_id = ?", filter.TargetID)
+	}
+	if filter.IP != "" {
+		query = query.Where("audit_events.ip = ?", filter.IP)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("audit_events.created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("audit_events.created_at < ?", filter.To)
+	}
+	return query
+}
+
+// withActor adds the actor's username, including deleted accounts
+func (r *auditRepository) withActor(query *gorm.DB) *gorm.DB {
+	return query.
+		Select("audit_events.*, COALESCE(users.username, '') AS actor_username").
+		Joins("LEFT JOIN users ON users.id = audit_events.actor_id")
+}
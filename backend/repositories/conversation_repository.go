@@ -7,6 +7,7 @@ import (
 	"dannyswat/learnspeak/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ConversationRepository interface {
@@ -21,6 +22,7 @@ type ConversationRepository interface {
 	DeleteLine(lineID uint) error
 	ReorderLines(conversationID uint, lineIDs []uint) error
 	LinkToTopic(conversationID uint, topicID uint) error
+	Restore(conversation *models.Conversation) error
 }
 
 type conversationRepository struct {
@@ -63,6 +65,7 @@ func (r *conversationRepository) Update(conversation *models.Conversation) error
 		"context":          conversation.Context,
 		"language_id":      conversation.LanguageID,
 		"difficulty_level": conversation.DifficultyLevel,
+		"is_public":        conversation.IsPublic,
 	}).Error
 }
 
@@ -214,3 +217,59 @@ func (r *conversationRepository) LinkToTopic(conversationID uint, topicID uint)
 
 	return r.db.Create(topicConversation).Error
 }
+
+// Restore writes a conversation and its lines back to an earlier state, recreating them if
+// they were deleted. Lines not in conversation.Lines are removed, and links to words that
+// have since been deleted are cleared.
+func (r *conversationRepository) Restore(conversation *models.Conversation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"title", "description", "context", "language_id", "difficulty_level",
+				"scenario_audio_url", "scenario_image_url", "updated_at",
+			}),
+		}).Create(conversation).Error; err != nil {
+			return err
+		}
+
+		keep := make([]uint, len(conversation.Lines))
+		var wordIDs []uint
+		for i, line := range conversation.Lines {
+			keep[i] = line.ID
+			if line.WordID != nil {
+				wordIDs = append(wordIDs, *line.WordID)
+			}
+		}
+		stale := tx.Where("conversation_id = ?", conversation.ID)
+		if len(keep) > 0 {
+			stale = stale.Where("id NOT IN ?", keep)
+		}
+		if err := stale.Delete(&models.ConversationLine{}).Error; err != nil {
+			return err
+		}
+
+		existing, err := existingWordIDs(tx, wordIDs)
+		if err != nil {
+			return err
+		}
+
+		for i := range conversation.Lines {
+			line := &conversation.Lines[i]
+			line.ConversationID = conversation.ID
+			if line.WordID != nil && !existing[*line.WordID] {
+				line.WordID = nil
+			}
+			if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"conversation_id", "sequence_order", "speaker_role", "english_text", "target_text",
+					"romanization", "audio_url", "image_url", "word_id", "is_learner_line", "updated_at",
+				}),
+			}).Create(line).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -1,7 +1,7 @@
 package repositories
 
 import (
-	"fmt"
+	"errors"
 	"strings"
 
 	"dannyswat/learnspeak/models"
@@ -9,12 +9,15 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrJourneyNotFound is returned when the journey doesn't exist
+var ErrJourneyNotFound = errors.New("journey not found")
+
 type JourneyRepository interface {
 	Create(journey *models.Journey) error
 	GetByID(id uint, includeTopics bool) (*models.Journey, error)
 	Update(journey *models.Journey) error
 	Delete(id uint) error
-	List(search string, languageCode string, createdBy uint, page, pageSize int, includeTopics bool) ([]models.Journey, int64, error)
+	List(search string, languageCode string, createdBy uint, status string, page, pageSize int, includeTopics bool) ([]models.Journey, int64, error)
 	AddTopics(journeyID uint, topicIDs []uint) error
 	RemoveTopics(journeyID uint, topicIDs []uint) error
 	ReorderTopics(journeyID uint, topicIDs []uint) error
@@ -22,6 +25,7 @@ type JourneyRepository interface {
 	GetTopicCount(journeyID uint) (int64, error)
 	GetTotalWords(journeyID uint) (int, error)
 	GetAssignedUserCount(journeyID uint) (int64, error)
+	SetStatus(id uint, from []string, to string) (bool, error)
 	// Invitation methods
 	CreateInvitation(invitation *models.JourneyInvitation) error
 	GetInvitationByToken(token string) (*models.JourneyInvitation, error)
@@ -57,7 +61,7 @@ func (r *journeyRepository) GetByID(id uint, includeTopics bool) (*models.Journe
 	err := query.First(&journey, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("journey not found")
+			return nil, ErrJourneyNotFound
 		}
 		return nil, err
 	}
@@ -84,14 +88,14 @@ func (r *journeyRepository) Delete(id uint) error {
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("journey not found")
+		return ErrJourneyNotFound
 	}
 
 	return nil
 }
 
 // List retrieves journeys with filtering and pagination
-func (r *journeyRepository) List(search string, languageCode string, createdBy uint, page, pageSize int, includeTopics bool) ([]models.Journey, int64, error) {
+func (r *journeyRepository) List(search string, languageCode string, createdBy uint, status string, page, pageSize int, includeTopics bool) ([]models.Journey, int64, error) {
 	var journeys []models.Journey
 	var total int64
 
@@ -113,6 +117,10 @@ func (r *journeyRepository) List(search string, languageCode string, createdBy u
 		query = query.Where("created_by = ?", createdBy)
 	}
 
+	if status != "" {
+		query = query.Where("journeys.status = ?", status)
+	}
+
 	// Count total
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -228,6 +236,14 @@ func (r *journeyRepository) GetAssignedUserCount(journeyID uint) (int64, error)
 	return count, err
 }
 
+// SetStatus moves a journey to status to if it is currently in one of from, reporting whether it did
+func (r *journeyRepository) SetStatus(id uint, from []string, to string) (bool, error) {
+	result := r.db.Model(&models.Journey{}).
+		Where("id = ? AND status IN ?", id, from).
+		Update("status", to)
+	return result.RowsAffected > 0, result.Error
+}
+
 // CreateInvitation creates a new journey invitation
 func (r *journeyRepository) CreateInvitation(invitation *models.JourneyInvitation) error {
 	return r.db.Create(invitation).Error
@@ -8,8 +8,13 @@ import (
 
 type LanguageRepository interface {
 	GetAllLanguages() ([]models.Language, error)
+	ListAll() ([]models.Language, error)
 	GetLanguageByID(id uint) (*models.Language, error)
 	GetByCode(code string) (*models.Language, error)
+	Create(language *models.Language) error
+	Update(language *models.Language) error
+	Delete(id uint) error
+	IsInUse(id uint) (bool, error)
 }
 
 type languageRepository struct {
@@ -43,3 +48,36 @@ func (r *languageRepository) GetByCode(code string) (*models.Language, error) {
 	}
 	return &language, nil
 }
+
+// ListAll returns every language, inactive ones included
+func (r *languageRepository) ListAll() ([]models.Language, error) {
+	var languages []models.Language
+	err := r.db.Order("name ASC").Find(&languages).Error
+	return languages, err
+}
+
+func (r *languageRepository) Create(language *models.Language) error {
+	return r.db.Create(language).Error
+}
+
+func (r *languageRepository) Update(language *models.Language) error {
+	return r.db.Save(language).Error
+}
+
+func (r *languageRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Language{}, id).Error
+}
+
+// IsInUse reports whether any content or placement test is in the language
+func (r *languageRepository) IsInUse(id uint) (bool, error) {
+	for _, model := range []interface{}{&models.WordTranslation{}, &models.Topic{}, &models.Journey{}, &models.Conversation{}, &models.PlacementTest{}} {
+		var count int64
+		if err := r.db.Model(model).Where("language_id = ?", id).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
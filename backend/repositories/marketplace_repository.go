@@ -0,0 +1,322 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dannyswat/learnspeak/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MarketplaceFilter narrows a marketplace listing of one content type
+type MarketplaceFilter struct {
+	ContentType  string
+	Search       string
+	LanguageCode string
+	Level        string
+	CreatedBy    uint
+	// IncludeUnlisted also returns content that isn't public, for an author's own listing
+	IncludeUnlisted bool
+	Sort            string // newest, rating or clones
+}
+
+// MarketplaceItem is a topic, journey or conversation with its ratings and clone count
+type MarketplaceItem struct {
+	ID                 uint
+	Name               string
+	Description        string
+	Level              string
+	LanguageID         uint
+	LanguageCode       string
+	LanguageName       string
+	LanguageNativeName string
+	CreatedBy          uint
+	AuthorName         string
+	AuthorEmail        string
+	ItemCount          int64 // words in a topic, topics in a journey, lines in a conversation
+	AverageRating      float64
+	RatingCount        int64
+	CloneCount         int64
+	Listed             bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// CloneRecord is a clone with the current names of the clone and the source author
+type CloneRecord struct {
+	models.ContentClone
+	CloneName   string
+	AuthorName  string
+	AuthorEmail string
+}
+
+type MarketplaceRepository interface {
+	List(filter MarketplaceFilter, page, pageSize int) ([]MarketplaceItem, int64, error)
+	GetItem(contentType string, id uint) (*MarketplaceItem, error)
+	GetRating(contentType string, contentID, userID uint) (*models.ContentRating, error)
+	UpsertRating(rating *models.ContentRating) error
+	DeleteRating(contentType string, contentID, userID uint) (bool, error)
+	ListRatings(contentType string, contentID uint, page, pageSize int) ([]models.ContentRating, int64, error)
+	CreateClones(clones []models.ContentClone) error
+	ListClones(clonedBy uint, contentType string, cloneID uint, page, pageSize int) ([]CloneRecord, int64, error)
+}
+
+type marketplaceRepository struct {
+	db *gorm.DB
+}
+
+func NewMarketplaceRepository(db *gorm.DB) MarketplaceRepository {
+	return &marketplaceRepository{db: db}
+}
+
+// marketplaceSource describes how a content type is stored
+type marketplaceSource struct {
+	table     string
+	name      string // name column
+	level     string // level column, empty if the type has none
+	itemCount string // subquery counting the content's items
+	listed    string // condition for appearing in the marketplace
+}
+
+var marketplaceSources = map[string]marketplaceSource{
+	models.MarketplaceTopic: {
+		table:     "topics",
+		name:      "name",
+		level:     "level",
+		itemCount: "(SELECT COUNT(*) FROM topic_words WHERE topic_words.topic_id = c.id)",
+		listed:    "(c.is_public AND c.status = '" + models.ContentStatusPublished + "' AND c.draft_of IS NULL)",
+	},
+	models.MarketplaceJourney: {
+		table:     "journeys",
+		name:      "name",
+		itemCount: "(SELECT COUNT(*) FROM journey_topics WHERE journey_topics.journey_id = c.id)",
+		listed:    "(c.is_public AND c.status = '" + models.ContentStatusPublished + "')",
+	},
+	models.MarketplaceConversation: {
+		table:     "conversations",
+		name:      "title",
+		level:     "difficulty_level",
+		itemCount: "(SELECT COUNT(*) FROM conversation_lines WHERE conversation_lines.conversation_id = c.id)",
+		listed:    "c.is_public",
+	},
+}
+
+// itemQuery selects content of one type with its language, author, ratings and clone count
+func (r *marketplaceRepository) itemQuery(contentType string) (*gorm.DB, error) {
+	source, ok := marketplaceSources[contentType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+
+	level := "''"
+	if source.level != "" {
+		level = "c." + source.level
+	}
+
+	return r.db.Table(source.table+" AS c").
+		Select(strings.Join([]string{
+			"c.id", "c." + source.name + " AS name", "c.description", level + " AS level",
+			"c.language_id", "l.code AS language_code", "l.name AS language_name", "l.native_name AS language_native_name",
+			"c.created_by", "u.name AS author_name", "u.email AS author_email",
+			source.itemCount + " AS item_count",
+			"COALESCE(rt.average_rating, 0) AS average_rating", "COALESCE(rt.rating_count, 0) AS rating_count",
+			"COALESCE(cl.clone_count, 0) AS clone_count",
+			source.listed + " AS listed",
+			"c.created_at", "c.updated_at",
+		}, ", ")).
+		Joins("JOIN languages l ON l.id = c.language_id").
+		Joins("JOIN users u ON u.id = c.created_by").
+		Joins(`LEFT JOIN (
+			SELECT content_id, AVG(rating) AS average_rating, COUNT(*) AS rating_count
+			FROM content_ratings WHERE content_type = ? GROUP BY content_id
+		) rt ON rt.content_id = c.id`, contentType).
+		Joins(`LEFT JOIN (
+			SELECT source_id, COUNT(*) AS clone_count
+			FROM content_clones WHERE content_type = ? GROUP BY source_id
+		) cl ON cl.source_id = c.id`, contentType), nil
+}
+
+// List retrieves content of one type. Only public content is listed unless the filter
+// includes unlisted content.
+func (r *marketplaceRepository) List(filter MarketplaceFilter, page, pageSize int) ([]MarketplaceItem, int64, error) {
+	source, ok := marketplaceSources[filter.ContentType]
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported content type %q", filter.ContentType)
+	}
+
+	// Filters only touch the content, its language and its author, so the count skips the
+	// rating and clone aggregates
+	where := func(query *gorm.DB) *gorm.DB {
+		if !filter.IncludeUnlisted {
+			query = query.Where(source.listed)
+		}
+		if filter.Search != "" {
+			searchPattern := "%" + strings.ToLower(filter.Search) + "%"
+			query = query.Where("LOWER(c."+source.name+") LIKE ? OR LOWER(c.description) LIKE ?", searchPattern, searchPattern)
+		}
+		if filter.LanguageCode != "" {
+			query = query.Where("l.code = ?", filter.LanguageCode)
+		}
+		if filter.Level != "" && source.level != "" {
+			query = query.Where("c."+source.level+" = ?", filter.Level)
+		}
+		if filter.CreatedBy > 0 {
+			query = query.Where("c.created_by = ?", filter.CreatedBy)
+		}
+		return query
+	}
+
+	var total int64
+	countQuery := where(r.db.Table(source.table + " AS c").
+		Joins("JOIN languages l ON l.id = c.language_id").
+		Joins("JOIN users u ON u.id = c.created_by"))
+	if err := countQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query, err := r.itemQuery(filter.ContentType)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch filter.Sort {
+	case "rating":
+		query = query.Order("average_rating DESC, rating_count DESC")
+	case "clones":
+		query = query.Order("clone_count DESC")
+	default:
+		query = query.Order("c.created_at DESC")
+	}
+
+	var items []MarketplaceItem
+	err = where(query).
+		Order("c.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// GetItem retrieves one topic, journey or conversation whether or not it is listed
+func (r *marketplaceRepository) GetItem(contentType string, id uint) (*MarketplaceItem, error) {
+	query, err := r.itemQuery(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []MarketplaceItem
+	if err := query.Where("c.id = ?", id).Limit(1).Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s not found", contentType)
+	}
+	return &items[0], nil
+}
+
+// GetRating returns a user's rating of content, or nil if they haven't rated it
+func (r *marketplaceRepository) GetRating(contentType string, contentID, userID uint) (*models.ContentRating, error) {
+	var rating models.ContentRating
+	err := r.db.
+		Where("content_type = ? AND content_id = ? AND user_id = ?", contentType, contentID, userID).
+		First(&rating).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// UpsertRating creates a rating or replaces the user's earlier rating of the same content
+func (r *marketplaceRepository) UpsertRating(rating *models.ContentRating) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "content_type"}, {Name: "content_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "comment", "updated_at"}),
+	}).Create(rating).Error
+}
+
+// DeleteRating removes a user's rating, reporting whether there was one
+func (r *marketplaceRepository) DeleteRating(contentType string, contentID, userID uint) (bool, error) {
+	result := r.db.
+		Where("content_type = ? AND content_id = ? AND user_id = ?", contentType, contentID, userID).
+		Delete(&models.ContentRating{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListRatings retrieves the ratings of content, most recently changed first
+func (r *marketplaceRepository) ListRatings(contentType string, contentID uint, page, pageSize int) ([]models.ContentRating, int64, error) {
+	var ratings []models.ContentRating
+	var total int64
+
+	query := r.db.Model(&models.ContentRating{}).
+		Where("content_type = ? AND content_id = ?", contentType, contentID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("User").
+		Order("updated_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&ratings).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ratings, total, nil
+}
+
+// CreateClones records clones made in one operation
+func (r *marketplaceRepository) CreateClones(clones []models.ContentClone) error {
+	if len(clones) == 0 {
+		return nil
+	}
+	return r.db.Create(&clones).Error
+}
+
+// ListClones retrieves a user's clones newest first, optionally of one type or one clone
+func (r *marketplaceRepository) ListClones(clonedBy uint, contentType string, cloneID uint, page, pageSize int) ([]CloneRecord, int64, error) {
+	where := func(query *gorm.DB) *gorm.DB {
+		query = query.Where("cc.cloned_by = ?", clonedBy)
+		if contentType != "" {
+			query = query.Where("cc.content_type = ?", contentType)
+		}
+		if cloneID > 0 {
+			query = query.Where("cc.clone_id = ?", cloneID)
+		}
+		return query
+	}
+
+	var total int64
+	if err := where(r.db.Table("content_clones AS cc")).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var records []CloneRecord
+	err := where(r.db.Table("content_clones AS cc")).
+		Select(`cc.*,
+			COALESCE(t.name, j.name, cv.title, '') AS clone_name,
+			COALESCE(u.name, '') AS author_name, COALESCE(u.email, '') AS author_email`).
+		Joins("LEFT JOIN topics t ON cc.content_type = ? AND t.id = cc.clone_id", models.MarketplaceTopic).
+		Joins("LEFT JOIN journeys j ON cc.content_type = ? AND j.id = cc.clone_id", models.MarketplaceJourney).
+		Joins("LEFT JOIN conversations cv ON cc.content_type = ? AND cv.id = cc.clone_id", models.MarketplaceConversation).
+		Joins("LEFT JOIN users u ON u.id = cc.source_author_id").
+		Order("cc.created_at DESC, cc.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&records).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, nil
+}
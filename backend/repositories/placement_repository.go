@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"fmt"
+
+	"dannyswat/learnspeak/models"
+
+	"gorm.io/gorm"
+)
+
+type PlacementRepository interface {
+	Create(test *models.PlacementTest) error
+	GetByID(id uint) (*models.PlacementTest, error)
+	Update(test *models.PlacementTest) error
+	CreateAnswer(answer *models.PlacementAnswer) error
+	// SampleQuestion picks a random quiz question at the given level that has not been asked yet.
+	// When journeyID is set, only topics in that journey are considered; otherwise public topics
+	// of the language are used.
+	SampleQuestion(languageID uint, journeyID *uint, level string, excludeIDs []uint) (*models.QuizQuestion, error)
+	// GetFirstTopicAtLevel returns the earliest public topic of a language at the given level
+	GetFirstTopicAtLevel(languageID uint, level string) (*models.Topic, error)
+}
+
+type placementRepository struct {
+	db *gorm.DB
+}
+
+func NewPlacementRepository(db *gorm.DB) PlacementRepository {
+	return &placementRepository{db: db}
+}
+
+// Create creates a new placement test
+func (r *placementRepository) Create(test *models.PlacementTest) error {
+	return r.db.Create(test).Error
+}
+
+// GetByID retrieves a placement test with its answers in the order they were given
+func (r *placementRepository) GetByID(id uint) (*models.PlacementTest, error) {
+	var test models.PlacementTest
+	err := r.db.
+		Preload("Language").
+		Preload("RecommendedTopic").
+		Preload("Answers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		First(&test, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("placement test not found")
+		}
+		return nil, err
+	}
+	return &test, nil
+}
+
+// Update updates an existing placement test
+func (r *placementRepository) Update(test *models.PlacementTest) error {
+	return r.db.Model(&models.PlacementTest{}).Where("id = ?", test.ID).Updates(map[string]interface{}{
+		"status":               test.Status,
+		"current_level":        test.CurrentLevel,
+		"estimated_level":      test.EstimatedLevel,
+		"recommended_topic_id": test.RecommendedTopicID,
+		"pending_question_id":  test.PendingQuestionID,
+		"completed_at":         test.CompletedAt,
+	}).Error
+}
+
+// CreateAnswer records a graded answer
+func (r *placementRepository) CreateAnswer(answer *models.PlacementAnswer) error {
+	return r.db.Create(answer).Error
+}
+
+// SampleQuestion picks a random unanswered quiz question at a level
+func (r *placementRepository) SampleQuestion(languageID uint, journeyID *uint, level string, excludeIDs []uint) (*models.QuizQuestion, error) {
+	var question models.QuizQuestion
+
+	query := r.db.Model(&models.QuizQuestion{}).
+		Joins("JOIN topics ON topics.id = topic_quizzes.topic_id").
+		Where("topics.language_id = ? AND topics.level = ?", languageID, level)
+
+	if journeyID != nil {
+		query = query.Joins("JOIN journey_topics ON journey_topics.topic_id = topics.id").
+			Where("journey_topics.journey_id = ?", *journeyID)
+	} else {
+		query = query.Where("topics.is_public = ? AND topics.status = ?", true, models.ContentStatusPublished)
+	}
+
+	if len(excludeIDs) > 0 {
+		query = query.Where("topic_quizzes.id NOT IN ?", excludeIDs)
+	}
+
+	err := query.Preload("Topic").Order("RANDOM()").First(&question).Error
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// GetFirstTopicAtLevel returns the earliest public topic of a language at a level
+func (r *placementRepository) GetFirstTopicAtLevel(languageID uint, level string) (*models.Topic, error) {
+	var topic models.Topic
+	err := r.db.
+		Where("language_id = ? AND level = ? AND is_public = ? AND status = ?", languageID, level, true, models.ContentStatusPublished).
+		Order("id ASC").
+		First(&topic).Error
+	if err != nil {
+		return nil, err
+	}
+	return &topic, nil
+}
@@ -4,6 +4,7 @@ import (
 	"dannyswat/learnspeak/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type QuizRepository interface {
@@ -14,6 +15,7 @@ type QuizRepository interface {
 	Delete(id uint) error
 	List(limit, offset int) ([]models.QuizQuestion, int64, error)
 	CountByTopicID(topicID uint) (int64, error)
+	Restore(question *models.QuizQuestion) error
 }
 
 type quizRepository struct {
@@ -93,3 +95,27 @@ func (r *quizRepository) CountByTopicID(topicID uint) (int64, error) {
 	err := r.db.Model(&models.QuizQuestion{}).Where("topic_id = ?", topicID).Count(&count).Error
 	return count, err
 }
+
+// Restore writes a quiz question back to an earlier state, recreating it if it was deleted.
+// A link to a word that has since been deleted is cleared.
+func (r *quizRepository) Restore(question *models.QuizQuestion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if question.WordID != nil {
+			existing, err := existingWordIDs(tx, []uint{*question.WordID})
+			if err != nil {
+				return err
+			}
+			if !existing[*question.WordID] {
+				question.WordID = nil
+			}
+		}
+
+		return tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"topic_id", "word_id", "question_type", "question_text", "audio_url", "image_url",
+				"correct_answer", "option_a", "option_b", "option_c", "option_d", "updated_at",
+			}),
+		}).Create(question).Error
+	})
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"errors"
+
+	"dannyswat/learnspeak/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrReviewNotFound is returned when the review doesn't exist
+var ErrReviewNotFound = errors.New("review not found")
+
+// ReviewFilter narrows a review query; zero values match everything
+type ReviewFilter struct {
+	ContentType string
+	ContentID   uint
+	Status      string
+	ReviewerID  uint
+	SubmittedBy uint
+}
+
+type ReviewRepository interface {
+	Create(review *models.ContentReview) error
+	GetByID(id uint) (*models.ContentReview, error)
+	GetPending(contentType string, contentID uint) (*models.ContentReview, error)
+	List(filter ReviewFilter, page, pageSize int) ([]models.ContentReview, int64, error)
+	SetReviewer(id uint, reviewerID uint) (bool, error)
+	Decide(review *models.ContentReview) (bool, error)
+	AddComment(comment *models.ReviewComment) error
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+// Create creates a new review
+func (r *reviewRepository) Create(review *models.ContentReview) error {
+	return r.db.Create(review).Error
+}
+
+// GetByID retrieves a review with its people and comments, oldest comment first
+func (r *reviewRepository) GetByID(id uint) (*models.ContentReview, error) {
+	var review models.ContentReview
+	err := r.db.
+		Preload("Submitter").
+		Preload("Reviewer").
+		Preload("Decider").
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC, id ASC")
+		}).
+		Preload("Comments.User").
+		First(&review, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetPending returns the open review of a topic or journey, or nil if there is none
+func (r *reviewRepository) GetPending(contentType string, contentID uint) (*models.ContentReview, error) {
+	var review models.ContentReview
+	err := r.db.
+		Where("content_type = ? AND content_id = ? AND status = ?", contentType, contentID, models.ReviewStatusPending).
+		First(&review).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// List retrieves reviews newest first
+func (r *reviewRepository) List(filter ReviewFilter, page, pageSize int) ([]models.ContentReview, int64, error) {
+	var reviews []models.ContentReview
+	var total int64
+
+	query := r.db.Model(&models.ContentReview{})
+	if filter.ContentType != "" {
+		query = query.Where("content_type = ?", filter.ContentType)
+	}
+	if filter.ContentID > 0 {
+		query = query.Where("content_id = ?", filter.ContentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ReviewerID > 0 {
+		query = query.Where("reviewer_id = ?", filter.ReviewerID)
+	}
+	if filter.SubmittedBy > 0 {
+		query = query.Where("submitted_by = ?", filter.SubmittedBy)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Submitter").
+		Preload("Reviewer").
+		Preload("Decider").
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&reviews).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, total, nil
+}
+
+// SetReviewer assigns a pending review, reporting whether it was still pending
+func (r *reviewRepository) SetReviewer(id uint, reviewerID uint) (bool, error) {
+	result := r.db.Model(&models.ContentReview{}).
+		Where("id = ? AND status = ?", id, models.ReviewStatusPending).
+		Update("reviewer_id", reviewerID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Decide records the outcome of a pending review, reporting whether it was still pending.
+// Only one decision wins when two reviewers act at once.
+func (r *reviewRepository) Decide(review *models.ContentReview) (bool, error) {
+	result := r.db.Model(&models.ContentReview{}).
+		Where("id = ? AND status = ?", review.ID, models.ReviewStatusPending).
+		Updates(map[string]interface{}{
+			"status":        review.Status,
+			"content_id":    review.ContentID,
+			"reviewer_id":   review.ReviewerID,
+			"decision_note": review.DecisionNote,
+			"decided_by":    review.DecidedBy,
+			"decided_at":    review.DecidedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// AddComment adds a comment to a review
+func (r *reviewRepository) AddComment(comment *models.ReviewComment) error {
+	return r.db.Create(comment).Error
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"errors"
+
+	"dannyswat/learnspeak/models"
+
+	"gorm.io/gorm"
+)
+
+// RevisionFilter narrows a revision listing; zero values match everything
+type RevisionFilter struct {
+	EntityType string
+	EntityID   uint
+	Action     string
+	UserID     uint
+}
+
+type RevisionRepository interface {
+	Create(revision *models.Revision) error
+	GetByID(id uint) (*models.Revision, error)
+	Latest(entityType string, entityID uint) (*models.Revision, error)
+	List(filter RevisionFilter, page, pageSize int) ([]models.Revision, int64, error)
+}
+
+type revisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+// Create stores a revision as the next version of its entity
+func (r *revisionRepository) Create(revision *models.Revision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.Revision{}).
+			Where("entity_type = ? AND entity_id = ?", revision.EntityType, revision.EntityID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		revision.Version = latest + 1
+		return tx.Omit("User").Create(revision).Error
+	})
+}
+
+// GetByID retrieves a revision with the user who made it
+func (r *revisionRepository) GetByID(id uint) (*models.Revision, error) {
+	var revision models.Revision
+	if err := r.db.Preload("User").First(&revision, id).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// Latest returns the most recent revision of an entity, or nil if it has none
+func (r *revisionRepository) Latest(entityType string, entityID uint) (*models.Revision, error) {
+	var revision models.Revision
+	err := r.db.
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("version DESC").
+		First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// List retrieves revisions newest first, without snapshots
+func (r *revisionRepository) List(filter RevisionFilter, page, pageSize int) ([]models.Revision, int64, error) {
+	var revisions []models.Revision
+	var total int64
+
+	query := r.db.Model(&models.Revision{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID > 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Omit("snapshot").
+		Preload("User").
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&revisions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+)
+
+// SearchHit is a raw match from the search query. A record can match more than once,
+// e.g. a word through its base word and through several translations.
+type SearchHit struct {
+	Type         string
+	ID           uint
+	Title        string
+	Snippet      string
+	Level        string
+	LanguageID   uint
+	MatchedField string
+	Score        float64
+}
+
+type SearchRepository interface {
+	Search(query string, includePrivate bool, limit int) ([]SearchHit, error)
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// searchSQL matches words, translations, topics, conversations and conversation lines using
+// full-text search (learnspeak_search_text splits CJK into per-character tokens) plus trigram
+// similarity for typos. Romanization is compared with tones stripped on both sides.
+// The expressions mirror the indexes in database/migrations/0004_search.up.sql.
+const searchSQL = `
+WITH p AS (
+	SELECT
+		learnspeak_search_text(@query) AS q,
+		learnspeak_strip_tones(@query) AS qr,
+		plainto_tsquery('simple', learnspeak_search_text(@query)) AS tsq,
+		plainto_tsquery('simple', learnspeak_strip_tones(@query)) AS rtsq
+)
+SELECT * FROM (
+	SELECT 'word' AS type, w.id, w.base_word AS title, coalesce(w.notes, '') AS snippet,
+		'' AS level, 0 AS language_id, 'baseWord' AS matched_field,
+		ts_rank(to_tsvector('simple', learnspeak_search_text(w.base_word || ' ' || coalesce(w.notes, ''))), p.tsq)
+			+ similarity(learnspeak_search_text(w.base_word), p.q) AS score
+	FROM words w CROSS JOIN p
+	WHERE to_tsvector('simple', learnspeak_search_text(w.base_word || ' ' || coalesce(w.notes, ''))) @@ p.tsq
+		OR learnspeak_search_text(w.base_word) % p.q
+
+	UNION ALL
+
+	SELECT 'word', w.id, w.base_word,
+		wt.translation || CASE WHEN coalesce(wt.romanization, '') <> '' THEN ' (' || wt.romanization || ')' ELSE '' END,
+		'', wt.language_id,
+		CASE WHEN to_tsvector('simple', learnspeak_search_text(wt.translation)) @@ p.tsq
+			OR learnspeak_search_text(wt.translation) % p.q THEN 'translation' ELSE 'romanization' END,
+		ts_rank(to_tsvector('simple', learnspeak_search_text(wt.translation)), p.tsq)
+			+ ts_rank(to_tsvector('simple', learnspeak_strip_tones(wt.romanization)), p.rtsq)
+			+ greatest(similarity(learnspeak_search_text(wt.translation), p.q), similarity(learnspeak_strip_tones(wt.romanization), p.qr))
+	FROM word_translations wt JOIN words w ON w.id = wt.word_id CROSS JOIN p
+	WHERE to_tsvector('simple', learnspeak_search_text(wt.translation)) @@ p.tsq
+		OR to_tsvector('simple', learnspeak_strip_tones(wt.romanization)) @@ p.rtsq
+		OR learnspeak_search_text(wt.translation) % p.q
+		OR learnspeak_strip_tones(wt.romanization) % p.qr
+
+	UNION ALL
+
+	SELECT 'topic', t.id, t.name, coalesce(t.description, ''), t.level, t.language_id, 'topic',
+		ts_rank(to_tsvector('simple', learnspeak_search_text(t.name || ' ' || coalesce(t.description, ''))), p.tsq)
+			+ similarity(learnspeak_search_text(t.name), p.q)
+	FROM topics t CROSS JOIN p
+	WHERE (@includePrivate OR (t.is_public AND t.status = 'published'))
+		AND (to_tsvector('simple', learnspeak_search_text(t.name || ' ' || coalesce(t.description, ''))) @@ p.tsq
+			OR learnspeak_search_text(t.name) % p.q)
+
+	UNION ALL
+
+	SELECT 'conversation', c.id, c.title, coalesce(c.description, ''), c.difficulty_level, c.language_id, 'conversation',
+		ts_rank(to_tsvector('simple', learnspeak_search_text(c.title || ' ' || coalesce(c.description, '') || ' ' || coalesce(c.context, ''))), p.tsq)
+			+ similarity(learnspeak_search_text(c.title), p.q)
+	FROM conversations c CROSS JOIN p
+	WHERE (@includePrivate OR EXISTS (
+			SELECT 1 FROM topic_conversations tc JOIN topics t ON t.id = tc.topic_id
+			WHERE tc.conversation_id = c.id AND t.is_public AND t.status = 'published'))
+		AND (to_tsvector('simple', learnspeak_search_text(c.title || ' ' || coalesce(c.description, '') || ' ' || coalesce(c.context, ''))) @@ p.tsq
+			OR learnspeak_search_text(c.title) % p.q)
+
+	UNION ALL
+
+	SELECT 'conversation', c.id, c.title, cl.target_text || ' / ' || cl.english_text, c.difficulty_level, c.language_id, 'line',
+		ts_rank(to_tsvector('simple', learnspeak_search_text(cl.english_text || ' ' || cl.target_text)), p.tsq)
+			+ ts_rank(to_tsvector('simple', learnspeak_strip_tones(cl.romanization)), p.rtsq)
+	FROM conversation_lines cl JOIN conversations c ON c.id = cl.conversation_id CROSS JOIN p
+	WHERE (@includePrivate OR EXISTS (
+			SELECT 1 FROM topic_conversations tc JOIN topics t ON t.id = tc.topic_id
+			WHERE tc.conversation_id = c.id AND t.is_public AND t.status = 'published'))
+		AND (to_tsvector('simple', learnspeak_search_text(cl.english_text || ' ' || cl.target_text)) @@ p.tsq
+			OR to_tsvector('simple', learnspeak_strip_tones(cl.romanization)) @@ p.rtsq)
+) hits
+ORDER BY score DESC
+LIMIT @limit
+`
+
+// Search returns the best matching hits across all searchable content. Topics that aren't
+// public and published, and conversations not linked to one, are only included when
+// includePrivate is set.
+func (r *searchRepository) Search(query string, includePrivate bool, limit int) ([]SearchHit, error) {
+	var hits []SearchHit
+	err := r.db.Raw(searchSQL, map[string]interface{}{
+		"query":          query,
+		"includePrivate": includePrivate,
+		"limit":          limit,
+	}).Scan(&hits).Error
+	return hits, err
+}
@@ -1,20 +1,24 @@
 package repositories
 
 import (
-	"fmt"
+	"errors"
 	"strings"
 
 	"dannyswat/learnspeak/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrTopicNotFound is returned when the topic doesn't exist
+var ErrTopicNotFound = errors.New("topic not found")
+
 type TopicRepository interface {
 	Create(topic *models.Topic) error
 	GetByID(id uint, includeWords bool) (*models.Topic, error)
 	Update(topic *models.Topic) error
 	Delete(id uint) error
-	List(search string, level, languageCode string, createdBy uint, isPublic *bool, page, pageSize int, includeWords bool) ([]models.Topic, int64, error)
+	List(search string, level, languageCode string, createdBy uint, isPublic *bool, status string, page, pageSize int, includeWords bool) ([]models.Topic, int64, error)
 	AddWords(topicID uint, wordIDs []uint) error
 	RemoveWords(topicID uint, wordIDs []uint) error
 	ReorderWords(topicID uint, wordIDs []uint) error
@@ -23,6 +27,13 @@ type TopicRepository interface {
 	GetQuizCount(topicID uint) (int64, error)
 	GetConversationCount(topicID uint) (int64, error)
 	GetJourneyUsageCount(topicID uint) (int64, error)
+	Restore(topic *models.Topic) error
+	SetStatus(id uint, from []string, to string) (bool, error)
+	GetDraftCopy(topicID uint) (*models.Topic, error)
+	CreateDraftCopy(draft *models.Topic) ([]uint, error)
+	MergeDraft(draftID, topicID uint) error
+	GetQuizQuestionIDs(topicID uint) ([]uint, error)
+	GetPublishedJourneyCount(topicID uint) (int64, error)
 }
 
 type topicRepository struct {
@@ -52,7 +63,7 @@ func (r *topicRepository) GetByID(id uint, includeWords bool) (*models.Topic, er
 	err := query.First(&topic, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("topic not found")
+			return nil, ErrTopicNotFound
 		}
 		return nil, err
 	}
@@ -79,14 +90,14 @@ func (r *topicRepository) Delete(id uint) error {
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("topic not found")
+		return ErrTopicNotFound
 	}
 
 	return nil
 }
 
 // List retrieves topics with filtering and pagination
-func (r *topicRepository) List(search string, level, languageCode string, createdBy uint, isPublic *bool, page, pageSize int, includeWords bool) ([]models.Topic, int64, error) {
+func (r *topicRepository) List(search string, level, languageCode string, createdBy uint, isPublic *bool, status string, page, pageSize int, includeWords bool) ([]models.Topic, int64, error) {
 	var topics []models.Topic
 	var total int64
 
@@ -116,6 +127,10 @@ func (r *topicRepository) List(search string, level, languageCode string, create
 		query = query.Where("is_public = ?", *isPublic)
 	}
 
+	if status != "" {
+		query = query.Where("topics.status = ?", status)
+	}
+
 	// Count total
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -235,3 +250,208 @@ func (r *topicRepository) GetJourneyUsageCount(topicID uint) (int64, error) {
 	err := r.db.Table("journey_topics").Where("topic_id = ?", topicID).Count(&count).Error
 	return count, err
 }
+
+// Restore writes a topic and its word list back to an earlier state, recreating the topic
+// if it was deleted. Words that have since been deleted are left out.
+func (r *topicRepository) Restore(topic *models.Topic) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "level", "language_id", "is_public", "updated_at"}),
+		}).Create(topic).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("topic_id = ?", topic.ID).Delete(&models.TopicWord{}).Error; err != nil {
+			return err
+		}
+
+		wordIDs := make([]uint, len(topic.Words))
+		for i := range topic.Words {
+			wordIDs[i] = topic.Words[i].WordID
+		}
+		existing, err := existingWordIDs(tx, wordIDs)
+		if err != nil {
+			return err
+		}
+
+		var topicWords []models.TopicWord
+		for _, wordID := range wordIDs {
+			if existing[wordID] {
+				topicWords = append(topicWords, models.TopicWord{
+					TopicID:       topic.ID,
+					WordID:        wordID,
+					SequenceOrder: len(topicWords) + 1,
+				})
+			}
+		}
+		if len(topicWords) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Create(&topicWords).Error
+	})
+}
+
+// SetStatus moves a topic to status to if it is currently in one of from, reporting whether it did
+func (r *topicRepository) SetStatus(id uint, from []string, to string) (bool, error) {
+	result := r.db.Model(&models.Topic{}).
+		Where("id = ? AND status IN ?", id, from).
+		Update("status", to)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetDraftCopy returns the open draft copy of a published topic, or nil if there is none
+func (r *topicRepository) GetDraftCopy(topicID uint) (*models.Topic, error) {
+	var draft models.Topic
+	err := r.db.Preload("Language").Preload("Creator").
+		Where("draft_of = ?", topicID).
+		Order("id DESC").
+		First(&draft).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// CreateDraftCopy creates draft as a copy of the topic in its DraftOf, with the topic's word
+// list, quiz questions and conversation links, and returns the IDs of the copied questions.
+// Conversations are linked, not copied.
+func (r *topicRepository) CreateDraftCopy(draft *models.Topic) ([]uint, error) {
+	var questionIDs []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		topicID := *draft.DraftOf
+		if err := tx.Create(draft).Error; err != nil {
+			return err
+		}
+
+		var words []models.TopicWord
+		if err := tx.Where("topic_id = ?", topicID).Order("sequence_order ASC").Find(&words).Error; err != nil {
+			return err
+		}
+		for i := range words {
+			words[i].ID = 0
+			words[i].TopicID = draft.ID
+		}
+		if len(words) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&words).Error; err != nil {
+				return err
+			}
+		}
+
+		var questions []models.QuizQuestion
+		if err := tx.Where("topic_id = ?", topicID).Order("id ASC").Find(&questions).Error; err != nil {
+			return err
+		}
+		for i := range questions {
+			questions[i].ID = 0
+			questions[i].TopicID = draft.ID
+		}
+		if len(questions) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&questions).Error; err != nil {
+				return err
+			}
+		}
+		for _, question := range questions {
+			questionIDs = append(questionIDs, question.ID)
+		}
+
+		var links []models.TopicConversation
+		if err := tx.Where("topic_id = ?", topicID).Order("sequence_order ASC").Find(&links).Error; err != nil {
+			return err
+		}
+		for i := range links {
+			links[i].ID = 0
+			links[i].TopicID = draft.ID
+		}
+		if len(links) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&links).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return questionIDs, nil
+}
+
+// MergeDraft publishes a draft copy by replacing the word list, quiz questions and
+// conversation links of the topic it copies with its own, writing its fields into the topic
+// and deleting the copy. The topic keeps its ID, so learners' progress and journeys are
+// unaffected.
+func (r *topicRepository) MergeDraft(draftID, topicID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var draft models.Topic
+		if err := tx.First(&draft, draftID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Topic{}).Where("id = ?", topicID).Updates(map[string]interface{}{
+			"name":        draft.Name,
+			"description": draft.Description,
+			"level":       draft.Level,
+			"language_id": draft.LanguageID,
+			"is_public":   draft.IsPublic,
+			"status":      models.ContentStatusPublished,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTopicNotFound
+		}
+
+		// The draft's word list replaces the topic's
+		if err := tx.Where("topic_id = ?", topicID).Delete(&models.TopicWord{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TopicWord{}).Where("topic_id = ?", draftID).Update("topic_id", topicID).Error; err != nil {
+			return err
+		}
+
+		// So do its quiz questions and conversation links
+		if err := tx.Where("topic_id = ?", topicID).Delete(&models.QuizQuestion{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.QuizQuestion{}).Where("topic_id = ?", draftID).Update("topic_id", topicID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("topic_id = ?", topicID).Delete(&models.TopicConversation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TopicConversation{}).Where("topic_id = ?", draftID).Update("topic_id", topicID).Error; err != nil {
+			return err
+		}
+
+		// Progress and bookmarks on a draft only come from teachers previewing it
+		if err := tx.Where("topic_id = ?", draftID).Delete(&models.UserProgress{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("topic_id = ?", draftID).Delete(&models.UserBookmark{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.Topic{}, draftID).Error
+	})
+}
+
+// GetQuizQuestionIDs returns the IDs of a topic's quiz questions
+func (r *topicRepository) GetQuizQuestionIDs(topicID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.QuizQuestion{}).Where("topic_id = ?", topicID).Order("id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetPublishedJourneyCount returns the number of published journeys using this topic
+func (r *topicRepository) GetPublishedJourneyCount(topicID uint) (int64, error) {
+	var count int64
+	err := r.db.Table("journey_topics").
+		Joins("JOIN journeys ON journeys.id = journey_topics.journey_id").
+		Where("journey_topics.topic_id = ? AND journeys.status = ?", topicID, models.ContentStatusPublished).
+		Count(&count).Error
+	return count, err
+}
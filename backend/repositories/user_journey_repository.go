@@ -99,15 +99,17 @@ func (r *userJourneyRepository) GetUserJourneys(userID uint, status *string, pag
 	var userJourneys []models.UserJourney
 	var total int64
 
+	// Learners only see published journeys; archived ones drop off their list
 	query := r.db.Model(&models.UserJourney{}).
-		Where("user_id = ?", userID).
+		Joins("JOIN journeys ON journeys.id = user_journeys.journey_id AND journeys.status = ?", models.ContentStatusPublished).
+		Where("user_journeys.user_id = ?", userID).
 		Preload("Journey.Language").
 		Preload("Journey.Creator").
 		Preload("AssignedByUser")
 
 	// Add status filter if provided
 	if status != nil && *status != "" {
-		query = query.Where("status = ?", *status)
+		query = query.Where("user_journeys.status = ?", *status)
 	}
 
 	// Count total
@@ -118,7 +120,7 @@ func (r *userJourneyRepository) GetUserJourneys(userID uint, status *string, pag
 	// Get paginated results
 	offset := (page - 1) * pageSize
 	err := query.Offset(offset).Limit(pageSize).
-		Order("assigned_at DESC").
+		Order("user_journeys.assigned_at DESC").
 		Find(&userJourneys).Error
 
 	return userJourneys, total, err
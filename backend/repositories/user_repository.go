@@ -39,6 +39,9 @@ type UserRepository interface {
 
 	// GetRoleByName retrieves a role by name
 	GetRoleByName(name string) (*models.Role, error)
+
+	// ReplaceRoles sets the user's roles to exactly roles
+	ReplaceRoles(user *models.User, roles []models.Role) error
 }
 
 type userRepository struct {
@@ -158,3 +161,8 @@ func (r *userRepository) GetRoleByName(name string) (*models.Role, error) {
 	err := r.db.Where("name = ?", name).First(&role).Error
 	return &role, err
 }
+
+// ReplaceRoles sets the user's roles to exactly roles
+func (r *userRepository) ReplaceRoles(user *models.User, roles []models.Role) error {
+	return r.db.Model(user).Association("Roles").Replace(roles)
+}
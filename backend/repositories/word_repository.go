@@ -7,6 +7,7 @@ import (
 	"dannyswat/learnspeak/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type WordRepository interface {
@@ -18,7 +19,13 @@ type WordRepository interface {
 	GetTranslationsByWordID(wordID uint) ([]models.WordTranslation, error)
 	CreateTranslation(translation *models.WordTranslation) error
 	UpdateTranslation(translation *models.WordTranslation) error
+	ReplaceTranslation(translation *models.WordTranslation) error
 	DeleteTranslation(id uint) error
+	ReplaceExamples(translationID uint, examples []models.WordExample) error
+	ReplaceRelations(wordID uint, relations []models.WordRelation) error
+	FindByBaseWordAndLanguage(baseWord string, languageID uint) (*models.Word, error)
+	FindByBaseWord(baseWord string) (*models.Word, error)
+	Restore(word *models.Word) error
 }
 
 type wordRepository struct {
@@ -34,12 +41,23 @@ func (r *wordRepository) Create(word *models.Word) error {
 	return r.db.Create(word).Error
 }
 
-// GetByID retrieves a word by ID with translations
+// preloadDetails loads a word's translations with their examples, and its related words
+func preloadDetails(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Translations.Language").
+		Preload("Translations.Examples", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence_order ASC, id ASC")
+		}).
+		Preload("Relations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("relation_type ASC, id ASC")
+		}).
+		Preload("Relations.RelatedWord")
+}
+
+// GetByID retrieves a word by ID with translations, examples and related words
 func (r *wordRepository) GetByID(id uint) (*models.Word, error) {
 	var word models.Word
-	err := r.db.
-		Preload("Creator").
-		Preload("Translations.Language").
+	err := preloadDetails(r.db.Preload("Creator")).
 		First(&word, id).Error
 
 	if err != nil {
@@ -56,7 +74,11 @@ func (r *wordRepository) GetByID(id uint) (*models.Word, error) {
 func (r *wordRepository) Update(word *models.Word) error {
 	// Use Model().Where().Updates() instead of Save() to ensure we're updating, not creating
 	// Save() can sometimes create a new record if ID is not properly set in GORM context
-	result := r.db.Model(&models.Word{}).Where("id = ?", word.ID).Updates(word)
+	// Select writes cleared fields too; translations and relations have their own methods
+	result := r.db.Model(&models.Word{}).Where("id = ?", word.ID).
+		Select("base_word", "image_url", "notes", "part_of_speech").
+		Omit(clause.Associations).
+		Updates(word)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -66,24 +88,33 @@ func (r *wordRepository) Update(word *models.Word) error {
 	return nil
 }
 
-// Delete deletes a word by ID
+// Delete deletes a word by ID with its translations, examples and links to related words
 func (r *wordRepository) Delete(id uint) error {
-	// First delete all translations
-	if err := r.db.Where("word_id = ?", id).Delete(&models.WordTranslation{}).Error; err != nil {
-		return err
-	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// First delete all translations and their examples
+		if err := tx.Where("translation_id IN (?)", tx.Model(&models.WordTranslation{}).Select("id").Where("word_id = ?", id)).
+			Delete(&models.WordExample{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("word_id = ?", id).Delete(&models.WordTranslation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("word_id = ? OR related_word_id = ?", id, id).Delete(&models.WordRelation{}).Error; err != nil {
+			return err
+		}
 
-	// Then delete the word
-	result := r.db.Delete(&models.Word{}, id)
-	if result.Error != nil {
-		return result.Error
-	}
+		// Then delete the word
+		result := tx.Delete(&models.Word{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("word not found")
-	}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("word not found")
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // List retrieves words with filtering and pagination
@@ -120,9 +151,7 @@ func (r *wordRepository) List(search string, languageID, createdBy uint, page, p
 	query = query.Offset(offset).Limit(pageSize)
 
 	// Fetch with relations
-	err := query.
-		Preload("Creator").
-		Preload("Translations.Language").
+	err := preloadDetails(query.Preload("Creator")).
 		Order("created_at DESC").
 		Find(&words).Error
 
@@ -151,24 +180,213 @@ func (r *wordRepository) CreateTranslation(translation *models.WordTranslation)
 
 // UpdateTranslation updates an existing translation
 func (r *wordRepository) UpdateTranslation(translation *models.WordTranslation) error {
-	return r.db.Model(&models.WordTranslation{}).Where("id = ?", translation.ID).Updates(map[string]interface{}{
+	return updateTranslation(r.db, translation)
+}
+
+// ReplaceTranslation updates an existing translation and replaces its example sentences
+// with translation.Examples, in one transaction
+func (r *wordRepository) ReplaceTranslation(translation *models.WordTranslation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateTranslation(tx, translation); err != nil {
+			return err
+		}
+		return replaceExamples(tx, translation.ID, translation.Examples)
+	})
+}
+
+func updateTranslation(tx *gorm.DB, translation *models.WordTranslation) error {
+	return tx.Model(&models.WordTranslation{}).Where("id = ?", translation.ID).Updates(map[string]interface{}{
 		"language_id":  translation.LanguageID,
 		"translation":  translation.Translation,
 		"romanization": translation.Romanization,
 		"audio_url":    translation.AudioURL,
+		"measure_word": translation.MeasureWord,
+		"gender":       translation.Gender,
 	}).Error
 }
 
-// DeleteTranslation deletes a translation by ID
+// DeleteTranslation deletes a translation by ID with its examples
 func (r *wordRepository) DeleteTranslation(id uint) error {
-	result := r.db.Delete(&models.WordTranslation{}, id)
-	if result.Error != nil {
-		return result.Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("translation_id = ?", id).Delete(&models.WordExample{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.WordTranslation{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("translation not found")
+		}
+
+		return nil
+	})
+}
+
+// ReplaceExamples replaces a translation's example sentences, numbering them in order
+func (r *wordRepository) ReplaceExamples(translationID uint, examples []models.WordExample) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceExamples(tx, translationID, examples)
+	})
+}
+
+// ReplaceRelations replaces a word's links to related words. Links are stored in both
+// directions, so the related words gain or lose the link too.
+func (r *wordRepository) ReplaceRelations(wordID uint, relations []models.WordRelation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRelations(tx, wordID, relations)
+	})
+}
+
+func replaceExamples(tx *gorm.DB, translationID uint, examples []models.WordExample) error {
+	if err := tx.Where("translation_id = ?", translationID).Delete(&models.WordExample{}).Error; err != nil {
+		return err
+	}
+	if len(examples) == 0 {
+		return nil
 	}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("translation not found")
+	rows := make([]models.WordExample, len(examples))
+	for i, example := range examples {
+		rows[i] = models.WordExample{
+			TranslationID: translationID,
+			Sentence:      example.Sentence,
+			Romanization:  example.Romanization,
+			Meaning:       example.Meaning,
+			AudioURL:      example.AudioURL,
+			SequenceOrder: i + 1,
+		}
 	}
+	return tx.Create(&rows).Error
+}
 
-	return nil
+// replaceRelations drops links to words that no longer exist, and to the word itself
+func replaceRelations(tx *gorm.DB, wordID uint, relations []models.WordRelation) error {
+	if err := tx.Where("word_id = ? OR related_word_id = ?", wordID, wordID).Delete(&models.WordRelation{}).Error; err != nil {
+		return err
+	}
+
+	ids := make([]uint, len(relations))
+	for i, relation := range relations {
+		ids[i] = relation.RelatedWordID
+	}
+	existing, err := existingWordIDs(tx, ids)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[uint]bool, len(relations))
+	rows := make([]models.WordRelation, 0, 2*len(relations))
+	for _, relation := range relations {
+		relatedID := relation.RelatedWordID
+		if relatedID == wordID || !existing[relatedID] || seen[relatedID] {
+			continue
+		}
+		seen[relatedID] = true
+		rows = append(rows,
+			models.WordRelation{WordID: wordID, RelatedWordID: relatedID, RelationType: relation.RelationType},
+			models.WordRelation{WordID: relatedID, RelatedWordID: wordID, RelationType: relation.RelationType},
+		)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Omit(clause.Associations).Create(&rows).Error
+}
+
+// FindByBaseWordAndLanguage finds a word with the same base word (case-insensitive)
+// that already has a translation in the given language
+func (r *wordRepository) FindByBaseWordAndLanguage(baseWord string, languageID uint) (*models.Word, error) {
+	var word models.Word
+	err := r.db.
+		Joins("JOIN word_translations ON word_translations.word_id = words.id").
+		Where("LOWER(words.base_word) = ? AND word_translations.language_id = ?", strings.ToLower(strings.TrimSpace(baseWord)), languageID).
+		Order("words.id ASC").
+		First(&word).Error
+	if err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+// FindByBaseWord finds the oldest word with the same base word (case-insensitive), with translations
+func (r *wordRepository) FindByBaseWord(baseWord string) (*models.Word, error) {
+	var word models.Word
+	err := r.db.
+		Preload("Translations.Language").
+		Where("LOWER(base_word) = ?", strings.ToLower(strings.TrimSpace(baseWord))).
+		Order("id ASC").
+		First(&word).Error
+	if err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+// Restore writes a word, its translations with their examples and its related words back to
+// an earlier state, recreating them if they were deleted. Translations not in
+// word.Translations are removed, and links to words deleted since are dropped.
+func (r *wordRepository) Restore(word *models.Word) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"base_word", "image_url", "notes", "part_of_speech", "updated_at"}),
+		}).Create(word).Error; err != nil {
+			return err
+		}
+
+		keep := make([]uint, len(word.Translations))
+		for i := range word.Translations {
+			keep[i] = word.Translations[i].ID
+		}
+		stale := tx.Model(&models.WordTranslation{}).Where("word_id = ?", word.ID)
+		if len(keep) > 0 {
+			stale = stale.Where("id NOT IN ?", keep)
+		}
+		var staleIDs []uint
+		if err := stale.Pluck("id", &staleIDs).Error; err != nil {
+			return err
+		}
+		if len(staleIDs) > 0 {
+			if err := tx.Where("translation_id IN ?", staleIDs).Delete(&models.WordExample{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", staleIDs).Delete(&models.WordTranslation{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range word.Translations {
+			translation := &word.Translations[i]
+			translation.WordID = word.ID
+			if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"word_id", "language_id", "translation", "romanization", "audio_url", "measure_word", "gender"}),
+			}).Create(translation).Error; err != nil {
+				return err
+			}
+			if err := replaceExamples(tx, translation.ID, translation.Examples); err != nil {
+				return err
+			}
+		}
+		return replaceRelations(tx, word.ID, word.Relations)
+	})
+}
+
+// existingWordIDs returns the subset of ids that still refer to a word
+func existingWordIDs(tx *gorm.DB, ids []uint) (map[uint]bool, error) {
+	existing := make(map[uint]bool, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	var found []uint
+	if err := tx.Model(&models.Word{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
 }
@@ -1,10 +1,16 @@
 package routes
 
 import (
+	"log/slog"
+
 	"dannyswat/learnspeak/config"
 	"dannyswat/learnspeak/database"
 	"dannyswat/learnspeak/handlers"
+	"dannyswat/learnspeak/hanzi"
+	"dannyswat/learnspeak/metrics"
 	"dannyswat/learnspeak/middleware"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/ratelimit"
 	"dannyswat/learnspeak/repositories"
 	"dannyswat/learnspeak/services"
 
@@ -12,7 +18,7 @@ import (
 )
 
 // SetupRoutes configures all application routes
-func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
+func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string, limiter *ratelimit.Limiter) {
 	// Initialize repositories
 	wordRepo := repositories.NewWordRepository(database.DB)
 	languageRepo := repositories.NewLanguageRepository(database.DB)
@@ -23,21 +29,45 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 	userProgressRepo := repositories.NewUserProgressRepository(database.DB)
 	quizRepo := repositories.NewQuizRepository(database.DB)
 	conversationRepo := repositories.NewConversationRepository(database.DB)
+	placementRepo := repositories.NewPlacementRepository(database.DB)
+	searchRepo := repositories.NewSearchRepository(database.DB)
+	aiUsageRepo := repositories.NewAIUsageRepository(database.DB)
+	assetRepo := repositories.NewAssetRepository(database.DB)
+	revisionRepo := repositories.NewRevisionRepository(database.DB)
+	auditRepo := repositories.NewAuditRepository(database.DB)
+	reviewRepo := repositories.NewReviewRepository(database.DB)
+	marketplaceRepo := repositories.NewMarketplaceRepository(database.DB)
 
 	// Initialize services
-	wordService := services.NewWordService(wordRepo)
-	languageService := services.NewLanguageService(languageRepo)
-	topicService := services.NewTopicService(topicRepo, languageRepo)
-	journeyService := services.NewJourneyService(journeyRepo, languageRepo, topicRepo, userJourneyRepo, userProgressRepo)
-	userService := services.NewUserService(userRepo, topicRepo, userProgressRepo, userJourneyRepo)
-	quizService := services.NewQuizService(quizRepo, topicRepo, userProgressRepo)
-	conversationService := services.NewConversationService(conversationRepo, languageRepo)
-	ttsService := services.NewTTSService(cfg)
-	translationService := services.NewTranslationService(cfg)
-	imageGenerationService, err := services.NewImageGenerationService()
+	auditService := services.NewAuditService(auditRepo)
+	revisionService := services.NewRevisionService(revisionRepo, wordRepo, topicRepo, conversationRepo, quizRepo)
+	hanziSource := hanzi.NewSource(cfg.HanziDataDir)
+	scriptService := services.NewScriptService(hanziSource)
+	wordService := services.NewWordService(wordRepo, languageRepo, revisionService, scriptService)
+	languageService := services.NewLanguageService(languageRepo, auditService)
+	topicService := services.NewTopicService(topicRepo, languageRepo, revisionService)
+	journeyService := services.NewJourneyService(journeyRepo, languageRepo, topicRepo, userJourneyRepo, userProgressRepo, auditService)
+	reviewService := services.NewReviewService(reviewRepo, topicRepo, journeyRepo, userRepo, revisionService)
+	marketplaceService := services.NewMarketplaceService(marketplaceRepo, database.DB, revisionService)
+	characterService := services.NewCharacterService(hanziSource, topicRepo, userProgressRepo)
+	userService := services.NewUserService(userRepo, topicRepo, userProgressRepo, userJourneyRepo, auditService)
+	quizService := services.NewQuizService(quizRepo, topicRepo, userProgressRepo, revisionService)
+	conversationService := services.NewConversationService(conversationRepo, languageRepo, topicRepo, revisionService)
+	placementService := services.NewPlacementService(placementRepo, languageRepo, journeyRepo, quizRepo, userJourneyRepo, userProgressRepo)
+	searchService := services.NewSearchService(searchRepo, languageRepo)
+	aiUsageService := services.NewAIUsageService(aiUsageRepo, cfg)
+	ttsService := services.NewTTSService(cfg, aiUsageService, assetRepo, languageRepo)
+	translationService := services.NewTranslationService(cfg, aiUsageService, languageRepo)
+	aiQuotaService := services.NewAIQuotaService(aiUsageRepo, map[string]int{
+		models.AIUsageImage:       cfg.AIDailyImageQuota,
+		models.AIUsageTranslation: cfg.AIDailyTranslationQuota,
+		models.AIUsageTTS:         cfg.AIDailyTTSQuota,
+	})
+	assetService := services.NewAssetService(assetRepo, cfg, uploadDir)
+	imageGenerationService, err := services.NewImageGenerationService(aiUsageService, assetService)
 	if err != nil {
 		// Log error but don't fail - image generation is optional
-		e.Logger.Errorf("Failed to initialize image generation service: %v", err)
+		slog.Error("Failed to initialize image generation service", "error", err)
 	}
 
 	// Initialize handlers
@@ -48,24 +78,42 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 	userHandler := handlers.NewUserHandler(userService)
 	quizHandler := handlers.NewQuizHandler(quizService)
 	conversationHandler := handlers.NewConversationHandler(conversationService)
-	uploadHandler := handlers.NewFileUploadHandler(uploadDir, 10) // 10MB max
-	ttsHandler := handlers.NewTTSHandler(ttsService)
-	translationHandler := handlers.NewTranslationHandler(translationService)
+	placementHandler := handlers.NewPlacementHandler(placementService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	revisionHandler := handlers.NewRevisionHandler(revisionService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	marketplaceHandler := handlers.NewMarketplaceHandler(marketplaceService)
+	characterHandler := handlers.NewCharacterHandler(characterService)
+	scriptHandler := handlers.NewScriptHandler(scriptService)
+	coursePackageHandler := handlers.NewCoursePackageHandler(services.NewCoursePackageService(database.DB, uploadDir, revisionService, assetService))
+	wordImportHandler := handlers.NewWordImportHandler(services.NewWordImportService(database.DB, revisionService))
+	uploadHandler := handlers.NewFileUploadHandler(uploadDir, 10, assetService) // 10MB max
+	assetHandler := handlers.NewAssetHandler(assetService, cfg.AssetGCGracePeriod)
+	ttsHandler := handlers.NewTTSHandler(ttsService, aiQuotaService)
+	translationHandler := handlers.NewTranslationHandler(translationService, aiQuotaService, scriptService)
+	aiUsageHandler := handlers.NewAIUsageHandler(aiQuotaService, aiUsageService)
+	healthHandler := handlers.NewHealthHandler(services.NewHealthService(database.DB, uploadDir, ttsService, translationService, imageGenerationService))
 
 	// Always create image generation handler (will show proper error if not configured)
 	var imageGenerationHandler *handlers.ImageGenerationHandler
 	if imageGenerationService != nil {
-		imageGenerationHandler = handlers.NewImageGenerationHandler(imageGenerationService)
+		imageGenerationHandler = handlers.NewImageGenerationHandler(imageGenerationService, aiQuotaService)
 	}
 
+	// Login lockout shares the rate limit store so it holds across replicas
+	handlers.SetLoginLockout(ratelimit.NewLockout(limiter.Store(), cfg.LoginMaxFailures, cfg.LoginAccountMaxFailures, cfg.LoginFailureWindow, cfg.LoginLockDuration))
+	handlers.SetAuditLog(auditService)
+
 	// API version 1
 	api := e.Group("/api/v1")
+	api.Use(middleware.RateLimitByIP(limiter, "api", cfg.APIRateLimit))
 
 	// Public routes (no authentication required)
 	auth := api.Group("/auth")
 	{
-		auth.POST("/register", handlers.Register)
-		auth.POST("/login", handlers.Login)
+		auth.POST("/register", handlers.Register, middleware.RateLimitByIP(limiter, "register", cfg.RegisterRateLimit))
+		auth.POST("/login", handlers.Login, middleware.RateLimitByIP(limiter, "login", cfg.LoginRateLimit))
 	}
 
 	// Public invitation routes (no authentication required)
@@ -85,9 +133,17 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 		// Image browser (list all uploaded images with pagination)
 		protected.GET("/upload/images", uploadHandler.ListImages)
 
+		// Asset library (uploaded and generated images and recordings)
+		protected.GET("/assets", assetHandler.ListAssets)
+		protected.GET("/assets/:id", assetHandler.GetAsset)
+		protected.GET("/assets/:id/references", assetHandler.GetAssetReferences)
+
 		// Languages
 		protected.GET("/languages", languageHandler.GetLanguages)
 
+		// Unified search across words, topics and conversations
+		protected.GET("/search", searchHandler.Search)
+
 		// User management
 		protected.GET("/users", userHandler.SearchUsers)
 		protected.GET("/users/learners", userHandler.GetLearners)
@@ -99,10 +155,20 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 
 		// Flashcard activities
 		flashcardHandler := handlers.NewFlashcardHandler(database.DB)
+		flashcardExportHandler := handlers.NewFlashcardExportHandler(services.NewFlashcardExportService(database.DB, uploadDir))
 		protected.GET("/topics/:id/flashcards", flashcardHandler.GetTopicFlashcards)
+		protected.GET("/topics/:id/flashcards/export", flashcardExportHandler.ExportTopicFlashcards)
 		protected.POST("/topics/:id/flashcards/complete", flashcardHandler.CompleteFlashcardActivity)
 		protected.POST("/words/:wordId/bookmark", flashcardHandler.ToggleBookmark)
 		protected.GET("/bookmarks", flashcardHandler.GetBookmarkedWords)
+		protected.GET("/bookmarks/export", flashcardExportHandler.ExportBookmarks)
+
+		// Chinese characters and handwriting practice
+		protected.GET("/characters", characterHandler.LookupCharacters)
+		protected.GET("/characters/:char", characterHandler.GetCharacter)
+		protected.GET("/topics/:id/handwriting", characterHandler.GetHandwritingPractice)
+		protected.POST("/topics/:id/handwriting/attempts", characterHandler.RecordHandwritingAttempt)
+		protected.POST("/convert", scriptHandler.ConvertScript)
 
 		protected.GET("/words/:id", wordHandler.GetWord)
 
@@ -124,6 +190,12 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 		// Invitation acceptance (authenticated users)
 		protected.POST("/invitations/:token/accept", journeyHandler.AcceptInvitation)
 
+		// Adaptive placement tests (learners)
+		protected.POST("/placement-tests", placementHandler.StartPlacementTest)
+		protected.GET("/placement-tests/:id", placementHandler.GetPlacementTest)
+		protected.POST("/placement-tests/:id/answers", placementHandler.SubmitPlacementAnswer)
+		protected.POST("/placement-tests/:id/apply", placementHandler.ApplyPlacementResult)
+
 		// Example: Admin-only routes
 		admin := protected.Group("/admin")
 		admin.Use(middleware.RequireRole("admin"))
@@ -134,6 +206,25 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 			admin.GET("/users/:id", userHandler.GetUser)
 			admin.PUT("/users/:id", userHandler.UpdateUser)
 			admin.DELETE("/users/:id", userHandler.DeleteUser)
+
+			// Languages and their TTS, translation and display settings
+			admin.GET("/languages", languageHandler.ListAllLanguages)
+			admin.POST("/languages", languageHandler.CreateLanguage)
+			admin.GET("/languages/:id", languageHandler.GetLanguage)
+			admin.PUT("/languages/:id", languageHandler.UpdateLanguage)
+			admin.DELETE("/languages/:id", languageHandler.DeleteLanguage)
+
+			// Paid AI usage against daily quotas
+			admin.GET("/usage", aiUsageHandler.GetUsage)
+			admin.GET("/usage/monthly", aiUsageHandler.GetMonthlyUsage)
+			admin.GET("/usage/monthly/users/:id", aiUsageHandler.GetUserMonthlyUsage)
+
+			// Remove uploaded files no content refers to
+			admin.POST("/assets/gc", assetHandler.CollectGarbage)
+
+			// Append-only log of logins and user, journey, invitation and language changes
+			admin.GET("/audit", auditHandler.ListEvents)
+			admin.GET("/audit/export", auditHandler.ExportEvents)
 		}
 
 		// Example: Teacher routes
@@ -145,6 +236,14 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 			teacher.POST("/words", wordHandler.CreateWord)
 			teacher.PUT("/words/:id", wordHandler.UpdateWord)
 			teacher.DELETE("/words/:id", wordHandler.DeleteWord)
+			teacher.POST("/words/:id/translations/derive", wordHandler.DeriveTranslation)
+
+			// Asset library tags
+			teacher.PUT("/assets/:id/tags", assetHandler.UpdateAssetTags)
+
+			// Bulk word import/export (CSV/XLSX)
+			teacher.GET("/words/export", wordImportHandler.ExportWords)
+			teacher.POST("/words/import", wordImportHandler.ImportWords)
 
 			// Topic management
 			teacher.GET("/topics", topicHandler.ListTopics)
@@ -153,6 +252,9 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 			teacher.DELETE("/topics/:id", topicHandler.DeleteTopic)
 			teacher.POST("/topics/:id/words", topicHandler.AddWordsToTopic)
 			teacher.PUT("/topics/:id/words/reorder", topicHandler.ReorderWords)
+			teacher.POST("/topics/:id/draft", topicHandler.CreateDraft) // Editable copy of a published topic
+			teacher.POST("/topics/:id/archive", reviewHandler.ArchiveTopic)
+			teacher.POST("/topics/:id/unarchive", reviewHandler.UnarchiveTopic)
 
 			// Journey management
 			teacher.GET("/journeys", journeyHandler.ListJourneys)
@@ -163,6 +265,22 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 			teacher.POST("/journeys/:id/assign", journeyHandler.AssignJourney)
 			teacher.POST("/journeys/:id/unassign", journeyHandler.UnassignJourney)
 			teacher.GET("/journeys/:id/assignments", journeyHandler.GetJourneyAssignments)
+			teacher.POST("/journeys/:id/archive", reviewHandler.ArchiveJourney)
+			teacher.POST("/journeys/:id/unarchive", reviewHandler.UnarchiveJourney)
+
+			// Publishing workflow: draft -> in_review -> published -> archived
+			teacher.POST("/reviews", reviewHandler.SubmitReview)
+			teacher.GET("/reviews", reviewHandler.ListReviews)
+			teacher.GET("/reviews/:id", reviewHandler.GetReview)
+			teacher.PUT("/reviews/:id/reviewer", reviewHandler.AssignReviewer)
+			teacher.POST("/reviews/:id/comments", reviewHandler.AddComment)
+			teacher.POST("/reviews/:id/approve", reviewHandler.Approve)
+			teacher.POST("/reviews/:id/request-changes", reviewHandler.RequestChanges)
+			teacher.POST("/reviews/:id/withdraw", reviewHandler.Withdraw)
+
+			// Course packages (export/import as zip with media)
+			teacher.GET("/journeys/:id/export", coursePackageHandler.ExportJourney)
+			teacher.POST("/journeys/import", coursePackageHandler.ImportJourney)
 
 			// Journey invitations
 			teacher.POST("/journeys/:id/invite", journeyHandler.GenerateInvitation)
@@ -186,28 +304,49 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, uploadDir string) {
 			teacher.DELETE("/conversations/:id/lines/:lineId", conversationHandler.DeleteLine)
 			teacher.PUT("/conversations/:id/lines/reorder", conversationHandler.ReorderLines)
 
+			// Revision history of words, topics, conversations and quiz questions
+			teacher.GET("/revisions", revisionHandler.ListRevisions)
+			teacher.GET("/revisions/:id", revisionHandler.GetRevision)
+			teacher.POST("/revisions/:id/restore", revisionHandler.RestoreRevision)
+
+			// Marketplace of public content, with ratings and clone-to-my-library
+			teacher.GET("/marketplace", marketplaceHandler.ListContent)
+			teacher.GET("/marketplace/clones", marketplaceHandler.ListClones) // Own clones with attribution
+			teacher.GET("/marketplace/:contentType/:id", marketplaceHandler.GetContent)
+			teacher.GET("/marketplace/:contentType/:id/ratings", marketplaceHandler.ListRatings)
+			teacher.PUT("/marketplace/:contentType/:id/rating", marketplaceHandler.RateContent)
+			teacher.DELETE("/marketplace/:contentType/:id/rating", marketplaceHandler.DeleteRating)
+			teacher.POST("/marketplace/:contentType/:id/clone", marketplaceHandler.CloneContent)
+
 			// File uploads
 			teacher.POST("/upload/audio", uploadHandler.UploadAudio)
 			teacher.POST("/upload/image", uploadHandler.UploadImage)
 
+			// Own AI spend against the monthly budget
+			teacher.GET("/usage/monthly", aiUsageHandler.GetMyMonthlyUsage)
+
+			// Paid AI calls share a per-user rate limit on top of the daily quotas
+			aiRateLimit := middleware.RateLimitByUser(limiter, "ai", cfg.AIRateLimit)
+
 			// TTS (Text-to-Speech)
-			teacher.POST("/tts/generate", ttsHandler.GenerateTTS)
+			teacher.POST("/tts/generate", ttsHandler.GenerateTTS, aiRateLimit)
 			teacher.DELETE("/tts/cache", ttsHandler.DeleteCachedAudio)
 
 			// Translation (AI-powered)
-			teacher.POST("/translate", translationHandler.Translate)
-			teacher.POST("/translate/batch", translationHandler.BatchTranslate)
+			teacher.POST("/translate", translationHandler.Translate, aiRateLimit)
+			teacher.POST("/translate/batch", translationHandler.BatchTranslate, aiRateLimit)
 
 			// Image Generation (AI-powered)
 			if imageGenerationHandler != nil {
-				teacher.POST("/images/generate", imageGenerationHandler.GenerateImage)
-				teacher.POST("/images/generate/batch", imageGenerationHandler.BatchGenerateImages)
+				teacher.POST("/images/generate", imageGenerationHandler.GenerateImage, aiRateLimit)
+				teacher.POST("/images/generate/batch", imageGenerationHandler.BatchGenerateImages, aiRateLimit)
 			}
 		}
-	} // Health check endpoint (public)
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{
-			"status": "ok",
-		})
-	})
+	} // Health check endpoints (public)
+	e.GET("/health", healthHandler.Livez) // kept for existing monitors
+	e.GET("/livez", healthHandler.Livez)
+	e.GET("/readyz", healthHandler.Readyz)
+
+	// Prometheus metrics (protected by METRICS_TOKEN when set)
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()), middleware.MetricsAuth(cfg.MetricsToken))
 }
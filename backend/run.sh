@@ -5,4 +5,4 @@ cd "$SCRIPT_DIR"
 export CGO_CFLAGS="-I${SCRIPT_DIR}/lib/speechsdk/MicrosoftCognitiveServicesSpeech.framework/Headers"
 export CGO_LDFLAGS="-F${SCRIPT_DIR}/lib/speechsdk -framework MicrosoftCognitiveServicesSpeech -Wl,-rpath,${SCRIPT_DIR}/lib/speechsdk"
 
-go run main.go
\ No newline at end of file
+go run .
\ No newline at end of file
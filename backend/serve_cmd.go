@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/database"
+	appmiddleware "dannyswat/learnspeak/middleware"
+	"dannyswat/learnspeak/ratelimit"
+	"dannyswat/learnspeak/routes"
+	"dannyswat/learnspeak/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// runServeCommand migrates and seeds the database, then starts the API server
+func runServeCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	slog.Info("Starting LearnSpeak API Server", "port", cfg.Port, "environment", cfg.Environment)
+
+	// Connect to database
+	if err := database.Connect(cfg); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	// Run migrations
+	if err := database.Migrate(); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	// Seed default roles
+	if err := database.SeedDefaultRoles(); err != nil {
+		return fmt.Errorf("failed to seed default roles: %w", err)
+	}
+
+	// Seed admin user
+	if err := database.SeedAdminUser(); err != nil {
+		return fmt.Errorf("failed to seed admin user: %w", err)
+	}
+
+	// Initialize Echo
+	e := echo.New()
+
+	// Register custom validator
+	e.Validator = utils.NewValidator()
+
+	// Client IPs feed rate limits, so forwarding headers are only trusted from private-network proxies
+	if cfg.TrustProxyHeaders {
+		e.IPExtractor = appmiddleware.ProxyIPExtractor()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
+
+	rateLimitStore := newRateLimitStore(cfg)
+	defer rateLimitStore.Close()
+
+	// Middleware
+	e.Use(appmiddleware.RequestID())
+	e.Use(appmiddleware.ClientInfo())
+	e.Use(appmiddleware.Metrics())
+	e.Use(appmiddleware.RequestLogger())
+	e.Use(middleware.Recover())
+	e.Use(appmiddleware.SecurityHeaders(cfg.ContentSecurityPolicy, cfg.Environment == "production"))
+
+	// CORS middleware - only needed for development with separate frontend server
+	if cfg.CORSAllowedOrigins != "" {
+		origins := []string{}
+		for _, origin := range splitAndTrim(cfg.CORSAllowedOrigins, ",") {
+			if origin != "" {
+				origins = append(origins, origin)
+			}
+		}
+		if len(origins) > 0 {
+			e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+				AllowOrigins: origins,
+				AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE, echo.PATCH},
+				AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+			}))
+			slog.Info("CORS enabled", "origins", origins)
+		}
+	}
+
+	// Request size limit
+	e.Use(middleware.BodyLimit(strconv.FormatInt(cfg.MaxUploadSize, 10)))
+
+	// Create uploads directory
+	uploadsDir := "./uploads"
+	if err := os.MkdirAll(uploadsDir, 0755); err != nil {
+		slog.Warn("Failed to create uploads directory", "error", err)
+	}
+
+	// Serve uploaded files
+	e.Static("/uploads", uploadsDir)
+
+	// Setup routes
+	routes.SetupRoutes(e, cfg, uploadsDir, ratelimit.NewLimiter(rateLimitStore))
+
+	// Serve static files from frontend build (production)
+	// The frontend build should be placed in ./frontend
+	staticDir := "./frontend"
+	if _, err := os.Stat(staticDir); err == nil {
+		e.Use(middleware.StaticWithConfig(middleware.StaticConfig{
+			Root:   staticDir,
+			Index:  "index.html",
+			Browse: false,
+			HTML5:  true, // Support client-side routing
+		}))
+		slog.Info("Serving static files", "dir", staticDir)
+	} else {
+		slog.Info("Static files directory not found, running in API-only mode", "dir", staticDir)
+	}
+
+	// Start server
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		ReadTimeout:       cfg.ServerReadTimeout,
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      cfg.ServerWriteTimeout,
+		IdleTimeout:       cfg.ServerIdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- e.StartServer(server)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to start server: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Stop accepting connections and let in-flight requests finish, then wait for
+	// background work. Both share one deadline; a second signal exits immediately.
+	stop()
+	slog.Info("Shutting down", "timeout", cfg.ShutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server did not drain in time", "error", err)
+	}
+	if err := utils.Workers.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Background workers did not finish in time", "error", err)
+	}
+
+	slog.Info("Server stopped")
+	return nil
+}
+
+// newRateLimitStore connects to RATE_LIMIT_REDIS_URL when set. If Redis is unreachable the
+// server still starts, with counters kept per instance.
+func newRateLimitStore(cfg *config.Config) ratelimit.Store {
+	if cfg.RateLimitRedisURL == "" {
+		return ratelimit.NewMemoryStore()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	store, err := ratelimit.NewRedisStore(ctx, cfg.RateLimitRedisURL, "learnspeak:")
+	if err != nil {
+		slog.Error("Rate limit store unavailable, using in-memory counters", "error", err)
+		return ratelimit.NewMemoryStore()
+	}
+	slog.Info("Rate limiting with Redis")
+	return store
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/repositories"
+)
+
+// QuotaExceededError is returned when a reservation would exceed the user's daily quota
+type QuotaExceededError struct {
+	Category string
+	Limit    int
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("daily %s quota of %d exceeded", e.Category, e.Limit)
+}
+
+// AIQuotaReservation is a number of calls reserved against one day's quota. Calls that
+// turn out to be free (cache hits) or fail are handed back with Release.
+type AIQuotaReservation struct {
+	userID   uint
+	date     time.Time
+	category string
+	count    int
+}
+
+// AIQuotaService enforces per-user daily quotas on paid AI calls
+type AIQuotaService struct {
+	repo   repositories.AIUsageRepository
+	limits map[string]int
+}
+
+// NewAIQuotaService creates a quota service. limits maps a category to its daily quota;
+// a missing or zero entry means unlimited, but usage is still recorded.
+func NewAIQuotaService(repo repositories.AIUsageRepository, limits map[string]int) *AIQuotaService {
+	return &AIQuotaService{repo: repo, limits: limits}
+}
+
+// usageDate is the UTC day quotas are counted against
+func usageDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Reserve reserves n calls of category for userID today, or returns *QuotaExceededError
+func (s *AIQuotaService) Reserve(ctx context.Context, userID uint, category string, n int) (*AIQuotaReservation, error) {
+	reservation := &AIQuotaReservation{
+		userID:   userID,
+		date:     usageDate(time.Now()),
+		category: category,
+		count:    n,
+	}
+	if n <= 0 {
+		return reservation, nil
+	}
+
+	limit := s.limits[category]
+	ok, err := s.repo.Reserve(userID, reservation.date, category, n, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check AI quota: %w", err)
+	}
+	if !ok {
+		slog.InfoContext(ctx, "AI quota exceeded", "user_id", userID, "category", category, "limit", limit, "requested", n)
+		return nil, &QuotaExceededError{Category: category, Limit: limit}
+	}
+	return reservation, nil
+}
+
+// Release hands back n calls of a reservation. Failures are logged rather than returned,
+// since the caller's request has already succeeded or failed on its own terms.
+func (s *AIQuotaService) Release(ctx context.Context, reservation *AIQuotaReservation, n int) {
+	if reservation == nil || n <= 0 {
+		return
+	}
+	if n > reservation.count {
+		n = reservation.count
+	}
+	reservation.count -= n
+	if err := s.repo.Release(reservation.userID, reservation.date, reservation.category, n); err != nil {
+		slog.WarnContext(ctx, "Failed to release AI quota", "user_id", reservation.userID, "category", reservation.category, "count", n, "error", err)
+	}
+}
+
+// GetUsageReport returns every user's usage on date's UTC day
+func (s *AIQuotaService) GetUsageReport(date time.Time) (*dto.AIUsageReportResponse, error) {
+	day := usageDate(date)
+	usages, err := s.repo.ListByDate(day)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]dto.AIUserUsage, len(usages))
+	for i, u := range usages {
+		users[i] = dto.AIUserUsage{
+			UserID:   u.UserID,
+			Username: u.Username,
+			Name:     u.Name,
+			Usage:    u.Counts,
+		}
+	}
+
+	return &dto.AIUsageReportResponse{
+		Date:   day.Format("2006-01-02"),
+		Limits: s.limits,
+		Users:  users,
+	}, nil
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+	"dannyswat/learnspeak/utils"
+)
+
+// BudgetExceededError is returned instead of calling a paid provider once the month's
+// estimated spend has reached a budget cap
+type BudgetExceededError struct {
+	Scope   string // "user" or "organization"
+	Budget  float64
+	Spent   float64
+	ResetAt time.Time
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("monthly AI budget for this %s exhausted ($%.2f of $%.2f)", e.Scope, e.Spent, e.Budget)
+}
+
+// AIUsageEvent describes one provider call, or a cache hit that avoided one
+type AIUsageEvent struct {
+	Provider   string
+	Operation  string
+	Characters int
+	Images     int
+	CacheHit   bool
+}
+
+// AIPricing holds the list prices used to estimate cost, in USD
+type AIPricing struct {
+	TTSPerMillionChars         float64
+	TranslationPerMillionChars float64
+	ImagePerProvider           map[string]float64
+}
+
+// AIUsageService records AI provider calls in the ai_usage ledger, estimates their cost and
+// enforces monthly budget caps. A nil *AIUsageService records nothing and never blocks.
+type AIUsageService struct {
+	repo       repositories.AIUsageRepository
+	pricing    AIPricing
+	userBudget float64
+	orgBudget  float64
+}
+
+// NewAIUsageService creates a usage service with prices and budgets from cfg
+func NewAIUsageService(repo repositories.AIUsageRepository, cfg *config.Config) *AIUsageService {
+	return &AIUsageService{
+		repo: repo,
+		pricing: AIPricing{
+			TTSPerMillionChars:         cfg.AIPriceTTSPerMillionChars,
+			TranslationPerMillionChars: cfg.AIPriceTranslationPerMillionChars,
+			ImagePerProvider: map[string]float64{
+				models.AIProviderAzureOpenAI: cfg.AIPriceImageAzure,
+				models.AIProviderIdeogram:    cfg.AIPriceImageIdeogram,
+				models.AIProviderOpenAI:      cfg.AIPriceImageOpenAI,
+				// Self-hosted Stable Diffusion and placeholders cost nothing per image
+			},
+		},
+		userBudget: cfg.AIMonthlyUserBudget,
+		orgBudget:  cfg.AIMonthlyOrgBudget,
+	}
+}
+
+// EstimateCost prices an event. Cache hits are free.
+func (s *AIUsageService) EstimateCost(event AIUsageEvent) float64 {
+	if event.CacheHit {
+		return 0
+	}
+	switch event.Provider {
+	case models.AIProviderAzureTTS:
+		return float64(event.Characters) * s.pricing.TTSPerMillionChars / 1e6
+	case models.AIProviderAzureTranslator:
+		return float64(event.Characters) * s.pricing.TranslationPerMillionChars / 1e6
+	default:
+		return float64(event.Images) * s.pricing.ImagePerProvider[event.Provider]
+	}
+}
+
+// Record appends event to the ledger, attributed to the user in ctx. Failures are logged
+// so accounting problems never fail the generation that has already happened.
+func (s *AIUsageService) Record(ctx context.Context, event AIUsageEvent) {
+	if s == nil {
+		return
+	}
+
+	entry := &models.AIUsage{
+		Provider:      event.Provider,
+		Operation:     event.Operation,
+		Characters:    event.Characters,
+		Images:        event.Images,
+		EstimatedCost: s.EstimateCost(event),
+		CacheHit:      event.CacheHit,
+	}
+	if userID, ok := utils.UserIDFromContext(ctx); ok {
+		entry.UserID = &userID
+	}
+	if err := s.repo.CreateEntry(entry); err != nil {
+		slog.WarnContext(ctx, "Failed to record AI usage", "provider", event.Provider, "operation", event.Operation, "error", err)
+	}
+}
+
+// CheckBudget returns *BudgetExceededError when the user in ctx or the organisation has
+// reached its monthly budget. Call it only before a paid provider call, so cached results
+// stay available.
+func (s *AIUsageService) CheckBudget(ctx context.Context) error {
+	if s == nil || (s.userBudget <= 0 && s.orgBudget <= 0) {
+		return nil
+	}
+
+	from, to := monthRange(time.Now())
+	if userID, ok := utils.UserIDFromContext(ctx); ok && s.userBudget > 0 {
+		spent, err := s.repo.SumCost(from, to, &userID)
+		if err != nil {
+			return fmt.Errorf("failed to check AI budget: %w", err)
+		}
+		if spent >= s.userBudget {
+			return &BudgetExceededError{Scope: "user", Budget: s.userBudget, Spent: spent, ResetAt: to}
+		}
+	}
+	if s.orgBudget > 0 {
+		spent, err := s.repo.SumCost(from, to, nil)
+		if err != nil {
+			return fmt.Errorf("failed to check AI budget: %w", err)
+		}
+		if spent >= s.orgBudget {
+			return &BudgetExceededError{Scope: "organization", Budget: s.orgBudget, Spent: spent, ResetAt: to}
+		}
+	}
+	return nil
+}
+
+// GetMonthlyReport returns the organisation's spend in month, by operation and by user
+func (s *AIUsageService) GetMonthlyReport(month time.Time) (*dto.AIMonthlyUsageReport, error) {
+	from, to := monthRange(month)
+	summaries, err := s.repo.SummarizeByOperation(from, to, nil)
+	if err != nil {
+		return nil, err
+	}
+	costs, err := s.repo.SummarizeByUser(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.AIMonthlyUsageReport{
+		Month:      from.Format("2006-01"),
+		Budget:     s.orgBudget,
+		UserBudget: s.userBudget,
+		Breakdown:  toUsageBreakdown(summaries),
+		Users:      make([]dto.AIUserCost, len(costs)),
+	}
+	for _, b := range report.Breakdown {
+		report.EstimatedCost += b.EstimatedCost
+	}
+	for i, c := range costs {
+		report.Users[i] = dto.AIUserCost{
+			UserID:        c.UserID,
+			Username:      c.Username,
+			Name:          c.Name,
+			Calls:         c.Calls,
+			CacheHits:     c.CacheHits,
+			EstimatedCost: c.EstimatedCost,
+		}
+	}
+	return report, nil
+}
+
+// GetUserMonthlyReport returns one user's spend in month by operation
+func (s *AIUsageService) GetUserMonthlyReport(userID uint, month time.Time) (*dto.AIUserMonthlyUsageReport, error) {
+	from, to := monthRange(month)
+	summaries, err := s.repo.SummarizeByOperation(from, to, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.AIUserMonthlyUsageReport{
+		Month:     from.Format("2006-01"),
+		UserID:    userID,
+		Budget:    s.userBudget,
+		Breakdown: toUsageBreakdown(summaries),
+	}
+	for _, b := range report.Breakdown {
+		report.EstimatedCost += b.EstimatedCost
+	}
+	return report, nil
+}
+
+func toUsageBreakdown(summaries []repositories.AIUsageSummary) []dto.AIUsageBreakdown {
+	breakdown := make([]dto.AIUsageBreakdown, len(summaries))
+	for i, u := range summaries {
+		breakdown[i] = dto.AIUsageBreakdown{
+			Provider:      u.Provider,
+			Operation:     u.Operation,
+			Calls:         u.Calls,
+			CacheHits:     u.CacheHits,
+			Characters:    u.Characters,
+			Images:        u.Images,
+			EstimatedCost: u.EstimatedCost,
+		}
+	}
+	return breakdown
+}
+
+// monthRange returns the start of t's calendar month (UTC) and the start of the next
+func monthRange(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(0, 1, 0)
+}
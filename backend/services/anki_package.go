@@ -0,0 +1,336 @@
+package services
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Anki .apkg files are zip archives holding a SQLite collection (collection.anki2),
+// a "media" JSON index and the media files named by their index.
+// This writer produces the legacy schema 11 layout, which every Anki version can import.
+
+const (
+	// ankiModelID identifies the LearnSpeak note type; keeping it fixed lets repeated
+	// imports reuse the same note type instead of creating copies
+	ankiModelID int64 = 1735689600000
+
+	ankiFieldSeparator = "\x1f"
+)
+
+var ankiFieldNames = []string{"Word", "Image", "Translation", "Romanization", "Audio", "Notes"}
+
+const ankiSchema = `
+CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null, models text not null, decks text not null, dconf text not null, tags text not null);
+CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null, flags integer not null, data text not null);
+CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null, mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null, ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null);
+CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn on notes (usn);
+CREATE INDEX ix_cards_usn on cards (usn);
+CREATE INDEX ix_revlog_usn on revlog (usn);
+CREATE INDEX ix_cards_nid on cards (nid);
+CREATE INDEX ix_cards_sched on cards (did, queue, due);
+CREATE INDEX ix_revlog_cid on revlog (cid);
+CREATE INDEX ix_notes_csum on notes (csum);
+`
+
+// ankiDeck is the content of an .apkg export
+type ankiDeck struct {
+	Name  string
+	RTL   bool // translation fields are right-to-left
+	Notes []ankiNote
+	Media []ankiMedia
+}
+
+// ankiNote is one note using the LearnSpeak note type; Fields follow ankiFieldNames
+type ankiNote struct {
+	GUID   string
+	Fields []string
+	Tags   []string
+}
+
+// ankiMedia is a file embedded in the package under Name
+type ankiMedia struct {
+	Name string
+	Path string
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// writeAnkiPackage writes deck as an .apkg archive to w
+func writeAnkiPackage(deck *ankiDeck, w io.Writer) error {
+	tmp, err := os.CreateTemp("", "learnspeak-anki-*.anki2")
+	if err != nil {
+		return fmt.Errorf("failed to create collection file: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := writeAnkiCollection(tmp.Name(), deck); err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+
+	if err := addFileToZip(zw, "collection.anki2", tmp.Name()); err != nil {
+		return err
+	}
+
+	index := make(map[string]string, len(deck.Media))
+	for i, m := range deck.Media {
+		key := strconv.Itoa(i)
+		if err := addFileToZip(zw, key, m.Path); err != nil {
+			return err
+		}
+		index[key] = m.Name
+	}
+
+	mediaJSON, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	f, err := zw.Create("media")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(mediaJSON); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeAnkiCollection(path string, deck *ankiDeck) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("failed to open collection: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(ankiSchema); err != nil {
+		return fmt.Errorf("failed to create collection schema: %w", err)
+	}
+
+	now := time.Now()
+	sec := now.Unix()
+	ms := now.UnixMilli()
+	deckID := ankiDeckID(deck.Name)
+
+	conf, models, decks, dconf, err := ankiCollectionJSON(deck, deckID, sec)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		sec, ms, ms, conf, models, decks, dconf,
+	); err != nil {
+		return fmt.Errorf("failed to write collection: %w", err)
+	}
+
+	for i, note := range deck.Notes {
+		id := ms + int64(i)
+		tags := ""
+		if len(note.Tags) > 0 {
+			tags = " " + strings.Join(note.Tags, " ") + " "
+		}
+
+		if _, err := tx.Exec(
+			`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+			id, note.GUID, ankiModelID, sec, tags,
+			strings.Join(note.Fields, ankiFieldSeparator), note.Fields[0], ankiChecksum(note.Fields[0]),
+		); err != nil {
+			return fmt.Errorf("failed to write note: %w", err)
+		}
+
+		// New card: type 0, queue 0, due is the position in the new queue
+		if _, err := tx.Exec(
+			`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			id, id, deckID, sec, i+1,
+		); err != nil {
+			return fmt.Errorf("failed to write card: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ankiCollectionJSON builds the JSON documents stored in the col table
+func ankiCollectionJSON(deck *ankiDeck, deckID, mod int64) (conf, models, decks, dconf string, err error) {
+	fields := make([]map[string]interface{}, len(ankiFieldNames))
+	for i, name := range ankiFieldNames {
+		fields[i] = map[string]interface{}{
+			"name":   name,
+			"ord":    i,
+			"sticky": false,
+			"rtl":    deck.RTL && (name == "Translation" || name == "Romanization"),
+			"font":   "Arial",
+			"size":   20,
+			"media":  []string{},
+		}
+	}
+
+	model := map[string]interface{}{
+		"id":    ankiModelID,
+		"name":  "LearnSpeak Flashcard",
+		"type":  0,
+		"mod":   mod,
+		"usn":   -1,
+		"sortf": 0,
+		"did":   deckID,
+		"tmpls": []map[string]interface{}{{
+			"name":  "Card 1",
+			"ord":   0,
+			"qfmt":  "<div class=word>{{Word}}</div>{{#Image}}<div>{{Image}}</div>{{/Image}}",
+			"afmt":  "{{FrontSide}}<hr id=answer><div class=translation>{{Translation}}</div><div class=romanization>{{Romanization}}</div>{{Audio}}{{#Notes}}<div class=notes>{{Notes}}</div>{{/Notes}}",
+			"did":   nil,
+			"bqfmt": "",
+			"bafmt": "",
+		}},
+		"flds":      fields,
+		"css":       ".card { font-family: arial; font-size: 24px; text-align: center; }\n.translation { font-size: 32px; }\n.romanization, .notes { color: #666; font-size: 18px; }\nimg { max-width: 100%; max-height: 300px; }",
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"tags":      []string{},
+		"vers":      []string{},
+		"req":       []interface{}{[]interface{}{0, "any", []int{0}}},
+	}
+
+	newDeck := func(id int64, name string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":               id,
+			"name":             name,
+			"desc":             "",
+			"mod":              mod,
+			"usn":              -1,
+			"collapsed":        false,
+			"browserCollapsed": false,
+			"newToday":         []int{0, 0},
+			"revToday":         []int{0, 0},
+			"lrnToday":         []int{0, 0},
+			"timeToday":        []int{0, 0},
+			"dyn":              0,
+			"conf":             1,
+			"extendNew":        10,
+			"extendRev":        50,
+		}
+	}
+
+	deckConf := map[string]interface{}{
+		"id":       1,
+		"name":     "Default",
+		"mod":      0,
+		"usn":      0,
+		"maxTaken": 60,
+		"autoplay": true,
+		"timer":    0,
+		"replayq":  true,
+		"dyn":      false,
+		"new": map[string]interface{}{
+			"delays":        []int{1, 10},
+			"ints":          []int{1, 4, 7},
+			"initialFactor": 2500,
+			"order":         1,
+			"perDay":        20,
+			"bury":          true,
+			"separate":      true,
+		},
+		"rev": map[string]interface{}{
+			"perDay":   200,
+			"ease4":    1.3,
+			"fuzz":     0.05,
+			"ivlFct":   1,
+			"maxIvl":   36500,
+			"minSpace": 1,
+			"bury":     true,
+		},
+		"lapse": map[string]interface{}{
+			"delays":      []int{10},
+			"mult":        0,
+			"minInt":      1,
+			"leechFails":  8,
+			"leechAction": 0,
+		},
+	}
+
+	parts := []interface{}{
+		map[string]interface{}{
+			"nextPos":       1,
+			"estTimes":      true,
+			"activeDecks":   []int64{deckID},
+			"sortType":      "noteFld",
+			"timeLim":       0,
+			"sortBackwards": false,
+			"addToCur":      true,
+			"curDeck":       deckID,
+			"newBust":       true,
+			"curModel":      strconv.FormatInt(ankiModelID, 10),
+			"dueCounts":     true,
+			"collapseTime":  1200,
+		},
+		map[string]interface{}{strconv.FormatInt(ankiModelID, 10): model},
+		map[string]interface{}{
+			"1":                           newDeck(1, "Default"),
+			strconv.FormatInt(deckID, 10): newDeck(deckID, deck.Name),
+		},
+		map[string]interface{}{"1": deckConf},
+	}
+
+	out := make([]string, len(parts))
+	for i, part := range parts {
+		data, err := json.Marshal(part)
+		if err != nil {
+			return "", "", "", "", err
+		}
+		out[i] = string(data)
+	}
+	return out[0], out[1], out[2], out[3], nil
+}
+
+// ankiDeckID derives a stable deck ID from the deck name so re-imports land in the same deck
+func ankiDeckID(name string) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int64(h.Sum32()) + 1<<32
+}
+
+// ankiChecksum is the first 8 hex digits of the SHA1 of the field with HTML stripped
+func ankiChecksum(field string) int64 {
+	sum := sha1.Sum([]byte(htmlTagPattern.ReplaceAllString(field, "")))
+	v, _ := strconv.ParseInt(hex.EncodeToString(sum[:])[:8], 16, 64)
+	return v
+}
+
+func addFileToZip(zw *zip.Writer, name, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer src.Close()
+
+	dst, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
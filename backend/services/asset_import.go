@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"dannyswat/learnspeak/audio"
+	"dannyswat/learnspeak/models"
+
+	"gorm.io/gorm"
+)
+
+// AssetImport prepares files for content that is saved later, e.g. by a course package
+// import. Files are cleaned or transcoded like uploads, but kept in a staging directory
+// until Commit moves them into place and records them, so content that fails to save
+// leaves nothing behind.
+type AssetImport struct {
+	s      *AssetService
+	dir    string
+	staged []stagedAsset
+	urls   map[string]string // source and content hash to URL, for files staged twice
+}
+
+type stagedAsset struct {
+	url          string
+	source       string
+	originalName string
+	path         string      // in the staging directory
+	info         *audio.Info // nil for images, and for recordings stored without ffmpeg
+}
+
+// NewImport starts an import, staging files in a new directory inside the upload directory
+// so Commit can rename them into place
+func (s *AssetService) NewImport() (*AssetImport, error) {
+	if err := os.MkdirAll(s.uploadDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(s.uploadDir, ".import-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	return &AssetImport{s: s, dir: dir, urls: make(map[string]string)}, nil
+}
+
+// AddImage strips the metadata from an image and returns the URL it will be served from.
+// Returns imaging.ErrInvalidImage for data that isn't a supported image.
+func (imp *AssetImport) AddImage(ctx context.Context, data []byte, name string) (string, error) {
+	cleaned, _, err := cleanImage(data)
+	if err != nil {
+		return "", err
+	}
+	return imp.stage(ctx, cleaned, strings.ToLower(path.Ext(name)), models.AssetSourceUpload, name, nil)
+}
+
+// AddAudio transcodes a recording to the canonical MP3 and returns the URL it will be served
+// from. Without ffmpeg the recording is kept as it is. Returns audio.ErrInvalidAudio for data
+// that isn't audio, or is silent.
+func (imp *AssetImport) AddAudio(ctx context.Context, data []byte, name string) (string, error) {
+	ext := strings.ToLower(path.Ext(name))
+	if imp.s.ffmpeg == nil {
+		return imp.stage(ctx, data, ext, models.AssetSourceAudio, name, nil)
+	}
+
+	in, err := os.CreateTemp(imp.dir, ".in-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(in.Name())
+	_, err = in.Write(data)
+	if closeErr := in.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to stage audio: %w", err)
+	}
+	out := strings.TrimSuffix(in.Name(), ext) + ".mp3"
+	defer os.Remove(out)
+
+	ctx, cancel := context.WithTimeout(ctx, imp.s.audioTimeout)
+	defer cancel()
+	info, err := imp.s.transcodeAudio(ctx, in.Name(), out)
+	if err != nil {
+		return "", err
+	}
+	mp3, err := os.ReadFile(out)
+	if err != nil {
+		return "", fmt.Errorf("failed to read processed audio: %w", err)
+	}
+	return imp.stage(ctx, mp3, ".mp3", models.AssetSourceAudio, name, info)
+}
+
+// stage writes data to the staging directory under its content hash. Content already in
+// the asset library is not staged again: its existing URL is returned instead.
+func (imp *AssetImport) stage(ctx context.Context, data []byte, ext, source, originalName string, info *audio.Info) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if url, ok := imp.urls[source+":"+hash]; ok {
+		return url, nil
+	}
+
+	existing, err := imp.s.repo.FindByHash(source, hash)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("failed to look up duplicate: %w", err)
+	}
+	if err == nil {
+		if existingPath, ok := resolveUploadPath(imp.s.uploadDir, existing.URL); ok {
+			// Restarts the garbage collection grace period, as for duplicate uploads
+			now := time.Now()
+			if os.Chtimes(existingPath, now, now) == nil {
+				slog.DebugContext(ctx, "Reused asset for imported file", "name", originalName, "existing", existing.URL)
+				imp.urls[source+":"+hash] = existing.URL
+				return existing.URL, nil
+			}
+		}
+	}
+
+	filename := hash[:32] + ext
+	stagedPath := filepath.Join(imp.dir, filename)
+	if err := os.WriteFile(stagedPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to stage %s: %w", originalName, err)
+	}
+
+	url := path.Join("/uploads", assetSourceDirs[source], filename)
+	imp.staged = append(imp.staged, stagedAsset{
+		url:          url,
+		source:       source,
+		originalName: originalName,
+		path:         stagedPath,
+		info:         info,
+	})
+	imp.urls[source+":"+hash] = url
+	return url, nil
+}
+
+// Commit moves the staged files into place and records them in the asset library. Call it
+// once the content referring to them is saved. Files that fail to be recorded are logged
+// and left for "learnspeak assets process".
+func (imp *AssetImport) Commit(ctx context.Context) error {
+	for _, staged := range imp.staged {
+		destPath, ok := resolveUploadPath(imp.s.uploadDir, staged.url)
+		if !ok {
+			return fmt.Errorf("%s is not in the upload directory", staged.url)
+		}
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return fmt.Errorf("failed to create media directory: %w", err)
+		}
+		if err := os.Rename(staged.path, destPath); err != nil {
+			return fmt.Errorf("failed to move %s into place: %w", staged.url, err)
+		}
+
+		if err := imp.record(ctx, staged, destPath); err != nil {
+			slog.WarnContext(ctx, "Failed to record imported file", "url", staged.url, "error", err)
+		}
+	}
+	imp.staged = nil
+	return nil
+}
+
+func (imp *AssetImport) record(ctx context.Context, staged stagedAsset, destPath string) error {
+	if staged.source == models.AssetSourceAudio {
+		if staged.info == nil {
+			return nil
+		}
+		_, err := imp.s.recordAudio(ctx, staged.url, destPath, staged.info, staged.originalName, false)
+		return err
+	}
+
+	asset, img, err := imp.s.recordImage(ctx, staged.url, staged.source, staged.originalName, false)
+	if err != nil {
+		return err
+	}
+	if imp.s.variants {
+		return imp.s.buildVariants(ctx, asset, img)
+	}
+	return nil
+}
+
+// Discard removes the staging directory with any file not committed. It is safe to call
+// after Commit.
+func (imp *AssetImport) Discard() {
+	if err := os.RemoveAll(imp.dir); err != nil {
+		slog.Warn("Failed to remove import staging directory", "dir", imp.dir, "error", err)
+	}
+}
@@ -0,0 +1,923 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"dannyswat/learnspeak/audio"
+	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/imaging"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+	"dannyswat/learnspeak/utils"
+
+	"gorm.io/gorm"
+)
+
+// assetVariantsDir holds every variant, outside the directories the image browser lists
+const assetVariantsDir = "image-variants"
+
+// assetSourceDirs maps asset sources to their directory inside the upload directory
+var assetSourceDirs = map[string]string{
+	models.AssetSourceUpload:    "image",
+	models.AssetSourceProfile:   "profile",
+	models.AssetSourceGenerated: "image-cache",
+	models.AssetSourceAudio:     "audio",
+}
+
+// gcSources are the asset sources CollectGarbage cleans up. The generated image cache is
+// pruned with the other caches by CacheService.
+var gcSources = []string{models.AssetSourceUpload, models.AssetSourceProfile, models.AssetSourceAudio}
+
+// ErrAssetInUse is returned when deleting a file that content still refers to
+var ErrAssetInUse = errors.New("file is used by other content")
+
+type assetVariantSize struct {
+	name    string
+	maxSize int
+}
+
+// AssetService records uploaded and generated files in the asset library. Images are stripped
+// of metadata and get dimensions, a blurhash and resized and WebP/AVIF variants; audio is
+// transcoded to normalised mono MP3 and gets its duration.
+type AssetService struct {
+	repo      repositories.AssetRepository
+	uploadDir string
+	variants  bool
+	sizes     []assetVariantSize
+	encoders  []*imaging.ExternalEncoder
+
+	ffmpeg       *audio.FFmpeg
+	audioOptions audio.Options
+	audioTimeout time.Duration
+}
+
+// NewAssetService creates an asset service. WebP and AVIF variants are only built when
+// their encoder commands are installed, and audio is only processed when ffmpeg is.
+func NewAssetService(repo repositories.AssetRepository, cfg *config.Config, uploadDir string) *AssetService {
+	s := &AssetService{
+		repo:      repo,
+		uploadDir: uploadDir,
+		variants:  cfg.ImageVariantsEnabled,
+		sizes: []assetVariantSize{
+			{models.AssetVariantThumb, cfg.ImageThumbSize},
+			{models.AssetVariantMedium, cfg.ImageMediumSize},
+		},
+		ffmpeg: audio.NewFFmpeg(cfg.FFmpegPath, cfg.FFprobePath),
+		audioOptions: audio.Options{
+			SampleRate:  cfg.AudioSampleRate,
+			Bitrate:     cfg.AudioBitrate,
+			Loudness:    cfg.AudioLoudnessTarget,
+			TrimSilence: cfg.AudioTrimSilence,
+		},
+		audioTimeout: cfg.AudioProcessTimeout,
+	}
+
+	if s.ffmpeg == nil && cfg.FFmpegPath != "off" {
+		slog.Warn("ffmpeg not found, uploaded audio is stored without processing", "ffmpeg", cfg.FFmpegPath, "ffprobe", cfg.FFprobePath)
+	}
+
+	for _, enc := range []*imaging.ExternalEncoder{
+		imaging.NewWebPEncoder(cfg.ImageWebPEncoder),
+		imaging.NewAVIFEncoder(cfg.ImageAVIFEncoder),
+	} {
+		if enc != nil {
+			s.encoders = append(s.encoders, enc)
+		}
+	}
+
+	if s.variants {
+		formats := make([]string, len(s.encoders))
+		for i, enc := range s.encoders {
+			formats[i] = enc.Format
+		}
+		slog.Info("Image variants enabled", "thumb", cfg.ImageThumbSize, "medium", cfg.ImageMediumSize, "encoders", strings.Join(formats, ","))
+	}
+	return s
+}
+
+// ProcessImage cleans and records the image at url, an /uploads/ path, and builds its variants
+func (s *AssetService) ProcessImage(ctx context.Context, url, source string) (*dto.AssetResponse, error) {
+	asset, img, err := s.recordImage(ctx, url, source, "", false)
+	if err != nil {
+		return nil, err
+	}
+	if s.variants {
+		if err := s.buildVariants(ctx, asset, img); err != nil {
+			return nil, err
+		}
+	}
+	return s.toAssetResponse(asset), nil
+}
+
+// ProcessImageAsync cleans and records an image like ProcessImage, but builds the variants in
+// the background so the request doesn't wait for the encoders. An upload whose content is
+// already in the library is removed and the existing asset returned instead; generated
+// images are never deduplicated since the image cache finds them by name.
+func (s *AssetService) ProcessImageAsync(ctx context.Context, url, source, originalName string) (*dto.AssetResponse, error) {
+	asset, img, err := s.recordImage(ctx, url, source, originalName, source != models.AssetSourceGenerated)
+	if err != nil {
+		return nil, err
+	}
+	if s.variants && img != nil {
+		utils.Workers.Go("image-variants", func(ctx context.Context) {
+			if err := s.buildVariants(ctx, asset, img); err != nil {
+				slog.Warn("Failed to build image variants", "url", asset.URL, "error", err)
+			}
+		})
+	}
+	return s.toAssetResponse(asset), nil
+}
+
+// recordImage strips metadata from the file in place and upserts its asset row. The file is
+// rewritten upright when it relied on an EXIF orientation. With dedupe, a file whose content
+// is already recorded for source is removed and the existing asset returned with a nil image.
+func (s *AssetService) recordImage(ctx context.Context, url, source, originalName string, dedupe bool) (*models.Asset, image.Image, error) {
+	localPath, ok := resolveUploadPath(s.uploadDir, url)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not in the upload directory", url)
+	}
+
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	cleaned, decoded, err := cleanImage(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(cleaned, data) {
+		if err := writeFileAtomic(localPath, cleaned); err != nil {
+			return nil, nil, fmt.Errorf("failed to write cleaned image: %w", err)
+		}
+		slog.DebugContext(ctx, "Stripped image metadata", "url", url, "before", len(data), "after", len(cleaned))
+	}
+
+	sum := sha256.Sum256(cleaned)
+	hash := hex.EncodeToString(sum[:])
+	if dedupe {
+		existing, err := s.reuseDuplicate(ctx, url, localPath, source, hash)
+		if err != nil || existing != nil {
+			return existing, nil, err
+		}
+	}
+
+	bounds := decoded.Image.Bounds()
+	asset := &models.Asset{
+		URL:          url,
+		Kind:         models.AssetKindImage,
+		Source:       source,
+		Format:       decoded.Format,
+		Width:        bounds.Dx(),
+		Height:       bounds.Dy(),
+		Bytes:        int64(len(cleaned)),
+		Blurhash:     imaging.Blurhash(decoded.Image),
+		SHA256:       hash,
+		OriginalName: originalName,
+		UploadedBy:   uploaderFromContext(ctx),
+	}
+	if err := s.repo.Upsert(asset); err != nil {
+		return nil, nil, fmt.Errorf("failed to save asset: %w", err)
+	}
+	return asset, decoded.Image, nil
+}
+
+// cleanImage decodes an image and strips its metadata, re-encoding it upright when it
+// relied on an EXIF orientation
+func cleanImage(data []byte) ([]byte, *imaging.Decoded, error) {
+	decoded, err := imaging.Decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleaned := imaging.StripMetadata(data, decoded.Format)
+	if decoded.Orientation > 1 {
+		if cleaned, err = imaging.Encode(decoded.Image, imaging.FormatJPEG); err != nil {
+			return nil, nil, err
+		}
+	}
+	return cleaned, decoded, nil
+}
+
+// buildVariants writes the thumb and medium sizes in the original's format family (JPEG, or
+// PNG when it has transparency) plus every size, and the full image, in WebP and AVIF.
+// Sizes at least as large as the original are skipped.
+func (s *AssetService) buildVariants(ctx context.Context, asset *models.Asset, img image.Image) error {
+	if err := s.removeVariantFiles(asset.URL); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.uploadDir, assetVariantsDir, asset.Source)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create variants directory: %w", err)
+	}
+
+	baseFormat := imaging.FormatJPEG
+	if !imaging.IsOpaque(img) {
+		baseFormat = imaging.FormatPNG
+	}
+	longest := max(asset.Width, asset.Height)
+
+	var variants []models.AssetVariant
+	write := func(name, format string, sized image.Image, data []byte) error {
+		filename := fmt.Sprintf("%s_%s.%s", variantBaseName(asset.URL), name, variantExt(format))
+		if err := os.WriteFile(filepath.Join(dir, filename), data, 0644); err != nil {
+			return fmt.Errorf("failed to write variant: %w", err)
+		}
+		b := sized.Bounds()
+		variants = append(variants, models.AssetVariant{
+			Name:   name,
+			Format: format,
+			URL:    path.Join("/uploads", assetVariantsDir, asset.Source, filename),
+			Width:  b.Dx(),
+			Height: b.Dy(),
+			Bytes:  int64(len(data)),
+		})
+		return nil
+	}
+	encodeAll := func(name string, sized image.Image) error {
+		for _, enc := range s.encoders {
+			data, err := enc.Encode(ctx, sized)
+			if err != nil {
+				// A broken encoder shouldn't cost the other variants
+				slog.WarnContext(ctx, "Image encoder failed", "format", enc.Format, "url", asset.URL, "error", err)
+				continue
+			}
+			if err := write(name, enc.Format, sized, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, size := range s.sizes {
+		if size.maxSize <= 0 || size.maxSize >= longest {
+			continue
+		}
+		sized := imaging.Fit(img, size.maxSize)
+		data, err := imaging.Encode(sized, baseFormat)
+		if err != nil {
+			return err
+		}
+		if err := write(size.name, baseFormat, sized, data); err != nil {
+			return err
+		}
+		if err := encodeAll(size.name, sized); err != nil {
+			return err
+		}
+	}
+	if err := encodeAll(models.AssetVariantFull, img); err != nil {
+		return err
+	}
+
+	if err := s.repo.ReplaceVariants(asset.ID, variants); err != nil {
+		return fmt.Errorf("failed to save variants: %w", err)
+	}
+	asset.Variants = variants
+	slog.DebugContext(ctx, "Built image variants", "url", asset.URL, "variants", len(variants))
+	return nil
+}
+
+// AudioProcessingEnabled reports whether ffmpeg is available to process audio
+func (s *AssetService) AudioProcessingEnabled() bool {
+	return s.ffmpeg != nil
+}
+
+// ProcessAudio transcodes the uploaded recording at url to the canonical mono MP3, trimming
+// silence and normalising loudness, and records its duration. The original is replaced, so
+// the returned asset's URL ends in .mp3; a recording already in the library is removed and
+// the existing asset returned instead. Returns nil without ffmpeg.
+func (s *AssetService) ProcessAudio(ctx context.Context, url, originalName string) (*dto.AssetResponse, error) {
+	if s.ffmpeg == nil {
+		return nil, nil
+	}
+
+	localPath, ok := resolveUploadPath(s.uploadDir, url)
+	if !ok {
+		return nil, fmt.Errorf("%s is not in the upload directory", url)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.audioTimeout)
+	defer cancel()
+
+	tmp, err := os.CreateTemp(filepath.Dir(localPath), ".tmp-*.mp3")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	info, err := s.transcodeAudio(ctx, localPath, tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	mp3Path := strings.TrimSuffix(localPath, filepath.Ext(localPath)) + ".mp3"
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), mp3Path); err != nil {
+		return nil, fmt.Errorf("failed to save processed audio: %w", err)
+	}
+	if mp3Path != localPath {
+		os.Remove(localPath)
+	}
+
+	mp3URL := strings.TrimSuffix(url, path.Ext(url)) + ".mp3"
+	asset, err := s.recordAudio(ctx, mp3URL, mp3Path, info, originalName, true)
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Processed audio", "url", asset.URL, "duration", info.Duration.String())
+	return s.toAssetResponse(asset), nil
+}
+
+// transcodeAudio writes the recording at in to out as the canonical MP3 and probes the result
+func (s *AssetService) transcodeAudio(ctx context.Context, in, out string) (*audio.Info, error) {
+	// Probing first tells a corrupt or non-audio file apart from an ffmpeg failure
+	if _, err := s.ffmpeg.Probe(ctx, in); err != nil {
+		return nil, err
+	}
+	if err := s.ffmpeg.Transcode(ctx, in, out, s.audioOptions); err != nil {
+		return nil, err
+	}
+	info, err := s.ffmpeg.Probe(ctx, out)
+	if err != nil {
+		return nil, err
+	}
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("%w: recording is silent", audio.ErrInvalidAudio)
+	}
+	return info, nil
+}
+
+// RecordAudio records the duration and format of the audio file at url without changing it.
+// Returns nil without ffmpeg.
+func (s *AssetService) RecordAudio(ctx context.Context, url string) (*dto.AssetResponse, error) {
+	if s.ffmpeg == nil {
+		return nil, nil
+	}
+
+	localPath, ok := resolveUploadPath(s.uploadDir, url)
+	if !ok {
+		return nil, fmt.Errorf("%s is not in the upload directory", url)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.audioTimeout)
+	defer cancel()
+
+	info, err := s.ffmpeg.Probe(ctx, localPath)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := s.recordAudio(ctx, url, localPath, info, "", false)
+	if err != nil {
+		return nil, err
+	}
+	return s.toAssetResponse(asset), nil
+}
+
+// recordAudio upserts the asset row of the recording at localPath, deduplicating like recordImage
+func (s *AssetService) recordAudio(ctx context.Context, url, localPath string, info *audio.Info, originalName string, dedupe bool) (*models.Asset, error) {
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if dedupe {
+		existing, err := s.reuseDuplicate(ctx, url, localPath, models.AssetSourceAudio, hash)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
+	asset := &models.Asset{
+		URL:          url,
+		Kind:         models.AssetKindAudio,
+		Source:       models.AssetSourceAudio,
+		Format:       strings.TrimPrefix(path.Ext(url), "."),
+		Bytes:        int64(len(data)),
+		DurationMs:   info.Duration.Milliseconds(),
+		SampleRate:   info.SampleRate,
+		Channels:     info.Channels,
+		SHA256:       hash,
+		OriginalName: originalName,
+		UploadedBy:   uploaderFromContext(ctx),
+	}
+	if err := s.repo.Upsert(asset); err != nil {
+		return nil, fmt.Errorf("failed to save asset: %w", err)
+	}
+	return asset, nil
+}
+
+// reuseDuplicate returns the asset of source that already holds the content with hash,
+// removing the newly uploaded copy at localPath, or nil when the content is new
+func (s *AssetService) reuseDuplicate(ctx context.Context, url, localPath, source, hash string) (*models.Asset, error) {
+	existing, err := s.repo.FindByHash(source, hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up duplicate: %w", err)
+	}
+	if existing.URL == url {
+		return nil, nil
+	}
+
+	existingPath, ok := resolveUploadPath(s.uploadDir, existing.URL)
+	if !ok {
+		return nil, nil
+	}
+	// Touching the file restarts its garbage collection grace period, so it survives until
+	// the content being edited is saved
+	now := time.Now()
+	if err := os.Chtimes(existingPath, now, now); err != nil {
+		// The recorded file is gone; keep the new copy instead
+		return nil, nil
+	}
+
+	if err := os.Remove(localPath); err != nil {
+		return nil, fmt.Errorf("failed to remove duplicate upload: %w", err)
+	}
+	slog.DebugContext(ctx, "Reused asset for duplicate upload", "url", url, "existing", existing.URL)
+	return existing, nil
+}
+
+// uploaderFromContext returns the authenticated user's ID, or nil outside a request
+func uploaderFromContext(ctx context.Context) *uint {
+	if userID, ok := utils.UserIDFromContext(ctx); ok {
+		return &userID
+	}
+	return nil
+}
+
+// GetAsset returns an asset with its variants, tags and reference count
+func (s *AssetService) GetAsset(id uint) (*dto.AssetResponse, error) {
+	asset, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.withRefCounts([]models.Asset{*asset})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// GetReferences lists the content that uses an asset
+func (s *AssetService) GetReferences(id uint) (*dto.AssetReferencesResponse, error) {
+	asset, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := s.repo.References(asset.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AssetReferencesResponse{
+		AssetID:    asset.ID,
+		URL:        asset.URL,
+		References: make([]dto.AssetReferenceResponse, len(refs)),
+	}
+	for i, ref := range refs {
+		response.References[i] = dto.AssetReferenceResponse{Type: ref.Type, Field: ref.Field, ID: ref.ID}
+	}
+	return response, nil
+}
+
+// SetTags replaces an asset's tags. Tags are trimmed, lower-cased and deduplicated.
+func (s *AssetService) SetTags(id uint, tags []string) (*dto.AssetResponse, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if err := s.repo.SetTags(id, normalized); err != nil {
+		return nil, fmt.Errorf("failed to save tags: %w", err)
+	}
+	return s.GetAsset(id)
+}
+
+// GetAssetByURL returns the asset recorded for url
+func (s *AssetService) GetAssetByURL(url string) (*dto.AssetResponse, error) {
+	asset, err := s.repo.GetByURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return s.toAssetResponse(asset), nil
+}
+
+// GetAssetsByURLs returns the recorded assets of urls, keyed by URL
+func (s *AssetService) GetAssetsByURLs(urls []string) (map[string]*dto.AssetResponse, error) {
+	assets, err := s.repo.GetByURLs(urls)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*dto.AssetResponse, len(assets))
+	for url, asset := range assets {
+		result[url] = s.toAssetResponse(asset)
+	}
+	return result, nil
+}
+
+// ListAssets searches the asset library, newest first
+func (s *AssetService) ListAssets(params *dto.AssetFilterParams) (*dto.AssetListResponse, error) {
+	page, pageSize := params.Page, params.PageSize
+	assets, total, err := s.repo.List(repositories.AssetFilter{
+		Source:     params.Source,
+		Kind:       params.Kind,
+		Query:      strings.TrimSpace(params.Query),
+		Tag:        strings.TrimSpace(params.Tag),
+		UploadedBy: params.UploadedBy,
+		Unused:     params.Unused,
+	}, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.withRefCounts(assets)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AssetListResponse{
+		Assets:     responses,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// browsableImageSources are the sources the image browser lists
+var browsableImageSources = []string{models.AssetSourceUpload, models.AssetSourceGenerated}
+
+// SearchImages finds uploaded and generated images by the word, translation or prompt they
+// were generated for, the words that use them, their file name or tags, best match first
+func (s *AssetService) SearchImages(query string, page, pageSize int) (*dto.AssetListResponse, error) {
+	assets, total, err := s.repo.SearchImages(query, browsableImageSources, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.AssetResponse, len(assets))
+	for i := range assets {
+		responses[i] = *s.toAssetResponse(&assets[i])
+	}
+	return &dto.AssetListResponse{
+		Assets:     responses,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// DescribeImage records the word, translation and prompt a generated image was made for, so
+// the image browser can find it instead of generating another
+func (s *AssetService) DescribeImage(url, word, translation, prompt string) error {
+	return s.repo.Describe(url, strings.TrimSpace(word), strings.TrimSpace(translation), prompt)
+}
+
+// withRefCounts converts assets to responses carrying how many content rows use each one
+func (s *AssetService) withRefCounts(assets []models.Asset) ([]dto.AssetResponse, error) {
+	urls := make([]string, len(assets))
+	for i := range assets {
+		urls[i] = assets[i].URL
+	}
+	counts, err := s.repo.RefCounts(urls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count references: %w", err)
+	}
+
+	responses := make([]dto.AssetResponse, len(assets))
+	for i := range assets {
+		responses[i] = *s.toAssetResponse(&assets[i])
+		count := counts[assets[i].URL]
+		responses[i].RefCount = &count
+	}
+	return responses, nil
+}
+
+// AssetGCOptions controls which files CollectGarbage removes
+type AssetGCOptions struct {
+	// GracePeriod keeps unused files modified more recently, so a fresh upload isn't removed
+	// before the content using it is saved
+	GracePeriod time.Duration
+	// DryRun reports what would be removed without deleting anything
+	DryRun bool
+}
+
+// CollectGarbage removes uploaded images, profile photos and recordings that no content
+// refers to, with their variants and asset rows, and drops the rows of files that no longer
+// exist. Each file is checked again just before it is removed, so content saved during the
+// run keeps its file.
+func (s *AssetService) CollectGarbage(ctx context.Context, opts AssetGCOptions) (*dto.AssetGCResponse, error) {
+	inUse, err := s.repo.ReferencedURLs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referenced files: %w", err)
+	}
+
+	cutoff := time.Now().Add(-opts.GracePeriod)
+	result := &dto.AssetGCResponse{DryRun: opts.DryRun}
+
+	for _, source := range gcSources {
+		dirName := assetSourceDirs[source]
+		dirResult := dto.AssetGCDirResponse{Name: dirName}
+		dir := filepath.Join(s.uploadDir, dirName)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+
+		onDisk := make(map[string]bool, len(entries))
+		for _, entry := range entries {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+
+			url := path.Join("/uploads", dirName, entry.Name())
+			onDisk[url] = true
+			if inUse[url] {
+				dirResult.InUse++
+				continue
+			}
+			if info.ModTime().After(cutoff) {
+				dirResult.Kept++
+				continue
+			}
+
+			if !opts.DryRun {
+				used, err := s.repo.IsReferenced(url)
+				if err != nil {
+					return nil, fmt.Errorf("failed to check references: %w", err)
+				}
+				if used {
+					dirResult.InUse++
+					continue
+				}
+				if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+					return nil, fmt.Errorf("failed to remove %s: %w", url, err)
+				}
+				if err := s.DeleteAsset(url); err != nil {
+					return nil, fmt.Errorf("failed to remove asset %s: %w", url, err)
+				}
+			}
+			dirResult.Removed++
+			dirResult.RemovedBytes += info.Size()
+		}
+
+		recorded, err := s.repo.ListURLs(source)
+		if err != nil {
+			return nil, err
+		}
+		missing := 0
+		for _, url := range recorded {
+			if onDisk[url] || inUse[url] {
+				continue
+			}
+			if !opts.DryRun {
+				if err := s.DeleteAsset(url); err != nil {
+					return nil, fmt.Errorf("failed to remove asset %s: %w", url, err)
+				}
+			}
+			missing++
+		}
+		if missing > 0 {
+			slog.Info("Dropped assets whose file is missing", "dir", dirName, "count", missing, "dryRun", opts.DryRun)
+		}
+
+		result.Dirs = append(result.Dirs, dirResult)
+	}
+
+	return result, nil
+}
+
+// DeleteAsset removes the asset recorded for url and its variant files. The original file is
+// left to the caller.
+func (s *AssetService) DeleteAsset(url string) error {
+	if err := s.removeVariantFiles(url); err != nil {
+		return err
+	}
+	return s.repo.DeleteByURL(url)
+}
+
+// removeVariantFiles deletes every variant file of url, including formats no longer built
+func (s *AssetService) removeVariantFiles(url string) error {
+	return removeAssetVariantFiles(s.uploadDir, url)
+}
+
+func removeAssetVariantFiles(uploadDir, url string) error {
+	source, ok := AssetSourceForURL(url)
+	if !ok {
+		return nil
+	}
+
+	pattern := filepath.Join(uploadDir, assetVariantsDir, source, variantBaseName(url)+"_*")
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if err := os.Remove(match); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove variant: %w", err)
+		}
+	}
+	return nil
+}
+
+// AssetSourceForURL returns the asset source of an /uploads/ URL from its directory
+func AssetSourceForURL(url string) (string, bool) {
+	dir := path.Base(path.Dir(url))
+	for source, sourceDir := range assetSourceDirs {
+		if dir == sourceDir && path.Dir(path.Dir(url)) == "/uploads" {
+			return source, true
+		}
+	}
+	return "", false
+}
+
+// AssetBackfillResult counts the images a backfill visited
+type AssetBackfillResult struct {
+	Processed int
+	Skipped   int // already recorded with the same size
+	Failed    int
+}
+
+// Backfill records every image in the upload, profile and image cache directories, and every
+// uploaded recording, that has no asset yet, or every file when force is set. Recordings are
+// only probed: transcoding them would change URLs that content already refers to.
+func (s *AssetService) Backfill(ctx context.Context, force bool) (*AssetBackfillResult, error) {
+	result := &AssetBackfillResult{}
+	for _, source := range []string{models.AssetSourceUpload, models.AssetSourceProfile, models.AssetSourceGenerated, models.AssetSourceAudio} {
+		if source == models.AssetSourceAudio && s.ffmpeg == nil {
+			slog.Warn("Skipping audio: ffmpeg is not installed")
+			continue
+		}
+
+		dirName := assetSourceDirs[source]
+		entries, err := os.ReadDir(filepath.Join(s.uploadDir, dirName))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		urls := make([]string, 0, len(entries))
+		sizes := make(map[string]int64, len(entries))
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			url := path.Join("/uploads", dirName, entry.Name())
+			urls = append(urls, url)
+			sizes[url] = info.Size()
+		}
+
+		existing, err := s.repo.GetByURLs(urls)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, url := range urls {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			if asset, ok := existing[url]; ok && !force && asset.Bytes == sizes[url] {
+				result.Skipped++
+				continue
+			}
+			var err error
+			if source == models.AssetSourceAudio {
+				_, err = s.RecordAudio(ctx, url)
+			} else {
+				_, err = s.ProcessImage(ctx, url, source)
+			}
+			if err != nil {
+				slog.Warn("Failed to process file", "url", url, "error", err)
+				result.Failed++
+				continue
+			}
+			result.Processed++
+		}
+	}
+	return result, nil
+}
+
+func (s *AssetService) toAssetResponse(asset *models.Asset) *dto.AssetResponse {
+	tags := make([]string, len(asset.Tags))
+	for i, t := range asset.Tags {
+		tags[i] = t.Tag
+	}
+	uploaderName := ""
+	if asset.Uploader != nil {
+		uploaderName = asset.Uploader.Name
+	}
+
+	variants := make([]dto.AssetVariantResponse, len(asset.Variants))
+	for i, v := range asset.Variants {
+		variants[i] = dto.AssetVariantResponse{
+			Name:   v.Name,
+			Format: v.Format,
+			URL:    v.URL,
+			Width:  v.Width,
+			Height: v.Height,
+			Bytes:  v.Bytes,
+		}
+	}
+	return &dto.AssetResponse{
+		ID:           asset.ID,
+		URL:          asset.URL,
+		Kind:         asset.Kind,
+		Source:       asset.Source,
+		Format:       asset.Format,
+		Width:        asset.Width,
+		Height:       asset.Height,
+		Bytes:        asset.Bytes,
+		Blurhash:     asset.Blurhash,
+		DurationMs:   asset.DurationMs,
+		SampleRate:   asset.SampleRate,
+		Channels:     asset.Channels,
+		Variants:     variants,
+		OriginalName: asset.OriginalName,
+		Word:         asset.Word,
+		Translation:  asset.Translation,
+		Prompt:       asset.Prompt,
+		UploadedBy:   asset.UploadedBy,
+		UploaderName: uploaderName,
+		Tags:         tags,
+		CreatedAt:    asset.CreatedAt,
+	}
+}
+
+// variantBaseName is the original's file name without extension, which prefixes its variants
+func variantBaseName(url string) string {
+	base := path.Base(url)
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+func variantExt(format string) string {
+	if format == imaging.FormatJPEG {
+		return "jpg"
+	}
+	return format
+}
+
+// writeFileAtomic replaces path with data so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+	"dannyswat/learnspeak/utils"
+)
+
+// ErrInvalidAuditFilter is returned for audit log query parameters that can't be parsed
+var ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+// auditExportBatchSize is how many events a CSV export loads at a time
+const auditExportBatchSize = 1000
+
+// AuditEntry describes one administrative or security event
+type AuditEntry struct {
+	Action     string
+	ActorID    *uint // defaults to the authenticated user in ctx
+	TargetType string
+	TargetID   uint // 0 when the event has no target
+	Details    map[string]interface{}
+}
+
+// AuditService appends to the audit log and queries it. Events carry the caller's IP, user
+// agent and request ID from the request context. A nil *AuditService records nothing.
+type AuditService struct {
+	repo repositories.AuditRepository
+}
+
+// NewAuditService creates an audit service
+func NewAuditService(repo repositories.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record appends an event. Failures are logged so auditing never fails the action that
+// has already happened.
+func (s *AuditService) Record(ctx context.Context, entry AuditEntry) {
+	if s == nil {
+		return
+	}
+
+	client := utils.ClientFromContext(ctx)
+	event := &models.AuditEvent{
+		Action:     entry.Action,
+		ActorID:    entry.ActorID,
+		TargetType: entry.TargetType,
+		Details:    "{}",
+		IP:         client.IP,
+		UserAgent:  truncateRunes(client.UserAgent, 255),
+		RequestID:  utils.RequestIDFromContext(ctx),
+	}
+	if event.ActorID == nil {
+		if userID, ok := utils.UserIDFromContext(ctx); ok {
+			event.ActorID = &userID
+		}
+	}
+	if entry.TargetID > 0 {
+		targetID := entry.TargetID
+		event.TargetID = &targetID
+	}
+	if len(entry.Details) > 0 {
+		details, err := json.Marshal(entry.Details)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to encode audit details", "action", entry.Action, "error", err)
+		} else {
+			event.Details = string(details)
+		}
+	}
+
+	if err := s.repo.Create(event); err != nil {
+		slog.ErrorContext(ctx, "Failed to record audit event", "action", entry.Action, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "audit", "action", event.Action, "actor_id", event.ActorID, "target_type", event.TargetType, "target_id", event.TargetID, "ip", event.IP)
+}
+
+// ListEvents retrieves audit events newest first
+func (s *AuditService) ListEvents(params *dto.AuditFilterParams) (*dto.AuditEventListResponse, error) {
+	filter, err := auditFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	events, total, err := s.repo.List(filter, params.Page, params.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.AuditEventResponse, len(events))
+	for i := range events {
+		responses[i] = toAuditEventResponse(&events[i])
+	}
+
+	return &dto.AuditEventListResponse{
+		Events:     responses,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(params.PageSize))),
+	}, nil
+}
+
+// ExportCSV writes every matching event to w as CSV, oldest first
+func (s *AuditService) ExportCSV(params *dto.AuditFilterParams, w io.Writer) error {
+	filter, err := auditFilter(params)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(utf8BOM); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "created_at", "action", "actor_id", "actor_username", "target_type", "target_id", "ip", "user_agent", "request_id", "details"}); err != nil {
+		return err
+	}
+
+	err = s.repo.Each(filter, auditExportBatchSize, func(events []models.AuditEvent) error {
+		for _, event := range events {
+			// Usernames from failed logins and user agents are attacker-controlled
+			record := []string{
+				strconv.FormatUint(uint64(event.ID), 10),
+				event.CreatedAt.UTC().Format(time.RFC3339),
+				event.Action,
+				optionalID(event.ActorID),
+				utils.CSVCell(event.ActorUsername),
+				event.TargetType,
+				optionalID(event.TargetID),
+				event.IP,
+				utils.CSVCell(event.UserAgent),
+				event.RequestID,
+				utils.CSVCell(event.Details),
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// auditFilter converts query parameters. Dates are YYYY-MM-DD (whole days, UTC) or RFC 3339.
+func auditFilter(params *dto.AuditFilterParams) (repositories.AuditFilter, error) {
+	filter := repositories.AuditFilter{
+		ActorID:    params.ActorID,
+		TargetType: params.TargetType,
+		TargetID:   params.TargetID,
+		IP:         strings.TrimSpace(params.IP),
+	}
+	for _, action := range strings.Split(params.Action, ",") {
+		if action = strings.TrimSpace(action); action != "" {
+			filter.Actions = append(filter.Actions, action)
+		}
+	}
+
+	var err error
+	if params.From != "" {
+		if filter.From, _, err = parseAuditTime(params.From); err != nil {
+			return filter, fmt.Errorf("%w: from: %v", ErrInvalidAuditFilter, err)
+		}
+	}
+	if params.To != "" {
+		to, isDate, err := parseAuditTime(params.To)
+		if err != nil {
+			return filter, fmt.Errorf("%w: to: %v", ErrInvalidAuditFilter, err)
+		}
+		if isDate {
+			// A date includes the whole day
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = to
+	}
+	return filter, nil
+}
+
+// parseAuditTime parses YYYY-MM-DD or RFC 3339, reporting which it was
+func parseAuditTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	return t, false, nil
+}
+
+func toAuditEventResponse(event *models.AuditEvent) dto.AuditEventResponse {
+	response := dto.AuditEventResponse{
+		ID:            event.ID,
+		Action:        event.Action,
+		ActorID:       event.ActorID,
+		ActorUsername: event.ActorUsername,
+		TargetType:    event.TargetType,
+		TargetID:      event.TargetID,
+		IP:            event.IP,
+		UserAgent:     event.UserAgent,
+		RequestID:     event.RequestID,
+		CreatedAt:     event.CreatedAt,
+	}
+	if event.Details != "" && event.Details != "{}" {
+		response.Details = json.RawMessage(event.Details)
+	}
+	return response
+}
+
+func optionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
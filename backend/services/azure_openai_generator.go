@@ -3,7 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"dannyswat/learnspeak/config"
@@ -24,7 +24,7 @@ func NewAzureOpenAIGenerator() (*AzureOpenAIGenerator, error) {
 
 	// Check if Azure OpenAI is configured
 	if cfg.AzureOpenAIKey == "" || cfg.AzureOpenAIEndpoint == "" {
-		log.Println("Warning: Azure OpenAI credentials not configured")
+		slog.Warn("Azure OpenAI credentials not configured")
 		return &AzureOpenAIGenerator{
 			client:     nil,
 			configured: false,
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dannyswat/learnspeak/repositories"
+
+	"gorm.io/gorm"
+)
+
+// DefaultCachePruneAge is how old a cache file must be before it is pruned, so audio and
+// images generated for content that hasn't been saved yet survive
+const DefaultCachePruneAge = 24 * time.Hour
+
+// cacheDirs are the generated-content caches inside the upload directory. Content never
+// refers to translation-cache entries, so that cache is pruned by age only.
+var cacheDirs = []struct {
+	name       string
+	referenced bool // content links to the files, so referenced ones are kept
+}{
+	{"tts-cache", true},
+	{"image-cache", true},
+	{"translation-cache", false},
+}
+
+// CachePruneOptions controls which cache files PruneCaches removes
+type CachePruneOptions struct {
+	// OlderThan only removes files last modified before now minus OlderThan
+	// (DefaultCachePruneAge when zero)
+	OlderThan time.Duration
+	// DryRun reports what would be removed without deleting anything
+	DryRun bool
+}
+
+// CachePruneResult summarises a prune run per cache directory
+type CachePruneResult struct {
+	Dirs []CacheDirPruneResult
+}
+
+// CacheDirPruneResult is the outcome for one cache directory
+type CacheDirPruneResult struct {
+	Name         string
+	Removed      int
+	RemovedBytes int64
+	Kept         int
+	InUse        int
+}
+
+// CacheService maintains the TTS, image and translation caches
+type CacheService struct {
+	uploadDir string
+	assetRepo repositories.AssetRepository
+}
+
+// NewCacheService creates a new cache service
+func NewCacheService(db *gorm.DB, uploadDir string) *CacheService {
+	return &CacheService{uploadDir: uploadDir, assetRepo: repositories.NewAssetRepository(db)}
+}
+
+// PruneCaches removes old cache files that no content refers to. Audio and images saved on
+// words, translations, conversations and quiz questions are served straight from the
+// cache, so those files are always kept.
+func (s *CacheService) PruneCaches(opts CachePruneOptions) (*CachePruneResult, error) {
+	inUse, err := s.referencedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	olderThan := opts.OlderThan
+	if olderThan <= 0 {
+		olderThan = DefaultCachePruneAge
+	}
+	cutoff := time.Now().Add(-olderThan)
+	result := &CachePruneResult{}
+
+	for _, cache := range cacheDirs {
+		name := cache.name
+		dirResult := CacheDirPruneResult{Name: name}
+		dir := filepath.Join(s.uploadDir, name)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				result.Dirs = append(result.Dirs, dirResult)
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if cache.referenced && inUse[filepath.Clean(path)] {
+				dirResult.InUse++
+				continue
+			}
+			if info.ModTime().After(cutoff) {
+				dirResult.Kept++
+				continue
+			}
+
+			if !opts.DryRun {
+				if err := os.Remove(path); err != nil {
+					return nil, fmt.Errorf("failed to remove %s: %w", path, err)
+				}
+				if name == "image-cache" {
+					if err := s.removeAsset("/uploads/image-cache/" + entry.Name()); err != nil {
+						return nil, err
+					}
+				}
+			}
+			dirResult.Removed++
+			dirResult.RemovedBytes += info.Size()
+		}
+
+		result.Dirs = append(result.Dirs, dirResult)
+	}
+
+	return result, nil
+}
+
+// removeAsset drops the asset row and variants of a pruned image
+func (s *CacheService) removeAsset(url string) error {
+	if err := removeAssetVariantFiles(s.uploadDir, url); err != nil {
+		return err
+	}
+	if err := s.assetRepo.DeleteByURL(url); err != nil {
+		return fmt.Errorf("failed to remove asset %s: %w", url, err)
+	}
+	return nil
+}
+
+// referencedFiles returns the local paths of every uploaded file referenced by content
+func (s *CacheService) referencedFiles() (map[string]bool, error) {
+	urls, err := s.assetRepo.ReferencedURLs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referenced files: %w", err)
+	}
+
+	inUse := make(map[string]bool, len(urls))
+	for url := range urls {
+		if localPath, ok := resolveUploadPath(s.uploadDir, url); ok {
+			inUse[filepath.Clean(localPath)] = true
+		}
+	}
+	return inUse, nil
+}
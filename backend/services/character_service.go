@@ -0,0 +1,311 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/hanzi"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+)
+
+var (
+	// ErrCharacterDataUnavailable is returned when the character data isn't installed
+	ErrCharacterDataUnavailable = errors.New("character data is not available")
+	ErrCharacterNotFound        = errors.New("character not found")
+	ErrPracticeTopicNotFound    = errors.New("topic not found")
+)
+
+// handwritingPassScore is the share of strokes, in percent, written without a mistake
+// for an attempt to count as completed
+const handwritingPassScore = 70.0
+
+// maxLookupCharacters bounds the characters looked up from one text
+const maxLookupCharacters = 100
+
+// CharacterService serves Chinese character data and handwriting practice
+type CharacterService interface {
+	GetCharacter(char string) (*dto.CharacterResponse, error)
+	LookupText(text string) (*dto.CharacterListResponse, error)
+	GetPractice(topicID uint, includeUnpublished bool, userID uint) (*dto.HandwritingPracticeResponse, error)
+	RecordAttempt(topicID uint, req *dto.HandwritingAttemptRequest, includeUnpublished bool, userID uint) (*dto.HandwritingAttemptResponse, error)
+}
+
+type characterService struct {
+	source       *hanzi.Source
+	topicRepo    repositories.TopicRepository
+	progressRepo repositories.UserProgressRepository
+}
+
+func NewCharacterService(source *hanzi.Source, topicRepo repositories.TopicRepository, progressRepo repositories.UserProgressRepository) CharacterService {
+	return &characterService{
+		source:       source,
+		topicRepo:    topicRepo,
+		progressRepo: progressRepo,
+	}
+}
+
+// GetCharacter returns a character with its stroke order
+func (s *characterService) GetCharacter(char string) (*dto.CharacterResponse, error) {
+	data, err := s.dataset()
+	if err != nil {
+		return nil, err
+	}
+
+	r, size := utf8.DecodeRuneInString(char)
+	if size != len(char) || !hanzi.IsHan(r) {
+		return nil, fmt.Errorf("%q is not a single Chinese character", char)
+	}
+	c, ok := data.Lookup(r, true)
+	if !ok {
+		return nil, ErrCharacterNotFound
+	}
+
+	response := &dto.CharacterResponse{
+		CharacterInfo: toCharacterInfo(c),
+		Strokes:       c.Strokes,
+		Medians:       c.Medians,
+	}
+	if response.Strokes == nil {
+		response.Strokes = []string{}
+		response.Medians = [][][2]int{}
+	}
+	return response, nil
+}
+
+// LookupText returns the distinct Chinese characters of a text, without stroke order
+func (s *characterService) LookupText(text string) (*dto.CharacterListResponse, error) {
+	data, err := s.dataset()
+	if err != nil {
+		return nil, err
+	}
+
+	chars := hanzi.HanCharacters(text)
+	if len(chars) > maxLookupCharacters {
+		return nil, fmt.Errorf("text has more than %d distinct characters", maxLookupCharacters)
+	}
+
+	response := &dto.CharacterListResponse{
+		Characters: []dto.CharacterInfo{},
+		Missing:    []string{},
+	}
+	for _, r := range chars {
+		c, ok := data.Lookup(r, false)
+		if !ok {
+			response.Missing = append(response.Missing, string(r))
+			continue
+		}
+		response.Characters = append(response.Characters, toCharacterInfo(c))
+	}
+	return response, nil
+}
+
+// GetPractice lists the characters of a topic's words, in word order, with the user's
+// handwriting attempts on the topic
+func (s *characterService) GetPractice(topicID uint, includeUnpublished bool, userID uint) (*dto.HandwritingPracticeResponse, error) {
+	data, err := s.dataset()
+	if err != nil {
+		return nil, err
+	}
+	topic, err := s.practiceTopic(topicID, includeUnpublished)
+	if err != nil {
+		return nil, err
+	}
+
+	chars, words, err := s.topicCharacters(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.HandwritingPracticeResponse{
+		TopicID:    topic.ID,
+		TopicName:  topic.Name,
+		Characters: make([]dto.PracticeCharacter, len(chars)),
+	}
+	for i, r := range chars {
+		c, _ := data.Lookup(r, false)
+		response.Characters[i] = dto.PracticeCharacter{
+			CharacterInfo: toCharacterInfo(c),
+			Words:         words[r],
+		}
+	}
+
+	progress, err := s.progressRepo.GetUserTopicProgress(userID, topicID)
+	if err != nil {
+		return nil, err
+	}
+	var lastPracticed time.Time
+	for _, p := range progress {
+		if p.ActivityType != "handwriting" {
+			continue
+		}
+		response.Attempts++
+		if p.Score != nil && (response.BestScore == nil || *p.Score > *response.BestScore) {
+			score := *p.Score
+			response.BestScore = &score
+		}
+		if p.CreatedAt.After(lastPracticed) {
+			lastPracticed = p.CreatedAt
+		}
+	}
+	if !lastPracticed.IsZero() {
+		response.LastPracticedAt = lastPracticed.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	return response, nil
+}
+
+// RecordAttempt scores a handwriting attempt by the strokes written without a mistake and
+// records it as the user's progress on the topic
+func (s *characterService) RecordAttempt(topicID uint, req *dto.HandwritingAttemptRequest, includeUnpublished bool, userID uint) (*dto.HandwritingAttemptResponse, error) {
+	data, err := s.dataset()
+	if err != nil {
+		return nil, err
+	}
+	if !data.HasStrokes() {
+		return nil, ErrCharacterDataUnavailable
+	}
+	topic, err := s.practiceTopic(topicID, includeUnpublished)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only characters of the topic's words count
+	_, inTopic, err := s.topicCharacters(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.HandwritingAttemptResponse{Characters: make([]dto.HandwritingCharScore, len(req.Characters))}
+	var strokes, correct int
+	for i, result := range req.Characters {
+		r, size := utf8.DecodeRuneInString(result.Character)
+		if size != len(result.Character) || inTopic[r] == nil {
+			return nil, fmt.Errorf("%q is not a character of this topic", result.Character)
+		}
+		c, _ := data.Lookup(r, false)
+		if c.StrokeCount == 0 {
+			return nil, fmt.Errorf("%q has no stroke data", result.Character)
+		}
+
+		clean := c.StrokeCount - result.Mistakes
+		if clean < 0 {
+			clean = 0
+		}
+		strokes += c.StrokeCount
+		correct += clean
+		response.Characters[i] = dto.HandwritingCharScore{
+			Character:   result.Character,
+			StrokeCount: c.StrokeCount,
+			Mistakes:    result.Mistakes,
+			Accuracy:    roundPercent(float64(clean) / float64(c.StrokeCount) * 100),
+		}
+	}
+	response.Score = roundPercent(float64(correct) / float64(strokes) * 100)
+	response.Passed = response.Score >= handwritingPassScore
+
+	now := time.Now()
+	maxScore := 100.0
+	progress := &models.UserProgress{
+		UserID:           userID,
+		TopicID:          &topicID,
+		JourneyID:        req.JourneyID,
+		ActivityType:     "handwriting",
+		Completed:        response.Passed,
+		Score:            &response.Score,
+		MaxScore:         &maxScore,
+		TimeSpentSeconds: req.TimeSpentSeconds,
+		CompletedAt:      &now,
+	}
+	if err := s.progressRepo.Create(progress); err != nil {
+		return nil, fmt.Errorf("failed to save progress: %w", err)
+	}
+	response.ProgressID = progress.ID
+
+	return response, nil
+}
+
+func (s *characterService) dataset() (*hanzi.Dataset, error) {
+	data, err := s.source.Dataset()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCharacterDataUnavailable, err)
+	}
+	return data, nil
+}
+
+// practiceTopic loads a topic learners may practise
+func (s *characterService) practiceTopic(topicID uint, includeUnpublished bool) (*models.Topic, error) {
+	topic, err := s.topicRepo.GetByID(topicID, false)
+	if err != nil {
+		return nil, ErrPracticeTopicNotFound
+	}
+	if !TopicVisible(topic, includeUnpublished) {
+		return nil, ErrPracticeTopicNotFound
+	}
+	return topic, nil
+}
+
+// topicCharacters returns the distinct Chinese characters of a topic's translations in its
+// language, in word order, with the words each appears in
+func (s *characterService) topicCharacters(topic *models.Topic) ([]rune, map[rune][]dto.PracticeWordInfo, error) {
+	topicWords, err := s.topicRepo.GetTopicWords(topic.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var chars []rune
+	words := make(map[rune][]dto.PracticeWordInfo)
+	for _, tw := range topicWords {
+		for _, t := range tw.Word.Translations {
+			if t.LanguageID != topic.LanguageID {
+				continue
+			}
+			for _, r := range hanzi.HanCharacters(t.Translation) {
+				if words[r] == nil {
+					chars = append(chars, r)
+				}
+				words[r] = append(words[r], dto.PracticeWordInfo{WordID: tw.WordID, BaseWord: tw.Word.BaseWord, Translation: t.Translation})
+			}
+		}
+	}
+	return chars, words, nil
+}
+
+func toCharacterInfo(c *hanzi.Character) dto.CharacterInfo {
+	info := dto.CharacterInfo{
+		Character:     c.Character,
+		Definition:    c.Definition,
+		Pinyin:        c.Pinyin,
+		Radical:       c.Radical,
+		Decomposition: c.Decomposition,
+		StrokeCount:   c.StrokeCount,
+		Traditional:   c.Traditional,
+		Simplified:    c.Simplified,
+	}
+	if info.Pinyin == nil {
+		info.Pinyin = []string{}
+	}
+	if info.Traditional == nil {
+		info.Traditional = []string{}
+	}
+	if info.Simplified == nil {
+		info.Simplified = []string{}
+	}
+	if c.Etymology != nil {
+		info.Etymology = &dto.CharacterEtymology{
+			Type:     c.Etymology.Type,
+			Hint:     c.Etymology.Hint,
+			Phonetic: c.Etymology.Phonetic,
+			Semantic: c.Etymology.Semantic,
+		}
+	}
+	return info
+}
+
+func roundPercent(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -14,7 +14,7 @@ type ConversationService interface {
 	UpdateConversation(id uint, req *dto.UpdateConversationRequest, userID uint) (*dto.ConversationResponse, error)
 	DeleteConversation(id uint, userID uint) error
 	ListConversations(params *dto.ConversationFilterParams) (*dto.ConversationListResponse, error)
-	GetConversationsByTopic(topicID uint) ([]dto.ConversationResponse, error)
+	GetConversationsByTopic(topicID uint, includeUnpublished bool) ([]dto.ConversationResponse, error)
 	AddLineToConversation(conversationID uint, req *dto.CreateConversationLineRequest, userID uint) (*dto.ConversationLineResponse, error)
 	UpdateLine(conversationID uint, lineID uint, req *dto.UpdateConversationLineRequest, userID uint) (*dto.ConversationLineResponse, error)
 	DeleteLine(conversationID uint, lineID uint, userID uint) error
@@ -24,12 +24,16 @@ type ConversationService interface {
 type conversationService struct {
 	conversationRepo repositories.ConversationRepository
 	languageRepo     repositories.LanguageRepository
+	topicRepo        repositories.TopicRepository
+	revisions        RevisionService
 }
 
-func NewConversationService(conversationRepo repositories.ConversationRepository, languageRepo repositories.LanguageRepository) ConversationService {
+func NewConversationService(conversationRepo repositories.ConversationRepository, languageRepo repositories.LanguageRepository, topicRepo repositories.TopicRepository, revisions RevisionService) ConversationService {
 	return &conversationService{
 		conversationRepo: conversationRepo,
 		languageRepo:     languageRepo,
+		topicRepo:        topicRepo,
+		revisions:        revisions,
 	}
 }
 
@@ -41,6 +45,17 @@ func (s *conversationService) CreateConversation(req *dto.CreateConversationRequ
 		return nil, fmt.Errorf("language not found: %s", req.LanguageCode)
 	}
 
+	// Conversations are added to a published topic through its draft copy
+	if req.TopicID != nil && *req.TopicID > 0 {
+		topic, err := s.topicRepo.GetByID(*req.TopicID, false)
+		if err != nil {
+			return nil, ErrTopicNotFound
+		}
+		if err := checkTopicEditable(topic); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create conversation model with lines
 	conversation := &models.Conversation{
 		Title:            req.Title,
@@ -51,6 +66,7 @@ func (s *conversationService) CreateConversation(req *dto.CreateConversationRequ
 		ScenarioAudioURL: req.ScenarioAudioURL,
 		ScenarioImageURL: req.ScenarioImageURL,
 		CreatedBy:        userID,
+		IsPublic:         req.IsPublic,
 		Lines:            make([]models.ConversationLine, len(req.Lines)),
 	}
 
@@ -76,6 +92,8 @@ func (s *conversationService) CreateConversation(req *dto.CreateConversationRequ
 		return nil, fmt.Errorf("failed to create conversation: %w", err)
 	}
 
+	s.revisions.Record(models.RevisionEntityConversation, conversation.ID, models.RevisionActionCreate, userID, nil)
+
 	// Link to topic if provided
 	if req.TopicID != nil && *req.TopicID > 0 {
 		if err := s.conversationRepo.LinkToTopic(conversation.ID, *req.TopicID); err != nil {
@@ -116,6 +134,7 @@ func (s *conversationService) UpdateConversation(id uint, req *dto.UpdateConvers
 	if conversation.CreatedBy != userID {
 		return nil, fmt.Errorf("unauthorized to update this conversation")
 	}
+	before := s.revisions.Snapshot(models.RevisionEntityConversation, id)
 
 	// Validate language if changed
 	if req.LanguageCode != "" {
@@ -145,12 +164,17 @@ func (s *conversationService) UpdateConversation(id uint, req *dto.UpdateConvers
 	if req.ScenarioImageURL != "" {
 		conversation.ScenarioImageURL = req.ScenarioImageURL
 	}
+	if req.IsPublic != nil {
+		conversation.IsPublic = *req.IsPublic
+	}
 
 	// Update conversation
 	if err := s.conversationRepo.Update(conversation); err != nil {
 		return nil, fmt.Errorf("failed to update conversation: %w", err)
 	}
 
+	s.revisions.Record(models.RevisionEntityConversation, id, models.RevisionActionUpdate, userID, before)
+
 	// Fetch updated conversation
 	updatedConversation, err := s.conversationRepo.GetByID(id)
 	if err != nil {
@@ -173,7 +197,13 @@ func (s *conversationService) DeleteConversation(id uint, userID uint) error {
 		return fmt.Errorf("unauthorized to delete this conversation")
 	}
 
-	return s.conversationRepo.Delete(id)
+	before := s.revisions.Snapshot(models.RevisionEntityConversation, id)
+	if err := s.conversationRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.revisions.Record(models.RevisionEntityConversation, id, models.RevisionActionDelete, userID, before)
+	return nil
 }
 
 // ListConversations retrieves conversations with filtering and pagination
@@ -208,8 +238,13 @@ func (s *conversationService) ListConversations(params *dto.ConversationFilterPa
 	}, nil
 }
 
-// GetConversationsByTopic retrieves all conversations for a specific topic
-func (s *conversationService) GetConversationsByTopic(topicID uint) ([]dto.ConversationResponse, error) {
+// GetConversationsByTopic retrieves all conversations for a specific topic.
+// includeUnpublished allows topics learners can't see yet.
+func (s *conversationService) GetConversationsByTopic(topicID uint, includeUnpublished bool) ([]dto.ConversationResponse, error) {
+	if _, err := visibleTopic(s.topicRepo, topicID, includeUnpublished); err != nil {
+		return nil, err
+	}
+
 	conversations, err := s.conversationRepo.GetByTopicID(topicID)
 	if err != nil {
 		return nil, err
@@ -257,10 +292,13 @@ func (s *conversationService) AddLineToConversation(conversationID uint, req *dt
 		line.WordID = req.WordID
 	}
 
+	before := s.revisions.Snapshot(models.RevisionEntityConversation, conversationID)
 	if err := s.conversationRepo.AddLinesToConversation(conversationID, []models.ConversationLine{line}); err != nil {
 		return nil, fmt.Errorf("failed to add line to conversation: %w", err)
 	}
 
+	s.revisions.Record(models.RevisionEntityConversation, conversationID, models.RevisionActionUpdate, userID, before)
+
 	return s.toConversationLineResponse(&line), nil
 }
 
@@ -315,10 +353,13 @@ func (s *conversationService) UpdateLine(conversationID uint, lineID uint, req *
 		line.WordID = req.WordID
 	}
 
+	before := s.revisions.Snapshot(models.RevisionEntityConversation, conversationID)
 	if err := s.conversationRepo.UpdateLine(line); err != nil {
 		return nil, fmt.Errorf("failed to update line: %w", err)
 	}
 
+	s.revisions.Record(models.RevisionEntityConversation, conversationID, models.RevisionActionUpdate, userID, before)
+
 	return s.toConversationLineResponse(line), nil
 }
 
@@ -334,7 +375,13 @@ func (s *conversationService) DeleteLine(conversationID uint, lineID uint, userI
 		return fmt.Errorf("unauthorized to modify this conversation")
 	}
 
-	return s.conversationRepo.DeleteLine(lineID)
+	before := s.revisions.Snapshot(models.RevisionEntityConversation, conversationID)
+	if err := s.conversationRepo.DeleteLine(lineID); err != nil {
+		return err
+	}
+
+	s.revisions.Record(models.RevisionEntityConversation, conversationID, models.RevisionActionUpdate, userID, before)
+	return nil
 }
 
 // ReorderLines updates the sequence order of lines
@@ -349,7 +396,13 @@ func (s *conversationService) ReorderLines(conversationID uint, lineIDs []uint,
 		return fmt.Errorf("unauthorized to modify this conversation")
 	}
 
-	return s.conversationRepo.ReorderLines(conversationID, lineIDs)
+	before := s.revisions.Snapshot(models.RevisionEntityConversation, conversationID)
+	if err := s.conversationRepo.ReorderLines(conversationID, lineIDs); err != nil {
+		return err
+	}
+
+	s.revisions.Record(models.RevisionEntityConversation, conversationID, models.RevisionActionUpdate, userID, before)
+	return nil
 }
 
 // Helper function to convert model to response DTO
@@ -370,6 +423,7 @@ func (s *conversationService) toConversationResponse(conversation *models.Conver
 		ScenarioAudioURL: conversation.ScenarioAudioURL,
 		ScenarioImageURL: conversation.ScenarioImageURL,
 		Lines:            lines,
+		IsPublic:         conversation.IsPublic,
 		CreatedBy:        conversation.CreatedBy,
 		CreatedAt:        conversation.CreatedAt,
 		UpdatedAt:        conversation.UpdatedAt,
@@ -0,0 +1,769 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"dannyswat/learnspeak/audio"
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/imaging"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// coursePackageManifest is the name of the JSON document inside a course package zip
+	coursePackageManifest = "package.json"
+	// coursePackageMaxFileSize limits the size of any single file extracted from a package
+	coursePackageMaxFileSize = 50 * 1024 * 1024
+	// coursePackageMaxTotalSize limits the bytes extracted from a package altogether
+	coursePackageMaxTotalSize = 1024 * 1024 * 1024
+	// coursePackageMaxEntries limits the number of files in a package
+	coursePackageMaxEntries = 10000
+)
+
+// packageMediaKinds are the media types a package may bring in, matching what can be
+// uploaded. Imported files are served from /uploads, so anything else (HTML, SVG) is refused.
+var packageMediaKinds = map[string]string{
+	".mp3": models.AssetKindAudio, ".wav": models.AssetKindAudio, ".ogg": models.AssetKindAudio,
+	".m4a": models.AssetKindAudio, ".webm": models.AssetKindAudio,
+	".jpg": models.AssetKindImage, ".jpeg": models.AssetKindImage, ".png": models.AssetKindImage,
+	".gif": models.AssetKindImage, ".webp": models.AssetKindImage,
+}
+
+// ImportOptions controls how a course package is imported
+type ImportOptions struct {
+	// PreserveCreators keeps the original creators when a user with the same username exists
+	// on this instance. Otherwise all imported content is owned by the importing user.
+	PreserveCreators bool
+}
+
+// CoursePackageService exports journeys as self-contained zip archives and imports them back
+type CoursePackageService struct {
+	db        *gorm.DB
+	uploadDir string
+	revisions RevisionService
+	assets    *AssetService
+}
+
+// NewCoursePackageService creates a new course package service
+func NewCoursePackageService(db *gorm.DB, uploadDir string, revisions RevisionService, assets *AssetService) *CoursePackageService {
+	return &CoursePackageService{
+		db:        db,
+		uploadDir: uploadDir,
+		revisions: revisions,
+		assets:    assets,
+	}
+}
+
+// ExportJourney writes a journey with its topics, words, translations, quizzes,
+// conversations and referenced media files to w as a zip archive
+func (s *CoursePackageService) ExportJourney(journeyID uint, w io.Writer) error {
+	journeyRepo := repositories.NewJourneyRepository(s.db)
+	topicRepo := repositories.NewTopicRepository(s.db)
+	wordRepo := repositories.NewWordRepository(s.db)
+	quizRepo := repositories.NewQuizRepository(s.db)
+	conversationRepo := repositories.NewConversationRepository(s.db)
+
+	journey, err := journeyRepo.GetByID(journeyID, true)
+	if err != nil {
+		return err
+	}
+
+	pkg := &dto.CoursePackage{
+		FormatVersion: dto.CoursePackageFormatVersion,
+		ExportedAt:    time.Now().UTC().Format(time.RFC3339),
+		Journey: dto.PackageJourney{
+			Name:         journey.Name,
+			Description:  journey.Description,
+			LanguageCode: journey.Language.Code,
+			CreatedBy:    journey.Creator.Username,
+			TopicIDs:     make([]uint, 0, len(journey.Topics)),
+		},
+		Topics:        []dto.PackageTopic{},
+		Words:         []dto.PackageWord{},
+		Conversations: []dto.PackageConversation{},
+		Media:         []dto.PackageMedia{},
+	}
+
+	zw := zip.NewWriter(w)
+	media := newMediaCollector(s.uploadDir, zw)
+
+	exportedWords := make(map[uint]bool)
+	exportedConversations := make(map[uint]bool)
+
+	exportWord := func(wordID uint) error {
+		if exportedWords[wordID] {
+			return nil
+		}
+		word, err := wordRepo.GetByID(wordID)
+		if err != nil {
+			return fmt.Errorf("failed to load word %d: %w", wordID, err)
+		}
+		pw := dto.PackageWord{
+			SourceID:     word.ID,
+			BaseWord:     word.BaseWord,
+			ImageURL:     media.add(word.ImageURL),
+			Notes:        word.Notes,
+			PartOfSpeech: word.PartOfSpeech,
+			CreatedBy:    word.Creator.Username,
+			Translations: make([]dto.PackageTranslation, 0, len(word.Translations)),
+		}
+		for _, t := range word.Translations {
+			translation := dto.PackageTranslation{
+				LanguageCode: t.Language.Code,
+				Translation:  t.Translation,
+				Romanization: t.Romanization,
+				AudioURL:     media.add(t.AudioURL),
+				MeasureWord:  t.MeasureWord,
+				Gender:       t.Gender,
+			}
+			for _, e := range t.Examples {
+				translation.Examples = append(translation.Examples, dto.PackageExample{
+					Sentence:     e.Sentence,
+					Romanization: e.Romanization,
+					Meaning:      e.Meaning,
+					AudioURL:     media.add(e.AudioURL),
+				})
+			}
+			pw.Translations = append(pw.Translations, translation)
+		}
+		for _, relation := range word.Relations {
+			pw.Relations = append(pw.Relations, dto.PackageWordRelation{
+				WordID:       relation.RelatedWordID,
+				RelationType: relation.RelationType,
+			})
+		}
+		pkg.Words = append(pkg.Words, pw)
+		exportedWords[wordID] = true
+		return nil
+	}
+
+	for _, jt := range journey.Topics {
+		topic, err := topicRepo.GetByID(jt.TopicID, true)
+		if err != nil {
+			return fmt.Errorf("failed to load topic %d: %w", jt.TopicID, err)
+		}
+		pkg.Journey.TopicIDs = append(pkg.Journey.TopicIDs, topic.ID)
+
+		pt := dto.PackageTopic{
+			SourceID:        topic.ID,
+			Name:            topic.Name,
+			Description:     topic.Description,
+			Level:           topic.Level,
+			LanguageCode:    topic.Language.Code,
+			IsPublic:        topic.IsPublic,
+			CreatedBy:       topic.Creator.Username,
+			WordIDs:         make([]uint, 0, len(topic.Words)),
+			ConversationIDs: []uint{},
+			Quizzes:         []dto.PackageQuiz{},
+		}
+
+		for _, tw := range topic.Words {
+			if err := exportWord(tw.WordID); err != nil {
+				return err
+			}
+			pt.WordIDs = append(pt.WordIDs, tw.WordID)
+		}
+
+		questions, err := quizRepo.GetByTopicID(topic.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load quizzes for topic %d: %w", topic.ID, err)
+		}
+		for _, q := range questions {
+			if q.WordID != nil {
+				if err := exportWord(*q.WordID); err != nil {
+					return err
+				}
+			}
+			pt.Quizzes = append(pt.Quizzes, dto.PackageQuiz{
+				WordID:        q.WordID,
+				QuestionType:  q.QuestionType,
+				QuestionText:  q.QuestionText,
+				AudioURL:      media.addPtr(q.AudioURL),
+				ImageURL:      media.addPtr(q.ImageURL),
+				CorrectAnswer: q.CorrectAnswer,
+				OptionA:       q.OptionA,
+				OptionB:       q.OptionB,
+				OptionC:       q.OptionC,
+				OptionD:       q.OptionD,
+			})
+		}
+
+		conversations, err := conversationRepo.GetByTopicID(topic.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load conversations for topic %d: %w", topic.ID, err)
+		}
+		for _, summary := range conversations {
+			pt.ConversationIDs = append(pt.ConversationIDs, summary.ID)
+			if exportedConversations[summary.ID] {
+				continue
+			}
+
+			conversation, err := conversationRepo.GetByID(summary.ID)
+			if err != nil {
+				return fmt.Errorf("failed to load conversation %d: %w", summary.ID, err)
+			}
+			pc := dto.PackageConversation{
+				SourceID:         conversation.ID,
+				Title:            conversation.Title,
+				Description:      conversation.Description,
+				Context:          conversation.Context,
+				LanguageCode:     conversation.Language.Code,
+				DifficultyLevel:  conversation.DifficultyLevel,
+				ScenarioAudioURL: media.add(conversation.ScenarioAudioURL),
+				ScenarioImageURL: media.add(conversation.ScenarioImageURL),
+				CreatedBy:        conversation.Creator.Username,
+				Lines:            make([]dto.PackageConversationLine, 0, len(conversation.Lines)),
+			}
+			for _, line := range conversation.Lines {
+				if line.WordID != nil {
+					if err := exportWord(*line.WordID); err != nil {
+						return err
+					}
+				}
+				pc.Lines = append(pc.Lines, dto.PackageConversationLine{
+					SequenceOrder: line.SequenceOrder,
+					SpeakerRole:   line.SpeakerRole,
+					EnglishText:   line.EnglishText,
+					TargetText:    line.TargetText,
+					Romanization:  line.Romanization,
+					AudioURL:      media.add(line.AudioURL),
+					ImageURL:      media.add(line.ImageURL),
+					WordID:        line.WordID,
+					IsLearnerLine: line.IsLearnerLine,
+				})
+			}
+			pkg.Conversations = append(pkg.Conversations, pc)
+			exportedConversations[conversation.ID] = true
+		}
+
+		pkg.Topics = append(pkg.Topics, pt)
+	}
+
+	if media.err != nil {
+		return media.err
+	}
+	pkg.Media = media.entries
+
+	// Only links between packaged words can be imported
+	for i := range pkg.Words {
+		relations := pkg.Words[i].Relations[:0]
+		for _, relation := range pkg.Words[i].Relations {
+			if exportedWords[relation.WordID] {
+				relations = append(relations, relation)
+			}
+		}
+		pkg.Words[i].Relations = relations
+	}
+
+	manifest, err := zw.Create(coursePackageManifest)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(manifest)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(pkg); err != nil {
+		return fmt.Errorf("failed to write package manifest: %w", err)
+	}
+
+	return zw.Close()
+}
+
+// ImportJourney imports a course package zip as a new journey owned by userID.
+// Words are deduplicated by base word and journey language; everything else is created fresh.
+// Media is processed like uploads and only moved into the upload directory once the content
+// is saved.
+func (s *CoursePackageService) ImportJourney(ctx context.Context, r io.ReaderAt, size int64, userID uint, opts ImportOptions) (*dto.CoursePackageImportResult, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid package archive: %w", err)
+	}
+	if len(zr.File) > coursePackageMaxEntries {
+		return nil, fmt.Errorf("invalid package: more than %d files", coursePackageMaxEntries)
+	}
+
+	archive := &packageArchive{
+		files:     make(map[string]*zip.File, len(zr.File)),
+		remaining: coursePackageMaxTotalSize,
+	}
+	for _, f := range zr.File {
+		archive.files[f.Name] = f
+	}
+
+	manifestFile, ok := archive.files[coursePackageManifest]
+	if !ok {
+		return nil, fmt.Errorf("invalid package: %s not found", coursePackageManifest)
+	}
+	manifestData, err := archive.read(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg dto.CoursePackage
+	if err := json.Unmarshal(manifestData, &pkg); err != nil {
+		return nil, fmt.Errorf("invalid package manifest: %w", err)
+	}
+	if pkg.FormatVersion < 1 || pkg.FormatVersion > dto.CoursePackageFormatVersion {
+		return nil, fmt.Errorf("unsupported package format version %d", pkg.FormatVersion)
+	}
+
+	if len(pkg.Media) > coursePackageMaxEntries {
+		return nil, fmt.Errorf("invalid package manifest: more than %d media files", coursePackageMaxEntries)
+	}
+
+	result := &dto.CoursePackageImportResult{}
+
+	// Stage media first so content can point at the new URLs
+	media, err := s.assets.NewImport()
+	if err != nil {
+		return nil, err
+	}
+	defer media.Discard()
+	mediaURLs := make(map[string]string, len(pkg.Media))
+	for _, m := range pkg.Media {
+		f, ok := archive.files[m.Path]
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("media file %s missing from package", m.Path))
+			continue
+		}
+		kind, ok := packageMediaKinds[strings.ToLower(path.Ext(f.Name))]
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("media file %s skipped: unsupported file type", m.Path))
+			continue
+		}
+		data, err := archive.read(f)
+		if err != nil {
+			return nil, err
+		}
+		var newURL string
+		if kind == models.AssetKindAudio {
+			newURL, err = media.AddAudio(ctx, data, path.Base(f.Name))
+		} else {
+			newURL, err = media.AddImage(ctx, data, path.Base(f.Name))
+		}
+		if errors.Is(err, imaging.ErrInvalidImage) || errors.Is(err, audio.ErrInvalidAudio) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("media file %s skipped: %v", m.Path, err))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to import media file %s: %w", m.Path, err)
+		}
+		mediaURLs[m.URL] = newURL
+		result.MediaImported++
+	}
+	remap := func(url string) string {
+		if newURL, ok := mediaURLs[url]; ok {
+			return newURL
+		}
+		return url
+	}
+	remapPtr := func(url *string) *string {
+		if url == nil {
+			return nil
+		}
+		mapped := remap(*url)
+		return &mapped
+	}
+
+	history := newRevisionLog(s.revisions)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		languageRepo := repositories.NewLanguageRepository(tx)
+		userRepo := repositories.NewUserRepository(tx)
+		wordRepo := repositories.NewWordRepository(tx)
+		topicRepo := repositories.NewTopicRepository(tx)
+		quizRepo := repositories.NewQuizRepository(tx)
+		conversationRepo := repositories.NewConversationRepository(tx)
+		journeyRepo := repositories.NewJourneyRepository(tx)
+
+		languages := make(map[string]uint)
+		languageID := func(code string) (uint, error) {
+			if id, ok := languages[code]; ok {
+				return id, nil
+			}
+			language, err := languageRepo.GetByCode(code)
+			if err != nil {
+				return 0, fmt.Errorf("language %s is not available on this instance", code)
+			}
+			languages[code] = language.ID
+			return language.ID, nil
+		}
+
+		creators := make(map[string]uint)
+		creatorID := func(username string) uint {
+			if !opts.PreserveCreators || username == "" {
+				return userID
+			}
+			if id, ok := creators[username]; ok {
+				return id
+			}
+			id := userID
+			if user, err := userRepo.GetByUsername(username); err == nil {
+				id = user.ID
+			}
+			creators[username] = id
+			return id
+		}
+
+		journeyLanguageID, err := languageID(pkg.Journey.LanguageCode)
+		if err != nil {
+			return err
+		}
+
+		// Words: reuse an existing word with the same base word in the journey language
+		wordIDs := make(map[uint]uint, len(pkg.Words))
+		createdWords := make(map[uint]bool, len(pkg.Words))
+		for _, pw := range pkg.Words {
+			if existing, err := wordRepo.FindByBaseWordAndLanguage(pw.BaseWord, journeyLanguageID); err == nil {
+				wordIDs[pw.SourceID] = existing.ID
+				result.WordsReused++
+				continue
+			}
+
+			word := &models.Word{
+				BaseWord:     pw.BaseWord,
+				ImageURL:     remap(pw.ImageURL),
+				Notes:        pw.Notes,
+				PartOfSpeech: pw.PartOfSpeech,
+				CreatedBy:    creatorID(pw.CreatedBy),
+			}
+			if err := wordRepo.Create(word); err != nil {
+				return fmt.Errorf("failed to create word %s: %w", pw.BaseWord, err)
+			}
+			for _, pt := range pw.Translations {
+				langID, err := languageID(pt.LanguageCode)
+				if err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("skipped %s translation of '%s': %v", pt.LanguageCode, pw.BaseWord, err))
+					continue
+				}
+				examples := make([]models.WordExample, len(pt.Examples))
+				for i, pe := range pt.Examples {
+					examples[i] = models.WordExample{
+						Sentence:      pe.Sentence,
+						Romanization:  pe.Romanization,
+						Meaning:       pe.Meaning,
+						AudioURL:      remap(pe.AudioURL),
+						SequenceOrder: i + 1,
+					}
+				}
+				if err := wordRepo.CreateTranslation(&models.WordTranslation{
+					WordID:       word.ID,
+					LanguageID:   langID,
+					Translation:  pt.Translation,
+					Romanization: pt.Romanization,
+					AudioURL:     remap(pt.AudioURL),
+					MeasureWord:  pt.MeasureWord,
+					Gender:       pt.Gender,
+					Examples:     examples,
+				}); err != nil {
+					return fmt.Errorf("failed to create translation for %s: %w", pw.BaseWord, err)
+				}
+			}
+			wordIDs[pw.SourceID] = word.ID
+			createdWords[word.ID] = true
+			history.created(models.RevisionEntityWord, word.ID)
+			result.WordsCreated++
+		}
+
+		// Links between words, leaving reused words' links as they are
+		for _, pw := range pkg.Words {
+			wordID := wordIDs[pw.SourceID]
+			if !createdWords[wordID] || len(pw.Relations) == 0 {
+				continue
+			}
+			var relations []models.WordRelation
+			for _, pr := range pw.Relations {
+				if relatedID, ok := wordIDs[pr.WordID]; ok && createdWords[relatedID] {
+					relations = append(relations, models.WordRelation{RelatedWordID: relatedID, RelationType: pr.RelationType})
+				}
+			}
+			if err := wordRepo.ReplaceRelations(wordID, relations); err != nil {
+				return fmt.Errorf("failed to link related words of %s: %w", pw.BaseWord, err)
+			}
+		}
+
+		mapWordID := func(id *uint) *uint {
+			if id == nil {
+				return nil
+			}
+			if newID, ok := wordIDs[*id]; ok {
+				return &newID
+			}
+			return nil
+		}
+
+		// Conversations
+		conversationIDs := make(map[uint]uint, len(pkg.Conversations))
+		for _, pc := range pkg.Conversations {
+			langID, err := languageID(pc.LanguageCode)
+			if err != nil {
+				return err
+			}
+			conversation := &models.Conversation{
+				Title:            pc.Title,
+				Description:      pc.Description,
+				Context:          pc.Context,
+				LanguageID:       langID,
+				DifficultyLevel:  pc.DifficultyLevel,
+				ScenarioAudioURL: remap(pc.ScenarioAudioURL),
+				ScenarioImageURL: remap(pc.ScenarioImageURL),
+				CreatedBy:        creatorID(pc.CreatedBy),
+			}
+			for _, pl := range pc.Lines {
+				conversation.Lines = append(conversation.Lines, models.ConversationLine{
+					SequenceOrder: pl.SequenceOrder,
+					SpeakerRole:   pl.SpeakerRole,
+					EnglishText:   pl.EnglishText,
+					TargetText:    pl.TargetText,
+					Romanization:  pl.Romanization,
+					AudioURL:      remap(pl.AudioURL),
+					ImageURL:      remap(pl.ImageURL),
+					WordID:        mapWordID(pl.WordID),
+					IsLearnerLine: pl.IsLearnerLine,
+				})
+			}
+			if err := conversationRepo.Create(conversation); err != nil {
+				return fmt.Errorf("failed to create conversation %s: %w", pc.Title, err)
+			}
+			conversationIDs[pc.SourceID] = conversation.ID
+			history.created(models.RevisionEntityConversation, conversation.ID)
+			result.ConversationsCreated++
+		}
+
+		// Topics with their words, quizzes and conversations
+		topicIDs := make(map[uint]uint, len(pkg.Topics))
+		for _, pt := range pkg.Topics {
+			langID, err := languageID(pt.LanguageCode)
+			if err != nil {
+				return err
+			}
+			topic := &models.Topic{
+				Name:        pt.Name,
+				Description: pt.Description,
+				Level:       pt.Level,
+				LanguageID:  langID,
+				CreatedBy:   creatorID(pt.CreatedBy),
+				// Imported topics stay private until the new owner publishes them
+				IsPublic: false,
+			}
+			if err := topicRepo.Create(topic); err != nil {
+				return fmt.Errorf("failed to create topic %s: %w", pt.Name, err)
+			}
+			topicIDs[pt.SourceID] = topic.ID
+			history.created(models.RevisionEntityTopic, topic.ID)
+			result.TopicsCreated++
+
+			topicWordIDs := make([]uint, 0, len(pt.WordIDs))
+			for _, id := range pt.WordIDs {
+				if newID, ok := wordIDs[id]; ok {
+					topicWordIDs = append(topicWordIDs, newID)
+				}
+			}
+			if len(topicWordIDs) > 0 {
+				if err := topicRepo.AddWords(topic.ID, topicWordIDs); err != nil {
+					return fmt.Errorf("failed to add words to topic %s: %w", pt.Name, err)
+				}
+			}
+
+			for _, pq := range pt.Quizzes {
+				question := &models.QuizQuestion{
+					TopicID:       topic.ID,
+					WordID:        mapWordID(pq.WordID),
+					QuestionType:  pq.QuestionType,
+					QuestionText:  pq.QuestionText,
+					AudioURL:      remapPtr(pq.AudioURL),
+					ImageURL:      remapPtr(pq.ImageURL),
+					CorrectAnswer: pq.CorrectAnswer,
+					OptionA:       pq.OptionA,
+					OptionB:       pq.OptionB,
+					OptionC:       pq.OptionC,
+					OptionD:       pq.OptionD,
+				}
+				if err := quizRepo.Create(question); err != nil {
+					return fmt.Errorf("failed to create quiz question for topic %s: %w", pt.Name, err)
+				}
+				history.created(models.RevisionEntityQuizQuestion, question.ID)
+				result.QuizzesCreated++
+			}
+
+			for _, id := range pt.ConversationIDs {
+				if newID, ok := conversationIDs[id]; ok {
+					if err := conversationRepo.LinkToTopic(newID, topic.ID); err != nil {
+						return fmt.Errorf("failed to link conversation to topic %s: %w", pt.Name, err)
+					}
+				}
+			}
+		}
+
+		// Journey
+		journey := &models.Journey{
+			Name:        pkg.Journey.Name,
+			Description: pkg.Journey.Description,
+			LanguageID:  journeyLanguageID,
+			CreatedBy:   creatorID(pkg.Journey.CreatedBy),
+		}
+		if err := journeyRepo.Create(journey); err != nil {
+			return fmt.Errorf("failed to create journey: %w", err)
+		}
+		journeyTopicIDs := make([]uint, 0, len(pkg.Journey.TopicIDs))
+		for _, id := range pkg.Journey.TopicIDs {
+			if newID, ok := topicIDs[id]; ok {
+				journeyTopicIDs = append(journeyTopicIDs, newID)
+			}
+		}
+		if len(journeyTopicIDs) > 0 {
+			if err := journeyRepo.AddTopics(journey.ID, journeyTopicIDs); err != nil {
+				return fmt.Errorf("failed to add topics to journey: %w", err)
+			}
+		}
+		result.JourneyID = journey.ID
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	history.record(userID)
+
+	// The journey is saved, so failing to move its media only costs the files
+	if err := media.Commit(ctx); err != nil {
+		slog.ErrorContext(ctx, "Failed to save imported media", "journey", result.JourneyID, "error", err)
+		result.Warnings = append(result.Warnings, fmt.Sprintf("some media could not be saved: %v", err))
+	}
+
+	return result, nil
+}
+
+// packageArchive reads files from a course package, within the size limits for a single
+// file and for the package as a whole
+type packageArchive struct {
+	files     map[string]*zip.File
+	remaining int64
+}
+
+func (a *packageArchive) read(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	limit := min(int64(coursePackageMaxFileSize), a.remaining)
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	if int64(len(data)) > limit {
+		if limit < coursePackageMaxFileSize {
+			return nil, fmt.Errorf("package exceeds the maximum total size of %d bytes", coursePackageMaxTotalSize)
+		}
+		return nil, fmt.Errorf("%s exceeds the maximum file size", f.Name)
+	}
+	a.remaining -= int64(len(data))
+	return data, nil
+}
+
+// mediaCollector bundles local media files into a zip while content is exported
+type mediaCollector struct {
+	uploadDir string
+	zw        *zip.Writer
+	seen      map[string]bool
+	written   map[string]bool
+	entries   []dto.PackageMedia
+	err       error
+}
+
+func newMediaCollector(uploadDir string, zw *zip.Writer) *mediaCollector {
+	return &mediaCollector{
+		uploadDir: uploadDir,
+		zw:        zw,
+		seen:      make(map[string]bool),
+		written:   make(map[string]bool),
+		entries:   []dto.PackageMedia{},
+	}
+}
+
+// add bundles the file behind a local /uploads/ URL and returns the URL unchanged.
+// External URLs and missing files are left as plain references.
+func (m *mediaCollector) add(url string) string {
+	if m.err != nil || url == "" || m.seen[url] {
+		return url
+	}
+	m.seen[url] = true
+
+	localPath, ok := resolveUploadPath(m.uploadDir, url)
+	if !ok {
+		return url
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return url
+	}
+
+	hash := sha256.Sum256(data)
+	hashStr := hex.EncodeToString(hash[:])
+	zipPath := "media/" + hashStr + strings.ToLower(filepath.Ext(localPath))
+
+	// The same content may be referenced through different URLs
+	if !m.written[zipPath] {
+		entry, err := m.zw.Create(zipPath)
+		if err != nil {
+			m.err = err
+			return url
+		}
+		if _, err := entry.Write(data); err != nil {
+			m.err = err
+			return url
+		}
+		m.written[zipPath] = true
+	}
+
+	m.entries = append(m.entries, dto.PackageMedia{
+		URL:    url,
+		Path:   zipPath,
+		SHA256: hashStr,
+	})
+	return url
+}
+
+func (m *mediaCollector) addPtr(url *string) *string {
+	if url != nil {
+		m.add(*url)
+	}
+	return url
+}
+
+// resolveUploadPath maps a /uploads/... URL to a file inside uploadDir
+func resolveUploadPath(uploadDir, url string) (string, bool) {
+	const prefix = "/uploads/"
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+
+	rel := filepath.FromSlash(path.Clean("/" + strings.TrimPrefix(url, prefix)))
+	localPath := filepath.Join(uploadDir, rel)
+
+	base, err := filepath.Abs(uploadDir)
+	if err != nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(localPath)
+	if err != nil || !strings.HasPrefix(abs, base+string(filepath.Separator)) {
+		return "", false
+	}
+	return localPath, true
+}
@@ -5,7 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -78,15 +78,19 @@ func (m *FileCacheManager) CacheImage(imageURL, prompt, size string) (string, er
 		return "", fmt.Errorf("failed to read image data: %w", err)
 	}
 
-	// Save to cache
+	return m.SaveImage(imageData, prompt, size)
+}
+
+// SaveImage writes image bytes to the cache file for prompt and size
+func (m *FileCacheManager) SaveImage(data []byte, prompt, size string) (string, error) {
 	cacheKey := m.getCacheKey(prompt, size)
 	cachedPath := filepath.Join(m.cacheDir, cacheKey+".png")
 
-	if err := os.WriteFile(cachedPath, imageData, 0644); err != nil {
+	if err := os.WriteFile(cachedPath, data, 0644); err != nil {
 		return "", fmt.Errorf("failed to write cache file: %w", err)
 	}
 
-	log.Printf("Cached image: %s (size: %d bytes)", cachedPath, len(imageData))
+	slog.Debug("Cached image", "path", cachedPath, "bytes", len(data))
 	return "/" + cachedPath, nil
 }
 
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"dannyswat/learnspeak/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	FlashcardExportFormatAnki    = "apkg"
+	FlashcardExportFormatQuizlet = "tsv"
+)
+
+// FlashcardExportService exports topic flashcards and bookmarked words for study in
+// Anki (.apkg with embedded images and audio) or Quizlet (tab-separated term/definition)
+type FlashcardExportService struct {
+	db        *gorm.DB
+	uploadDir string
+}
+
+// NewFlashcardExportService creates a new flashcard export service
+func NewFlashcardExportService(db *gorm.DB, uploadDir string) *FlashcardExportService {
+	return &FlashcardExportService{
+		db:        db,
+		uploadDir: uploadDir,
+	}
+}
+
+// ExportTopic writes the flashcards of a topic in the given format and returns a file name.
+// Only translations in the topic's language are included. includeUnpublished allows
+// topics learners can't see yet.
+func (s *FlashcardExportService) ExportTopic(topicID uint, format string, includeUnpublished bool, w io.Writer) (string, error) {
+	var topic models.Topic
+	if err := s.db.Preload("Language").First(&topic, topicID).Error; err != nil || !TopicVisible(&topic, includeUnpublished) {
+		return "", ErrTopicNotFound
+	}
+
+	var topicWords []models.TopicWord
+	if err := s.db.Where("topic_id = ?", topicID).
+		Order("sequence_order ASC").
+		Preload("Word.Translations").
+		Preload("Word.Translations.Language").
+		Find(&topicWords).Error; err != nil {
+		return "", fmt.Errorf("failed to load flashcards: %w", err)
+	}
+
+	words := make([]models.Word, 0, len(topicWords))
+	for _, tw := range topicWords {
+		words = append(words, tw.Word)
+	}
+
+	fileBase := fmt.Sprintf("topic-%d", topic.ID)
+	return s.export("LearnSpeak::"+topic.Name, fileBase, []string{"learnspeak", exportTag(topic.Name)}, words, &topic.Language, format, w)
+}
+
+// ExportBookmarks writes a user's bookmarked words in the given format and returns a file name.
+// When languageID is zero all translations are included.
+func (s *FlashcardExportService) ExportBookmarks(userID, languageID uint, format string, w io.Writer) (string, error) {
+	var bookmarks []models.UserBookmark
+	if err := s.db.Where("user_id = ? AND word_id IS NOT NULL", userID).
+		Order("created_at ASC").
+		Preload("Word.Translations").
+		Preload("Word.Translations.Language").
+		Find(&bookmarks).Error; err != nil {
+		return "", fmt.Errorf("failed to load bookmarks: %w", err)
+	}
+
+	var language *models.Language
+	if languageID > 0 {
+		language = &models.Language{}
+		if err := s.db.First(language, languageID).Error; err != nil {
+			return "", fmt.Errorf("language not found")
+		}
+	}
+
+	words := make([]models.Word, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if b.Word != nil {
+			words = append(words, *b.Word)
+		}
+	}
+
+	return s.export("LearnSpeak::Bookmarks", "bookmarks", []string{"learnspeak", "bookmarks"}, words, language, format, w)
+}
+
+func (s *FlashcardExportService) export(deckName, fileBase string, tags []string, words []models.Word, language *models.Language, format string, w io.Writer) (string, error) {
+	switch strings.ToLower(format) {
+	case "", FlashcardExportFormatAnki:
+		deck := s.buildAnkiDeck(deckName, tags, words, language)
+		if err := writeAnkiPackage(deck, w); err != nil {
+			return "", err
+		}
+		return fileBase + ".apkg", nil
+	case FlashcardExportFormatQuizlet:
+		if err := writeQuizletTSV(words, language, w); err != nil {
+			return "", err
+		}
+		return fileBase + ".tsv", nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func (s *FlashcardExportService) buildAnkiDeck(name string, tags []string, words []models.Word, language *models.Language) *ankiDeck {
+	deck := &ankiDeck{
+		Name: name,
+		RTL:  language != nil && language.Direction == "rtl",
+	}
+
+	mediaNames := make(map[string]string)
+	addMedia := func(url string) string {
+		if url == "" {
+			return ""
+		}
+		if name, ok := mediaNames[url]; ok {
+			return name
+		}
+		localPath, ok := resolveUploadPath(s.uploadDir, url)
+		if !ok {
+			return ""
+		}
+		if _, err := os.Stat(localPath); err != nil {
+			return ""
+		}
+		// Name media by URL hash so re-imports into Anki reuse the same files
+		sum := sha1.Sum([]byte(url))
+		name := "learnspeak_" + hex.EncodeToString(sum[:8]) + strings.ToLower(path.Ext(url))
+		mediaNames[url] = name
+		deck.Media = append(deck.Media, ankiMedia{Name: name, Path: localPath})
+		return name
+	}
+
+	for _, word := range words {
+		translations := filterTranslations(word.Translations, language)
+
+		var texts, romanizations []string
+		audio := ""
+		for _, t := range translations {
+			text := html.EscapeString(t.Translation)
+			if language == nil {
+				text = html.EscapeString(t.Language.Name) + ": " + text
+			}
+			texts = append(texts, text)
+			if t.Romanization != "" {
+				romanizations = append(romanizations, html.EscapeString(t.Romanization))
+			}
+			if name := addMedia(t.AudioURL); name != "" {
+				audio += "[sound:" + name + "]"
+			}
+		}
+
+		image := ""
+		if name := addMedia(word.ImageURL); name != "" {
+			image = `<img src="` + html.EscapeString(name) + `">`
+		}
+
+		deck.Notes = append(deck.Notes, ankiNote{
+			GUID: fmt.Sprintf("learnspeak-word-%d", word.ID),
+			Fields: []string{
+				html.EscapeString(word.BaseWord),
+				image,
+				strings.Join(texts, "<br>"),
+				strings.Join(romanizations, "<br>"),
+				audio,
+				strings.ReplaceAll(html.EscapeString(word.Notes), "\n", "<br>"),
+			},
+			Tags: tags,
+		})
+	}
+
+	return deck
+}
+
+// writeQuizletTSV writes one "term<TAB>definition" line per word, the format accepted by
+// Quizlet's import. Romanization is appended to the definition in parentheses.
+func writeQuizletTSV(words []models.Word, language *models.Language, w io.Writer) error {
+	for _, word := range words {
+		var definitions []string
+		for _, t := range filterTranslations(word.Translations, language) {
+			definition := t.Translation
+			if t.Romanization != "" {
+				definition += " (" + t.Romanization + ")"
+			}
+			if language == nil {
+				definition = t.Language.Name + ": " + definition
+			}
+			definitions = append(definitions, definition)
+		}
+		if len(definitions) == 0 {
+			continue
+		}
+
+		line := tsvField(word.BaseWord) + "\t" + tsvField(strings.Join(definitions, "; ")) + "\n"
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterTranslations returns the translations in language, or all of them when language is nil
+func filterTranslations(translations []models.WordTranslation, language *models.Language) []models.WordTranslation {
+	if language == nil {
+		return translations
+	}
+	var filtered []models.WordTranslation
+	for _, t := range translations {
+		if t.LanguageID == language.ID {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// tsvField flattens tabs and line breaks, which would otherwise split the record
+func tsvField(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+var exportTagPattern = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// exportTag turns a name into a single lowercase token usable as an Anki tag
+func exportTag(name string) string {
+	tag := strings.Trim(exportTagPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if tag == "" {
+		return "topic"
+	}
+	return tag
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"dannyswat/learnspeak/database"
+	"dannyswat/learnspeak/dto"
+
+	"gorm.io/gorm"
+)
+
+// healthCheckTimeout bounds each dependency check so a hung dependency can't stall /readyz
+const healthCheckTimeout = 3 * time.Second
+
+// HealthService reports the state of the dependencies the API needs to serve traffic
+type HealthService struct {
+	db                 *gorm.DB
+	uploadDir          string
+	ttsService         *TTSService
+	translationService *TranslationService
+	imageService       *ImageGenerationService
+}
+
+// NewHealthService creates a new health service. imageService may be nil when image
+// generation failed to initialise.
+func NewHealthService(
+	db *gorm.DB,
+	uploadDir string,
+	ttsService *TTSService,
+	translationService *TranslationService,
+	imageService *ImageGenerationService,
+) *HealthService {
+	return &HealthService{
+		db:                 db,
+		uploadDir:          uploadDir,
+		ttsService:         ttsService,
+		translationService: translationService,
+		imageService:       imageService,
+	}
+}
+
+type healthCheck struct {
+	name     string
+	required bool
+	check    func(ctx context.Context) (status, message string)
+}
+
+// Readiness checks every dependency concurrently. The database and upload directory are
+// required; AI providers are optional and only degrade the overall status.
+func (s *HealthService) Readiness(ctx context.Context) *dto.HealthResponse {
+	checks := []healthCheck{
+		{"database", true, s.checkDatabase},
+		{"migrations", true, s.checkMigrations},
+		{"uploads", true, s.checkUploadDir},
+		{"tts", false, s.checkConfigured(s.ttsService != nil && s.ttsService.IsConfigured(), "Azure TTS", "AZURE_TTS_KEY")},
+		{"translator", false, s.checkConfigured(s.translationService != nil && s.translationService.IsConfigured(), "Azure Translator", "AZURE_TRANSLATOR_KEY")},
+		{"imageGeneration", false, s.checkImageGeneration},
+	}
+
+	components := make([]dto.HealthComponent, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c healthCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			status, message := c.check(checkCtx)
+			components[i] = dto.HealthComponent{
+				Name:      c.name,
+				Status:    status,
+				Required:  c.required,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+				Message:   message,
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	overall := dto.HealthStatusOK
+	for _, c := range components {
+		if c.Status == dto.HealthStatusOK {
+			continue
+		}
+		if c.Required {
+			overall = dto.HealthStatusFail
+			break
+		}
+		overall = dto.HealthStatusDegraded
+	}
+
+	return &dto.HealthResponse{
+		Status:     overall,
+		Components: components,
+		CheckedAt:  time.Now().UTC(),
+	}
+}
+
+func (s *HealthService) checkDatabase(ctx context.Context) (string, string) {
+	// Driver errors can contain connection details, so they are logged rather than returned
+	sqlDB, err := s.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Readiness check: database unreachable", "error", err)
+		return dto.HealthStatusFail, "database is unreachable"
+	}
+	return dto.HealthStatusOK, ""
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) (string, string) {
+	pending, err := database.Registry.Pending(s.db.WithContext(ctx))
+	if err != nil {
+		slog.WarnContext(ctx, "Readiness check: failed to read migration state", "error", err)
+		return dto.HealthStatusFail, "failed to read migration state"
+	}
+	if len(pending) > 0 {
+		return dto.HealthStatusFail, fmt.Sprintf("%d pending migration(s), next is %s", len(pending), pending[0].Version())
+	}
+
+	migrations := database.Registry.GetMigrations()
+	if len(migrations) == 0 {
+		return dto.HealthStatusOK, ""
+	}
+	return dto.HealthStatusOK, "schema version " + migrations[len(migrations)-1].Version()
+}
+
+func (s *HealthService) checkUploadDir(ctx context.Context) (string, string) {
+	f, err := os.CreateTemp(s.uploadDir, ".readyz-*")
+	if err != nil {
+		return dto.HealthStatusFail, fmt.Sprintf("upload directory is not writable: %v", err)
+	}
+	name := f.Name()
+	f.Close()
+	if err := os.Remove(name); err != nil {
+		return dto.HealthStatusFail, fmt.Sprintf("failed to remove probe file: %v", err)
+	}
+	return dto.HealthStatusOK, ""
+}
+
+func (s *HealthService) checkImageGeneration(ctx context.Context) (string, string) {
+	if s.imageService == nil {
+		return dto.HealthStatusFail, "image generation service failed to initialise"
+	}
+	if !s.imageService.IsConfigured() {
+		return dto.HealthStatusUnconfigured, s.imageService.ProviderName() + " credentials are not set"
+	}
+
+	// A provider cooling down after repeated failures is being skipped by the fallback chain
+	var unhealthy []string
+	for _, p := range s.imageService.ProviderStatuses() {
+		if p.Configured && !p.Healthy {
+			unhealthy = append(unhealthy, p.ID)
+		}
+	}
+	if len(unhealthy) > 0 {
+		return dto.HealthStatusDegraded, fmt.Sprintf("%s (unhealthy: %s)", s.imageService.ProviderName(), strings.Join(unhealthy, ", "))
+	}
+	return dto.HealthStatusOK, s.imageService.ProviderName()
+}
+
+// checkConfigured reports a provider whose only readiness signal is having credentials
+func (s *HealthService) checkConfigured(configured bool, provider, envVar string) func(ctx context.Context) (string, string) {
+	return func(ctx context.Context) (string, string) {
+		if !configured {
+			return dto.HealthStatusUnconfigured, fmt.Sprintf("%s is not configured, set %s", provider, envVar)
+		}
+		return dto.HealthStatusOK, provider
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"time"
@@ -42,7 +42,7 @@ func NewIdeogramGenerator() (*IdeogramGenerator, error) {
 
 	// Check if Ideogram is configured
 	if cfg.IdeogramAPIKey == "" {
-		log.Println("Warning: Ideogram API key not configured")
+		slog.Warn("Ideogram API key not configured")
 		return &IdeogramGenerator{
 			apiKey:   "",
 			endpoint: "https://api.ideogram.ai/v1/ideogram-v3/generate",
@@ -3,19 +3,26 @@ package services
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
 
 	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/metrics"
+	"dannyswat/learnspeak/models"
 )
 
-// ImageGenerationService is the main service that uses the configured provider
+// ImageGenerationService generates images through the configured chain of providers
 type ImageGenerationService struct {
-	generator ImageGenerator
-	cache     ImageCacheManager
+	chain  *ImageGeneratorChain
+	cache  ImageCacheManager
+	usage  *AIUsageService
+	assets *AssetService
 }
 
-// NewImageGenerationService creates a new image generation service with the configured provider
-func NewImageGenerationService() (*ImageGenerationService, error) {
+// NewImageGenerationService creates a new image generation service with the providers listed
+// in IMAGE_GENERATION_PROVIDER, tried in that order. usage may be nil to skip accounting, and
+// assets nil to skip recording saved images in the asset library.
+func NewImageGenerationService(usage *AIUsageService, assets *AssetService) (*ImageGenerationService, error) {
 	cfg := config.AppConfig
 
 	// Create cache manager
@@ -24,51 +31,91 @@ func NewImageGenerationService() (*ImageGenerationService, error) {
 		return nil, fmt.Errorf("failed to create cache manager: %w", err)
 	}
 
-	// Create the appropriate generator based on configuration
-	var generator ImageGenerator
-	provider := cfg.ImageGenerationProvider
-
-	log.Printf("Initializing image generation with provider: %s", provider)
+	chain := NewImageGeneratorChain(cfg.ImageProviderFailureThreshold, cfg.ImageProviderCooldown)
+	for _, name := range strings.Split(cfg.ImageGenerationProvider, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
 
-	switch provider {
-	case "ideogram":
-		gen, err := NewIdeogramGenerator()
+		id, gen, err := newImageGenerator(name)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create Ideogram generator: %w", err)
+			return nil, err
 		}
-		generator = gen
+		if gen == nil {
+			slog.Warn("Unknown image generation provider, skipping", "provider", name)
+			continue
+		}
+		chain.Add(id, gen)
+
 		if gen.IsConfigured() {
-			log.Printf("✅ %s image generator initialized", gen.GetProviderName())
+			slog.Info("Image generator initialized", "provider", gen.GetProviderName())
 		} else {
-			log.Printf("⚠️  %s credentials not configured", gen.GetProviderName())
+			slog.Warn("Image generator credentials not configured", "provider", gen.GetProviderName())
 		}
+	}
 
-	case "azure":
-		fallthrough
-	default:
-		gen, err := NewAzureOpenAIGenerator()
+	if len(chain.providers) == 0 {
+		id, gen, err := newImageGenerator("azure")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create Azure OpenAI generator: %w", err)
-		}
-		generator = gen
-		if gen.IsConfigured() {
-			log.Printf("✅ %s image generator initialized", gen.GetProviderName())
-		} else {
-			log.Printf("⚠️  %s credentials not configured", gen.GetProviderName())
+			return nil, err
 		}
+		chain.Add(id, gen)
 	}
 
+	slog.Info("Initializing image generation", "providers", chain.GetProviderName())
+
 	return &ImageGenerationService{
-		generator: generator,
-		cache:     cache,
+		chain:  chain,
+		cache:  cache,
+		usage:  usage,
+		assets: assets,
 	}, nil
 }
 
+// newImageGenerator creates the generator for a provider name from IMAGE_GENERATION_PROVIDER,
+// returning its ledger ID. Unknown names return a nil generator.
+func newImageGenerator(name string) (string, ImageGenerator, error) {
+	switch name {
+	case "azure":
+		gen, err := NewAzureOpenAIGenerator()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create Azure OpenAI generator: %w", err)
+		}
+		return models.AIProviderAzureOpenAI, gen, nil
+	case "ideogram":
+		gen, err := NewIdeogramGenerator()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create Ideogram generator: %w", err)
+		}
+		return models.AIProviderIdeogram, gen, nil
+	case "openai":
+		gen, err := NewOpenAIImageGenerator()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create OpenAI image generator: %w", err)
+		}
+		return models.AIProviderOpenAI, gen, nil
+	case "stable-diffusion":
+		gen, err := NewStableDiffusionGenerator()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create Stable Diffusion generator: %w", err)
+		}
+		return models.AIProviderStableDiffusion, gen, nil
+	case "placeholder":
+		gen, err := NewPlaceholderImageGenerator()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create placeholder generator: %w", err)
+		}
+		return models.AIProviderPlaceholder, gen, nil
+	}
+	return "", nil, nil
+}
+
 // GenerateImage generates an educational image for a word
 func (s *ImageGenerationService) GenerateImage(ctx context.Context, opts ImageGeneratorOptions) (*GeneratedImageResult, error) {
-	// Check if generator is configured
-	if !s.generator.IsConfigured() {
-		return nil, fmt.Errorf("%s is not configured. Please check your .env file", s.generator.GetProviderName())
+	// Check if any provider is configured
+	if !s.chain.IsConfigured() {
+		return nil, fmt.Errorf("%s is not configured. Please check your .env file", s.chain.GetProviderName())
 	}
 
 	// Build prompt
@@ -85,36 +132,102 @@ func (s *ImageGenerationService) GenerateImage(ctx context.Context, opts ImageGe
 	// Check cache first (only if not using custom prompt)
 	if useCache {
 		cachedImage, err := s.cache.GetCachedImage(prompt, size)
+		metrics.ObserveCacheLookup(metrics.CacheImage, err == nil)
 		if err == nil {
-			log.Printf("✅ Using cached image for prompt: %s", prompt)
+			slog.DebugContext(ctx, "Using cached image", "prompt", prompt)
+			s.recordUsage(ctx, s.chain.PrimaryID(), true)
+			if s.assets != nil {
+				cachedImage.Asset, _ = s.assets.GetAssetByURL(cachedImage.LocalPath)
+				// Images cached before they were described become searchable on their next use
+				if cachedImage.Asset != nil && cachedImage.Asset.Word == "" && opts.Word != "" {
+					s.describeImage(ctx, cachedImage.LocalPath, opts, prompt)
+				}
+			}
 			return cachedImage, nil
 		}
 	}
 
-	// Generate new image using the configured provider
-	log.Printf("🎨 Generating new image using %s for prompt: %s", s.generator.GetProviderName(), prompt)
-	result, err := s.generator.GenerateImage(ctx, opts)
+	if err := s.usage.CheckBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	// Generate new image, falling back through the chain
+	slog.InfoContext(ctx, "Generating image", "providers", s.chain.GetProviderName(), "prompt", prompt)
+	result, err := s.chain.GenerateImage(ctx, opts)
 	if err != nil {
+		slog.ErrorContext(ctx, "Image generation failed", "error", err)
 		return nil, err
 	}
+	s.recordUsage(ctx, result.Provider, false)
 
-	// Download and cache image
+	// Providers that return bytes have no URL to link to, so they are always saved.
 	// If custom prompt was used, this will override any existing cache
-	if config.AppConfig.ImageCacheEnabled && result.URL != "" {
+	switch {
+	case len(result.Data) > 0:
+		localPath, err := s.cache.SaveImage(result.Data, prompt, size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save generated image: %w", err)
+		}
+		result.LocalPath = localPath
+		result.Data = nil
+	case config.AppConfig.ImageCacheEnabled && result.URL != "":
 		localPath, err := s.cache.CacheImage(result.URL, prompt, size)
 		if err != nil {
-			log.Printf("⚠️  Warning: failed to cache image: %v", err)
+			slog.WarnContext(ctx, "Failed to cache image", "error", err)
 		} else {
 			result.LocalPath = localPath
 			if opts.CustomPrompt != "" {
-				log.Printf("✅ Cached custom prompt image, overriding previous cache for: %s", prompt)
+				slog.DebugContext(ctx, "Cached custom prompt image, overriding previous cache", "prompt", prompt)
 			}
 		}
 	}
 
+	if result.LocalPath != "" && s.assets != nil {
+		asset, err := s.assets.ProcessImageAsync(ctx, result.LocalPath, models.AssetSourceGenerated, "")
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to process generated image", "path", result.LocalPath, "error", err)
+		} else {
+			s.describeImage(ctx, result.LocalPath, opts, prompt)
+			asset.Word, asset.Translation, asset.Prompt = opts.Word, opts.Translation, prompt
+		}
+		result.Asset = asset
+	}
+
 	return result, nil
 }
 
+// describeImage stores what a generated image depicts so it can be found by search
+func (s *ImageGenerationService) describeImage(ctx context.Context, url string, opts ImageGeneratorOptions, prompt string) {
+	if err := s.assets.DescribeImage(url, opts.Word, opts.Translation, prompt); err != nil {
+		slog.WarnContext(ctx, "Failed to describe generated image", "url", url, "error", err)
+	}
+}
+
+// recordUsage records one image in the usage ledger under the provider's ID
+func (s *ImageGenerationService) recordUsage(ctx context.Context, provider string, cacheHit bool) {
+	s.usage.Record(ctx, AIUsageEvent{
+		Provider:  provider,
+		Operation: models.AIOperationImage,
+		Images:    1,
+		CacheHit:  cacheHit,
+	})
+}
+
+// IsConfigured returns true if at least one provider in the chain has credentials
+func (s *ImageGenerationService) IsConfigured() bool {
+	return s.chain.IsConfigured()
+}
+
+// ProviderName returns the names of the providers in fallback order
+func (s *ImageGenerationService) ProviderName() string {
+	return s.chain.GetProviderName()
+}
+
+// ProviderStatuses reports the health of each provider in the chain
+func (s *ImageGenerationService) ProviderStatuses() []ImageProviderStatus {
+	return s.chain.Statuses()
+}
+
 // buildPrompt builds a consistent prompt regardless of provider
 func (s *ImageGenerationService) buildPrompt(opts ImageGeneratorOptions) string {
 	// Let the generator build its own prompt for now
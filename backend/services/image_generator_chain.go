@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"dannyswat/learnspeak/metrics"
+)
+
+// ImageProviderStatus is the health of one provider in the fallback chain
+type ImageProviderStatus struct {
+	ID                  string     `json:"id"`
+	Name                string     `json:"name"`
+	Configured          bool       `json:"configured"`
+	Healthy             bool       `json:"healthy"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	UnhealthyUntil      *time.Time `json:"unhealthyUntil,omitempty"`
+	LastError           string     `json:"lastError,omitempty"`
+}
+
+type imageProvider struct {
+	id        string
+	generator ImageGenerator
+
+	mu             sync.Mutex
+	failures       int
+	unhealthyUntil time.Time
+	lastError      string
+}
+
+// ImageGeneratorChain tries image providers in order until one succeeds. A provider that
+// fails failureThreshold times in a row is skipped for cooldown, then tried again.
+type ImageGeneratorChain struct {
+	providers        []*imageProvider
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+// NewImageGeneratorChain creates an empty chain
+func NewImageGeneratorChain(failureThreshold int, cooldown time.Duration) *ImageGeneratorChain {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &ImageGeneratorChain{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+	}
+}
+
+// Add appends a provider to the end of the chain
+func (c *ImageGeneratorChain) Add(id string, generator ImageGenerator) {
+	c.providers = append(c.providers, &imageProvider{id: id, generator: generator})
+}
+
+// GenerateImage implements ImageGenerator. It returns the first successful result, tagged
+// with the provider's ID, or all providers' errors joined.
+func (c *ImageGeneratorChain) GenerateImage(ctx context.Context, opts ImageGeneratorOptions) (*GeneratedImageResult, error) {
+	candidates := c.candidates(time.Now())
+	if len(candidates) == 0 {
+		return nil, errors.New("no image generation provider is configured")
+	}
+
+	var errs []error
+	for i, p := range candidates {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		result, err := p.generator.GenerateImage(ctx, opts)
+		metrics.ObserveProviderCall(p.id, "image", start, err)
+		if err == nil {
+			p.recordSuccess()
+			result.Provider = p.id
+			return result, nil
+		}
+
+		p.recordFailure(err, c.failureThreshold, c.cooldown)
+		errs = append(errs, fmt.Errorf("%s: %w", p.generator.GetProviderName(), err))
+		if i < len(candidates)-1 {
+			slog.WarnContext(ctx, "Image provider failed, trying next", "provider", p.id, "next", candidates[i+1].id, "error", err)
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// candidates returns the configured providers that aren't cooling down. If every one is,
+// all configured providers are returned so generation is still attempted.
+func (c *ImageGeneratorChain) candidates(now time.Time) []*imageProvider {
+	var configured, healthy []*imageProvider
+	for _, p := range c.providers {
+		if !p.generator.IsConfigured() {
+			continue
+		}
+		configured = append(configured, p)
+		if p.isHealthy(now) {
+			healthy = append(healthy, p)
+		}
+	}
+	if len(healthy) == 0 {
+		return configured
+	}
+	return healthy
+}
+
+// GetProviderName lists the chain's providers in order
+func (c *ImageGeneratorChain) GetProviderName() string {
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.generator.GetProviderName()
+	}
+	return strings.Join(names, " → ")
+}
+
+// IsConfigured returns true if at least one provider is configured
+func (c *ImageGeneratorChain) IsConfigured() bool {
+	for _, p := range c.providers {
+		if p.generator.IsConfigured() {
+			return true
+		}
+	}
+	return false
+}
+
+// PrimaryID returns the ID of the first configured provider, or ""
+func (c *ImageGeneratorChain) PrimaryID() string {
+	for _, p := range c.providers {
+		if p.generator.IsConfigured() {
+			return p.id
+		}
+	}
+	return ""
+}
+
+// Statuses reports the health of every provider in chain order
+func (c *ImageGeneratorChain) Statuses() []ImageProviderStatus {
+	now := time.Now()
+	statuses := make([]ImageProviderStatus, len(c.providers))
+	for i, p := range c.providers {
+		p.mu.Lock()
+		status := ImageProviderStatus{
+			ID:                  p.id,
+			Name:                p.generator.GetProviderName(),
+			Configured:          p.generator.IsConfigured(),
+			Healthy:             now.After(p.unhealthyUntil),
+			ConsecutiveFailures: p.failures,
+			LastError:           p.lastError,
+		}
+		if !status.Healthy {
+			until := p.unhealthyUntil
+			status.UnhealthyUntil = &until
+		}
+		p.mu.Unlock()
+		statuses[i] = status
+	}
+	return statuses
+}
+
+func (p *imageProvider) isHealthy(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return now.After(p.unhealthyUntil)
+}
+
+func (p *imageProvider) recordSuccess() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures = 0
+	p.unhealthyUntil = time.Time{}
+	p.lastError = ""
+}
+
+func (p *imageProvider) recordFailure(err error, threshold int, cooldown time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures++
+	p.lastError = err.Error()
+	if p.failures >= threshold {
+		p.unhealthyUntil = time.Now().Add(cooldown)
+		slog.Warn("Image provider marked unhealthy", "provider", p.id, "failures", p.failures, "cooldown", cooldown.String())
+	}
+}
@@ -2,6 +2,11 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dannyswat/learnspeak/dto"
 )
 
 // ImageGeneratorOptions contains options for image generation
@@ -20,6 +25,9 @@ type GeneratedImageResult struct {
 	LocalPath string
 	Prompt    string
 	Cached    bool
+	Data      []byte             // image bytes, for providers that don't host the result at a URL
+	Provider  string             // ID of the provider in the chain that produced the image
+	Asset     *dto.AssetResponse // dimensions and variants of the saved image, when recorded
 }
 
 // ImageGenerator is the interface that all image generation providers must implement
@@ -41,4 +49,32 @@ type ImageCacheManager interface {
 
 	// CacheImage saves a generated image to cache
 	CacheImage(imageURL, prompt, size string) (string, error)
+
+	// SaveImage stores image bytes under the prompt's cache key, even when caching is
+	// disabled, since it is the only copy of the image
+	SaveImage(data []byte, prompt, size string) (string, error)
+}
+
+// imageNegativePrompt keeps generated images child-safe and free of text
+const imageNegativePrompt = "violence, scary, dark, inappropriate, adult content, text, words, letters"
+
+// imagePrompt returns the custom prompt, or the educational prompt for the word
+func imagePrompt(opts ImageGeneratorOptions) string {
+	if opts.CustomPrompt != "" {
+		return opts.CustomPrompt
+	}
+	return fmt.Sprintf("A simple image of '%s' with clear visual representation for children language learning. ", opts.Word)
+}
+
+// parseImageSize parses "WIDTHxHEIGHT" with sides of 64 to 2048 pixels, falling back to 1024x1024
+func parseImageSize(size string) (int, int) {
+	w, h, ok := strings.Cut(size, "x")
+	if ok {
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if errW == nil && errH == nil && width >= 64 && height >= 64 && width <= 2048 && height <= 2048 {
+			return width, height
+		}
+	}
+	return 1024, 1024
 }
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"math"
 	"math/rand"
 	"time"
@@ -18,17 +20,17 @@ type JourneyService interface {
 	DeleteJourney(id uint, userID uint) error
 	ListJourneys(params *dto.JourneyFilterParams) (*dto.JourneyListResponse, error)
 	ReorderTopics(journeyID uint, topicIDs []uint, userID uint) error
-	AssignJourney(journeyID uint, userIDs []uint, assignedBy uint) (*dto.AssignJourneyResponse, error)
-	UnassignJourney(journeyID uint, userIDs []uint) error
+	AssignJourney(ctx context.Context, journeyID uint, userIDs []uint, assignedBy uint) (*dto.AssignJourneyResponse, error)
+	UnassignJourney(ctx context.Context, journeyID uint, userIDs []uint) error
 	StartJourney(journeyID uint, userID uint) error
 	GetUserJourneys(userID uint, status *string, page, pageSize int) (*dto.UserJourneyListResponse, error)
 	GetJourneyAssignments(journeyID uint, status *string, page, pageSize int) (*dto.UserJourneyListResponse, error)
 	// Invitation methods
-	GenerateInvitation(journeyID uint, req *dto.CreateInvitationRequest, createdBy uint) (*dto.InvitationResponse, error)
+	GenerateInvitation(ctx context.Context, journeyID uint, req *dto.CreateInvitationRequest, createdBy uint) (*dto.InvitationResponse, error)
 	GetInvitationDetails(token string) (*dto.InvitationDetailsResponse, error)
-	AcceptInvitation(token string, userID uint) error
+	AcceptInvitation(ctx context.Context, token string, userID uint) (uint, error)
 	GetJourneyInvitations(journeyID uint) ([]dto.InvitationResponse, error)
-	DeactivateInvitation(invitationID uint, journeyID uint, userID uint) error
+	DeactivateInvitation(ctx context.Context, invitationID uint, journeyID uint, userID uint) error
 }
 
 type journeyService struct {
@@ -37,6 +39,7 @@ type journeyService struct {
 	topicRepo        repositories.TopicRepository
 	userJourneyRepo  repositories.UserJourneyRepository
 	userProgressRepo repositories.UserProgressRepository
+	audit            *AuditService
 }
 
 func NewJourneyService(
@@ -45,6 +48,7 @@ func NewJourneyService(
 	topicRepo repositories.TopicRepository,
 	userJourneyRepo repositories.UserJourneyRepository,
 	userProgressRepo repositories.UserProgressRepository,
+	audit *AuditService,
 ) JourneyService {
 	return &journeyService{
 		journeyRepo:      journeyRepo,
@@ -52,6 +56,7 @@ func NewJourneyService(
 		topicRepo:        topicRepo,
 		userJourneyRepo:  userJourneyRepo,
 		userProgressRepo: userProgressRepo,
+		audit:            audit,
 	}
 }
 
@@ -73,6 +78,9 @@ func (s *journeyService) CreateJourney(req *dto.CreateJourneyRequest, userID uin
 			if topic.LanguageID != language.ID {
 				return nil, fmt.Errorf("topic '%s' (ID:%d) does not match journey language '%s'", topic.Name, topicID, language.Name)
 			}
+			if topic.DraftOf != nil {
+				return nil, fmt.Errorf("topic '%s' (ID:%d) is a draft copy; add the published topic instead", topic.Name, topicID)
+			}
 		}
 	}
 
@@ -82,6 +90,8 @@ func (s *journeyService) CreateJourney(req *dto.CreateJourneyRequest, userID uin
 		Description: req.Description,
 		LanguageID:  language.ID,
 		CreatedBy:   userID,
+		IsPublic:    req.IsPublic,
+		Status:      models.ContentStatusDraft,
 	}
 
 	// Create journey
@@ -122,6 +132,9 @@ func (s *journeyService) UpdateJourney(id uint, req *dto.UpdateJourneyRequest, u
 	if err != nil {
 		return nil, err
 	}
+	if err := checkJourneyEditable(journey); err != nil {
+		return nil, err
+	}
 
 	// Update journey fields
 	if req.Name != nil {
@@ -130,6 +143,9 @@ func (s *journeyService) UpdateJourney(id uint, req *dto.UpdateJourneyRequest, u
 	if req.Description != nil {
 		journey.Description = *req.Description
 	}
+	if req.IsPublic != nil {
+		journey.IsPublic = *req.IsPublic
+	}
 	if req.LanguageCode != nil {
 		language, err := s.languageRepo.GetByCode(*req.LanguageCode)
 		if err != nil {
@@ -154,6 +170,13 @@ func (s *journeyService) UpdateJourney(id uint, req *dto.UpdateJourneyRequest, u
 			if topic.LanguageID != journey.LanguageID {
 				return nil, fmt.Errorf("topic '%s' (ID:%d) does not match journey language", topic.Name, topicID)
 			}
+			if topic.DraftOf != nil {
+				return nil, fmt.Errorf("topic '%s' (ID:%d) is a draft copy; add the published topic instead", topic.Name, topicID)
+			}
+			// Learners of a published journey must only ever reach published topics
+			if journey.Status == models.ContentStatusPublished && topic.Status != models.ContentStatusPublished {
+				return nil, fmt.Errorf("topic '%s' (ID:%d) is %s; only published topics can be added to a published journey", topic.Name, topicID, topic.Status)
+			}
 		}
 
 		// Get current topics
@@ -258,6 +281,7 @@ func (s *journeyService) ListJourneys(params *dto.JourneyFilterParams) (*dto.Jou
 		params.Search,
 		params.LanguageCode,
 		params.CreatedBy,
+		params.Status,
 		params.Page,
 		params.PageSize,
 		params.IncludeTopics,
@@ -290,14 +314,26 @@ func (s *journeyService) ListJourneys(params *dto.JourneyFilterParams) (*dto.Jou
 // ReorderTopics reorders topics in a journey
 func (s *journeyService) ReorderTopics(journeyID uint, topicIDs []uint, userID uint) error {
 	// Fetch journey to check ownership
-	_, err := s.journeyRepo.GetByID(journeyID, false)
+	journey, err := s.journeyRepo.GetByID(journeyID, false)
 	if err != nil {
 		return err
 	}
+	if err := checkJourneyEditable(journey); err != nil {
+		return err
+	}
 
 	return s.journeyRepo.ReorderTopics(journeyID, topicIDs)
 }
 
+// checkJourneyEditable allows changes to draft and published journeys. Published journeys
+// are edited in place since they only reference topics; review and archive lock them.
+func checkJourneyEditable(journey *models.Journey) error {
+	if journey.Status == models.ContentStatusInReview || journey.Status == models.ContentStatusArchived {
+		return fmt.Errorf("%w: journey is %s", ErrContentLocked, journey.Status)
+	}
+	return nil
+}
+
 // toJourneyResponse converts a journey model to response DTO
 func (s *journeyService) toJourneyResponse(journey *models.Journey, includeTopics bool) (*dto.JourneyResponse, error) {
 	// Get counts
@@ -309,6 +345,8 @@ func (s *journeyService) toJourneyResponse(journey *models.Journey, includeTopic
 		ID:              journey.ID,
 		Name:            journey.Name,
 		Description:     journey.Description,
+		IsPublic:        journey.IsPublic,
+		Status:          journey.Status,
 		TopicCount:      int(topicCount),
 		TotalWords:      totalWords,
 		AssignedToCount: int(assignedToCount),
@@ -362,12 +400,15 @@ func (s *journeyService) toJourneyResponse(journey *models.Journey, includeTopic
 }
 
 // AssignJourney assigns a journey to multiple users
-func (s *journeyService) AssignJourney(journeyID uint, userIDs []uint, assignedBy uint) (*dto.AssignJourneyResponse, error) {
+func (s *journeyService) AssignJourney(ctx context.Context, journeyID uint, userIDs []uint, assignedBy uint) (*dto.AssignJourneyResponse, error) {
 	// Verify journey exists
-	_, err := s.journeyRepo.GetByID(journeyID, false)
+	journey, err := s.journeyRepo.GetByID(journeyID, false)
 	if err != nil {
 		return nil, fmt.Errorf("journey not found")
 	}
+	if journey.Status != models.ContentStatusPublished {
+		return nil, fmt.Errorf("only published journeys can be assigned")
+	}
 
 	var assignments []dto.JourneyAssignment
 	assignedCount := 0
@@ -396,6 +437,20 @@ func (s *journeyService) AssignJourney(journeyID uint, userIDs []uint, assignedB
 		assignedCount++
 	}
 
+	if assignedCount > 0 {
+		assignedIDs := make([]uint, len(assignments))
+		for i, assignment := range assignments {
+			assignedIDs[i] = assignment.UserID
+		}
+		s.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionJourneyAssign,
+			ActorID:    &assignedBy,
+			TargetType: models.AuditTargetJourney,
+			TargetID:   journeyID,
+			Details:    map[string]interface{}{"journeyName": journey.Name, "userIds": assignedIDs},
+		})
+	}
+
 	return &dto.AssignJourneyResponse{
 		AssignedCount: assignedCount,
 		Assignments:   assignments,
@@ -403,11 +458,25 @@ func (s *journeyService) AssignJourney(journeyID uint, userIDs []uint, assignedB
 }
 
 // UnassignJourney removes journey assignments from multiple users
-func (s *journeyService) UnassignJourney(journeyID uint, userIDs []uint) error {
+func (s *journeyService) UnassignJourney(ctx context.Context, journeyID uint, userIDs []uint) error {
+	unassigned := make([]uint, 0, len(userIDs))
+	defer func() {
+		// Record whoever was unassigned, even when a later user failed
+		if len(unassigned) > 0 {
+			s.audit.Record(ctx, AuditEntry{
+				Action:     models.AuditActionJourneyUnassign,
+				TargetType: models.AuditTargetJourney,
+				TargetID:   journeyID,
+				Details:    map[string]interface{}{"userIds": unassigned},
+			})
+		}
+	}()
+
 	for _, userID := range userIDs {
 		if err := s.userJourneyRepo.UnassignJourney(userID, journeyID); err != nil {
 			return fmt.Errorf("failed to unassign journey from user %d: %w", userID, err)
 		}
+		unassigned = append(unassigned, userID)
 	}
 	return nil
 }
@@ -610,12 +679,15 @@ func (s *journeyService) getNextTopic(userID, journeyID uint) *dto.JourneyTopicI
 }
 
 // GenerateInvitation creates a new invitation link for a journey
-func (s *journeyService) GenerateInvitation(journeyID uint, req *dto.CreateInvitationRequest, createdBy uint) (*dto.InvitationResponse, error) {
+func (s *journeyService) GenerateInvitation(ctx context.Context, journeyID uint, req *dto.CreateInvitationRequest, createdBy uint) (*dto.InvitationResponse, error) {
 	// Verify journey exists
 	journey, err := s.journeyRepo.GetByID(journeyID, false)
 	if err != nil {
 		return nil, fmt.Errorf("journey not found")
 	}
+	if journey.Status != models.ContentStatusPublished {
+		return nil, fmt.Errorf("only published journeys can have invitations")
+	}
 
 	// Generate unique token
 	token := generateInvitationToken()
@@ -641,6 +713,21 @@ func (s *journeyService) GenerateInvitation(journeyID uint, req *dto.CreateInvit
 		return nil, fmt.Errorf("failed to create invitation: %w", err)
 	}
 
+	details := map[string]interface{}{"journeyId": journeyID, "journeyName": journey.Name}
+	if req.MaxUses != nil {
+		details["maxUses"] = *req.MaxUses
+	}
+	if expiresAt != nil {
+		details["expiresAt"] = expiresAt.UTC().Format(time.RFC3339)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     models.AuditActionInvitationCreate,
+		ActorID:    &createdBy,
+		TargetType: models.AuditTargetInvitation,
+		TargetID:   invitation.ID,
+		Details:    details,
+	})
+
 	// Get full invitation with relations
 	fullInvitation, err := s.journeyRepo.GetInvitationByToken(token)
 	if err != nil {
@@ -661,7 +748,7 @@ func (s *journeyService) GetInvitationDetails(token string) (*dto.InvitationDeta
 	}
 
 	// Check if invitation is valid
-	if !invitation.IsValid() {
+	if !invitation.IsValid() || invitation.Journey.Status != models.ContentStatusPublished {
 		message := "This invitation link has expired or is no longer valid"
 		if invitation.MaxUses != nil && invitation.CurrentUses >= *invitation.MaxUses {
 			message = "This invitation link has reached its maximum usage limit"
@@ -692,41 +779,49 @@ func (s *journeyService) GetInvitationDetails(token string) (*dto.InvitationDeta
 	}, nil
 }
 
-// AcceptInvitation assigns the journey to the user via invitation
-func (s *journeyService) AcceptInvitation(token string, userID uint) error {
+// AcceptInvitation assigns the journey to the user via invitation and returns the journey ID
+func (s *journeyService) AcceptInvitation(ctx context.Context, token string, userID uint) (uint, error) {
 	invitation, err := s.journeyRepo.GetInvitationByToken(token)
 	if err != nil {
-		return fmt.Errorf("invitation not found")
+		return 0, fmt.Errorf("invitation not found")
 	}
 
-	// Check if invitation is valid
-	if !invitation.IsValid() {
-		return fmt.Errorf("invitation is no longer valid")
+	// Check if invitation is valid; an archived journey stops taking learners
+	if !invitation.IsValid() || invitation.Journey.Status != models.ContentStatusPublished {
+		return 0, fmt.Errorf("invitation is no longer valid")
 	}
 
 	// Check if user is already assigned
 	isAssigned, err := s.userJourneyRepo.IsAssigned(userID, invitation.JourneyID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if isAssigned {
-		return fmt.Errorf("you are already enrolled in this journey")
+		return 0, fmt.Errorf("you are already enrolled in this journey")
 	}
 
 	// Assign journey to user
 	_, err = s.userJourneyRepo.AssignJourney(userID, invitation.JourneyID, invitation.CreatedBy)
 	if err != nil {
-		return fmt.Errorf("failed to assign journey: %w", err)
+		return 0, fmt.Errorf("failed to assign journey: %w", err)
 	}
 
 	// Increment invitation usage count
 	if err := s.journeyRepo.UpdateInvitationUses(invitation.ID); err != nil {
 		// Log error but don't fail the assignment
-		fmt.Printf("Warning: failed to update invitation uses: %v\n", err)
+		slog.WarnContext(ctx, "Failed to update invitation uses", "invitation_id", invitation.ID, "error", err)
 	}
 
-	return nil
+	s.audit.Record(ctx, AuditEntry{
+		Action:     models.AuditActionInvitationAccept,
+		ActorID:    &userID,
+		TargetType: models.AuditTargetInvitation,
+		TargetID:   invitation.ID,
+		Details:    map[string]interface{}{"journeyId": invitation.JourneyID, "journeyName": invitation.Journey.Name},
+	})
+
+	return invitation.JourneyID, nil
 }
 
 // GetJourneyInvitations retrieves all invitations for a journey
@@ -750,14 +845,25 @@ func (s *journeyService) GetJourneyInvitations(journeyID uint) ([]dto.Invitation
 }
 
 // DeactivateInvitation deactivates an invitation link
-func (s *journeyService) DeactivateInvitation(invitationID uint, journeyID uint, userID uint) error {
+func (s *journeyService) DeactivateInvitation(ctx context.Context, invitationID uint, journeyID uint, userID uint) error {
 	// Verify the invitation belongs to this journey
 	invitation, err := s.journeyRepo.GetInvitationByToken("")
 	if err == nil && invitation.JourneyID != journeyID {
 		return fmt.Errorf("invitation does not belong to this journey")
 	}
 
-	return s.journeyRepo.DeactivateInvitation(invitationID)
+	if err := s.journeyRepo.DeactivateInvitation(invitationID); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEntry{
+		Action:     models.AuditActionInvitationDeactivate,
+		ActorID:    &userID,
+		TargetType: models.AuditTargetInvitation,
+		TargetID:   invitationID,
+		Details:    map[string]interface{}{"journeyId": journeyID},
+	})
+	return nil
 }
 
 // Helper function to generate a secure random token
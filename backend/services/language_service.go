@@ -1,24 +1,263 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"dannyswat/learnspeak/dto"
 	"dannyswat/learnspeak/models"
 	"dannyswat/learnspeak/repositories"
 )
 
+var (
+	ErrLanguageNotFound = errors.New("language not found")
+	ErrLanguageExists   = errors.New("a language with this code already exists")
+	// ErrLanguageInUse is returned when deleting a language that content is written in
+	ErrLanguageInUse = errors.New("language is used by content; deactivate it instead")
+)
+
+// languageCodePattern matches BCP-47 style codes such as en, yue or zh-HK
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
 type LanguageService interface {
-	GetAllLanguages() ([]models.Language, error)
+	GetAllLanguages() ([]dto.LanguageResponse, error)
+	ListAll() ([]dto.LanguageResponse, error)
+	GetLanguage(id uint) (*dto.LanguageResponse, error)
+	CreateLanguage(ctx context.Context, req *dto.CreateLanguageRequest) (*dto.LanguageResponse, error)
+	UpdateLanguage(ctx context.Context, id uint, req *dto.UpdateLanguageRequest) (*dto.LanguageResponse, error)
+	DeleteLanguage(ctx context.Context, id uint) error
 }
 
 type languageService struct {
 	languageRepo repositories.LanguageRepository
+	audit        *AuditService
 }
 
-func NewLanguageService(languageRepo repositories.LanguageRepository) LanguageService {
+func NewLanguageService(languageRepo repositories.LanguageRepository, audit *AuditService) LanguageService {
 	return &languageService{
 		languageRepo: languageRepo,
+		audit:        audit,
+	}
+}
+
+// GetAllLanguages returns the active languages
+func (s *languageService) GetAllLanguages() ([]dto.LanguageResponse, error) {
+	languages, err := s.languageRepo.GetAllLanguages()
+	if err != nil {
+		return nil, err
+	}
+	return toLanguageResponses(languages), nil
+}
+
+// ListAll returns every language, inactive ones included
+func (s *languageService) ListAll() ([]dto.LanguageResponse, error) {
+	languages, err := s.languageRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	return toLanguageResponses(languages), nil
+}
+
+func (s *languageService) GetLanguage(id uint) (*dto.LanguageResponse, error) {
+	language, err := s.languageRepo.GetLanguageByID(id)
+	if err != nil {
+		return nil, ErrLanguageNotFound
+	}
+	response := toLanguageResponse(language)
+	return &response, nil
+}
+
+func (s *languageService) CreateLanguage(ctx context.Context, req *dto.CreateLanguageRequest) (*dto.LanguageResponse, error) {
+	if err := s.checkCode(req.Code, 0); err != nil {
+		return nil, err
+	}
+
+	language := &models.Language{
+		Code:               req.Code,
+		Name:               req.Name,
+		NativeName:         req.NativeName,
+		Direction:          req.Direction,
+		IsActive:           true,
+		DefaultVoice:       req.DefaultVoice,
+		Voices:             voicesJSON(req.Voices),
+		RomanizationScheme: req.RomanizationScheme,
+		TranslatorCode:     req.TranslatorCode,
+		FontFamily:         req.FontFamily,
+		Script:             req.Script,
+	}
+	if language.Direction == "" {
+		language.Direction = "ltr"
+	}
+	if req.IsActive != nil {
+		language.IsActive = *req.IsActive
+	}
+
+	if err := s.languageRepo.Create(language); err != nil {
+		return nil, fmt.Errorf("failed to create language: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditEntry{
+		Action:     models.AuditActionLanguageCreate,
+		TargetType: models.AuditTargetLanguage,
+		TargetID:   language.ID,
+		Details:    map[string]interface{}{"code": language.Code, "name": language.Name},
+	})
+
+	response := toLanguageResponse(language)
+	return &response, nil
+}
+
+func (s *languageService) UpdateLanguage(ctx context.Context, id uint, req *dto.UpdateLanguageRequest) (*dto.LanguageResponse, error) {
+	language, err := s.languageRepo.GetLanguageByID(id)
+	if err != nil {
+		return nil, ErrLanguageNotFound
+	}
+
+	if req.Name != nil && *req.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	var changed []string
+	set := func(field string, target *string, value *string) {
+		if value != nil && *value != *target {
+			*target = *value
+			changed = append(changed, field)
+		}
+	}
+	if req.Code != nil && *req.Code != language.Code {
+		if err := s.checkCode(*req.Code, id); err != nil {
+			return nil, err
+		}
 	}
+	set("code", &language.Code, req.Code)
+	set("name", &language.Name, req.Name)
+	set("nativeName", &language.NativeName, req.NativeName)
+	set("direction", &language.Direction, req.Direction)
+	set("defaultVoice", &language.DefaultVoice, req.DefaultVoice)
+	set("romanizationScheme", &language.RomanizationScheme, req.RomanizationScheme)
+	set("translatorCode", &language.TranslatorCode, req.TranslatorCode)
+	set("fontFamily", &language.FontFamily, req.FontFamily)
+	set("script", &language.Script, req.Script)
+	if req.Voices != nil {
+		voices := voicesJSON(req.Voices)
+		set("voices", &language.Voices, &voices)
+	}
+	if req.IsActive != nil && *req.IsActive != language.IsActive {
+		language.IsActive = *req.IsActive
+		changed = append(changed, "isActive")
+	}
+
+	if len(changed) > 0 {
+		if err := s.languageRepo.Update(language); err != nil {
+			return nil, fmt.Errorf("failed to update language: %w", err)
+		}
+		s.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionLanguageUpdate,
+			TargetType: models.AuditTargetLanguage,
+			TargetID:   language.ID,
+			Details:    map[string]interface{}{"code": language.Code, "changes": changed},
+		})
+	}
+
+	response := toLanguageResponse(language)
+	return &response, nil
+}
+
+// DeleteLanguage deletes a language no content is written in
+func (s *languageService) DeleteLanguage(ctx context.Context, id uint) error {
+	language, err := s.languageRepo.GetLanguageByID(id)
+	if err != nil {
+		return ErrLanguageNotFound
+	}
+
+	inUse, err := s.languageRepo.IsInUse(id)
+	if err != nil {
+		return fmt.Errorf("failed to check language usage: %w", err)
+	}
+	if inUse {
+		return ErrLanguageInUse
+	}
+
+	if err := s.languageRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete language: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditEntry{
+		Action:     models.AuditActionLanguageDelete,
+		TargetType: models.AuditTargetLanguage,
+		TargetID:   language.ID,
+		Details:    map[string]interface{}{"code": language.Code, "name": language.Name},
+	})
+	return nil
+}
+
+// checkCode verifies a language code is well formed and not used by another language
+func (s *languageService) checkCode(code string, id uint) error {
+	if !languageCodePattern.MatchString(code) {
+		return fmt.Errorf("invalid language code %q: expected a BCP-47 code such as th or pt-BR", code)
+	}
+	if existing, err := s.languageRepo.GetByCode(code); err == nil && existing.ID != id {
+		return ErrLanguageExists
+	}
+	return nil
+}
+
+// languageVoices decodes a language's alternate voices
+func languageVoices(language *models.Language) []models.LanguageVoice {
+	var voices []models.LanguageVoice
+	if language.Voices != "" {
+		_ = json.Unmarshal([]byte(language.Voices), &voices)
+	}
+	return voices
+}
+
+func voicesJSON(voices []dto.LanguageVoice) string {
+	list := make([]models.LanguageVoice, len(voices))
+	for i, voice := range voices {
+		list[i] = models.LanguageVoice{Voice: voice.Voice, Name: voice.Name, Gender: voice.Gender}
+	}
+	data, _ := json.Marshal(list)
+	return string(data)
 }
 
-func (s *languageService) GetAllLanguages() ([]models.Language, error) {
-	return s.languageRepo.GetAllLanguages()
+func toLanguageResponses(languages []models.Language) []dto.LanguageResponse {
+	responses := make([]dto.LanguageResponse, len(languages))
+	for i := range languages {
+		responses[i] = toLanguageResponse(&languages[i])
+	}
+	return responses
+}
+
+func toLanguageResponse(language *models.Language) dto.LanguageResponse {
+	response := dto.LanguageResponse{
+		ID:                 language.ID,
+		Code:               language.Code,
+		Name:               language.Name,
+		NativeName:         language.NativeName,
+		Direction:          language.Direction,
+		IsActive:           language.IsActive,
+		DefaultVoice:       language.DefaultVoice,
+		Voices:             []dto.LanguageVoice{},
+		RomanizationScheme: language.RomanizationScheme,
+		TranslatorCode:     translatorCode(language),
+		FontFamily:         language.FontFamily,
+		Script:             language.Script,
+		CreatedAt:          language.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:          language.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	for _, voice := range languageVoices(language) {
+		response.Voices = append(response.Voices, dto.LanguageVoice{Voice: voice.Voice, Name: voice.Name, Gender: voice.Gender})
+	}
+	return response
+}
+
+// translatorCode returns the code Azure Translator knows a language by
+func translatorCode(language *models.Language) string {
+	if language.TranslatorCode != "" {
+		return language.TranslatorCode
+	}
+	return language.Code
 }
@@ -0,0 +1,597 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidContentType = errors.New("contentType must be topic, journey or conversation")
+	// ErrNotInMarketplace is returned for content that doesn't exist or isn't public
+	ErrNotInMarketplace = errors.New("content not found in the marketplace")
+)
+
+type MarketplaceService interface {
+	ListContent(params *dto.MarketplaceFilterParams, userID uint) (*dto.MarketplaceListResponse, error)
+	GetContent(contentType string, id uint, userID uint) (*dto.MarketplaceItemResponse, error)
+	RateContent(contentType string, id uint, req *dto.RateContentRequest, userID uint) (*dto.ContentRatingResponse, error)
+	DeleteRating(contentType string, id uint, userID uint) error
+	ListRatings(contentType string, id uint, page, pageSize int, userID uint) (*dto.ContentRatingListResponse, error)
+	CloneContent(contentType string, id uint, userID uint) (*dto.CloneResponse, error)
+	ListClones(params *dto.ClonedContentFilterParams, userID uint) (*dto.ClonedContentListResponse, error)
+}
+
+type marketplaceService struct {
+	marketplaceRepo repositories.MarketplaceRepository
+	db              *gorm.DB
+	revisions       RevisionService
+}
+
+// NewMarketplaceService creates a marketplace service. db is used to clone content in one
+// transaction.
+func NewMarketplaceService(marketplaceRepo repositories.MarketplaceRepository, db *gorm.DB, revisions RevisionService) MarketplaceService {
+	return &marketplaceService{
+		marketplaceRepo: marketplaceRepo,
+		db:              db,
+		revisions:       revisions,
+	}
+}
+
+// ListContent lists public content of one type, or the user's own content when Mine is set
+func (s *marketplaceService) ListContent(params *dto.MarketplaceFilterParams, userID uint) (*dto.MarketplaceListResponse, error) {
+	if !isMarketplaceType(params.ContentType) {
+		return nil, ErrInvalidContentType
+	}
+
+	filter := repositories.MarketplaceFilter{
+		ContentType:  params.ContentType,
+		Search:       params.Search,
+		LanguageCode: params.LanguageCode,
+		Level:        params.Level,
+		CreatedBy:    params.CreatedBy,
+		Sort:         params.Sort,
+	}
+	if params.Mine {
+		filter.CreatedBy = userID
+		filter.IncludeUnlisted = true
+	}
+
+	items, total, err := s.marketplaceRepo.List(filter, params.Page, params.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.MarketplaceItemResponse, len(items))
+	for i := range items {
+		responses[i] = toMarketplaceItemResponse(params.ContentType, &items[i])
+	}
+
+	return &dto.MarketplaceListResponse{
+		Items:      responses,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(params.PageSize))),
+	}, nil
+}
+
+// GetContent retrieves listed content, or the user's own content, with their rating of it
+func (s *marketplaceService) GetContent(contentType string, id uint, userID uint) (*dto.MarketplaceItemResponse, error) {
+	item, err := s.visibleItem(contentType, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toMarketplaceItemResponse(contentType, item)
+	rating, err := s.marketplaceRepo.GetRating(contentType, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rating != nil {
+		response.MyRating = &rating.Rating
+	}
+	return &response, nil
+}
+
+// RateContent sets the user's rating of someone else's listed content
+func (s *marketplaceService) RateContent(contentType string, id uint, req *dto.RateContentRequest, userID uint) (*dto.ContentRatingResponse, error) {
+	item, err := s.listedItem(contentType, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.CreatedBy == userID {
+		return nil, fmt.Errorf("you can't rate your own %s", contentType)
+	}
+
+	rating := &models.ContentRating{
+		ContentType: contentType,
+		ContentID:   id,
+		UserID:      userID,
+		Rating:      req.Rating,
+		Comment:     req.Comment,
+	}
+	if err := s.marketplaceRepo.UpsertRating(rating); err != nil {
+		return nil, fmt.Errorf("failed to save rating: %w", err)
+	}
+
+	saved, err := s.marketplaceRepo.GetRating(contentType, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := toContentRatingResponse(saved)
+	return &response, nil
+}
+
+// DeleteRating removes the user's rating of content
+func (s *marketplaceService) DeleteRating(contentType string, id uint, userID uint) error {
+	if !isMarketplaceType(contentType) {
+		return ErrInvalidContentType
+	}
+	deleted, err := s.marketplaceRepo.DeleteRating(contentType, id, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: you haven't rated this %s", ErrNotInMarketplace, contentType)
+	}
+	return nil
+}
+
+// ListRatings lists the ratings of listed content, or of the user's own content
+func (s *marketplaceService) ListRatings(contentType string, id uint, page, pageSize int, userID uint) (*dto.ContentRatingListResponse, error) {
+	item, err := s.visibleItem(contentType, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings, total, err := s.marketplaceRepo.ListRatings(contentType, id, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ContentRatingResponse, len(ratings))
+	for i := range ratings {
+		responses[i] = toContentRatingResponse(&ratings[i])
+	}
+
+	return &dto.ContentRatingListResponse{
+		Ratings:       responses,
+		AverageRating: roundRating(item.AverageRating),
+		Total:         total,
+		Page:          page,
+		PageSize:      pageSize,
+		TotalPages:    int(math.Ceil(float64(total) / float64(pageSize))),
+	}, nil
+}
+
+// CloneContent deep-copies listed content into the user's library: a topic with its words,
+// translations, quiz questions and conversations; a journey with all its topics; or a
+// conversation with its lines and their words. Media is referenced, not duplicated. Copies
+// are private drafts owned by the user, and each copied topic, journey and conversation
+// records the source it came from.
+func (s *marketplaceService) CloneContent(contentType string, id uint, userID uint) (*dto.CloneResponse, error) {
+	item, err := s.listedItem(contentType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.CloneResponse{ContentType: contentType}
+	history := newRevisionLog(s.revisions)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		c := &contentCloner{
+			userID:           userID,
+			history:          history,
+			wordRepo:         repositories.NewWordRepository(tx),
+			topicRepo:        repositories.NewTopicRepository(tx),
+			quizRepo:         repositories.NewQuizRepository(tx),
+			conversationRepo: repositories.NewConversationRepository(tx),
+			journeyRepo:      repositories.NewJourneyRepository(tx),
+			words:            make(map[uint]uint),
+			conversations:    make(map[uint]uint),
+			result:           result,
+		}
+
+		var cloneID uint
+		var err error
+		switch contentType {
+		case models.MarketplaceTopic:
+			cloneID, err = c.cloneTopic(id)
+		case models.MarketplaceJourney:
+			cloneID, err = c.cloneJourney(id)
+		case models.MarketplaceConversation:
+			cloneID, err = c.cloneConversation(id)
+		}
+		if err != nil {
+			return err
+		}
+		result.ID = cloneID
+
+		return repositories.NewMarketplaceRepository(tx).CreateClones(c.clones)
+	})
+	if err != nil {
+		return nil, err
+	}
+	history.record(userID)
+
+	result.Name = item.Name
+	result.ClonedFrom = dto.CloneAttribution{
+		SourceID:   id,
+		SourceName: item.Name,
+		Author:     &dto.CreatorInfo{ID: item.CreatedBy, Name: item.AuthorName, Email: item.AuthorEmail},
+		ClonedAt:   time.Now().Format("2006-01-02T15:04:05Z07:00"),
+	}
+	return result, nil
+}
+
+// ListClones lists content the user cloned, with attribution to its source
+func (s *marketplaceService) ListClones(params *dto.ClonedContentFilterParams, userID uint) (*dto.ClonedContentListResponse, error) {
+	if params.ContentType != "" && !isMarketplaceType(params.ContentType) {
+		return nil, ErrInvalidContentType
+	}
+
+	records, total, err := s.marketplaceRepo.ListClones(userID, params.ContentType, params.CloneID, params.Page, params.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ClonedContentResponse, len(records))
+	for i, record := range records {
+		responses[i] = dto.ClonedContentResponse{
+			ContentType: record.ContentType,
+			ID:          record.CloneID,
+			Name:        record.CloneName,
+			ClonedFrom: dto.CloneAttribution{
+				SourceID:   record.SourceID,
+				SourceName: record.SourceName,
+				ClonedAt:   record.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			},
+		}
+		if record.AuthorName != "" {
+			responses[i].ClonedFrom.Author = &dto.CreatorInfo{ID: record.SourceAuthorID, Name: record.AuthorName, Email: record.AuthorEmail}
+		}
+	}
+
+	return &dto.ClonedContentListResponse{
+		Clones:     responses,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(params.PageSize))),
+	}, nil
+}
+
+// listedItem returns content that is in the marketplace
+func (s *marketplaceService) listedItem(contentType string, id uint) (*repositories.MarketplaceItem, error) {
+	if !isMarketplaceType(contentType) {
+		return nil, ErrInvalidContentType
+	}
+	item, err := s.marketplaceRepo.GetItem(contentType, id)
+	if err != nil || !item.Listed {
+		return nil, ErrNotInMarketplace
+	}
+	return item, nil
+}
+
+// visibleItem returns content that is in the marketplace or belongs to the user
+func (s *marketplaceService) visibleItem(contentType string, id uint, userID uint) (*repositories.MarketplaceItem, error) {
+	if !isMarketplaceType(contentType) {
+		return nil, ErrInvalidContentType
+	}
+	item, err := s.marketplaceRepo.GetItem(contentType, id)
+	if err != nil || (!item.Listed && item.CreatedBy != userID) {
+		return nil, ErrNotInMarketplace
+	}
+	return item, nil
+}
+
+// contentCloner copies content inside one transaction. Words and conversations shared by
+// several topics of a journey are copied once.
+type contentCloner struct {
+	userID           uint
+	history          *revisionLog
+	wordRepo         repositories.WordRepository
+	topicRepo        repositories.TopicRepository
+	quizRepo         repositories.QuizRepository
+	conversationRepo repositories.ConversationRepository
+	journeyRepo      repositories.JourneyRepository
+	words            map[uint]uint
+	conversations    map[uint]uint
+	clones           []models.ContentClone
+	result           *dto.CloneResponse
+}
+
+func (c *contentCloner) record(contentType string, sourceID uint, sourceName string, authorID uint, cloneID uint) {
+	c.clones = append(c.clones, models.ContentClone{
+		ContentType:    contentType,
+		SourceID:       sourceID,
+		SourceName:     sourceName,
+		SourceAuthorID: authorID,
+		CloneID:        cloneID,
+		ClonedBy:       c.userID,
+	})
+}
+
+// cloneWord copies a word and its translations, keeping its image and audio URLs
+func (c *contentCloner) cloneWord(id uint) (uint, error) {
+	if newID, ok := c.words[id]; ok {
+		return newID, nil
+	}
+
+	source, err := c.wordRepo.GetByID(id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load word %d: %w", id, err)
+	}
+	word := &models.Word{
+		BaseWord:     source.BaseWord,
+		ImageURL:     source.ImageURL,
+		Notes:        source.Notes,
+		PartOfSpeech: source.PartOfSpeech,
+		CreatedBy:    c.userID,
+	}
+	if err := c.wordRepo.Create(word); err != nil {
+		return 0, fmt.Errorf("failed to copy word %s: %w", source.BaseWord, err)
+	}
+	for _, t := range source.Translations {
+		examples := make([]models.WordExample, len(t.Examples))
+		for i, e := range t.Examples {
+			examples[i] = models.WordExample{
+				Sentence:      e.Sentence,
+				Romanization:  e.Romanization,
+				Meaning:       e.Meaning,
+				AudioURL:      e.AudioURL,
+				SequenceOrder: e.SequenceOrder,
+			}
+		}
+		if err := c.wordRepo.CreateTranslation(&models.WordTranslation{
+			WordID:       word.ID,
+			LanguageID:   t.LanguageID,
+			Translation:  t.Translation,
+			Romanization: t.Romanization,
+			AudioURL:     t.AudioURL,
+			MeasureWord:  t.MeasureWord,
+			Gender:       t.Gender,
+			Examples:     examples,
+		}); err != nil {
+			return 0, fmt.Errorf("failed to copy translation of %s: %w", source.BaseWord, err)
+		}
+	}
+
+	c.words[id] = word.ID
+	c.history.created(models.RevisionEntityWord, word.ID)
+	c.result.WordsCreated++
+	return word.ID, nil
+}
+
+func (c *contentCloner) cloneWordPtr(id *uint) (*uint, error) {
+	if id == nil {
+		return nil, nil
+	}
+	newID, err := c.cloneWord(*id)
+	if err != nil {
+		return nil, err
+	}
+	return &newID, nil
+}
+
+// cloneConversation copies a conversation with its lines and their words
+func (c *contentCloner) cloneConversation(id uint) (uint, error) {
+	if newID, ok := c.conversations[id]; ok {
+		return newID, nil
+	}
+
+	source, err := c.conversationRepo.GetByID(id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load conversation %d: %w", id, err)
+	}
+	conversation := &models.Conversation{
+		Title:            source.Title,
+		Description:      source.Description,
+		Context:          source.Context,
+		LanguageID:       source.LanguageID,
+		DifficultyLevel:  source.DifficultyLevel,
+		ScenarioAudioURL: source.ScenarioAudioURL,
+		ScenarioImageURL: source.ScenarioImageURL,
+		CreatedBy:        c.userID,
+		Lines:            make([]models.ConversationLine, len(source.Lines)),
+	}
+	for i, line := range source.Lines {
+		wordID, err := c.cloneWordPtr(line.WordID)
+		if err != nil {
+			return 0, err
+		}
+		conversation.Lines[i] = models.ConversationLine{
+			SequenceOrder: line.SequenceOrder,
+			SpeakerRole:   line.SpeakerRole,
+			EnglishText:   line.EnglishText,
+			TargetText:    line.TargetText,
+			Romanization:  line.Romanization,
+			AudioURL:      line.AudioURL,
+			ImageURL:      line.ImageURL,
+			WordID:        wordID,
+			IsLearnerLine: line.IsLearnerLine,
+		}
+	}
+	if err := c.conversationRepo.Create(conversation); err != nil {
+		return 0, fmt.Errorf("failed to copy conversation %s: %w", source.Title, err)
+	}
+
+	c.conversations[id] = conversation.ID
+	c.history.created(models.RevisionEntityConversation, conversation.ID)
+	c.result.ConversationsCreated++
+	c.record(models.MarketplaceConversation, source.ID, source.Title, source.CreatedBy, conversation.ID)
+	return conversation.ID, nil
+}
+
+// cloneTopic copies a topic with its words, quiz questions and conversations
+func (c *contentCloner) cloneTopic(id uint) (uint, error) {
+	source, err := c.topicRepo.GetByID(id, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load topic %d: %w", id, err)
+	}
+	topic := &models.Topic{
+		Name:        source.Name,
+		Description: source.Description,
+		Level:       source.Level,
+		LanguageID:  source.LanguageID,
+		CreatedBy:   c.userID,
+		IsPublic:    false,
+		Status:      models.ContentStatusDraft,
+	}
+	if err := c.topicRepo.Create(topic); err != nil {
+		return 0, fmt.Errorf("failed to copy topic %s: %w", source.Name, err)
+	}
+	c.history.created(models.RevisionEntityTopic, topic.ID)
+	c.result.TopicsCreated++
+
+	wordIDs := make([]uint, 0, len(source.Words))
+	for _, tw := range source.Words {
+		wordID, err := c.cloneWord(tw.WordID)
+		if err != nil {
+			return 0, err
+		}
+		wordIDs = append(wordIDs, wordID)
+	}
+	if len(wordIDs) > 0 {
+		if err := c.topicRepo.AddWords(topic.ID, wordIDs); err != nil {
+			return 0, fmt.Errorf("failed to add words to topic %s: %w", source.Name, err)
+		}
+	}
+
+	questions, err := c.quizRepo.GetByTopicID(id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load quizzes for topic %d: %w", id, err)
+	}
+	for _, q := range questions {
+		wordID, err := c.cloneWordPtr(q.WordID)
+		if err != nil {
+			return 0, err
+		}
+		question := &models.QuizQuestion{
+			TopicID:       topic.ID,
+			WordID:        wordID,
+			QuestionType:  q.QuestionType,
+			QuestionText:  q.QuestionText,
+			AudioURL:      q.AudioURL,
+			ImageURL:      q.ImageURL,
+			CorrectAnswer: q.CorrectAnswer,
+			OptionA:       q.OptionA,
+			OptionB:       q.OptionB,
+			OptionC:       q.OptionC,
+			OptionD:       q.OptionD,
+		}
+		if err := c.quizRepo.Create(question); err != nil {
+			return 0, fmt.Errorf("failed to copy quiz question for topic %s: %w", source.Name, err)
+		}
+		c.history.created(models.RevisionEntityQuizQuestion, question.ID)
+		c.result.QuizzesCreated++
+	}
+
+	conversations, err := c.conversationRepo.GetByTopicID(id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load conversations for topic %d: %w", id, err)
+	}
+	for _, conversation := range conversations {
+		conversationID, err := c.cloneConversation(conversation.ID)
+		if err != nil {
+			return 0, err
+		}
+		if err := c.conversationRepo.LinkToTopic(conversationID, topic.ID); err != nil {
+			return 0, fmt.Errorf("failed to link conversation to topic %s: %w", source.Name, err)
+		}
+	}
+
+	c.record(models.MarketplaceTopic, source.ID, source.Name, source.CreatedBy, topic.ID)
+	return topic.ID, nil
+}
+
+// cloneJourney copies a journey and every topic in it
+func (c *contentCloner) cloneJourney(id uint) (uint, error) {
+	source, err := c.journeyRepo.GetByID(id, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load journey %d: %w", id, err)
+	}
+	journey := &models.Journey{
+		Name:        source.Name,
+		Description: source.Description,
+		LanguageID:  source.LanguageID,
+		CreatedBy:   c.userID,
+		IsPublic:    false,
+		Status:      models.ContentStatusDraft,
+	}
+	if err := c.journeyRepo.Create(journey); err != nil {
+		return 0, fmt.Errorf("failed to copy journey %s: %w", source.Name, err)
+	}
+
+	topicIDs := make([]uint, 0, len(source.Topics))
+	for _, jt := range source.Topics {
+		topicID, err := c.cloneTopic(jt.TopicID)
+		if err != nil {
+			return 0, err
+		}
+		topicIDs = append(topicIDs, topicID)
+	}
+	if len(topicIDs) > 0 {
+		if err := c.journeyRepo.AddTopics(journey.ID, topicIDs); err != nil {
+			return 0, fmt.Errorf("failed to add topics to journey %s: %w", source.Name, err)
+		}
+	}
+
+	c.record(models.MarketplaceJourney, source.ID, source.Name, source.CreatedBy, journey.ID)
+	return journey.ID, nil
+}
+
+func isMarketplaceType(contentType string) bool {
+	switch contentType {
+	case models.MarketplaceTopic, models.MarketplaceJourney, models.MarketplaceConversation:
+		return true
+	}
+	return false
+}
+
+func toMarketplaceItemResponse(contentType string, item *repositories.MarketplaceItem) dto.MarketplaceItemResponse {
+	return dto.MarketplaceItemResponse{
+		ContentType: contentType,
+		ID:          item.ID,
+		Name:        item.Name,
+		Description: item.Description,
+		Level:       item.Level,
+		Language: &dto.LanguageInfo{
+			ID:         item.LanguageID,
+			Code:       item.LanguageCode,
+			Name:       item.LanguageName,
+			NativeName: item.LanguageNativeName,
+		},
+		Author:        &dto.CreatorInfo{ID: item.CreatedBy, Name: item.AuthorName, Email: item.AuthorEmail},
+		ItemCount:     int(item.ItemCount),
+		AverageRating: roundRating(item.AverageRating),
+		RatingCount:   item.RatingCount,
+		CloneCount:    item.CloneCount,
+		IsListed:      item.Listed,
+		CreatedAt:     item.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:     item.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func toContentRatingResponse(rating *models.ContentRating) dto.ContentRatingResponse {
+	return dto.ContentRatingResponse{
+		ID:        rating.ID,
+		User:      toCreatorInfo(&rating.User),
+		Rating:    rating.Rating,
+		Comment:   rating.Comment,
+		CreatedAt: rating.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: rating.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// roundRating rounds an average rating to one decimal place
+func roundRating(average float64) float64 {
+	return math.Round(average*10) / 10
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"dannyswat/learnspeak/config"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+)
+
+// OpenAIImageGenerator implements ImageGenerator against any OpenAI-compatible images API,
+// e.g. api.openai.com or a local stand-in server set with OPENAI_IMAGE_BASE_URL
+type OpenAIImageGenerator struct {
+	client     *openai.Client
+	model      string
+	configured bool
+}
+
+// NewOpenAIImageGenerator creates an OpenAI-compatible image generator. It is configured
+// when an API key or a custom base URL is set; local servers often need no key.
+func NewOpenAIImageGenerator() (*OpenAIImageGenerator, error) {
+	cfg := config.AppConfig
+
+	if cfg.OpenAIImageAPIKey == "" && cfg.OpenAIImageBaseURL == "" {
+		return &OpenAIImageGenerator{model: cfg.OpenAIImageModel}, nil
+	}
+
+	opts := []option.RequestOption{
+		option.WithAPIKey(cfg.OpenAIImageAPIKey),
+		option.WithMaxRetries(1), // the chain falls back instead of retrying
+	}
+	if cfg.OpenAIImageBaseURL != "" {
+		opts = append(opts, option.WithBaseURL(cfg.OpenAIImageBaseURL))
+	}
+	client := openai.NewClient(opts...)
+
+	return &OpenAIImageGenerator{
+		client:     &client,
+		model:      cfg.OpenAIImageModel,
+		configured: true,
+	}, nil
+}
+
+// GenerateImage generates an image, accepting either a hosted URL or base64 data in the response
+func (g *OpenAIImageGenerator) GenerateImage(ctx context.Context, opts ImageGeneratorOptions) (*GeneratedImageResult, error) {
+	if g.client == nil {
+		return nil, fmt.Errorf("OpenAI-compatible image API is not configured. Please add OPENAI_IMAGE_API_KEY or OPENAI_IMAGE_BASE_URL to your .env file")
+	}
+
+	prompt := imagePrompt(opts)
+	size := opts.Size
+	if size == "" {
+		size = "1024x1024"
+	}
+
+	params := openai.ImageGenerateParams{
+		Prompt: prompt,
+		Model:  openai.ImageModel(g.model),
+		Size:   openai.ImageGenerateParamsSize(size),
+		N:      openai.Int(1),
+	}
+	// Quality and style values differ between models, so only dall-e-3 gets them
+	if g.model == "dall-e-3" {
+		if opts.Quality == "hd" {
+			params.Quality = openai.ImageGenerateParamsQualityHD
+		}
+		if opts.Style == "natural" {
+			params.Style = openai.ImageGenerateParamsStyleNatural
+		}
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 90*time.Second)
+	defer cancel()
+
+	resp, err := g.client.Images.Generate(ctxWithTimeout, params)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI images API error: %w", err)
+	}
+	if len(resp.Data) == 0 {
+		return nil, fmt.Errorf("no images generated")
+	}
+
+	image := resp.Data[0]
+	result := &GeneratedImageResult{
+		URL:    image.URL,
+		Prompt: prompt,
+	}
+	if image.URL == "" {
+		if image.B64JSON == "" {
+			return nil, fmt.Errorf("response contained neither an image URL nor image data")
+		}
+		data, err := base64.StdEncoding.DecodeString(image.B64JSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image data: %w", err)
+		}
+		result.Data = data
+	}
+	return result, nil
+}
+
+// GetProviderName returns the name of the image generation provider
+func (g *OpenAIImageGenerator) GetProviderName() string {
+	return "OpenAI-compatible (" + g.model + ")"
+}
+
+// IsConfigured returns true if the provider is properly configured
+func (g *OpenAIImageGenerator) IsConfigured() bool {
+	return g.configured
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// PlaceholderImageGenerator implements ImageGenerator without any external service. It
+// draws a symmetric block pattern derived from the prompt, so the same word always gets
+// the same image. Use it for tests, offline development, or as the last link of a chain.
+type PlaceholderImageGenerator struct{}
+
+// NewPlaceholderImageGenerator creates a placeholder image generator
+func NewPlaceholderImageGenerator() (*PlaceholderImageGenerator, error) {
+	return &PlaceholderImageGenerator{}, nil
+}
+
+// GenerateImage renders the placeholder PNG for the prompt and size
+func (g *PlaceholderImageGenerator) GenerateImage(ctx context.Context, opts ImageGeneratorOptions) (*GeneratedImageResult, error) {
+	prompt := imagePrompt(opts)
+	width, height := parseImageSize(opts.Size)
+	sum := sha256.Sum256([]byte(prompt))
+
+	// Pastel background with a darker foreground of a related hue
+	bg := color.RGBA{R: 160 + sum[0]%96, G: 160 + sum[1]%96, B: 160 + sum[2]%96, A: 255}
+	fg := color.RGBA{R: sum[3] % 128, G: sum[4] % 128, B: sum[5] % 128, A: 255}
+
+	// A 5x5 grid mirrored around the middle column, drawn in a centred square
+	const grid = 5
+	cell := max(1, min(width, height)*3/4/grid)
+	offsetX := (width - cell*grid) / 2
+	offsetY := (height - cell*grid) / 2
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := bg
+			gx, gy := (x-offsetX)/cell, (y-offsetY)/cell
+			if x >= offsetX && y >= offsetY && gx < grid && gy < grid {
+				col := gx
+				if col > grid/2 {
+					col = grid - 1 - col
+				}
+				bit := gy*(grid/2+1) + col
+				if sum[6+bit/8]&(1<<(bit%8)) != 0 {
+					c = fg
+				}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode placeholder image: %w", err)
+	}
+
+	return &GeneratedImageResult{
+		Prompt: prompt,
+		Data:   buf.Bytes(),
+	}, nil
+}
+
+// GetProviderName returns the name of the image generation provider
+func (g *PlaceholderImageGenerator) GetProviderName() string {
+	return "Placeholder"
+}
+
+// IsConfigured always returns true; the placeholder needs no credentials
+func (g *PlaceholderImageGenerator) IsConfigured() bool {
+	return true
+}
@@ -0,0 +1,529 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+)
+
+const (
+	// placementMaxQuestions caps the length of a placement test
+	placementMaxQuestions = 15
+	// placementMinAnswersPerLevel is the number of answers needed before a level is judged
+	placementMinAnswersPerLevel = 3
+	// placementPassThreshold matches the quiz pass threshold (percentage)
+	placementPassThreshold = 70.0
+)
+
+type PlacementService interface {
+	StartTest(req *dto.StartPlacementTestRequest, userID uint) (*dto.PlacementTestResponse, error)
+	GetTest(id uint, userID uint) (*dto.PlacementTestResponse, error)
+	SubmitAnswer(id uint, req *dto.PlacementAnswerRequest, userID uint) (*dto.PlacementTestResponse, error)
+	ApplyResult(id uint, userID uint) (*dto.PlacementTestResponse, error)
+}
+
+type placementService struct {
+	placementRepo    repositories.PlacementRepository
+	languageRepo     repositories.LanguageRepository
+	journeyRepo      repositories.JourneyRepository
+	quizRepo         repositories.QuizRepository
+	userJourneyRepo  repositories.UserJourneyRepository
+	userProgressRepo repositories.UserProgressRepository
+}
+
+func NewPlacementService(
+	placementRepo repositories.PlacementRepository,
+	languageRepo repositories.LanguageRepository,
+	journeyRepo repositories.JourneyRepository,
+	quizRepo repositories.QuizRepository,
+	userJourneyRepo repositories.UserJourneyRepository,
+	userProgressRepo repositories.UserProgressRepository,
+) PlacementService {
+	return &placementService{
+		placementRepo:    placementRepo,
+		languageRepo:     languageRepo,
+		journeyRepo:      journeyRepo,
+		quizRepo:         quizRepo,
+		userJourneyRepo:  userJourneyRepo,
+		userProgressRepo: userProgressRepo,
+	}
+}
+
+// levelStats holds the running tally for one level tier
+type levelStats struct {
+	answered int
+	correct  int
+}
+
+func (l levelStats) accuracy() float64 {
+	if l.answered == 0 {
+		return 0
+	}
+	return float64(l.correct) / float64(l.answered) * 100
+}
+
+func (l levelStats) passed() bool {
+	return l.answered >= placementMinAnswersPerLevel && l.accuracy() >= placementPassThreshold
+}
+
+func (l levelStats) failed() bool {
+	return l.answered >= placementMinAnswersPerLevel && l.accuracy() < placementPassThreshold
+}
+
+// StartTest starts a new placement test and returns the first question
+func (s *placementService) StartTest(req *dto.StartPlacementTestRequest, userID uint) (*dto.PlacementTestResponse, error) {
+	language, err := s.languageRepo.GetByCode(req.LanguageCode)
+	if err != nil {
+		return nil, fmt.Errorf("language not found: %s", req.LanguageCode)
+	}
+
+	if req.JourneyID != nil {
+		journey, err := s.journeyRepo.GetByID(*req.JourneyID, false)
+		if err != nil {
+			return nil, err
+		}
+		if journey.LanguageID != language.ID {
+			return nil, fmt.Errorf("journey '%s' does not match language '%s'", journey.Name, language.Name)
+		}
+
+		isAssigned, err := s.userJourneyRepo.IsAssigned(userID, journey.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !isAssigned {
+			return nil, fmt.Errorf("you are not enrolled in this journey")
+		}
+	}
+
+	// Start in the middle tier so the test can move either way
+	test := &models.PlacementTest{
+		UserID:       userID,
+		LanguageID:   language.ID,
+		JourneyID:    req.JourneyID,
+		Status:       models.PlacementStatusInProgress,
+		CurrentLevel: models.TopicLevels[len(models.TopicLevels)/2],
+	}
+
+	// Pick the first question before saving, so a language without questions leaves no test behind
+	first := s.sampleNearest(test, tallyLevels(nil))
+	if first == nil {
+		return nil, fmt.Errorf("no quiz questions are available for placement in %s", language.Name)
+	}
+	test.PendingQuestionID = &first.ID
+
+	if err := s.placementRepo.Create(test); err != nil {
+		return nil, fmt.Errorf("failed to create placement test: %w", err)
+	}
+
+	test.Language = *language
+
+	return s.toResponse(test, first, nil), nil
+}
+
+// GetTest retrieves a placement test owned by the user
+func (s *placementService) GetTest(id uint, userID uint) (*dto.PlacementTestResponse, error) {
+	test, err := s.getOwnedTest(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var next *models.QuizQuestion
+	if test.Status == models.PlacementStatusInProgress && test.PendingQuestionID != nil {
+		next, err = s.quizRepo.GetByID(*test.PendingQuestionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.toResponse(test, next, nil), nil
+}
+
+// SubmitAnswer grades the pending question, adapts the level and returns the next question or the result
+func (s *placementService) SubmitAnswer(id uint, req *dto.PlacementAnswerRequest, userID uint) (*dto.PlacementTestResponse, error) {
+	test, err := s.getOwnedTest(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if test.Status != models.PlacementStatusInProgress {
+		return nil, fmt.Errorf("placement test is already completed")
+	}
+
+	if test.PendingQuestionID == nil || *test.PendingQuestionID != req.QuestionID {
+		return nil, fmt.Errorf("question %d is not the current placement question", req.QuestionID)
+	}
+
+	question, err := s.quizRepo.GetByID(req.QuestionID)
+	if err != nil {
+		return nil, fmt.Errorf("question not found")
+	}
+
+	isCorrect := req.Answer == question.CorrectAnswer
+	answer := models.PlacementAnswer{
+		PlacementTestID: test.ID,
+		QuestionID:      question.ID,
+		TopicID:         question.TopicID,
+		Level:           test.CurrentLevel,
+		Answer:          req.Answer,
+		IsCorrect:       isCorrect,
+	}
+	if err := s.placementRepo.CreateAnswer(&answer); err != nil {
+		return nil, fmt.Errorf("failed to save answer: %w", err)
+	}
+	test.Answers = append(test.Answers, answer)
+
+	next, err := s.advance(test, &answer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(test, next, &isCorrect), nil
+}
+
+// ApplyResult marks the journey topics below the recommended starting point as completed
+func (s *placementService) ApplyResult(id uint, userID uint) (*dto.PlacementTestResponse, error) {
+	test, err := s.getOwnedTest(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if test.Status != models.PlacementStatusCompleted {
+		return nil, fmt.Errorf("placement test must be completed before it can be applied")
+	}
+	if test.JourneyID == nil {
+		return nil, fmt.Errorf("placement test is not linked to a journey")
+	}
+
+	masteredRank := models.LevelRank(test.EstimatedLevel)
+	if masteredRank < 0 {
+		return nil, fmt.Errorf("no level was mastered, there is nothing to mark as completed")
+	}
+
+	journeyTopics, err := s.journeyRepo.GetJourneyTopics(*test.JourneyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, jt := range journeyTopics {
+		// Stop at the recommended starting topic; everything before it is skipped
+		if test.RecommendedTopicID != nil && jt.TopicID == *test.RecommendedTopicID {
+			break
+		}
+		if models.LevelRank(jt.Topic.Level) > masteredRank {
+			break
+		}
+
+		topicID := jt.TopicID
+		if err := s.userProgressRepo.Create(&models.UserProgress{
+			UserID:       userID,
+			TopicID:      &topicID,
+			JourneyID:    test.JourneyID,
+			ActivityType: "flashcard",
+			Completed:    true,
+			CompletedAt:  &now,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to save progress: %w", err)
+		}
+
+		quizCount, err := s.quizRepo.CountByTopicID(topicID)
+		if err != nil {
+			return nil, err
+		}
+		if quizCount > 0 {
+			if err := s.userProgressRepo.Create(&models.UserProgress{
+				UserID:       userID,
+				TopicID:      &topicID,
+				JourneyID:    test.JourneyID,
+				ActivityType: "quiz",
+				Completed:    true,
+				CompletedAt:  &now,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to save progress: %w", err)
+			}
+		}
+	}
+
+	if err := s.userJourneyRepo.MarkAsStarted(userID, *test.JourneyID); err != nil {
+		return nil, err
+	}
+
+	test.Status = models.PlacementStatusApplied
+	if err := s.placementRepo.Update(test); err != nil {
+		return nil, fmt.Errorf("failed to update placement test: %w", err)
+	}
+
+	return s.toResponse(test, nil, nil), nil
+}
+
+// advance decides the next level after an answer and picks the next question.
+// It finishes the test when the level has been determined or no questions remain.
+func (s *placementService) advance(test *models.PlacementTest, last *models.PlacementAnswer) (*models.QuizQuestion, error) {
+	stats := tallyLevels(test.Answers)
+
+	finished := len(test.Answers) >= placementMaxQuestions
+	if last != nil && !finished {
+		rank := models.LevelRank(test.CurrentLevel)
+		current := stats[test.CurrentLevel]
+
+		switch {
+		case current.passed():
+			if rank == len(models.TopicLevels)-1 || stats[models.TopicLevels[rank+1]].failed() {
+				finished = true
+			} else {
+				test.CurrentLevel = models.TopicLevels[rank+1]
+			}
+		case current.failed():
+			if rank == 0 || stats[models.TopicLevels[rank-1]].passed() {
+				finished = true
+			} else {
+				test.CurrentLevel = models.TopicLevels[rank-1]
+			}
+		}
+	}
+
+	var next *models.QuizQuestion
+	if !finished {
+		next = s.sampleNearest(test, stats)
+		// Out of questions at every undecided level: judge with what we have
+		finished = next == nil
+	}
+
+	if finished {
+		if err := s.finish(test, stats); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	test.PendingQuestionID = &next.ID
+	if err := s.placementRepo.Update(test); err != nil {
+		return nil, fmt.Errorf("failed to update placement test: %w", err)
+	}
+
+	return next, nil
+}
+
+// sampleNearest picks a question at the current level, falling back to the nearest
+// undecided level when the current one has run out of questions
+func (s *placementService) sampleNearest(test *models.PlacementTest, stats map[string]levelStats) *models.QuizQuestion {
+	exclude := answeredQuestionIDs(test.Answers)
+	rank := models.LevelRank(test.CurrentLevel)
+
+	candidates := []int{rank}
+	for distance := 1; distance < len(models.TopicLevels); distance++ {
+		candidates = append(candidates, rank-distance, rank+distance)
+	}
+
+	for i, r := range candidates {
+		if r < 0 || r >= len(models.TopicLevels) {
+			continue
+		}
+		level := models.TopicLevels[r]
+		if i > 0 && (stats[level].passed() || stats[level].failed()) {
+			continue
+		}
+		question, err := s.placementRepo.SampleQuestion(test.LanguageID, test.JourneyID, level, exclude)
+		if err == nil {
+			test.CurrentLevel = level
+			return question
+		}
+	}
+	return nil
+}
+
+// finish estimates the mastered level, picks a starting topic and records the activity
+func (s *placementService) finish(test *models.PlacementTest, stats map[string]levelStats) error {
+	now := time.Now()
+
+	// The estimated level is the highest tier the learner passed
+	test.EstimatedLevel = ""
+	for _, level := range models.TopicLevels {
+		if stats[level].passed() {
+			test.EstimatedLevel = level
+		}
+	}
+
+	recommended, err := s.recommendTopic(test)
+	if err != nil {
+		return err
+	}
+	if recommended != nil {
+		test.RecommendedTopicID = &recommended.ID
+		test.RecommendedTopic = recommended
+	}
+
+	test.Status = models.PlacementStatusCompleted
+	test.PendingQuestionID = nil
+	test.CompletedAt = &now
+
+	if err := s.placementRepo.Update(test); err != nil {
+		return fmt.Errorf("failed to update placement test: %w", err)
+	}
+
+	if len(test.Answers) > 0 {
+		correct := 0
+		for _, a := range test.Answers {
+			if a.IsCorrect {
+				correct++
+			}
+		}
+		score := float64(correct) / float64(len(test.Answers)) * 100.0
+		if err := s.userProgressRepo.Create(&models.UserProgress{
+			UserID:       test.UserID,
+			JourneyID:    test.JourneyID,
+			ActivityType: "placement",
+			Completed:    true,
+			Score:        &score,
+			CompletedAt:  &now,
+		}); err != nil {
+			return fmt.Errorf("failed to save progress: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// recommendTopic returns the first topic above the mastered level.
+// Within a journey this follows the journey order; otherwise the first public topic at the next level.
+func (s *placementService) recommendTopic(test *models.PlacementTest) (*models.Topic, error) {
+	masteredRank := models.LevelRank(test.EstimatedLevel)
+
+	if test.JourneyID != nil {
+		journeyTopics, err := s.journeyRepo.GetJourneyTopics(*test.JourneyID)
+		if err != nil {
+			return nil, err
+		}
+		for _, jt := range journeyTopics {
+			if models.LevelRank(jt.Topic.Level) > masteredRank {
+				topic := jt.Topic
+				return &topic, nil
+			}
+		}
+		// Everything is mastered: point at the last topic for review
+		if len(journeyTopics) > 0 {
+			topic := journeyTopics[len(journeyTopics)-1].Topic
+			return &topic, nil
+		}
+		return nil, nil
+	}
+
+	for rank := masteredRank + 1; rank < len(models.TopicLevels); rank++ {
+		topic, err := s.placementRepo.GetFirstTopicAtLevel(test.LanguageID, models.TopicLevels[rank])
+		if err == nil {
+			return topic, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *placementService) getOwnedTest(id uint, userID uint) (*models.PlacementTest, error) {
+	test, err := s.placementRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if test.UserID != userID {
+		return nil, fmt.Errorf("placement test not found")
+	}
+	return test, nil
+}
+
+// tallyLevels groups answers by level tier
+func tallyLevels(answers []models.PlacementAnswer) map[string]levelStats {
+	stats := make(map[string]levelStats)
+	for _, a := range answers {
+		l := stats[a.Level]
+		l.answered++
+		if a.IsCorrect {
+			l.correct++
+		}
+		stats[a.Level] = l
+	}
+	return stats
+}
+
+func answeredQuestionIDs(answers []models.PlacementAnswer) []uint {
+	ids := make([]uint, 0, len(answers))
+	for _, a := range answers {
+		ids = append(ids, a.QuestionID)
+	}
+	return ids
+}
+
+// toResponse converts a placement test to its response DTO
+func (s *placementService) toResponse(test *models.PlacementTest, next *models.QuizQuestion, lastCorrect *bool) *dto.PlacementTestResponse {
+	stats := tallyLevels(test.Answers)
+
+	response := &dto.PlacementTestResponse{
+		ID:                test.ID,
+		Status:            test.Status,
+		JourneyID:         test.JourneyID,
+		CurrentLevel:      test.CurrentLevel,
+		QuestionsAnswered: len(test.Answers),
+		MaxQuestions:      placementMaxQuestions,
+		LastAnswerCorrect: lastCorrect,
+		EstimatedLevel:    test.EstimatedLevel,
+		LevelResults:      make([]dto.PlacementLevelResult, 0, len(models.TopicLevels)),
+		CreatedAt:         test.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if test.Language.ID > 0 {
+		response.Language = &dto.LanguageInfo{
+			ID:         test.Language.ID,
+			Code:       test.Language.Code,
+			Name:       test.Language.Name,
+			NativeName: test.Language.NativeName,
+		}
+	}
+
+	for _, level := range models.TopicLevels {
+		l := stats[level]
+		response.CorrectAnswers += l.correct
+		response.LevelResults = append(response.LevelResults, dto.PlacementLevelResult{
+			Level:    level,
+			Answered: l.answered,
+			Correct:  l.correct,
+			Accuracy: l.accuracy(),
+		})
+	}
+
+	if next != nil {
+		response.NextQuestion = &dto.QuizQuestionForPractice{
+			ID:           next.ID,
+			QuestionType: next.QuestionType,
+			QuestionText: next.QuestionText,
+			AudioURL:     next.AudioURL,
+			ImageURL:     next.ImageURL,
+			OptionA:      next.OptionA,
+			OptionB:      next.OptionB,
+			OptionC:      next.OptionC,
+			OptionD:      next.OptionD,
+			WordID:       next.WordID,
+		}
+	}
+
+	if test.RecommendedTopic != nil && test.RecommendedTopic.ID > 0 {
+		response.RecommendedTopic = &dto.PlacementTopicInfo{
+			ID:    test.RecommendedTopic.ID,
+			Name:  test.RecommendedTopic.Name,
+			Level: test.RecommendedTopic.Level,
+		}
+	}
+
+	if test.Status == models.PlacementStatusApplied && test.JourneyID != nil {
+		completedTopicIDs, err := s.userProgressRepo.GetCompletedTopicIDs(test.UserID, *test.JourneyID)
+		if err == nil {
+			response.CompletedTopicIDs = completedTopicIDs
+		}
+	}
+
+	if test.CompletedAt != nil {
+		completedAt := test.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.CompletedAt = &completedAt
+	}
+
+	return response
+}
@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
 	"dannyswat/learnspeak/dto"
 	"dannyswat/learnspeak/models"
 	"dannyswat/learnspeak/repositories"
 	"errors"
+	"log/slog"
 	"math/rand"
 	"time"
 )
@@ -13,26 +15,27 @@ type QuizService struct {
 	quizRepo     repositories.QuizRepository
 	topicRepo    repositories.TopicRepository
 	progressRepo repositories.UserProgressRepository
+	revisions    RevisionService
 }
 
 func NewQuizService(
 	quizRepo repositories.QuizRepository,
 	topicRepo repositories.TopicRepository,
 	progressRepo repositories.UserProgressRepository,
+	revisions RevisionService,
 ) *QuizService {
 	return &QuizService{
 		quizRepo:     quizRepo,
 		topicRepo:    topicRepo,
 		progressRepo: progressRepo,
+		revisions:    revisions,
 	}
 }
 
-// CreateQuestion creates a new quiz question
-func (s *QuizService) CreateQuestion(req *dto.CreateQuizQuestionRequest) (*models.QuizQuestion, error) {
-	// Verify topic exists
-	_, err := s.topicRepo.GetByID(req.TopicID, false)
-	if err != nil {
-		return nil, errors.New("topic not found")
+// CreateQuestion creates a new quiz question on a draft topic
+func (s *QuizService) CreateQuestion(req *dto.CreateQuizQuestionRequest, userID uint) (*models.QuizQuestion, error) {
+	if err := s.checkEditable(req.TopicID); err != nil {
+		return nil, err
 	}
 
 	question := &models.QuizQuestion{
@@ -53,6 +56,7 @@ func (s *QuizService) CreateQuestion(req *dto.CreateQuizQuestionRequest) (*model
 		return nil, err
 	}
 
+	s.revisions.Record(models.RevisionEntityQuizQuestion, question.ID, models.RevisionActionCreate, userID, nil)
 	return question, nil
 }
 
@@ -66,8 +70,13 @@ func (s *QuizService) GetTopicQuestions(topicID uint) ([]models.QuizQuestion, er
 	return s.quizRepo.GetByTopicID(topicID)
 }
 
-// GetTopicQuestionsForPractice retrieves quiz questions without correct answers
-func (s *QuizService) GetTopicQuestionsForPractice(topicID uint, shuffle bool) (*dto.QuizQuestionsResponse, error) {
+// GetTopicQuestionsForPractice retrieves quiz questions without correct answers.
+// includeUnpublished allows practising topics learners can't see yet.
+func (s *QuizService) GetTopicQuestionsForPractice(topicID uint, shuffle bool, includeUnpublished bool) (*dto.QuizQuestionsResponse, error) {
+	if _, err := visibleTopic(s.topicRepo, topicID, includeUnpublished); err != nil {
+		return nil, err
+	}
+
 	questions, err := s.quizRepo.GetByTopicID(topicID)
 	if err != nil {
 		return nil, err
@@ -103,12 +112,16 @@ func (s *QuizService) GetTopicQuestionsForPractice(topicID uint, shuffle bool) (
 	}, nil
 }
 
-// UpdateQuestion updates a quiz question
-func (s *QuizService) UpdateQuestion(id uint, req *dto.UpdateQuizQuestionRequest) (*models.QuizQuestion, error) {
+// UpdateQuestion updates a quiz question on a draft topic
+func (s *QuizService) UpdateQuestion(id uint, req *dto.UpdateQuizQuestionRequest, userID uint) (*models.QuizQuestion, error) {
 	question, err := s.quizRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkEditable(question.TopicID); err != nil {
+		return nil, err
+	}
+	before := s.revisions.Snapshot(models.RevisionEntityQuizQuestion, id)
 
 	// Update fields if provided
 	if req.QuestionType != "" {
@@ -143,16 +156,37 @@ func (s *QuizService) UpdateQuestion(id uint, req *dto.UpdateQuizQuestionRequest
 		return nil, err
 	}
 
+	s.revisions.Record(models.RevisionEntityQuizQuestion, id, models.RevisionActionUpdate, userID, before)
 	return question, nil
 }
 
-// DeleteQuestion deletes a quiz question
-func (s *QuizService) DeleteQuestion(id uint) error {
-	return s.quizRepo.Delete(id)
+// DeleteQuestion deletes a quiz question from a draft topic
+func (s *QuizService) DeleteQuestion(id uint, userID uint) error {
+	question, err := s.quizRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkEditable(question.TopicID); err != nil {
+		return err
+	}
+
+	before := s.revisions.Snapshot(models.RevisionEntityQuizQuestion, id)
+	if err := s.quizRepo.Delete(id); err != nil {
+		return err
+	}
+
+	if before != nil {
+		s.revisions.Record(models.RevisionEntityQuizQuestion, id, models.RevisionActionDelete, userID, before)
+	}
+	return nil
 }
 
 // SubmitQuiz processes a quiz submission and returns results
-func (s *QuizService) SubmitQuiz(userID uint, req *dto.QuizSubmissionRequest) (*dto.QuizResultResponse, error) {
+func (s *QuizService) SubmitQuiz(ctx context.Context, userID uint, req *dto.QuizSubmissionRequest, includeUnpublished bool) (*dto.QuizResultResponse, error) {
+	if _, err := visibleTopic(s.topicRepo, req.TopicID, includeUnpublished); err != nil {
+		return nil, err
+	}
+
 	// Get all questions for the topic
 	questions, err := s.quizRepo.GetByTopicID(req.TopicID)
 	if err != nil {
@@ -221,6 +255,7 @@ func (s *QuizService) SubmitQuiz(userID uint, req *dto.QuizSubmissionRequest) (*
 	if err := s.progressRepo.Create(progress); err != nil {
 		// Log error but don't fail the request
 		// The user should still see their results
+		slog.ErrorContext(ctx, "Failed to save quiz progress", "user_id", userID, "topic_id", req.TopicID, "error", err)
 	}
 
 	return &dto.QuizResultResponse{
@@ -237,3 +272,13 @@ func (s *QuizService) SubmitQuiz(userID uint, req *dto.QuizSubmissionRequest) (*
 func (s *QuizService) ListQuestions(limit, offset int) ([]models.QuizQuestion, int64, error) {
 	return s.quizRepo.List(limit, offset)
 }
+
+// checkEditable allows question changes on draft topics only, like the topic's own fields;
+// a published topic's questions change through its draft copy
+func (s *QuizService) checkEditable(topicID uint) error {
+	topic, err := s.topicRepo.GetByID(topicID, false)
+	if err != nil {
+		return ErrTopicNotFound
+	}
+	return checkTopicEditable(topic)
+}
@@ -0,0 +1,551 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+)
+
+var (
+	ErrReviewNotFound  = errors.New("review not found")
+	ErrContentNotFound = errors.New("content not found")
+	ErrReviewForbidden = errors.New("not allowed to act on this review")
+	// ErrReviewConflict is returned when content or a review isn't in the status an action needs
+	ErrReviewConflict = errors.New("review conflict")
+	// ErrContentLocked is returned when editing a topic or journey that isn't a draft
+	ErrContentLocked = errors.New("content is locked")
+)
+
+type ReviewService interface {
+	SubmitForReview(req *dto.SubmitReviewRequest, userID uint, isAdmin bool) (*dto.ReviewResponse, error)
+	ListReviews(params *dto.ReviewFilterParams) (*dto.ReviewListResponse, error)
+	GetReview(id uint) (*dto.ReviewResponse, error)
+	AssignReviewer(id uint, reviewerID uint, userID uint, isAdmin bool) (*dto.ReviewResponse, error)
+	AddComment(id uint, body string, userID uint) (*dto.ReviewCommentResponse, error)
+	Approve(id uint, note string, userID uint, isAdmin bool) (*dto.ReviewResponse, error)
+	RequestChanges(id uint, note string, userID uint, isAdmin bool) (*dto.ReviewResponse, error)
+	Withdraw(id uint, userID uint, isAdmin bool) (*dto.ReviewResponse, error)
+	Archive(contentType string, id uint, userID uint, isAdmin bool) error
+	Unarchive(contentType string, id uint, userID uint, isAdmin bool) error
+}
+
+type reviewService struct {
+	reviewRepo  repositories.ReviewRepository
+	topicRepo   repositories.TopicRepository
+	journeyRepo repositories.JourneyRepository
+	userRepo    repositories.UserRepository
+	revisions   RevisionService
+}
+
+func NewReviewService(
+	reviewRepo repositories.ReviewRepository,
+	topicRepo repositories.TopicRepository,
+	journeyRepo repositories.JourneyRepository,
+	userRepo repositories.UserRepository,
+	revisions RevisionService,
+) ReviewService {
+	return &reviewService{
+		reviewRepo:  reviewRepo,
+		topicRepo:   topicRepo,
+		journeyRepo: journeyRepo,
+		userRepo:    userRepo,
+		revisions:   revisions,
+	}
+}
+
+// reviewContent is the part of a topic or journey the workflow looks at
+type reviewContent struct {
+	name      string
+	status    string
+	createdBy uint
+	draftOf   *uint
+}
+
+// SubmitForReview moves a draft topic or journey to in_review and opens a review
+func (s *reviewService) SubmitForReview(req *dto.SubmitReviewRequest, userID uint, isAdmin bool) (*dto.ReviewResponse, error) {
+	content, err := s.content(req.ContentType, req.ContentID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && content.createdBy != userID {
+		return nil, fmt.Errorf("%w: only the creator can submit a %s for review", ErrReviewForbidden, req.ContentType)
+	}
+	if content.status != models.ContentStatusDraft {
+		return nil, fmt.Errorf("%w: %s is %s; only drafts can be submitted", ErrReviewConflict, req.ContentType, content.status)
+	}
+	if req.ContentType == models.ReviewContentJourney {
+		if err := s.checkJourneyTopics(req.ContentID); err != nil {
+			return nil, err
+		}
+	}
+	if req.ReviewerID != nil {
+		if err := s.checkReviewer(*req.ReviewerID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	moved, err := s.setStatus(req.ContentType, req.ContentID, models.ContentStatusDraft, models.ContentStatusInReview)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return nil, fmt.Errorf("%w: %s is no longer a draft", ErrReviewConflict, req.ContentType)
+	}
+
+	review := &models.ContentReview{
+		ContentType: req.ContentType,
+		ContentID:   req.ContentID,
+		Status:      models.ReviewStatusPending,
+		Note:        req.Note,
+		SubmittedBy: userID,
+		ReviewerID:  req.ReviewerID,
+	}
+	if err := s.reviewRepo.Create(review); err != nil {
+		// Put the content back so it can be edited and submitted again
+		if _, revertErr := s.setStatus(req.ContentType, req.ContentID, models.ContentStatusInReview, models.ContentStatusDraft); revertErr != nil {
+			slog.Error("Failed to return content to draft", "content_type", req.ContentType, "content_id", req.ContentID, "error", revertErr)
+		}
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	return s.GetReview(review.ID)
+}
+
+// ListReviews retrieves reviews newest first
+func (s *reviewService) ListReviews(params *dto.ReviewFilterParams) (*dto.ReviewListResponse, error) {
+	reviews, total, err := s.reviewRepo.List(repositories.ReviewFilter{
+		ContentType: params.ContentType,
+		ContentID:   params.ContentID,
+		Status:      params.Status,
+		ReviewerID:  params.ReviewerID,
+		SubmittedBy: params.SubmittedBy,
+	}, params.Page, params.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ReviewResponse, len(reviews))
+	for i := range reviews {
+		responses[i] = *s.toReviewResponse(&reviews[i])
+	}
+
+	return &dto.ReviewListResponse{
+		Reviews:    responses,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(params.PageSize))),
+	}, nil
+}
+
+// GetReview retrieves a review with its comments
+func (s *reviewService) GetReview(id uint) (*dto.ReviewResponse, error) {
+	review, err := s.getReview(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toReviewResponse(review), nil
+}
+
+// AssignReviewer sets who reviews a pending review. The submitter and admins can assign
+// anyone; other teachers can pick up an unassigned review themselves.
+func (s *reviewService) AssignReviewer(id uint, reviewerID uint, userID uint, isAdmin bool) (*dto.ReviewResponse, error) {
+	review, err := s.getReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != models.ReviewStatusPending {
+		return nil, fmt.Errorf("%w: review is %s", ErrReviewConflict, review.Status)
+	}
+
+	selfClaim := reviewerID == userID && review.ReviewerID == nil
+	if !isAdmin && userID != review.SubmittedBy && !selfClaim {
+		return nil, fmt.Errorf("%w: only the submitter or an admin can assign the reviewer", ErrReviewForbidden)
+	}
+	if err := s.checkReviewer(reviewerID, review.SubmittedBy); err != nil {
+		return nil, err
+	}
+
+	assigned, err := s.reviewRepo.SetReviewer(id, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, fmt.Errorf("%w: review is no longer pending", ErrReviewConflict)
+	}
+
+	return s.GetReview(id)
+}
+
+// AddComment adds a comment to a review
+func (s *reviewService) AddComment(id uint, body string, userID uint) (*dto.ReviewCommentResponse, error) {
+	if _, err := s.getReview(id); err != nil {
+		return nil, err
+	}
+
+	comment := &models.ReviewComment{ReviewID: id, UserID: userID, Body: body}
+	if err := s.reviewRepo.AddComment(comment); err != nil {
+		return nil, fmt.Errorf("failed to add comment: %w", err)
+	}
+
+	review, err := s.getReview(id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range review.Comments {
+		if review.Comments[i].ID == comment.ID {
+			response := toReviewCommentResponse(&review.Comments[i])
+			return &response, nil
+		}
+	}
+	response := toReviewCommentResponse(comment)
+	return &response, nil
+}
+
+// Approve publishes the content under review. An approved draft copy of a topic is merged
+// into the published topic it copies.
+func (s *reviewService) Approve(id uint, note string, userID uint, isAdmin bool) (*dto.ReviewResponse, error) {
+	review, err := s.getReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkDecider(review, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	content, err := s.content(review.ContentType, review.ContentID)
+	if err != nil {
+		return nil, err
+	}
+	if review.ContentType == models.ReviewContentJourney {
+		if err := s.checkJourneyTopics(review.ContentID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The status change is the lock: only one decision on the content succeeds
+	moved, err := s.setStatus(review.ContentType, review.ContentID, models.ContentStatusInReview, models.ContentStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return nil, fmt.Errorf("%w: %s is no longer in review", ErrReviewConflict, review.ContentType)
+	}
+
+	if review.ContentType == models.ReviewContentTopic && content.draftOf != nil {
+		draftID, topicID := review.ContentID, *content.draftOf
+		topicBefore := s.revisions.Snapshot(models.RevisionEntityTopic, topicID)
+		draftBefore := s.revisions.Snapshot(models.RevisionEntityTopic, draftID)
+		replaced := s.questionSnapshots(topicID)
+		moved := s.questionSnapshots(draftID)
+		if err := s.topicRepo.MergeDraft(draftID, topicID); err != nil {
+			if _, revertErr := s.topicRepo.SetStatus(draftID, []string{models.ContentStatusPublished}, models.ContentStatusInReview); revertErr != nil {
+				slog.Error("Failed to return draft copy to review", "topic_id", draftID, "error", revertErr)
+			}
+			return nil, fmt.Errorf("failed to publish draft copy: %w", err)
+		}
+		s.revisions.Record(models.RevisionEntityTopic, topicID, models.RevisionActionUpdate, userID, topicBefore)
+		s.revisions.Record(models.RevisionEntityTopic, draftID, models.RevisionActionDelete, userID, draftBefore)
+		for id, before := range replaced {
+			s.revisions.Record(models.RevisionEntityQuizQuestion, id, models.RevisionActionDelete, userID, before)
+		}
+		for id, before := range moved {
+			s.revisions.Record(models.RevisionEntityQuizQuestion, id, models.RevisionActionUpdate, userID, before)
+		}
+
+		// The review now belongs to the topic learners see
+		review.ContentID = topicID
+	}
+
+	if err := s.decide(review, models.ReviewStatusApproved, note, userID); err != nil {
+		return nil, err
+	}
+	return s.GetReview(id)
+}
+
+// questionSnapshots returns the revision snapshots of a topic's quiz questions by ID. Like
+// Snapshot, a failure only leaves them out of revision history.
+func (s *reviewService) questionSnapshots(topicID uint) map[uint][]byte {
+	ids, err := s.topicRepo.GetQuizQuestionIDs(topicID)
+	if err != nil {
+		slog.Warn("Failed to snapshot quiz questions for revision history", "topic_id", topicID, "error", err)
+		return nil
+	}
+	snapshots := make(map[uint][]byte, len(ids))
+	for _, id := range ids {
+		snapshots[id] = s.revisions.Snapshot(models.RevisionEntityQuizQuestion, id)
+	}
+	return snapshots
+}
+
+// RequestChanges returns the content under review to draft with the reviewer's note
+func (s *reviewService) RequestChanges(id uint, note string, userID uint, isAdmin bool) (*dto.ReviewResponse, error) {
+	review, err := s.getReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkDecider(review, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	if note == "" {
+		return nil, fmt.Errorf("a note explaining the changes is required")
+	}
+
+	if err := s.returnToDraft(review); err != nil {
+		return nil, err
+	}
+	if err := s.decide(review, models.ReviewStatusChangesRequested, note, userID); err != nil {
+		return nil, err
+	}
+	return s.GetReview(id)
+}
+
+// Withdraw closes a pending review and returns the content to draft
+func (s *reviewService) Withdraw(id uint, userID uint, isAdmin bool) (*dto.ReviewResponse, error) {
+	review, err := s.getReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != models.ReviewStatusPending {
+		return nil, fmt.Errorf("%w: review is %s", ErrReviewConflict, review.Status)
+	}
+	if !isAdmin && userID != review.SubmittedBy {
+		return nil, fmt.Errorf("%w: only the submitter can withdraw a review", ErrReviewForbidden)
+	}
+
+	if err := s.returnToDraft(review); err != nil {
+		return nil, err
+	}
+	if err := s.decide(review, models.ReviewStatusWithdrawn, "", userID); err != nil {
+		return nil, err
+	}
+	return s.GetReview(id)
+}
+
+// Archive hides published content from learners
+func (s *reviewService) Archive(contentType string, id uint, userID uint, isAdmin bool) error {
+	content, err := s.content(contentType, id)
+	if err != nil {
+		return err
+	}
+	if !isAdmin && content.createdBy != userID {
+		return fmt.Errorf("%w: only the creator can archive a %s", ErrReviewForbidden, contentType)
+	}
+	if contentType == models.ReviewContentTopic {
+		count, err := s.topicRepo.GetPublishedJourneyCount(id)
+		if err != nil {
+			return fmt.Errorf("failed to check topic usage: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: topic is used in %d published journey(s)", ErrReviewConflict, count)
+		}
+	}
+
+	moved, err := s.setStatus(contentType, id, models.ContentStatusPublished, models.ContentStatusArchived)
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return fmt.Errorf("%w: only published content can be archived", ErrReviewConflict)
+	}
+	return nil
+}
+
+// Unarchive returns archived content to draft; it needs another review to be republished
+func (s *reviewService) Unarchive(contentType string, id uint, userID uint, isAdmin bool) error {
+	content, err := s.content(contentType, id)
+	if err != nil {
+		return err
+	}
+	if !isAdmin && content.createdBy != userID {
+		return fmt.Errorf("%w: only the creator can unarchive a %s", ErrReviewForbidden, contentType)
+	}
+
+	moved, err := s.setStatus(contentType, id, models.ContentStatusArchived, models.ContentStatusDraft)
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return fmt.Errorf("%w: %s is not archived", ErrReviewConflict, contentType)
+	}
+	return nil
+}
+
+func (s *reviewService) getReview(id uint) (*models.ContentReview, error) {
+	review, err := s.reviewRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrReviewNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+// content loads the workflow state of a topic or journey
+func (s *reviewService) content(contentType string, id uint) (*reviewContent, error) {
+	switch contentType {
+	case models.ReviewContentTopic:
+		topic, err := s.topicRepo.GetByID(id, false)
+		if err != nil {
+			if errors.Is(err, repositories.ErrTopicNotFound) {
+				return nil, fmt.Errorf("%w: topic %d", ErrContentNotFound, id)
+			}
+			return nil, err
+		}
+		return &reviewContent{name: topic.Name, status: topic.Status, createdBy: topic.CreatedBy, draftOf: topic.DraftOf}, nil
+	case models.ReviewContentJourney:
+		journey, err := s.journeyRepo.GetByID(id, false)
+		if err != nil {
+			if errors.Is(err, repositories.ErrJourneyNotFound) {
+				return nil, fmt.Errorf("%w: journey %d", ErrContentNotFound, id)
+			}
+			return nil, err
+		}
+		return &reviewContent{name: journey.Name, status: journey.Status, createdBy: journey.CreatedBy}, nil
+	}
+	return nil, fmt.Errorf("unsupported content type %q", contentType)
+}
+
+func (s *reviewService) setStatus(contentType string, id uint, from, to string) (bool, error) {
+	if contentType == models.ReviewContentJourney {
+		return s.journeyRepo.SetStatus(id, []string{from}, to)
+	}
+	return s.topicRepo.SetStatus(id, []string{from}, to)
+}
+
+// returnToDraft moves the content of a pending review back to draft
+func (s *reviewService) returnToDraft(review *models.ContentReview) error {
+	moved, err := s.setStatus(review.ContentType, review.ContentID, models.ContentStatusInReview, models.ContentStatusDraft)
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return fmt.Errorf("%w: %s is no longer in review", ErrReviewConflict, review.ContentType)
+	}
+	return nil
+}
+
+// decide records the outcome of a review whose content has already moved on
+func (s *reviewService) decide(review *models.ContentReview, status, note string, userID uint) error {
+	now := time.Now()
+	review.Status = status
+	review.DecisionNote = note
+	review.DecidedBy = &userID
+	review.DecidedAt = &now
+	if review.ReviewerID == nil && status != models.ReviewStatusWithdrawn {
+		review.ReviewerID = &userID
+	}
+
+	decided, err := s.reviewRepo.Decide(review)
+	if err != nil {
+		return fmt.Errorf("failed to record review decision: %w", err)
+	}
+	if !decided {
+		return fmt.Errorf("%w: review is no longer pending", ErrReviewConflict)
+	}
+	return nil
+}
+
+// checkDecider allows the assigned reviewer, any other teacher while unassigned, and admins
+func (s *reviewService) checkDecider(review *models.ContentReview, userID uint, isAdmin bool) error {
+	if review.Status != models.ReviewStatusPending {
+		return fmt.Errorf("%w: review is %s", ErrReviewConflict, review.Status)
+	}
+	if isAdmin {
+		return nil
+	}
+	if userID == review.SubmittedBy {
+		return fmt.Errorf("%w: you can't review your own submission", ErrReviewForbidden)
+	}
+	if review.ReviewerID != nil && *review.ReviewerID != userID {
+		return fmt.Errorf("%w: the review is assigned to another reviewer", ErrReviewForbidden)
+	}
+	return nil
+}
+
+// checkReviewer checks that a user can review a submission
+func (s *reviewService) checkReviewer(reviewerID, submitterID uint) error {
+	if reviewerID == submitterID {
+		return fmt.Errorf("the reviewer must be someone other than the submitter")
+	}
+	reviewer, err := s.userRepo.GetByID(reviewerID)
+	if err != nil {
+		return fmt.Errorf("reviewer %d not found", reviewerID)
+	}
+	for _, role := range reviewer.Roles {
+		if role.Name == "teacher" || role.Name == "admin" {
+			return nil
+		}
+	}
+	return fmt.Errorf("reviewer must be a teacher or admin")
+}
+
+// checkJourneyTopics requires a journey to have topics, all of them published, so
+// learners never reach a draft through it
+func (s *reviewService) checkJourneyTopics(journeyID uint) error {
+	journeyTopics, err := s.journeyRepo.GetJourneyTopics(journeyID)
+	if err != nil {
+		return fmt.Errorf("failed to get journey topics: %w", err)
+	}
+	if len(journeyTopics) == 0 {
+		return fmt.Errorf("%w: journey has no topics", ErrReviewConflict)
+	}
+	for _, jt := range journeyTopics {
+		if jt.Topic.Status != models.ContentStatusPublished {
+			return fmt.Errorf("%w: topic '%s' (ID:%d) is %s; publish it first", ErrReviewConflict, jt.Topic.Name, jt.TopicID, jt.Topic.Status)
+		}
+	}
+	return nil
+}
+
+func (s *reviewService) toReviewResponse(review *models.ContentReview) *dto.ReviewResponse {
+	response := &dto.ReviewResponse{
+		ID:           review.ID,
+		ContentType:  review.ContentType,
+		ContentID:    review.ContentID,
+		Status:       review.Status,
+		Note:         review.Note,
+		SubmittedBy:  toCreatorInfo(&review.Submitter),
+		Reviewer:     toCreatorInfo(review.Reviewer),
+		DecisionNote: review.DecisionNote,
+		DecidedBy:    toCreatorInfo(review.Decider),
+		CreatedAt:    review.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:    review.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if review.DecidedAt != nil {
+		decidedAt := review.DecidedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.DecidedAt = &decidedAt
+	}
+	if content, err := s.content(review.ContentType, review.ContentID); err == nil {
+		response.ContentName = content.name
+		response.DraftOf = content.draftOf
+	}
+	for i := range review.Comments {
+		response.Comments = append(response.Comments, toReviewCommentResponse(&review.Comments[i]))
+	}
+	return response
+}
+
+func toReviewCommentResponse(comment *models.ReviewComment) dto.ReviewCommentResponse {
+	return dto.ReviewCommentResponse{
+		ID:        comment.ID,
+		User:      toCreatorInfo(&comment.User),
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// toCreatorInfo summarises a user, or returns nil if the user wasn't loaded
+func toCreatorInfo(user *models.User) *dto.CreatorInfo {
+	if user == nil || user.ID == 0 {
+		return nil
+	}
+	return &dto.CreatorInfo{ID: user.ID, Name: user.Name, Email: user.Email}
+}
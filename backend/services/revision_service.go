@@ -0,0 +1,753 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrRevisionNotFound is returned for an unknown revision ID
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// ErrRestoreUnauthorized is returned when restoring content the user may not edit
+var ErrRestoreUnauthorized = errors.New("unauthorized to restore this content")
+
+// RevisionService records the history of words, topics, conversations and quiz questions
+// and restores earlier states. Content services call Snapshot before a change and Record
+// after it; recording never fails the change itself.
+type RevisionService interface {
+	Snapshot(entityType string, id uint) []byte
+	Record(entityType string, id uint, action string, userID uint, before []byte)
+	ListRevisions(params *dto.RevisionFilterParams) (*dto.RevisionListResponse, error)
+	GetRevision(id uint) (*dto.RevisionResponse, error)
+	RestoreRevision(id uint, userID uint, isAdmin bool) (*dto.RevisionResponse, error)
+}
+
+type revisionService struct {
+	revisionRepo     repositories.RevisionRepository
+	wordRepo         repositories.WordRepository
+	topicRepo        repositories.TopicRepository
+	conversationRepo repositories.ConversationRepository
+	quizRepo         repositories.QuizRepository
+}
+
+func NewRevisionService(
+	revisionRepo repositories.RevisionRepository,
+	wordRepo repositories.WordRepository,
+	topicRepo repositories.TopicRepository,
+	conversationRepo repositories.ConversationRepository,
+	quizRepo repositories.QuizRepository,
+) RevisionService {
+	return &revisionService{
+		revisionRepo:     revisionRepo,
+		wordRepo:         wordRepo,
+		topicRepo:        topicRepo,
+		conversationRepo: conversationRepo,
+		quizRepo:         quizRepo,
+	}
+}
+
+// Snapshots are the recorded state of each entity type. They hold only editable content,
+// so timestamps and preloaded relations never show up as changes.
+
+type wordSnapshot struct {
+	BaseWord     string                 `json:"baseWord"`
+	ImageURL     string                 `json:"imageUrl"`
+	Notes        string                 `json:"notes"`
+	PartOfSpeech string                 `json:"partOfSpeech"`
+	CreatedBy    uint                   `json:"createdBy"`
+	Translations []translationSnapshot  `json:"translations"`
+	Relations    []wordRelationSnapshot `json:"relations"`
+}
+
+type translationSnapshot struct {
+	ID           uint              `json:"id"`
+	LanguageID   uint              `json:"languageId"`
+	Translation  string            `json:"translation"`
+	Romanization string            `json:"romanization"`
+	AudioURL     string            `json:"audioUrl"`
+	MeasureWord  string            `json:"measureWord"`
+	Gender       string            `json:"gender"`
+	Examples     []exampleSnapshot `json:"examples"` // in order, compared as a whole
+}
+
+type exampleSnapshot struct {
+	Sentence     string `json:"sentence"`
+	Romanization string `json:"romanization"`
+	Meaning      string `json:"meaning"`
+	AudioURL     string `json:"audioUrl"`
+}
+
+type wordRelationSnapshot struct {
+	RelatedWordID uint   `json:"relatedWordId"`
+	RelationType  string `json:"relationType"`
+}
+
+type topicSnapshot struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Level       string `json:"level"`
+	LanguageID  uint   `json:"languageId"`
+	CreatedBy   uint   `json:"createdBy"`
+	IsPublic    bool   `json:"isPublic"`
+	WordIDs     []uint `json:"wordIds"` // in sequence order
+}
+
+type conversationSnapshot struct {
+	Title            string                     `json:"title"`
+	Description      string                     `json:"description"`
+	Context          string                     `json:"context"`
+	LanguageID       uint                       `json:"languageId"`
+	DifficultyLevel  string                     `json:"difficultyLevel"`
+	ScenarioAudioURL string                     `json:"scenarioAudioUrl"`
+	ScenarioImageURL string                     `json:"scenarioImageUrl"`
+	CreatedBy        uint                       `json:"createdBy"`
+	Lines            []conversationLineSnapshot `json:"lines"`
+}
+
+type conversationLineSnapshot struct {
+	ID            uint   `json:"id"`
+	SequenceOrder int    `json:"sequenceOrder"`
+	SpeakerRole   string `json:"speakerRole"`
+	EnglishText   string `json:"englishText"`
+	TargetText    string `json:"targetText"`
+	Romanization  string `json:"romanization"`
+	AudioURL      string `json:"audioUrl"`
+	ImageURL      string `json:"imageUrl"`
+	WordID        *uint  `json:"wordId"`
+	IsLearnerLine bool   `json:"isLearnerLine"`
+}
+
+type quizQuestionSnapshot struct {
+	TopicID       uint    `json:"topicId"`
+	WordID        *uint   `json:"wordId"`
+	QuestionType  string  `json:"questionType"`
+	QuestionText  string  `json:"questionText"`
+	AudioURL      *string `json:"audioUrl"`
+	ImageURL      *string `json:"imageUrl"`
+	CorrectAnswer string  `json:"correctAnswer"`
+	OptionA       string  `json:"optionA"`
+	OptionB       string  `json:"optionB"`
+	OptionC       string  `json:"optionC"`
+	OptionD       string  `json:"optionD"`
+}
+
+// Snapshot returns the current state of an entity for a later Record, or nil if it
+// could not be loaded
+func (s *revisionService) Snapshot(entityType string, id uint) []byte {
+	snapshot, err := s.capture(entityType, id)
+	if err != nil {
+		slog.Warn("Failed to snapshot content for revision history", "entity_type", entityType, "entity_id", id, "error", err)
+		return nil
+	}
+	return snapshot
+}
+
+// Record stores a revision for a change that has already been made. before is the
+// Snapshot taken ahead of the change, nil for creates.
+func (s *revisionService) Record(entityType string, id uint, action string, userID uint, before []byte) {
+	if _, err := s.record(entityType, id, action, userID, before, nil); err != nil {
+		slog.Warn("Failed to record revision", "entity_type", entityType, "entity_id", id, "action", action, "error", err)
+	}
+}
+
+// revisionLog collects the revisions of changes made inside a transaction. Snapshots are
+// read outside it, so changes are recorded once the transaction has committed.
+type revisionLog struct {
+	revisions RevisionService
+	pending   []pendingRevision
+}
+
+type pendingRevision struct {
+	entityType string
+	id         uint
+	action     string
+	before     []byte
+}
+
+func newRevisionLog(revisions RevisionService) *revisionLog {
+	return &revisionLog{revisions: revisions}
+}
+
+// snapshot returns the committed state of an entity, to pass to updated. Entities already
+// in the log need none: their revision covers every change in the transaction.
+func (l *revisionLog) snapshot(entityType string, id uint) []byte {
+	if l.has(entityType, id) {
+		return nil
+	}
+	return l.revisions.Snapshot(entityType, id)
+}
+
+func (l *revisionLog) created(entityType string, id uint) {
+	l.pending = append(l.pending, pendingRevision{entityType, id, models.RevisionActionCreate, nil})
+}
+
+func (l *revisionLog) updated(entityType string, id uint, before []byte) {
+	if !l.has(entityType, id) {
+		l.pending = append(l.pending, pendingRevision{entityType, id, models.RevisionActionUpdate, before})
+	}
+}
+
+func (l *revisionLog) has(entityType string, id uint) bool {
+	for _, p := range l.pending {
+		if p.entityType == entityType && p.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// record stores the collected revisions; call it after the transaction commits
+func (l *revisionLog) record(userID uint) {
+	for _, p := range l.pending {
+		l.revisions.Record(p.entityType, p.id, p.action, userID, p.before)
+	}
+	l.pending = nil
+}
+
+// ListRevisions retrieves revisions newest first, without snapshots
+func (s *revisionService) ListRevisions(params *dto.RevisionFilterParams) (*dto.RevisionListResponse, error) {
+	revisions, total, err := s.revisionRepo.List(repositories.RevisionFilter{
+		EntityType: params.EntityType,
+		EntityID:   params.EntityID,
+		Action:     params.Action,
+		UserID:     params.UserID,
+	}, params.Page, params.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.RevisionResponse, len(revisions))
+	for i := range revisions {
+		responses[i] = *toRevisionResponse(&revisions[i], false)
+	}
+
+	return &dto.RevisionListResponse{
+		Revisions:  responses,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(params.PageSize))),
+	}, nil
+}
+
+// GetRevision retrieves a revision with its full snapshot
+func (s *revisionService) GetRevision(id uint) (*dto.RevisionResponse, error) {
+	revision, err := s.revisionRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return toRevisionResponse(revision, true), nil
+}
+
+// RestoreRevision puts an entity back to the state recorded in a revision, recreating it
+// if it has been deleted, and records the restore as a new revision
+func (s *revisionService) RestoreRevision(id uint, userID uint, isAdmin bool) (*dto.RevisionResponse, error) {
+	revision, err := s.revisionRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	// Missing means the entity was deleted, which the restore undoes
+	before, _ := s.capture(revision.EntityType, revision.EntityID)
+
+	if err := s.apply(revision, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.record(revision.EntityType, revision.EntityID, models.RevisionActionRestore, userID, before, &revision.ID)
+	if err != nil {
+		return nil, fmt.Errorf("restored, but failed to record the restore: %w", err)
+	}
+	if restored == nil {
+		// Already in the recorded state
+		return toRevisionResponse(revision, true), nil
+	}
+	if withUser, err := s.revisionRepo.GetByID(restored.ID); err == nil {
+		restored = withUser
+	}
+	return toRevisionResponse(restored, true), nil
+}
+
+// apply writes a revision's snapshot back through the entity's repository
+func (s *revisionService) apply(revision *models.Revision, userID uint, isAdmin bool) error {
+	snapshot := []byte(revision.Snapshot)
+
+	switch revision.EntityType {
+	case models.RevisionEntityWord:
+		var snap wordSnapshot
+		if err := json.Unmarshal(snapshot, &snap); err != nil {
+			return fmt.Errorf("invalid word snapshot: %w", err)
+		}
+		word := &models.Word{
+			ID:           revision.EntityID,
+			BaseWord:     snap.BaseWord,
+			ImageURL:     snap.ImageURL,
+			Notes:        snap.Notes,
+			PartOfSpeech: snap.PartOfSpeech,
+			CreatedBy:    snap.CreatedBy,
+			Translations: make([]models.WordTranslation, len(snap.Translations)),
+			Relations:    make([]models.WordRelation, len(snap.Relations)),
+		}
+		for i, t := range snap.Translations {
+			word.Translations[i] = models.WordTranslation{
+				ID:           t.ID,
+				LanguageID:   t.LanguageID,
+				Translation:  t.Translation,
+				Romanization: t.Romanization,
+				AudioURL:     t.AudioURL,
+				MeasureWord:  t.MeasureWord,
+				Gender:       t.Gender,
+				Examples:     make([]models.WordExample, len(t.Examples)),
+			}
+			for j, e := range t.Examples {
+				word.Translations[i].Examples[j] = models.WordExample{
+					Sentence:     e.Sentence,
+					Romanization: e.Romanization,
+					Meaning:      e.Meaning,
+					AudioURL:     e.AudioURL,
+				}
+			}
+		}
+		for i, r := range snap.Relations {
+			word.Relations[i] = models.WordRelation{RelatedWordID: r.RelatedWordID, RelationType: r.RelationType}
+		}
+		// Restoring the links edits the related words on the other end, as editing does
+		var current []models.WordRelation
+		if existing, err := s.wordRepo.GetByID(revision.EntityID); err == nil {
+			current = existing.Relations
+		}
+		related, err := relatedWordChanges(s.wordRepo, s, current, word.Relations, userID, isAdmin)
+		if err != nil {
+			return err
+		}
+		if err := s.wordRepo.Restore(word); err != nil {
+			return err
+		}
+		related.record(userID)
+		return nil
+
+	case models.RevisionEntityTopic:
+		var snap topicSnapshot
+		if err := json.Unmarshal(snapshot, &snap); err != nil {
+			return fmt.Errorf("invalid topic snapshot: %w", err)
+		}
+		// Same rule as editing: a topic outside draft changes only through review
+		if current, err := s.topicRepo.GetByID(revision.EntityID, false); err == nil && current.Status != models.ContentStatusDraft {
+			return fmt.Errorf("%w: topic is %s", ErrContentLocked, current.Status)
+		}
+		topic := &models.Topic{
+			ID:          revision.EntityID,
+			Name:        snap.Name,
+			Description: snap.Description,
+			Level:       snap.Level,
+			LanguageID:  snap.LanguageID,
+			CreatedBy:   snap.CreatedBy,
+			IsPublic:    snap.IsPublic,
+			Words:       make([]models.TopicWord, len(snap.WordIDs)),
+		}
+		for i, wordID := range snap.WordIDs {
+			topic.Words[i] = models.TopicWord{WordID: wordID, SequenceOrder: i + 1}
+		}
+		return s.topicRepo.Restore(topic)
+
+	case models.RevisionEntityConversation:
+		var snap conversationSnapshot
+		if err := json.Unmarshal(snapshot, &snap); err != nil {
+			return fmt.Errorf("invalid conversation snapshot: %w", err)
+		}
+		// Same ownership rule as editing a conversation
+		if snap.CreatedBy != userID {
+			return ErrRestoreUnauthorized
+		}
+		conversation := &models.Conversation{
+			ID:               revision.EntityID,
+			Title:            snap.Title,
+			Description:      snap.Description,
+			Context:          snap.Context,
+			LanguageID:       snap.LanguageID,
+			DifficultyLevel:  snap.DifficultyLevel,
+			ScenarioAudioURL: snap.ScenarioAudioURL,
+			ScenarioImageURL: snap.ScenarioImageURL,
+			CreatedBy:        snap.CreatedBy,
+			Lines:            make([]models.ConversationLine, len(snap.Lines)),
+		}
+		for i, line := range snap.Lines {
+			conversation.Lines[i] = models.ConversationLine{
+				ID:            line.ID,
+				SequenceOrder: line.SequenceOrder,
+				SpeakerRole:   line.SpeakerRole,
+				EnglishText:   line.EnglishText,
+				TargetText:    line.TargetText,
+				Romanization:  line.Romanization,
+				AudioURL:      line.AudioURL,
+				ImageURL:      line.ImageURL,
+				WordID:        line.WordID,
+				IsLearnerLine: line.IsLearnerLine,
+			}
+		}
+		return s.conversationRepo.Restore(conversation)
+
+	case models.RevisionEntityQuizQuestion:
+		var snap quizQuestionSnapshot
+		if err := json.Unmarshal(snapshot, &snap); err != nil {
+			return fmt.Errorf("invalid quiz question snapshot: %w", err)
+		}
+		topic, err := s.topicRepo.GetByID(snap.TopicID, false)
+		if err != nil {
+			return fmt.Errorf("topic %d of this question no longer exists; restore the topic first", snap.TopicID)
+		}
+		if err := checkTopicEditable(topic); err != nil {
+			return err
+		}
+		return s.quizRepo.Restore(&models.QuizQuestion{
+			ID:            revision.EntityID,
+			TopicID:       snap.TopicID,
+			WordID:        snap.WordID,
+			QuestionType:  snap.QuestionType,
+			QuestionText:  snap.QuestionText,
+			AudioURL:      snap.AudioURL,
+			ImageURL:      snap.ImageURL,
+			CorrectAnswer: snap.CorrectAnswer,
+			OptionA:       snap.OptionA,
+			OptionB:       snap.OptionB,
+			OptionC:       snap.OptionC,
+			OptionD:       snap.OptionD,
+		})
+
+	default:
+		return fmt.Errorf("unknown entity type %q", revision.EntityType)
+	}
+}
+
+// record stores a revision and returns it, or nil when an update or restore changed nothing.
+// Content that pre-dates revision history gets a baseline of its previous state first, so
+// the first tracked change can be undone too.
+func (s *revisionService) record(entityType string, id uint, action string, userID uint, before []byte, restoredFrom *uint) (*models.Revision, error) {
+	var after []byte
+	if action != models.RevisionActionDelete {
+		var err error
+		if after, err = s.capture(entityType, id); err != nil {
+			return nil, err
+		}
+	}
+
+	latest, err := s.revisionRepo.Latest(entityType, id)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil && before != nil {
+		baseline := &models.Revision{
+			EntityType: entityType,
+			EntityID:   id,
+			Action:     models.RevisionActionBaseline,
+			Snapshot:   string(before),
+			Changes:    "[]",
+		}
+		if err := s.revisionRepo.Create(baseline); err != nil {
+			return nil, err
+		}
+	}
+	if before == nil && latest != nil && action != models.RevisionActionCreate {
+		before = []byte(latest.Snapshot)
+	}
+
+	snapshot := after
+	if action == models.RevisionActionDelete {
+		if before == nil {
+			return nil, fmt.Errorf("no state recorded before the delete")
+		}
+		snapshot = before
+	}
+
+	changes, err := diffSnapshots(before, after)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 && (action == models.RevisionActionUpdate || action == models.RevisionActionRestore) {
+		return nil, nil
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &models.Revision{
+		EntityType:   entityType,
+		EntityID:     id,
+		Action:       action,
+		Snapshot:     string(snapshot),
+		Changes:      string(changesJSON),
+		RestoredFrom: restoredFrom,
+	}
+	if userID > 0 {
+		revision.UserID = &userID
+	}
+	if err := s.revisionRepo.Create(revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// capture loads an entity and encodes its snapshot
+func (s *revisionService) capture(entityType string, id uint) ([]byte, error) {
+	var snapshot interface{}
+
+	switch entityType {
+	case models.RevisionEntityWord:
+		word, err := s.wordRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		snap := wordSnapshot{
+			BaseWord:     word.BaseWord,
+			ImageURL:     word.ImageURL,
+			Notes:        word.Notes,
+			PartOfSpeech: word.PartOfSpeech,
+			CreatedBy:    word.CreatedBy,
+			Translations: make([]translationSnapshot, len(word.Translations)),
+			Relations:    make([]wordRelationSnapshot, len(word.Relations)),
+		}
+		for i, t := range word.Translations {
+			snap.Translations[i] = translationSnapshot{
+				ID:           t.ID,
+				LanguageID:   t.LanguageID,
+				Translation:  t.Translation,
+				Romanization: t.Romanization,
+				AudioURL:     t.AudioURL,
+				MeasureWord:  t.MeasureWord,
+				Gender:       t.Gender,
+				Examples:     make([]exampleSnapshot, len(t.Examples)),
+			}
+			for j, e := range t.Examples {
+				snap.Translations[i].Examples[j] = exampleSnapshot{
+					Sentence:     e.Sentence,
+					Romanization: e.Romanization,
+					Meaning:      e.Meaning,
+					AudioURL:     e.AudioURL,
+				}
+			}
+		}
+		for i, r := range word.Relations {
+			snap.Relations[i] = wordRelationSnapshot{RelatedWordID: r.RelatedWordID, RelationType: r.RelationType}
+		}
+		snapshot = snap
+
+	case models.RevisionEntityTopic:
+		topic, err := s.topicRepo.GetByID(id, false)
+		if err != nil {
+			return nil, err
+		}
+		topicWords, err := s.topicRepo.GetTopicWords(id)
+		if err != nil {
+			return nil, err
+		}
+		snap := topicSnapshot{
+			Name:        topic.Name,
+			Description: topic.Description,
+			Level:       topic.Level,
+			LanguageID:  topic.LanguageID,
+			CreatedBy:   topic.CreatedBy,
+			IsPublic:    topic.IsPublic,
+			WordIDs:     make([]uint, len(topicWords)),
+		}
+		for i, tw := range topicWords {
+			snap.WordIDs[i] = tw.WordID
+		}
+		snapshot = snap
+
+	case models.RevisionEntityConversation:
+		conversation, err := s.conversationRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		snap := conversationSnapshot{
+			Title:            conversation.Title,
+			Description:      conversation.Description,
+			Context:          conversation.Context,
+			LanguageID:       conversation.LanguageID,
+			DifficultyLevel:  conversation.DifficultyLevel,
+			ScenarioAudioURL: conversation.ScenarioAudioURL,
+			ScenarioImageURL: conversation.ScenarioImageURL,
+			CreatedBy:        conversation.CreatedBy,
+			Lines:            make([]conversationLineSnapshot, len(conversation.Lines)),
+		}
+		for i, line := range conversation.Lines {
+			snap.Lines[i] = conversationLineSnapshot{
+				ID:            line.ID,
+				SequenceOrder: line.SequenceOrder,
+				SpeakerRole:   line.SpeakerRole,
+				EnglishText:   line.EnglishText,
+				TargetText:    line.TargetText,
+				Romanization:  line.Romanization,
+				AudioURL:      line.AudioURL,
+				ImageURL:      line.ImageURL,
+				WordID:        line.WordID,
+				IsLearnerLine: line.IsLearnerLine,
+			}
+		}
+		snapshot = snap
+
+	case models.RevisionEntityQuizQuestion:
+		question, err := s.quizRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		snapshot = quizQuestionSnapshot{
+			TopicID:       question.TopicID,
+			WordID:        question.WordID,
+			QuestionType:  question.QuestionType,
+			QuestionText:  question.QuestionText,
+			AudioURL:      question.AudioURL,
+			ImageURL:      question.ImageURL,
+			CorrectAnswer: question.CorrectAnswer,
+			OptionA:       question.OptionA,
+			OptionB:       question.OptionB,
+			OptionC:       question.OptionC,
+			OptionD:       question.OptionD,
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown entity type %q", entityType)
+	}
+
+	return json.Marshal(snapshot)
+}
+
+// diffSnapshots lists the fields that differ between two snapshots, sorted by field. A nil
+// snapshot has no fields, so creates list every field as added and deletes as removed.
+func diffSnapshots(before, after []byte) ([]dto.RevisionChange, error) {
+	oldFields, err := flattenSnapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenSnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(oldFields)+len(newFields))
+	for field := range oldFields {
+		fields = append(fields, field)
+	}
+	for field := range newFields {
+		if _, ok := oldFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []dto.RevisionChange{}
+	for _, field := range fields {
+		oldValue, newValue := oldFields[field], newFields[field]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, dto.RevisionChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	return changes, nil
+}
+
+// flattenSnapshot maps each field path of a snapshot to its value. Lists of objects with
+// an "id" (translations, conversation lines) are keyed by that ID rather than position, so
+// reordering or removing one entry doesn't show every later entry as changed.
+func flattenSnapshot(snapshot []byte) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if len(snapshot) == 0 {
+		return fields, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(snapshot, &value); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	flattenValue(fields, "", value)
+	return fields, nil
+}
+
+func flattenValue(fields map[string]interface{}, path string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flattenValue(fields, childPath, child)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return
+		}
+		if items, ok := itemsByID(v); ok {
+			for id, item := range items {
+				delete(item, "id")
+				flattenValue(fields, fmt.Sprintf("%s[%s]", path, id), item)
+			}
+			return
+		}
+		fields[path] = v
+	default:
+		fields[path] = v
+	}
+}
+
+// itemsByID returns a list's objects keyed by their "id", or false if any item lacks one
+func itemsByID(list []interface{}) (map[string]map[string]interface{}, bool) {
+	items := make(map[string]map[string]interface{}, len(list))
+	for _, entry := range list {
+		item, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		id, ok := item["id"].(float64)
+		if !ok {
+			return nil, false
+		}
+		items[strconv.FormatFloat(id, 'f', -1, 64)] = item
+	}
+	return items, true
+}
+
+// toRevisionResponse converts a revision, with its snapshot if includeSnapshot is set
+func toRevisionResponse(revision *models.Revision, includeSnapshot bool) *dto.RevisionResponse {
+	response := &dto.RevisionResponse{
+		ID:           revision.ID,
+		EntityType:   revision.EntityType,
+		EntityID:     revision.EntityID,
+		Version:      revision.Version,
+		Action:       revision.Action,
+		Changes:      []dto.RevisionChange{},
+		RestoredFrom: revision.RestoredFrom,
+		UserID:       revision.UserID,
+		CreatedAt:    revision.CreatedAt,
+	}
+	if revision.Changes != "" {
+		if err := json.Unmarshal([]byte(revision.Changes), &response.Changes); err != nil {
+			slog.Warn("Invalid changes in revision", "revision_id", revision.ID, "error", err)
+		}
+	}
+	if includeSnapshot && revision.Snapshot != "" {
+		response.Snapshot = json.RawMessage(revision.Snapshot)
+	}
+	if revision.User != nil {
+		response.UserName = revision.User.Name
+	}
+	return response
+}
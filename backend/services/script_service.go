@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/hanzi"
+)
+
+var ErrUnknownConversion = errors.New("unknown conversion. Allowed: s2t, t2s, s2hk, hk2s, t2hk, hk2t")
+
+// ScriptService converts text between Simplified, Traditional and Hong Kong Chinese
+type ScriptService interface {
+	Convert(req *dto.ConvertScriptRequest) (*dto.ConvertScriptResponse, error)
+	ConvertText(text, conversion string) (string, error)
+	Pinyin(text string) (string, error)
+}
+
+type scriptService struct {
+	source *hanzi.Source
+}
+
+func NewScriptService(source *hanzi.Source) ScriptService {
+	return &scriptService{source: source}
+}
+
+// Convert converts text, optionally spelling the result in pinyin
+func (s *scriptService) Convert(req *dto.ConvertScriptRequest) (*dto.ConvertScriptResponse, error) {
+	converted, err := s.ConvertText(req.Text, req.Conversion)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ConvertScriptResponse{
+		Text:       req.Text,
+		Conversion: req.Conversion,
+		Converted:  converted,
+	}
+	if req.Pinyin {
+		if response.Pinyin, err = s.Pinyin(converted); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// ConvertText converts text by an OpenCC conversion name such as s2hk
+func (s *scriptService) ConvertText(text, conversion string) (string, error) {
+	if !hanzi.Conversion(conversion).Valid() {
+		return "", ErrUnknownConversion
+	}
+	data, err := s.dataset()
+	if err != nil {
+		return "", err
+	}
+	converted, err := data.Convert(text, hanzi.Conversion(conversion))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCharacterDataUnavailable, err)
+	}
+	return converted, nil
+}
+
+// Pinyin spells text in pinyin, one syllable per character
+func (s *scriptService) Pinyin(text string) (string, error) {
+	data, err := s.dataset()
+	if err != nil {
+		return "", err
+	}
+	pinyin, err := data.Pinyin(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCharacterDataUnavailable, err)
+	}
+	return pinyin, nil
+}
+
+func (s *scriptService) dataset() (*hanzi.Dataset, error) {
+	data, err := s.source.Dataset()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCharacterDataUnavailable, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+)
+
+const (
+	// searchMaxHits caps the raw matches ranked per query before grouping and paging
+	searchMaxHits = 500
+	// searchMaxQueryLength limits the query length in characters
+	searchMaxQueryLength = 100
+)
+
+type SearchService interface {
+	Search(params *dto.SearchParams, includePrivate bool) (*dto.SearchResponse, error)
+}
+
+type searchService struct {
+	searchRepo   repositories.SearchRepository
+	languageRepo repositories.LanguageRepository
+}
+
+func NewSearchService(searchRepo repositories.SearchRepository, languageRepo repositories.LanguageRepository) SearchService {
+	return &searchService{
+		searchRepo:   searchRepo,
+		languageRepo: languageRepo,
+	}
+}
+
+// Search runs a ranked search across words, translations, topics and conversations.
+// Hits for the same record are merged, keeping the best score; a record counts towards,
+// and is found by, every language it matched in.
+func (s *searchService) Search(params *dto.SearchParams, includePrivate bool) (*dto.SearchResponse, error) {
+	query := strings.TrimSpace(params.Query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+	if utf8.RuneCountInString(query) > searchMaxQueryLength {
+		return nil, fmt.Errorf("search query must be at most %d characters", searchMaxQueryLength)
+	}
+
+	// Set defaults
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 20
+	}
+	if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+
+	hits, err := s.searchRepo.Search(query, includePrivate, searchMaxHits)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	languages, err := s.languageRepo.GetAllLanguages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load languages: %w", err)
+	}
+	languageByID := make(map[uint]models.Language, len(languages))
+	for _, language := range languages {
+		languageByID[language.ID] = language
+	}
+
+	// Merge hits per record; hits arrive ordered by score so the first one wins. A word can
+	// match in several languages, so the best hit of each matched language is kept too.
+	type resultKey struct {
+		typ string
+		id  uint
+	}
+	type mergedHit struct {
+		best       *repositories.SearchHit
+		byLanguage map[uint]*repositories.SearchHit
+	}
+	merged := make(map[resultKey]*mergedHit)
+	var ordered []*mergedHit
+	for i := range hits {
+		hit := &hits[i]
+		key := resultKey{hit.Type, hit.ID}
+		entry, ok := merged[key]
+		if !ok {
+			entry = &mergedHit{best: hit, byLanguage: make(map[uint]*repositories.SearchHit)}
+			merged[key] = entry
+			ordered = append(ordered, entry)
+		}
+		if hit.LanguageID > 0 {
+			if _, seen := entry.byLanguage[hit.LanguageID]; !seen {
+				entry.byLanguage[hit.LanguageID] = hit
+			}
+			if entry.best.LanguageID == 0 {
+				entry.best.LanguageID = hit.LanguageID
+			}
+		}
+	}
+
+	typeCounts := make(map[string]int)
+	levelCounts := make(map[string]int)
+	languageCounts := make(map[uint]int)
+	for _, entry := range ordered {
+		typeCounts[entry.best.Type]++
+		if entry.best.Level != "" {
+			levelCounts[entry.best.Level]++
+		}
+		for languageID := range entry.byLanguage {
+			languageCounts[languageID]++
+		}
+	}
+
+	facets := dto.SearchFacets{
+		Types:     countFacets(typeCounts),
+		Levels:    countFacets(levelCounts),
+		Languages: []dto.SearchFacet{},
+	}
+	for id, count := range languageCounts {
+		facets.Languages = append(facets.Languages, dto.SearchFacet{
+			Value: strconv.FormatUint(uint64(id), 10),
+			Label: languageByID[id].Name,
+			Count: count,
+		})
+	}
+	sortFacets(facets.Languages)
+
+	var filtered []*repositories.SearchHit
+	for _, entry := range ordered {
+		hit := entry.best
+		if params.Type != "" && hit.Type != params.Type {
+			continue
+		}
+		if params.Level != "" && hit.Level != params.Level {
+			continue
+		}
+		if params.LanguageID > 0 {
+			// Show the match in the requested language
+			languageHit, ok := entry.byLanguage[params.LanguageID]
+			if !ok {
+				continue
+			}
+			hit = languageHit
+		}
+		filtered = append(filtered, hit)
+	}
+	if params.LanguageID > 0 {
+		sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].Score > filtered[j].Score })
+	}
+
+	total := len(filtered)
+	start := (params.Page - 1) * params.PageSize
+	if start > total {
+		start = total
+	}
+	end := start + params.PageSize
+	if end > total {
+		end = total
+	}
+
+	results := make([]dto.SearchResult, 0, end-start)
+	for _, hit := range filtered[start:end] {
+		result := dto.SearchResult{
+			Type:         hit.Type,
+			ID:           hit.ID,
+			Title:        hit.Title,
+			Snippet:      truncateSnippet(hit.Snippet),
+			Level:        hit.Level,
+			MatchedField: hit.MatchedField,
+			Score:        hit.Score,
+		}
+		if language, ok := languageByID[hit.LanguageID]; ok {
+			result.Language = &dto.LanguageInfo{
+				ID:         language.ID,
+				Code:       language.Code,
+				Name:       language.Name,
+				NativeName: language.NativeName,
+			}
+		}
+		results = append(results, result)
+	}
+
+	totalPages := (total + params.PageSize - 1) / params.PageSize
+
+	return &dto.SearchResponse{
+		Query:      query,
+		Results:    results,
+		Facets:     facets,
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func countFacets(counts map[string]int) []dto.SearchFacet {
+	facets := make([]dto.SearchFacet, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, dto.SearchFacet{Value: value, Count: count})
+	}
+	sortFacets(facets)
+	return facets
+}
+
+// sortFacets orders facets by count, then value, so responses are stable
+func sortFacets(facets []dto.SearchFacet) {
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+}
+
+// truncateSnippet shortens long descriptions for display in result lists
+func truncateSnippet(s string) string {
+	const maxRunes = 200
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	return string([]rune(s)[:maxRunes]) + "…"
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dannyswat/learnspeak/config"
+)
+
+// defaultComfyUIWorkflow is a plain txt2img graph in ComfyUI's API format. Placeholders are
+// quoted strings ("{{prompt}}") replaced with JSON values before the graph is submitted.
+//
+//go:embed comfyui_workflow.json
+var defaultComfyUIWorkflow string
+
+// Stable Diffusion server APIs
+const (
+	StableDiffusionWebUI   = "webui"   // AUTOMATIC1111 / Forge: POST /sdapi/v1/txt2img
+	StableDiffusionComfyUI = "comfyui" // ComfyUI: POST /prompt, poll /history, GET /view
+)
+
+// StableDiffusionGenerator implements ImageGenerator against a self-hosted Stable Diffusion
+// WebUI or ComfyUI server
+type StableDiffusionGenerator struct {
+	baseURL    string
+	api        string
+	steps      int
+	checkpoint string
+	workflow   string
+	client     *http.Client
+}
+
+// NewStableDiffusionGenerator creates a Stable Diffusion generator from STABLE_DIFFUSION_* settings
+func NewStableDiffusionGenerator() (*StableDiffusionGenerator, error) {
+	cfg := config.AppConfig
+
+	api := strings.ToLower(cfg.StableDiffusionAPI)
+	if api != StableDiffusionWebUI && api != StableDiffusionComfyUI {
+		return nil, fmt.Errorf("unknown STABLE_DIFFUSION_API %q: expected %s or %s", cfg.StableDiffusionAPI, StableDiffusionWebUI, StableDiffusionComfyUI)
+	}
+
+	workflow := defaultComfyUIWorkflow
+	if cfg.ComfyUIWorkflowFile != "" {
+		data, err := os.ReadFile(cfg.ComfyUIWorkflowFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ComfyUI workflow: %w", err)
+		}
+		workflow = string(data)
+	}
+
+	return &StableDiffusionGenerator{
+		baseURL:    strings.TrimRight(cfg.StableDiffusionURL, "/"),
+		api:        api,
+		steps:      cfg.StableDiffusionSteps,
+		checkpoint: cfg.ComfyUICheckpoint,
+		workflow:   workflow,
+		client:     &http.Client{Timeout: cfg.StableDiffusionTimeout},
+	}, nil
+}
+
+// GenerateImage generates an image on the configured server and returns its bytes
+func (g *StableDiffusionGenerator) GenerateImage(ctx context.Context, opts ImageGeneratorOptions) (*GeneratedImageResult, error) {
+	if g.baseURL == "" {
+		return nil, fmt.Errorf("Stable Diffusion is not configured. Please add STABLE_DIFFUSION_URL to your .env file")
+	}
+
+	prompt := imagePrompt(opts)
+	width, height := parseImageSize(opts.Size)
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, g.client.Timeout)
+	defer cancel()
+
+	var data []byte
+	var err error
+	if g.api == StableDiffusionComfyUI {
+		data, err = g.generateComfyUI(ctxWithTimeout, prompt, width, height)
+	} else {
+		data, err = g.generateWebUI(ctxWithTimeout, prompt, width, height)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeneratedImageResult{
+		Prompt: prompt,
+		Data:   data,
+	}, nil
+}
+
+func (g *StableDiffusionGenerator) generateWebUI(ctx context.Context, prompt string, width, height int) ([]byte, error) {
+	var resp struct {
+		Images []string `json:"images"`
+	}
+	err := g.doJSON(ctx, http.MethodPost, "/sdapi/v1/txt2img", map[string]interface{}{
+		"prompt":          prompt,
+		"negative_prompt": imageNegativePrompt,
+		"width":           width,
+		"height":          height,
+		"steps":           g.steps,
+		"seed":            promptSeed(prompt),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Images) == 0 {
+		return nil, fmt.Errorf("no images generated")
+	}
+
+	encoded := resp.Images[0]
+	if i := strings.Index(encoded, ","); strings.HasPrefix(encoded, "data:") && i >= 0 {
+		encoded = encoded[i+1:]
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image data: %w", err)
+	}
+	return data, nil
+}
+
+type comfyUIImage struct {
+	Filename  string `json:"filename"`
+	Subfolder string `json:"subfolder"`
+	Type      string `json:"type"`
+}
+
+func (g *StableDiffusionGenerator) generateComfyUI(ctx context.Context, prompt string, width, height int) ([]byte, error) {
+	workflow, err := g.renderWorkflow(prompt, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var queued struct {
+		PromptID string `json:"prompt_id"`
+	}
+	if err := g.doJSON(ctx, http.MethodPost, "/prompt", map[string]json.RawMessage{"prompt": workflow}, &queued); err != nil {
+		return nil, err
+	}
+	if queued.PromptID == "" {
+		return nil, fmt.Errorf("ComfyUI did not return a prompt ID")
+	}
+
+	// The history entry appears once the graph has finished executing
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		var history map[string]struct {
+			Outputs map[string]struct {
+				Images []comfyUIImage `json:"images"`
+			} `json:"outputs"`
+		}
+		if err := g.doJSON(ctx, http.MethodGet, "/history/"+url.PathEscape(queued.PromptID), nil, &history); err != nil {
+			return nil, err
+		}
+		if entry, ok := history[queued.PromptID]; ok {
+			for _, output := range entry.Outputs {
+				if len(output.Images) > 0 {
+					return g.fetchComfyUIImage(ctx, output.Images[0])
+				}
+			}
+			return nil, fmt.Errorf("ComfyUI workflow produced no images")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for ComfyUI: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// renderWorkflow substitutes the placeholders in the workflow template
+func (g *StableDiffusionGenerator) renderWorkflow(prompt string, width, height int) (json.RawMessage, error) {
+	quote := func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+	rendered := strings.NewReplacer(
+		`"{{prompt}}"`, quote(prompt),
+		`"{{negative_prompt}}"`, quote(imageNegativePrompt),
+		`"{{checkpoint}}"`, quote(g.checkpoint),
+		`"{{width}}"`, strconv.Itoa(width),
+		`"{{height}}"`, strconv.Itoa(height),
+		`"{{steps}}"`, strconv.Itoa(g.steps),
+		`"{{seed}}"`, strconv.FormatInt(promptSeed(prompt), 10),
+	).Replace(g.workflow)
+
+	if !json.Valid([]byte(rendered)) {
+		return nil, fmt.Errorf("ComfyUI workflow is not valid JSON")
+	}
+	return json.RawMessage(rendered), nil
+}
+
+func (g *StableDiffusionGenerator) fetchComfyUIImage(ctx context.Context, image comfyUIImage) ([]byte, error) {
+	query := url.Values{}
+	query.Set("filename", image.Filename)
+	query.Set("subfolder", image.Subfolder)
+	query.Set("type", image.Type)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/view?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ComfyUI error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ComfyUI error: status %d fetching image", resp.StatusCode)
+	}
+	return data, nil
+}
+
+// doJSON sends body as JSON (if non-nil) and decodes the JSON response into out
+func (g *StableDiffusionGenerator) doJSON(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Stable Diffusion error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Stable Diffusion error: status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// GetProviderName returns the name of the image generation provider
+func (g *StableDiffusionGenerator) GetProviderName() string {
+	if g.api == StableDiffusionComfyUI {
+		return "Stable Diffusion (ComfyUI)"
+	}
+	return "Stable Diffusion (WebUI)"
+}
+
+// IsConfigured returns true if the provider is properly configured
+func (g *StableDiffusionGenerator) IsConfigured() bool {
+	return g.baseURL != ""
+}
+
+// promptSeed derives a stable seed so regenerating a prompt gives the same image
+func promptSeed(prompt string) int64 {
+	sum := sha256.Sum256([]byte(prompt))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"dannyswat/learnspeak/repositories"
+)
+
+// TopicAudioOptions controls which audio RegenerateTopicAudio produces
+type TopicAudioOptions struct {
+	// Force re-synthesizes audio that already exists instead of only filling in missing audio
+	Force bool
+	// IncludeConversations also regenerates the lines of conversations linked to the topic
+	IncludeConversations bool
+}
+
+// TopicAudioResult summarises a regeneration run
+type TopicAudioResult struct {
+	Generated int
+	Skipped   int
+	Failed    int
+	Errors    []string
+}
+
+// TopicAudioService regenerates TTS audio for the content of a topic
+type TopicAudioService struct {
+	topicRepo        repositories.TopicRepository
+	wordRepo         repositories.WordRepository
+	conversationRepo repositories.ConversationRepository
+	ttsService       *TTSService
+}
+
+// NewTopicAudioService creates a new topic audio service
+func NewTopicAudioService(
+	topicRepo repositories.TopicRepository,
+	wordRepo repositories.WordRepository,
+	conversationRepo repositories.ConversationRepository,
+	ttsService *TTSService,
+) *TopicAudioService {
+	return &TopicAudioService{
+		topicRepo:        topicRepo,
+		wordRepo:         wordRepo,
+		conversationRepo: conversationRepo,
+		ttsService:       ttsService,
+	}
+}
+
+// RegenerateTopicAudio generates audio for every word translation in the topic's language.
+// Failures are collected per item so one bad entry doesn't stop the run.
+func (s *TopicAudioService) RegenerateTopicAudio(ctx context.Context, topicID uint, opts TopicAudioOptions) (*TopicAudioResult, error) {
+	topic, err := s.topicRepo.GetByID(topicID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TopicAudioResult{}
+	languageCode := topic.Language.Code
+
+	for _, topicWord := range topic.Words {
+		for i := range topicWord.Word.Translations {
+			translation := &topicWord.Word.Translations[i]
+			if translation.LanguageID != topic.LanguageID {
+				continue
+			}
+			if translation.AudioURL != "" && !opts.Force {
+				result.Skipped++
+				continue
+			}
+
+			audioURL, err := s.generate(ctx, translation.Translation, languageCode, opts.Force)
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("word %q: %v", topicWord.Word.BaseWord, err))
+				continue
+			}
+
+			translation.AudioURL = audioURL
+			if err := s.wordRepo.UpdateTranslation(translation); err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("word %q: failed to save audio: %v", topicWord.Word.BaseWord, err))
+				continue
+			}
+			result.Generated++
+		}
+	}
+
+	if !opts.IncludeConversations {
+		return result, nil
+	}
+
+	conversations, err := s.conversationRepo.GetByTopicID(topicID)
+	if err != nil {
+		return result, fmt.Errorf("failed to load conversations: %w", err)
+	}
+
+	for _, conversation := range conversations {
+		for i := range conversation.Lines {
+			line := &conversation.Lines[i]
+			if line.AudioURL != "" && !opts.Force {
+				result.Skipped++
+				continue
+			}
+
+			audioURL, err := s.generate(ctx, line.TargetText, conversation.Language.Code, opts.Force)
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("conversation %q line %d: %v", conversation.Title, line.SequenceOrder, err))
+				continue
+			}
+
+			line.AudioURL = audioURL
+			if err := s.conversationRepo.UpdateLine(line); err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("conversation %q line %d: failed to save audio: %v", conversation.Title, line.SequenceOrder, err))
+				continue
+			}
+			result.Generated++
+		}
+	}
+
+	return result, nil
+}
+
+// generate returns the audio URL for text. With force, a cached file is discarded and synthesized again.
+func (s *TopicAudioService) generate(ctx context.Context, text, languageCode string, force bool) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("text is empty")
+	}
+
+	req := &TTSRequest{Text: text, Language: languageCode}
+	generate := s.ttsService.GenerateAudio
+	if force {
+		generate = s.ttsService.RegenerateAudio
+	}
+	resp, err := generate(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.AudioURL, nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"math"
 
@@ -9,6 +10,9 @@ import (
 	"dannyswat/learnspeak/repositories"
 )
 
+// ErrTopicNotFound is returned for a topic that doesn't exist or that the user may not see
+var ErrTopicNotFound = errors.New("topic not found")
+
 type TopicService interface {
 	CreateTopic(req *dto.CreateTopicRequest, userID uint) (*dto.TopicResponse, error)
 	GetTopic(id uint, includeWords bool) (*dto.TopicResponse, error)
@@ -17,17 +21,20 @@ type TopicService interface {
 	ListTopics(params *dto.TopicFilterParams) (*dto.TopicListResponse, error)
 	ReorderWords(topicID uint, wordIDs []uint, userID uint) error
 	AddWordsToTopic(topicID uint, wordIDs []uint, userID uint) error
+	CreateDraft(topicID uint, userID uint) (*dto.TopicResponse, bool, error)
 }
 
 type topicService struct {
 	topicRepo    repositories.TopicRepository
 	languageRepo repositories.LanguageRepository
+	revisions    RevisionService
 }
 
-func NewTopicService(topicRepo repositories.TopicRepository, languageRepo repositories.LanguageRepository) TopicService {
+func NewTopicService(topicRepo repositories.TopicRepository, languageRepo repositories.LanguageRepository, revisions RevisionService) TopicService {
 	return &topicService{
 		topicRepo:    topicRepo,
 		languageRepo: languageRepo,
+		revisions:    revisions,
 	}
 }
 
@@ -47,6 +54,7 @@ func (s *topicService) CreateTopic(req *dto.CreateTopicRequest, userID uint) (*d
 		LanguageID:  language.ID,
 		CreatedBy:   userID,
 		IsPublic:    req.IsPublic,
+		Status:      models.ContentStatusDraft,
 	}
 
 	// Create topic
@@ -61,6 +69,8 @@ func (s *topicService) CreateTopic(req *dto.CreateTopicRequest, userID uint) (*d
 		}
 	}
 
+	s.revisions.Record(models.RevisionEntityTopic, topic.ID, models.RevisionActionCreate, userID, nil)
+
 	// Fetch the created topic with relations
 	createdTopic, err := s.topicRepo.GetByID(topic.ID, false)
 	if err != nil {
@@ -87,6 +97,10 @@ func (s *topicService) UpdateTopic(id uint, req *dto.UpdateTopicRequest, userID
 	if err != nil {
 		return nil, err
 	}
+	if err := checkTopicEditable(topic); err != nil {
+		return nil, err
+	}
+	before := s.revisions.Snapshot(models.RevisionEntityTopic, id)
 
 	// Update topic fields
 	if req.Name != nil {
@@ -171,6 +185,8 @@ func (s *topicService) UpdateTopic(id uint, req *dto.UpdateTopicRequest, userID
 		}
 	}
 
+	s.revisions.Record(models.RevisionEntityTopic, id, models.RevisionActionUpdate, userID, before)
+
 	// Fetch updated topic
 	updatedTopic, err := s.topicRepo.GetByID(id, false)
 	if err != nil {
@@ -183,10 +199,20 @@ func (s *topicService) UpdateTopic(id uint, req *dto.UpdateTopicRequest, userID
 // DeleteTopic deletes a topic
 func (s *topicService) DeleteTopic(id uint, userID uint) error {
 	// Fetch existing topic to check ownership
-	_, err := s.topicRepo.GetByID(id, false)
+	topic, err := s.topicRepo.GetByID(id, false)
 	if err != nil {
 		return err
 	}
+	if topic.Status == models.ContentStatusPublished || topic.Status == models.ContentStatusInReview {
+		return fmt.Errorf("%w: topic is %s; archive it before deleting", ErrContentLocked, topic.Status)
+	}
+	draft, err := s.topicRepo.GetDraftCopy(id)
+	if err != nil {
+		return fmt.Errorf("failed to check draft copies: %w", err)
+	}
+	if draft != nil {
+		return fmt.Errorf("%w: topic has a draft copy (ID:%d); delete it first", ErrContentLocked, draft.ID)
+	}
 
 	// Check if topic is used in journeys
 	usageCount, err := s.topicRepo.GetJourneyUsageCount(id)
@@ -198,7 +224,13 @@ func (s *topicService) DeleteTopic(id uint, userID uint) error {
 		return fmt.Errorf("topic is used in %d journey(s) and cannot be deleted", usageCount)
 	}
 
-	return s.topicRepo.Delete(id)
+	before := s.revisions.Snapshot(models.RevisionEntityTopic, id)
+	if err := s.topicRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.revisions.Record(models.RevisionEntityTopic, id, models.RevisionActionDelete, userID, before)
+	return nil
 }
 
 // ListTopics retrieves topics with filtering and pagination
@@ -220,6 +252,7 @@ func (s *topicService) ListTopics(params *dto.TopicFilterParams) (*dto.TopicList
 		params.LanguageCode,
 		params.CreatedBy,
 		params.IsPublic,
+		params.Status,
 		params.Page,
 		params.PageSize,
 		params.IncludeWords,
@@ -252,24 +285,125 @@ func (s *topicService) ListTopics(params *dto.TopicFilterParams) (*dto.TopicList
 // ReorderWords reorders words in a topic
 func (s *topicService) ReorderWords(topicID uint, wordIDs []uint, userID uint) error {
 	// Fetch topic to check ownership
-	_, err := s.topicRepo.GetByID(topicID, false)
+	topic, err := s.topicRepo.GetByID(topicID, false)
 	if err != nil {
 		return err
 	}
+	if err := checkTopicEditable(topic); err != nil {
+		return err
+	}
+	before := s.revisions.Snapshot(models.RevisionEntityTopic, topicID)
+
+	if err := s.topicRepo.ReorderWords(topicID, wordIDs); err != nil {
+		return err
+	}
 
-	return s.topicRepo.ReorderWords(topicID, wordIDs)
+	s.revisions.Record(models.RevisionEntityTopic, topicID, models.RevisionActionUpdate, userID, before)
+	return nil
 }
 
 // AddWordsToTopic adds words to an existing topic
 func (s *topicService) AddWordsToTopic(topicID uint, wordIDs []uint, userID uint) error {
 	// Fetch topic to check ownership
-	_, err := s.topicRepo.GetByID(topicID, false)
+	topic, err := s.topicRepo.GetByID(topicID, false)
 	if err != nil {
 		return err
 	}
+	if err := checkTopicEditable(topic); err != nil {
+		return err
+	}
+	before := s.revisions.Snapshot(models.RevisionEntityTopic, topicID)
 
 	// Add words to topic
-	return s.topicRepo.AddWords(topicID, wordIDs)
+	if err := s.topicRepo.AddWords(topicID, wordIDs); err != nil {
+		return err
+	}
+
+	s.revisions.Record(models.RevisionEntityTopic, topicID, models.RevisionActionUpdate, userID, before)
+	return nil
+}
+
+// CreateDraft returns an editable copy of a published topic, with its words, quiz questions
+// and conversations, creating it if the topic has none. Learners keep seeing the published
+// topic until the copy is approved.
+func (s *topicService) CreateDraft(topicID uint, userID uint) (*dto.TopicResponse, bool, error) {
+	topic, err := s.topicRepo.GetByID(topicID, false)
+	if err != nil {
+		return nil, false, err
+	}
+	if topic.Status != models.ContentStatusPublished {
+		return nil, false, fmt.Errorf("%w: topic is %s; only published topics need a draft copy", ErrContentLocked, topic.Status)
+	}
+
+	existing, err := s.topicRepo.GetDraftCopy(topicID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check draft copies: %w", err)
+	}
+	if existing != nil {
+		response, err := s.toTopicResponse(existing, false)
+		return response, false, err
+	}
+
+	draft := &models.Topic{
+		Name:        topic.Name,
+		Description: topic.Description,
+		Level:       topic.Level,
+		LanguageID:  topic.LanguageID,
+		CreatedBy:   userID,
+		IsPublic:    topic.IsPublic,
+		Status:      models.ContentStatusDraft,
+		DraftOf:     &topic.ID,
+	}
+	questionIDs, err := s.topicRepo.CreateDraftCopy(draft)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create draft copy: %w", err)
+	}
+
+	s.revisions.Record(models.RevisionEntityTopic, draft.ID, models.RevisionActionCreate, userID, nil)
+	for _, id := range questionIDs {
+		s.revisions.Record(models.RevisionEntityQuizQuestion, id, models.RevisionActionCreate, userID, nil)
+	}
+
+	createdDraft, err := s.topicRepo.GetByID(draft.ID, false)
+	if err != nil {
+		return nil, false, err
+	}
+	response, err := s.toTopicResponse(createdDraft, false)
+	return response, true, err
+}
+
+// TopicVisible reports whether a topic may be shown to a user. Learners only see published
+// topics; includeUnpublished (teachers and admins) also shows drafts, topics under review
+// and archived ones.
+func TopicVisible(topic *models.Topic, includeUnpublished bool) bool {
+	return topic.Status == models.ContentStatusPublished || includeUnpublished
+}
+
+// visibleTopic loads a topic the user may see, reporting hidden topics as not found
+func visibleTopic(topicRepo repositories.TopicRepository, id uint, includeUnpublished bool) (*models.Topic, error) {
+	topic, err := topicRepo.GetByID(id, false)
+	if errors.Is(err, repositories.ErrTopicNotFound) {
+		return nil, ErrTopicNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !TopicVisible(topic, includeUnpublished) {
+		return nil, ErrTopicNotFound
+	}
+	return topic, nil
+}
+
+// checkTopicEditable allows changes to drafts only; a published topic is edited
+// through a draft copy so learners aren't disrupted
+func checkTopicEditable(topic *models.Topic) error {
+	switch topic.Status {
+	case models.ContentStatusDraft:
+		return nil
+	case models.ContentStatusPublished:
+		return fmt.Errorf("%w: topic is published; create a draft copy to edit it", ErrContentLocked)
+	}
+	return fmt.Errorf("%w: topic is %s", ErrContentLocked, topic.Status)
 }
 
 // toTopicResponse converts a topic model to response DTO
@@ -286,6 +420,8 @@ func (s *topicService) toTopicResponse(topic *models.Topic, includeWords bool) (
 		Description:       topic.Description,
 		Level:             topic.Level,
 		IsPublic:          topic.IsPublic,
+		Status:            topic.Status,
+		DraftOf:           topic.DraftOf,
 		WordCount:         int(wordCount),
 		QuizCount:         int(quizCount),
 		ConversationCount: int(conversationCount),
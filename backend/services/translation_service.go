@@ -2,18 +2,25 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/metrics"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
 )
 
 // TranslationService handles text translation using Azure Translator
@@ -22,6 +29,8 @@ type TranslationService struct {
 	client       *http.Client
 	cacheDir     string
 	cacheEnabled bool
+	usage        *AIUsageService
+	languageRepo repositories.LanguageRepository
 }
 
 // TranslateRequest represents a translation request
@@ -30,6 +39,7 @@ type TranslateRequest struct {
 	FromLang   string `json:"fromLang"`   // e.g., "en"
 	ToLang     string `json:"toLang"`     // e.g., "zh-Hant" for Traditional Chinese
 	Suggestion bool   `json:"suggestion"` // Get alternative translations
+	Convert    string `json:"convert"`    // Optional Chinese script conversion of the result, e.g. "t2hk"
 }
 
 // BatchTranslateRequest represents a batch translation request
@@ -37,6 +47,7 @@ type BatchTranslateRequest struct {
 	Texts    []string `json:"texts" validate:"required,min=1"`
 	FromLang string   `json:"fromLang"`
 	ToLang   string   `json:"toLang"`
+	Convert  string   `json:"convert"`
 }
 
 // TranslationResult represents a single translation result
@@ -79,12 +90,13 @@ type azureDictionaryResponse struct {
 	} `json:"translations"`
 }
 
-// NewTranslationService creates a new translation service
-func NewTranslationService(cfg *config.Config) *TranslationService {
+// NewTranslationService creates a new translation service. usage may be nil to skip accounting.
+// languageRepo maps language codes to the codes Azure Translator uses.
+func NewTranslationService(cfg *config.Config, usage *AIUsageService, languageRepo repositories.LanguageRepository) *TranslationService {
 	// Create cache directory if it doesn't exist
 	cacheDir := filepath.Join(cfg.UploadDir, "translation-cache")
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		fmt.Printf("Warning: Could not create translation cache directory: %v\n", err)
+		slog.Warn("Could not create translation cache directory", "dir", cacheDir, "error", err)
 	}
 
 	return &TranslationService{
@@ -92,11 +104,13 @@ func NewTranslationService(cfg *config.Config) *TranslationService {
 		client:       &http.Client{},
 		cacheDir:     cacheDir,
 		cacheEnabled: cfg.TranslatorCacheEnabled,
+		usage:        usage,
+		languageRepo: languageRepo,
 	}
 }
 
 // Translate translates a single text
-func (s *TranslationService) Translate(req *TranslateRequest) (*TranslationResult, error) {
+func (s *TranslationService) Translate(ctx context.Context, req *TranslateRequest) (*TranslationResult, error) {
 	if req.Text == "" {
 		return nil, errors.New("text is required")
 	}
@@ -114,23 +128,35 @@ func (s *TranslationService) Translate(req *TranslateRequest) (*TranslationResul
 		req.ToLang = "zh-Hant"
 	}
 
+	from, to := s.translatorCode(req.FromLang), s.translatorCode(req.ToLang)
+
 	// Generate cache key
-	cacheKey := s.generateCacheKey(req.Text, req.FromLang, req.ToLang)
+	cacheKey := s.generateCacheKey(req.Text, from, to)
 	cacheFile := filepath.Join(s.cacheDir, cacheKey+".json")
 
 	// Check cache if enabled
 	if s.cacheEnabled {
-		if result, err := s.loadFromCache(cacheFile); err == nil {
+		result, err := s.loadFromCache(cacheFile)
+		metrics.ObserveCacheLookup(metrics.CacheTranslation, err == nil)
+		if err == nil {
 			result.Cached = true
+			s.recordUsage(ctx, models.AIOperationTranslate, req.Text, true)
 			return result, nil
 		}
 	}
 
+	if err := s.usage.CheckBudget(ctx); err != nil {
+		return nil, err
+	}
+
 	// Call Azure Translator API
-	translation, detectedLang, err := s.callAzureTranslator(req.Text, req.FromLang, req.ToLang)
+	start := time.Now()
+	translation, detectedLang, err := s.callAzureTranslator(req.Text, from, to)
+	metrics.ObserveProviderCall("azure-translator", "translate", start, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to translate: %w", err)
 	}
+	s.recordUsage(ctx, models.AIOperationTranslate, req.Text, false)
 
 	result := &TranslationResult{
 		Text:             req.Text,
@@ -141,20 +167,55 @@ func (s *TranslationService) Translate(req *TranslateRequest) (*TranslationResul
 
 	// Get alternatives if requested
 	if req.Suggestion {
-		alternatives, _ := s.getAlternativeTranslations(req.Text, req.FromLang, req.ToLang)
+		start := time.Now()
+		alternatives, err := s.getAlternativeTranslations(req.Text, from, to)
+		metrics.ObserveProviderCall("azure-translator", "dictionary", start, err)
+		if err != nil {
+			slog.WarnContext(ctx, "Dictionary lookup failed", "from", from, "to", to, "error", err)
+		} else {
+			s.recordUsage(ctx, models.AIOperationDictionary, req.Text, false)
+		}
 		result.Alternatives = alternatives
 	}
 
 	// Save to cache
 	if s.cacheEnabled {
-		s.saveToCache(cacheFile, result)
+		if err := s.saveToCache(cacheFile, result); err != nil {
+			slog.Warn("Failed to cache translation", "error", err)
+		}
 	}
 
 	return result, nil
 }
 
+// translatorCode maps a language code to the code Azure Translator knows it by, as set on
+// the language. Codes that aren't a language's, such as zh-Hant, are passed through.
+func (s *TranslationService) translatorCode(code string) string {
+	if s.languageRepo != nil {
+		if language, err := s.languageRepo.GetByCode(code); err == nil {
+			return translatorCode(language)
+		}
+	}
+	return code
+}
+
+// recordUsage records a translator call in the usage ledger; Azure bills per source character
+func (s *TranslationService) recordUsage(ctx context.Context, operation, text string, cacheHit bool) {
+	s.usage.Record(ctx, AIUsageEvent{
+		Provider:   models.AIProviderAzureTranslator,
+		Operation:  operation,
+		Characters: utf8.RuneCountInString(text),
+		CacheHit:   cacheHit,
+	})
+}
+
+// IsConfigured returns true if Azure Translator credentials are set
+func (s *TranslationService) IsConfigured() bool {
+	return s.config.AzureTranslatorKey != ""
+}
+
 // BatchTranslate translates multiple texts
-func (s *TranslationService) BatchTranslate(req *BatchTranslateRequest) (*BatchTranslationResult, error) {
+func (s *TranslationService) BatchTranslate(ctx context.Context, req *BatchTranslateRequest) (*BatchTranslationResult, error) {
 	if len(req.Texts) == 0 {
 		return nil, errors.New("at least one text is required")
 	}
@@ -187,7 +248,12 @@ func (s *TranslationService) BatchTranslate(req *BatchTranslateRequest) (*BatchT
 			ToLang:   req.ToLang,
 		}
 
-		result, err := s.Translate(translateReq)
+		result, err := s.Translate(ctx, translateReq)
+		var budgetErr *BudgetExceededError
+		if errors.As(err, &budgetErr) {
+			// The rest of the batch would fail the same way
+			return nil, err
+		}
 		if err != nil {
 			// On error, return partial translation with error message
 			result = &TranslationResult{
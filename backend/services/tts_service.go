@@ -1,25 +1,36 @@
 package services
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/audio"
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/common"
 	"github.com/Microsoft/cognitive-services-speech-sdk-go/speech"
 
 	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/metrics"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
 )
 
 // TTSService handles text-to-speech generation using Azure Cognitive Services
 type TTSService struct {
-	config      *config.Config
-	cacheDir    string
-	audioFormat string
+	config       *config.Config
+	cacheDir     string
+	audioFormat  string
+	usage        *AIUsageService
+	assetRepo    repositories.AssetRepository
+	languageRepo repositories.LanguageRepository
 }
 
 // TTSRequest represents a text-to-speech generation request
@@ -36,23 +47,38 @@ type TTSResponse struct {
 	Duration int    `json:"duration"` // in milliseconds
 }
 
-// NewTTSService creates a new TTS service instance
-func NewTTSService(cfg *config.Config) *TTSService {
+// NewTTSService creates a new TTS service instance. usage may be nil to skip accounting.
+// assetRepo is used to keep cached audio that content refers to from being deleted, and
+// languageRepo to find each language's default voice.
+func NewTTSService(cfg *config.Config, usage *AIUsageService, assetRepo repositories.AssetRepository, languageRepo repositories.LanguageRepository) *TTSService {
 	// Create cache directory if it doesn't exist
 	cacheDir := filepath.Join(cfg.UploadDir, "tts-cache")
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		fmt.Printf("Warning: Could not create TTS cache directory: %v\n", err)
+		slog.Warn("Could not create TTS cache directory", "dir", cacheDir, "error", err)
 	}
 
 	return &TTSService{
-		config:      cfg,
-		cacheDir:    cacheDir,
-		audioFormat: "audio-16khz-32kbitrate-mono-mp3", // High-quality MP3 format
+		config:       cfg,
+		cacheDir:     cacheDir,
+		audioFormat:  "audio-16khz-32kbitrate-mono-mp3", // High-quality MP3 format
+		usage:        usage,
+		assetRepo:    assetRepo,
+		languageRepo: languageRepo,
 	}
 }
 
 // GenerateAudio generates speech audio from text using Azure TTS
-func (s *TTSService) GenerateAudio(req *TTSRequest) (*TTSResponse, error) {
+func (s *TTSService) GenerateAudio(ctx context.Context, req *TTSRequest) (*TTSResponse, error) {
+	return s.generateAudio(ctx, req, s.config.TTSCacheEnabled)
+}
+
+// RegenerateAudio synthesizes the audio again even when it is cached, overwriting the cached
+// file so content that refers to it picks up the new audio
+func (s *TTSService) RegenerateAudio(ctx context.Context, req *TTSRequest) (*TTSResponse, error) {
+	return s.generateAudio(ctx, req, false)
+}
+
+func (s *TTSService) generateAudio(ctx context.Context, req *TTSRequest, useCache bool) (*TTSResponse, error) {
 	if req.Text == "" {
 		return nil, errors.New("text is required")
 	}
@@ -74,9 +100,12 @@ func (s *TTSService) GenerateAudio(req *TTSRequest) (*TTSResponse, error) {
 	audioPath := filepath.Join(s.cacheDir, audioFilename)
 
 	// Check cache if enabled
-	if s.config.TTSCacheEnabled {
-		if _, err := os.Stat(audioPath); err == nil {
+	if useCache {
+		_, err := os.Stat(audioPath)
+		metrics.ObserveCacheLookup(metrics.CacheTTS, err == nil)
+		if err == nil {
 			// Cache hit - return cached audio
+			s.recordUsage(ctx, req.Text, true)
 			audioURL := fmt.Sprintf("/uploads/tts-cache/%s", audioFilename)
 			return &TTSResponse{
 				AudioURL: audioURL,
@@ -85,11 +114,18 @@ func (s *TTSService) GenerateAudio(req *TTSRequest) (*TTSResponse, error) {
 		}
 	}
 
+	if err := s.usage.CheckBudget(ctx); err != nil {
+		return nil, err
+	}
+
 	// Generate new audio using Azure TTS
+	start := time.Now()
 	duration, err := s.synthesizeSpeech(req.Text, voice, audioPath)
+	metrics.ObserveProviderCall("azure-tts", "synthesize", start, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to synthesize speech: %w", err)
 	}
+	s.recordUsage(ctx, req.Text, false)
 
 	// Return audio URL
 	audioURL := fmt.Sprintf("/uploads/tts-cache/%s", audioFilename)
@@ -100,6 +136,21 @@ func (s *TTSService) GenerateAudio(req *TTSRequest) (*TTSResponse, error) {
 	}, nil
 }
 
+// recordUsage records a synthesis in the usage ledger; Azure bills per character
+func (s *TTSService) recordUsage(ctx context.Context, text string, cacheHit bool) {
+	s.usage.Record(ctx, AIUsageEvent{
+		Provider:   models.AIProviderAzureTTS,
+		Operation:  models.AIOperationSynthesize,
+		Characters: utf8.RuneCountInString(text),
+		CacheHit:   cacheHit,
+	})
+}
+
+// IsConfigured returns true if Azure TTS credentials are set
+func (s *TTSService) IsConfigured() bool {
+	return s.config.AzureTTSKey != ""
+}
+
 // synthesizeSpeech uses Azure Cognitive Services Speech SDK to generate audio
 func (s *TTSService) synthesizeSpeech(text, voice, outputPath string) (int, error) {
 	// Create speech config
@@ -151,31 +202,23 @@ func (s *TTSService) synthesizeSpeech(text, voice, outputPath string) (int, erro
 	return 0, fmt.Errorf("synthesis failed with reason: %v", outcome.Result.Reason)
 }
 
-// getDefaultVoice returns the default voice for a given language
+// getDefaultVoice returns the default voice set on a language, trying the base language of
+// a regional code such as en-US too, and falls back to the configured voice
 func (s *TTSService) getDefaultVoice(language string) string {
-	// Default to configured voice
-	if language == "" {
+	if language == "" || s.languageRepo == nil {
 		return s.config.AzureTTSVoice
 	}
 
-	// Map languages to Azure neural voices
-	voiceMap := map[string]string{
-		"zh-HK": "zh-HK-HiuMaanNeural",  // Cantonese (Hong Kong) - Female
-		"zh-CN": "zh-CN-XiaoxiaoNeural", // Mandarin (Simplified) - Female
-		"en":    "en-US-JennyNeural",    // English (US) - Female
-		"en-US": "en-US-JennyNeural",
-		"es":    "es-ES-ElviraNeural", // Spanish - Female
-		"fr":    "fr-FR-DeniseNeural", // French - Female
-		"ja":    "ja-JP-NanamiNeural", // Japanese - Female
-		"ko":    "ko-KR-SunHiNeural",  // Korean - Female
-		"vi":    "vi-VN-HoaiMyNeural", // Vietnamese - Female
+	codes := []string{language}
+	if base, _, ok := strings.Cut(language, "-"); ok {
+		codes = append(codes, base)
 	}
-
-	if voice, ok := voiceMap[language]; ok {
-		return voice
+	for _, code := range codes {
+		if l, err := s.languageRepo.GetByCode(code); err == nil && l.DefaultVoice != "" {
+			return l.DefaultVoice
+		}
 	}
 
-	// Fallback to configured default
 	return s.config.AzureTTSVoice
 }
 
@@ -186,11 +229,23 @@ func (s *TTSService) generateCacheKey(text, language, voice string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// DeleteCachedAudio deletes a specific cached audio file by URL
+// DeleteCachedAudio deletes a specific cached audio file by URL. Identical text shares one
+// cached file, so ErrAssetInUse is returned while any content still refers to it; the cache
+// prune removes it once it is unused.
 func (s *TTSService) DeleteCachedAudio(audioURL string) error {
 	// Extract filename from URL (e.g., "/uploads/tts-cache/abc123.mp3" -> "abc123.mp3")
 	filename := filepath.Base(audioURL)
 
+	if s.assetRepo != nil {
+		used, err := s.assetRepo.IsReferenced("/uploads/tts-cache/" + filename)
+		if err != nil {
+			return fmt.Errorf("failed to check references: %w", err)
+		}
+		if used {
+			return ErrAssetInUse
+		}
+	}
+
 	// Construct full path
 	audioPath := filepath.Join(s.cacheDir, filename)
 
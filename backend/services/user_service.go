@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"slices"
 
 	"dannyswat/learnspeak/dto"
 	"dannyswat/learnspeak/models"
@@ -15,9 +17,10 @@ type UserService interface {
 	GetLearners(params *dto.UserFilterParams) (*dto.UserListResponse, error)
 	GetTeachers(params *dto.UserFilterParams) (*dto.UserListResponse, error)
 	SearchUsers(params *dto.UserFilterParams) (*dto.UserListResponse, error)
-	UpdateUser(id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
-	DeleteUser(id uint) error
-	CreateUser(req *dto.CreateUserRequest) (*dto.UserResponse, error)
+	UpdateUser(ctx context.Context, id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
+	DeleteUser(ctx context.Context, id uint) error
+	CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*dto.UserResponse, error)
+	ResetPassword(ctx context.Context, id uint, newPassword string) error
 	GetTeacherStatistics(teacherID uint) (*dto.TeacherStatisticsResponse, error)
 }
 
@@ -26,6 +29,7 @@ type userService struct {
 	topicRepo        repositories.TopicRepository
 	userProgressRepo repositories.UserProgressRepository
 	userJourneyRepo  repositories.UserJourneyRepository
+	audit            *AuditService
 }
 
 func NewUserService(
@@ -33,12 +37,14 @@ func NewUserService(
 	topicRepo repositories.TopicRepository,
 	userProgressRepo repositories.UserProgressRepository,
 	userJourneyRepo repositories.UserJourneyRepository,
+	audit *AuditService,
 ) UserService {
 	return &userService{
 		userRepo:         userRepo,
 		topicRepo:        topicRepo,
 		userProgressRepo: userProgressRepo,
 		userJourneyRepo:  userJourneyRepo,
+		audit:            audit,
 	}
 }
 
@@ -134,30 +140,89 @@ func (s *userService) searchWithRole(query string, role *string, page, pageSize
 	return s.buildUserListResponse(users, total, page, pageSize), nil
 }
 
-// UpdateUser updates a user's information
-func (s *userService) UpdateUser(id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
+// UpdateUser updates a user's information and, when req.Roles is set, replaces their roles
+func (s *userService) UpdateUser(ctx context.Context, id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
 	user, err := s.userRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update fields if provided
-	if req.Name != nil {
+	// Update fields if provided, noting what changed for the audit log
+	changed := make(map[string]interface{})
+	if req.Name != nil && *req.Name != user.Name {
+		changed["name"] = map[string]string{"old": user.Name, "new": *req.Name}
 		user.Name = *req.Name
 	}
-	if req.Email != nil {
+	if req.Email != nil && *req.Email != user.Email {
+		changed["email"] = map[string]string{"old": user.Email, "new": *req.Email}
 		user.Email = *req.Email
 	}
 	if req.ProfilePicURL != nil {
+		if user.ProfilePicURL == nil || *user.ProfilePicURL != *req.ProfilePicURL {
+			changed["profilePicUrl"] = true
+		}
 		user.ProfilePicURL = req.ProfilePicURL
 	}
 
-	// Save updates
+	var roles []models.Role
+	if len(req.Roles) > 0 {
+		roles = make([]models.Role, 0, len(req.Roles))
+		for _, roleName := range req.Roles {
+			if slices.ContainsFunc(roles, func(r models.Role) bool { return r.Name == roleName }) {
+				continue
+			}
+			role, err := s.userRepo.GetRoleByName(roleName)
+			if err != nil {
+				return nil, fmt.Errorf("role '%s' not found: %w", roleName, err)
+			}
+			roles = append(roles, *role)
+		}
+	}
+
+	// Save updates; roles are written separately so Save doesn't touch user_roles
+	oldRoles := roleNames(user.Roles)
+	user.Roles = nil
 	if err := s.userRepo.Update(user); err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
+	if len(changed) > 0 {
+		s.audit.Record(ctx, AuditEntry{
+			Action:     models.AuditActionUserUpdate,
+			TargetType: models.AuditTargetUser,
+			TargetID:   user.ID,
+			Details:    map[string]interface{}{"username": user.Username, "changes": changed},
+		})
+	}
+
+	newRoles := oldRoles
+	if roles != nil {
+		newRoles = roleNames(roles)
+		if !slices.Equal(oldRoles, newRoles) {
+			if err := s.userRepo.ReplaceRoles(user, roles); err != nil {
+				return nil, fmt.Errorf("failed to update roles: %w", err)
+			}
+			s.audit.Record(ctx, AuditEntry{
+				Action:     models.AuditActionUserRolesChange,
+				TargetType: models.AuditTargetUser,
+				TargetID:   user.ID,
+				Details:    map[string]interface{}{"username": user.Username, "oldRoles": oldRoles, "newRoles": newRoles},
+			})
+		}
+	}
 
-	return s.toUserResponse(user), nil
+	response := s.toUserResponse(user)
+	response.Roles = newRoles
+	return response, nil
+}
+
+// roleNames lists the names of roles, sorted
+func roleNames(roles []models.Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	slices.Sort(names)
+	return names
 }
 
 // toUserResponse converts a user model to response DTO
@@ -197,7 +262,7 @@ func (s *userService) buildUserListResponse(users []models.User, total int64, pa
 }
 
 // DeleteUser soft deletes a user by ID
-func (s *userService) DeleteUser(id uint) error {
+func (s *userService) DeleteUser(ctx context.Context, id uint) error {
 	user, err := s.userRepo.GetByID(id)
 	if err != nil {
 		return fmt.Errorf("user not found: %w", err)
@@ -207,11 +272,18 @@ func (s *userService) DeleteUser(id uint) error {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
+	s.audit.Record(ctx, AuditEntry{
+		Action:     models.AuditActionUserDelete,
+		TargetType: models.AuditTargetUser,
+		TargetID:   user.ID,
+		Details:    map[string]interface{}{"username": user.Username, "email": user.Email, "roles": roleNames(user.Roles)},
+	})
+
 	return nil
 }
 
 // CreateUser creates a new user with specified roles (admin only)
-func (s *userService) CreateUser(req *dto.CreateUserRequest) (*dto.UserResponse, error) {
+func (s *userService) CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*dto.UserResponse, error) {
 	// Check if username already exists
 	existingUser, err := s.userRepo.GetByUsername(req.Username)
 	if err == nil && existingUser != nil {
@@ -260,9 +332,46 @@ func (s *userService) CreateUser(req *dto.CreateUserRequest) (*dto.UserResponse,
 		return nil, fmt.Errorf("failed to retrieve created user: %w", err)
 	}
 
+	s.audit.Record(ctx, AuditEntry{
+		Action:     models.AuditActionUserCreate,
+		TargetType: models.AuditTargetUser,
+		TargetID:   createdUser.ID,
+		Details:    map[string]interface{}{"username": createdUser.Username, "email": createdUser.Email, "roles": roleNames(createdUser.Roles)},
+	})
+
 	return s.toUserResponse(createdUser), nil
 }
 
+// ResetPassword sets a new password without checking the current one (admin only)
+func (s *userService) ResetPassword(ctx context.Context, id uint, newPassword string) error {
+	if len(newPassword) < 6 {
+		return fmt.Errorf("password must be at least 6 characters")
+	}
+
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.PasswordHash = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditEntry{
+		Action:     models.AuditActionUserPasswordReset,
+		TargetType: models.AuditTargetUser,
+		TargetID:   user.ID,
+		Details:    map[string]interface{}{"username": user.Username},
+	})
+	return nil
+}
+
 // GetTeacherStatistics gets dashboard statistics for a teacher
 func (s *userService) GetTeacherStatistics(teacherID uint) (*dto.TeacherStatisticsResponse, error) {
 	stats := &dto.TeacherStatisticsResponse{}
@@ -275,7 +384,7 @@ func (s *userService) GetTeacherStatistics(teacherID uint) (*dto.TeacherStatisti
 	stats.TotalStudents = totalStudents
 
 	// 2. Count total topics created by this teacher
-	_, totalTopics, err := s.topicRepo.List("", "", "", teacherID, nil, 1, 1, false)
+	_, totalTopics, err := s.topicRepo.List("", "", "", teacherID, nil, "", 1, 1, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total topics: %w", err)
 	}
@@ -0,0 +1,624 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/models"
+	"dannyswat/learnspeak/repositories"
+	"dannyswat/learnspeak/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	WordImportFormatCSV  = "csv"
+	WordImportFormatXLSX = "xlsx"
+
+	wordImportActionCreated   = "created"
+	wordImportActionUpdated   = "updated"
+	wordImportActionUnchanged = "unchanged"
+	wordImportActionSkipped   = "skipped"
+
+	// wordExportPageSize is the batch size used when reading words for export
+	wordExportPageSize = 500
+)
+
+// utf8BOM is written at the start of CSV exports so spreadsheet apps detect UTF-8
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// errWordImportDryRun rolls back the import transaction after a dry run
+var errWordImportDryRun = errors.New("dry run")
+
+// WordImportService handles bulk import and export of words and translations as CSV or XLSX
+type WordImportService struct {
+	db        *gorm.DB
+	revisions RevisionService
+}
+
+// NewWordImportService creates a new word import service
+func NewWordImportService(db *gorm.DB, revisions RevisionService) *WordImportService {
+	return &WordImportService{
+		db:        db,
+		revisions: revisions,
+	}
+}
+
+// wordImportColumns holds the resolved column indexes for a mapping; -1 means unmapped
+type wordImportColumns struct {
+	id           int
+	baseWord     int
+	notes        int
+	imageURL     int
+	translations []wordImportTranslationColumns
+}
+
+type wordImportTranslationColumns struct {
+	language     models.Language
+	translation  int
+	romanization int
+}
+
+// wordImportRow is a parsed and validated spreadsheet row
+type wordImportRow struct {
+	row          int
+	id           uint
+	baseWord     string
+	notes        string
+	imageURL     string
+	translations []wordImportTranslation
+}
+
+type wordImportTranslation struct {
+	languageID   uint
+	translation  string
+	romanization string
+}
+
+// ImportWords creates or updates words from a CSV or XLSX file. Rows are matched to existing
+// words by the ID column when mapped, otherwise by base word (case-insensitive), so importing
+// the same file twice leaves the data unchanged. Invalid rows are skipped and reported.
+// In a dry run all changes are rolled back and only the result is returned.
+func (s *WordImportService) ImportWords(r io.ReaderAt, size int64, filename string, mapping *dto.WordImportMapping, userID uint, dryRun bool) (*dto.WordImportResult, error) {
+	rows, err := readWordSheet(r, size, filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	languages, err := repositories.NewLanguageRepository(s.db).GetAllLanguages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load languages: %w", err)
+	}
+
+	header := rows[0]
+	if mapping == nil {
+		mapping = defaultWordImportMapping(header, languages)
+	}
+	columns, err := resolveWordImportColumns(header, mapping, languages)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.WordImportResult{
+		DryRun: dryRun,
+		Rows:   []dto.WordImportRowResult{},
+	}
+
+	var parsed []wordImportRow
+	for i, values := range rows[1:] {
+		rowNum := i + 2
+		if isBlankRow(values) {
+			continue
+		}
+		result.TotalRows++
+
+		row, rowErrors := parseWordImportRow(rowNum, values, header, columns)
+		if len(rowErrors) > 0 {
+			result.Errors = append(result.Errors, rowErrors...)
+			result.Skipped++
+			result.Rows = append(result.Rows, dto.WordImportRowResult{
+				Row:      rowNum,
+				BaseWord: row.baseWord,
+				Action:   wordImportActionSkipped,
+			})
+			continue
+		}
+		parsed = append(parsed, row)
+	}
+
+	history := newRevisionLog(s.revisions)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		wordRepo := repositories.NewWordRepository(tx)
+		for _, row := range parsed {
+			rowResult, rowErr, err := upsertImportedWord(wordRepo, history, row, userID)
+			if err != nil {
+				return fmt.Errorf("row %d: %w", row.row, err)
+			}
+			if rowErr != nil {
+				result.Errors = append(result.Errors, *rowErr)
+			}
+
+			switch rowResult.Action {
+			case wordImportActionCreated:
+				result.Created++
+				if dryRun {
+					rowResult.WordID = 0
+				}
+			case wordImportActionUpdated:
+				result.Updated++
+			case wordImportActionUnchanged:
+				result.Unchanged++
+			case wordImportActionSkipped:
+				result.Skipped++
+			}
+			result.Rows = append(result.Rows, rowResult)
+		}
+
+		if dryRun {
+			return errWordImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errWordImportDryRun) {
+		return nil, err
+	}
+	if !dryRun {
+		history.record(userID)
+	}
+
+	sort.SliceStable(result.Rows, func(i, j int) bool {
+		return result.Rows[i].Row < result.Rows[j].Row
+	})
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Row < result.Errors[j].Row
+	})
+
+	return result, nil
+}
+
+// upsertImportedWord creates or updates the word for a row and logs its revision. A row-level
+// problem is returned as a row error; database failures are returned as err and abort the import.
+func upsertImportedWord(wordRepo repositories.WordRepository, history *revisionLog, row wordImportRow, userID uint) (dto.WordImportRowResult, *dto.WordImportRowError, error) {
+	rowResult := dto.WordImportRowResult{
+		Row:      row.row,
+		BaseWord: row.baseWord,
+		Action:   wordImportActionSkipped,
+	}
+
+	var existing *models.Word
+	if row.id > 0 {
+		word, err := wordRepo.GetByID(row.id)
+		if err != nil {
+			return rowResult, &dto.WordImportRowError{Row: row.row, Message: fmt.Sprintf("word %d not found", row.id)}, nil
+		}
+		existing = word
+	} else {
+		word, err := wordRepo.FindByBaseWord(row.baseWord)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return rowResult, nil, err
+		}
+		existing = word
+	}
+
+	if existing == nil {
+		if len(row.translations) == 0 {
+			return rowResult, &dto.WordImportRowError{Row: row.row, Message: "at least one translation is required for new words"}, nil
+		}
+
+		word := &models.Word{
+			BaseWord:  row.baseWord,
+			Notes:     row.notes,
+			ImageURL:  row.imageURL,
+			CreatedBy: userID,
+		}
+		if err := wordRepo.Create(word); err != nil {
+			return rowResult, nil, err
+		}
+		for _, t := range row.translations {
+			translation := &models.WordTranslation{
+				WordID:       word.ID,
+				LanguageID:   t.languageID,
+				Translation:  t.translation,
+				Romanization: t.romanization,
+			}
+			if err := wordRepo.CreateTranslation(translation); err != nil {
+				return rowResult, nil, err
+			}
+		}
+
+		history.created(models.RevisionEntityWord, word.ID)
+		rowResult.WordID = word.ID
+		rowResult.Action = wordImportActionCreated
+		return rowResult, nil, nil
+	}
+
+	rowResult.WordID = existing.ID
+	changed := false
+	before := history.snapshot(models.RevisionEntityWord, existing.ID)
+
+	// Empty cells never clear existing values
+	updates := &models.Word{ID: existing.ID}
+	wordChanged := false
+	// Rows matched by base word keep the stored spelling; rows matched by ID may rename
+	if row.id > 0 && row.baseWord != existing.BaseWord {
+		updates.BaseWord = row.baseWord
+		wordChanged = true
+	}
+	if row.notes != "" && row.notes != existing.Notes {
+		updates.Notes = row.notes
+		wordChanged = true
+	}
+	if row.imageURL != "" && row.imageURL != existing.ImageURL {
+		updates.ImageURL = row.imageURL
+		wordChanged = true
+	}
+	if wordChanged {
+		if err := wordRepo.Update(updates); err != nil {
+			return rowResult, nil, err
+		}
+		changed = true
+	}
+
+	for _, t := range row.translations {
+		var current *models.WordTranslation
+		for i := range existing.Translations {
+			if existing.Translations[i].LanguageID == t.languageID {
+				current = &existing.Translations[i]
+				break
+			}
+		}
+
+		if current == nil {
+			translation := &models.WordTranslation{
+				WordID:       existing.ID,
+				LanguageID:   t.languageID,
+				Translation:  t.translation,
+				Romanization: t.romanization,
+			}
+			if err := wordRepo.CreateTranslation(translation); err != nil {
+				return rowResult, nil, err
+			}
+			changed = true
+			continue
+		}
+
+		romanization := current.Romanization
+		if t.romanization != "" {
+			romanization = t.romanization
+		}
+		if t.translation == current.Translation && romanization == current.Romanization {
+			continue
+		}
+
+		current.Translation = t.translation
+		current.Romanization = romanization
+		if err := wordRepo.UpdateTranslation(current); err != nil {
+			return rowResult, nil, err
+		}
+		changed = true
+	}
+
+	if changed {
+		history.updated(models.RevisionEntityWord, existing.ID, before)
+		rowResult.Action = wordImportActionUpdated
+	} else {
+		rowResult.Action = wordImportActionUnchanged
+	}
+	return rowResult, nil, nil
+}
+
+// ExportWords writes the words matching the filter as CSV or XLSX, using the same
+// column layout that ImportWords accepts without a mapping
+func (s *WordImportService) ExportWords(params *dto.WordExportParams, w io.Writer) error {
+	format := strings.ToLower(params.Format)
+	if format == "" {
+		format = WordImportFormatCSV
+	}
+	if format != WordImportFormatCSV && format != WordImportFormatXLSX {
+		return fmt.Errorf("unsupported format: %s", params.Format)
+	}
+
+	wordRepo := repositories.NewWordRepository(s.db)
+	languageRepo := repositories.NewLanguageRepository(s.db)
+
+	var words []models.Word
+	for page := 1; ; page++ {
+		batch, total, err := wordRepo.List(params.Search, params.LanguageID, params.CreatedBy, page, wordExportPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list words: %w", err)
+		}
+		words = append(words, batch...)
+		if len(batch) == 0 || int64(len(words)) >= total {
+			break
+		}
+	}
+
+	var languages []models.Language
+	if params.Languages != "" {
+		for _, code := range strings.Split(params.Languages, ",") {
+			code = strings.TrimSpace(code)
+			if code == "" {
+				continue
+			}
+			language, err := languageRepo.GetByCode(code)
+			if err != nil {
+				return fmt.Errorf("language not found: %s", code)
+			}
+			languages = append(languages, *language)
+		}
+	} else {
+		used := make(map[uint]models.Language)
+		for _, word := range words {
+			for _, t := range word.Translations {
+				used[t.LanguageID] = t.Language
+			}
+		}
+		for _, language := range used {
+			languages = append(languages, language)
+		}
+		sort.Slice(languages, func(i, j int) bool {
+			return languages[i].ID < languages[j].ID
+		})
+	}
+
+	header := []string{"id", "baseWord", "notes", "imageUrl"}
+	for _, language := range languages {
+		header = append(header, language.Code+" translation", language.Code+" romanization")
+	}
+
+	rows := [][]string{header}
+	for _, word := range words {
+		row := []string{strconv.FormatUint(uint64(word.ID), 10), word.BaseWord, word.Notes, word.ImageURL}
+		for _, language := range languages {
+			translation, romanization := "", ""
+			for _, t := range word.Translations {
+				if t.LanguageID == language.ID {
+					translation, romanization = t.Translation, t.Romanization
+					break
+				}
+			}
+			row = append(row, translation, romanization)
+		}
+		rows = append(rows, row)
+	}
+
+	if format == WordImportFormatXLSX {
+		return utils.WriteXLSX(w, "Words", rows)
+	}
+
+	if _, err := w.Write(utf8BOM); err != nil {
+		return err
+	}
+	// Words are user-entered, so every cell is escaped against formula injection
+	for _, row := range rows {
+		for i := range row {
+			row[i] = utils.CSVCell(row[i])
+		}
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// readWordSheet reads all rows from a CSV or XLSX file, chosen by file extension
+func readWordSheet(r io.ReaderAt, size int64, filename string) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx":
+		return utils.ReadXLSX(r, size)
+	case ".csv":
+		data, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		cr := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+		cr.FieldsPerRecord = -1
+		rows, err := cr.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv file: %w", err)
+		}
+		for _, row := range rows {
+			for i := range row {
+				row[i] = utils.CSVValue(row[i])
+			}
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unsupported file type: only .csv and .xlsx files are allowed")
+	}
+}
+
+// defaultWordImportMapping maps the column headers written by ExportWords
+func defaultWordImportMapping(header []string, languages []models.Language) *dto.WordImportMapping {
+	mapping := &dto.WordImportMapping{}
+	for _, h := range header {
+		name := strings.TrimSpace(h)
+		switch strings.ToLower(name) {
+		case "id":
+			mapping.ID = name
+		case "baseword", "base word":
+			mapping.BaseWord = name
+		case "notes":
+			mapping.Notes = name
+		case "imageurl", "image url":
+			mapping.ImageURL = name
+		}
+	}
+
+	for _, language := range languages {
+		tm := dto.WordImportTranslationMapping{LanguageCode: language.Code}
+		for _, h := range header {
+			name := strings.TrimSpace(h)
+			switch strings.ToLower(name) {
+			case strings.ToLower(language.Code + " translation"):
+				tm.Translation = name
+			case strings.ToLower(language.Code + " romanization"):
+				tm.Romanization = name
+			}
+		}
+		if tm.Translation != "" {
+			mapping.Translations = append(mapping.Translations, tm)
+		}
+	}
+
+	return mapping
+}
+
+// resolveWordImportColumns turns a header-name mapping into column indexes
+func resolveWordImportColumns(header []string, mapping *dto.WordImportMapping, languages []models.Language) (*wordImportColumns, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		key := strings.ToLower(strings.TrimSpace(h))
+		if _, exists := index[key]; !exists {
+			index[key] = i
+		}
+	}
+
+	lookup := func(field, name string, required bool) (int, error) {
+		if name == "" {
+			if required {
+				return -1, fmt.Errorf("no column mapped to %s", field)
+			}
+			return -1, nil
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("column %q mapped to %s not found in header", name, field)
+		}
+		return i, nil
+	}
+
+	var err error
+	columns := &wordImportColumns{}
+	if columns.id, err = lookup("id", mapping.ID, false); err != nil {
+		return nil, err
+	}
+	if columns.baseWord, err = lookup("baseWord", mapping.BaseWord, true); err != nil {
+		return nil, err
+	}
+	if columns.notes, err = lookup("notes", mapping.Notes, false); err != nil {
+		return nil, err
+	}
+	if columns.imageURL, err = lookup("imageUrl", mapping.ImageURL, false); err != nil {
+		return nil, err
+	}
+
+	byCode := make(map[string]models.Language, len(languages))
+	for _, language := range languages {
+		byCode[strings.ToLower(language.Code)] = language
+	}
+
+	seen := make(map[uint]bool)
+	for _, tm := range mapping.Translations {
+		language, ok := byCode[strings.ToLower(strings.TrimSpace(tm.LanguageCode))]
+		if !ok {
+			return nil, fmt.Errorf("language not found: %s", tm.LanguageCode)
+		}
+		if seen[language.ID] {
+			return nil, fmt.Errorf("language %s is mapped more than once", language.Code)
+		}
+		seen[language.ID] = true
+
+		tc := wordImportTranslationColumns{language: language}
+		if tc.translation, err = lookup(language.Code+" translation", tm.Translation, true); err != nil {
+			return nil, err
+		}
+		if tc.romanization, err = lookup(language.Code+" romanization", tm.Romanization, false); err != nil {
+			return nil, err
+		}
+		columns.translations = append(columns.translations, tc)
+	}
+
+	return columns, nil
+}
+
+// parseWordImportRow extracts and validates one data row
+func parseWordImportRow(rowNum int, values, header []string, columns *wordImportColumns) (wordImportRow, []dto.WordImportRowError) {
+	var rowErrors []dto.WordImportRowError
+	cell := func(i int) string {
+		if i < 0 || i >= len(values) {
+			return ""
+		}
+		return strings.TrimSpace(values[i])
+	}
+	fail := func(col int, message string) {
+		rowErrors = append(rowErrors, dto.WordImportRowError{
+			Row:     rowNum,
+			Column:  strings.TrimSpace(header[col]),
+			Message: message,
+		})
+	}
+	checkLength := func(col int, value string, max int) {
+		if utf8.RuneCountInString(value) > max {
+			fail(col, fmt.Sprintf("must be at most %d characters", max))
+		}
+	}
+
+	row := wordImportRow{
+		row:      rowNum,
+		baseWord: cell(columns.baseWord),
+		notes:    cell(columns.notes),
+		imageURL: cell(columns.imageURL),
+	}
+
+	if columns.id >= 0 {
+		if raw := cell(columns.id); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil || id == 0 {
+				fail(columns.id, "must be a positive integer")
+			} else {
+				row.id = uint(id)
+			}
+		}
+	}
+
+	if row.baseWord == "" {
+		fail(columns.baseWord, "is required")
+	}
+	checkLength(columns.baseWord, row.baseWord, 255)
+	if columns.imageURL >= 0 {
+		checkLength(columns.imageURL, row.imageURL, 500)
+	}
+
+	for _, tc := range columns.translations {
+		t := wordImportTranslation{
+			languageID:   tc.language.ID,
+			translation:  cell(tc.translation),
+			romanization: cell(tc.romanization),
+		}
+		if t.translation == "" {
+			if t.romanization != "" {
+				fail(tc.translation, "is required when romanization is given")
+			}
+			continue
+		}
+		if tc.romanization >= 0 {
+			checkLength(tc.romanization, t.romanization, 255)
+		}
+		row.translations = append(row.translations, t)
+	}
+
+	return row, rowErrors
+}
+
+func isBlankRow(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -1,40 +1,65 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"unicode/utf8"
 
 	"dannyswat/learnspeak/dto"
 	"dannyswat/learnspeak/models"
 	"dannyswat/learnspeak/repositories"
 )
 
+// ErrTranslationExists is returned when deriving a translation the word already has
+var ErrTranslationExists = errors.New("word already has a translation in this language; set overwrite to replace it")
+
+// ErrRelatedWordForbidden is returned when a change would add, remove or retype a link on a
+// word the user neither created nor may edit as an admin
+var ErrRelatedWordForbidden = errors.New("only a related word's creator or an admin can change its links")
+
 type WordService interface {
-	CreateWord(req *dto.CreateWordRequest, userID uint) (*dto.WordResponse, error)
+	CreateWord(req *dto.CreateWordRequest, userID uint, isAdmin bool) (*dto.WordResponse, error)
 	GetWord(id uint) (*dto.WordResponse, error)
-	UpdateWord(id uint, req *dto.UpdateWordRequest, userID uint) (*dto.WordResponse, error)
-	DeleteWord(id uint, userID uint) error
+	UpdateWord(id uint, req *dto.UpdateWordRequest, userID uint, isAdmin bool) (*dto.WordResponse, error)
+	DeleteWord(id uint, userID uint, isAdmin bool) error
 	ListWords(params *dto.WordFilterParams) (*dto.WordListResponse, error)
+	DeriveTranslation(id uint, req *dto.DeriveTranslationRequest, userID uint) (*dto.WordResponse, error)
 }
 
 type wordService struct {
-	wordRepo repositories.WordRepository
+	wordRepo     repositories.WordRepository
+	languageRepo repositories.LanguageRepository
+	revisions    RevisionService
+	scripts      ScriptService
 }
 
-func NewWordService(wordRepo repositories.WordRepository) WordService {
+func NewWordService(wordRepo repositories.WordRepository, languageRepo repositories.LanguageRepository, revisions RevisionService, scripts ScriptService) WordService {
 	return &wordService{
-		wordRepo: wordRepo,
+		wordRepo:     wordRepo,
+		languageRepo: languageRepo,
+		revisions:    revisions,
+		scripts:      scripts,
 	}
 }
 
 // CreateWord creates a new word with translations
-func (s *wordService) CreateWord(req *dto.CreateWordRequest, userID uint) (*dto.WordResponse, error) {
+func (s *wordService) CreateWord(req *dto.CreateWordRequest, userID uint, isAdmin bool) (*dto.WordResponse, error) {
+	if err := s.checkRelations(0, req.Relations); err != nil {
+		return nil, err
+	}
+	related, err := relatedWordChanges(s.wordRepo, s.revisions, nil, toRelationModels(req.Relations), userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create word model
 	word := &models.Word{
-		BaseWord:  req.BaseWord,
-		ImageURL:  req.ImageURL,
-		Notes:     req.Notes,
-		CreatedBy: userID,
+		BaseWord:     req.BaseWord,
+		ImageURL:     req.ImageURL,
+		Notes:        req.Notes,
+		PartOfSpeech: req.PartOfSpeech,
+		CreatedBy:    userID,
 	}
 
 	// Create word
@@ -50,6 +75,9 @@ func (s *wordService) CreateWord(req *dto.CreateWordRequest, userID uint) (*dto.
 			Translation:  translationInput.Translation,
 			Romanization: translationInput.Romanization,
 			AudioURL:     translationInput.AudioURL,
+			MeasureWord:  translationInput.MeasureWord,
+			Gender:       translationInput.Gender,
+			Examples:     toExampleModels(translationInput.Examples),
 		}
 
 		if err := s.wordRepo.CreateTranslation(translation); err != nil {
@@ -57,6 +85,15 @@ func (s *wordService) CreateWord(req *dto.CreateWordRequest, userID uint) (*dto.
 		}
 	}
 
+	if len(req.Relations) > 0 {
+		if err := s.wordRepo.ReplaceRelations(word.ID, toRelationModels(req.Relations)); err != nil {
+			return nil, fmt.Errorf("failed to link related words: %w", err)
+		}
+	}
+
+	s.revisions.Record(models.RevisionEntityWord, word.ID, models.RevisionActionCreate, userID, nil)
+	related.record(userID)
+
 	// Fetch the created word with relations
 	createdWord, err := s.wordRepo.GetByID(word.ID)
 	if err != nil {
@@ -77,12 +114,22 @@ func (s *wordService) GetWord(id uint) (*dto.WordResponse, error) {
 }
 
 // UpdateWord updates an existing word
-func (s *wordService) UpdateWord(id uint, req *dto.UpdateWordRequest, userID uint) (*dto.WordResponse, error) {
+func (s *wordService) UpdateWord(id uint, req *dto.UpdateWordRequest, userID uint, isAdmin bool) (*dto.WordResponse, error) {
 	// Fetch existing word
 	word, err := s.wordRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkRelations(id, req.Relations); err != nil {
+		return nil, err
+	}
+	related := newRevisionLog(s.revisions)
+	if req.Relations != nil {
+		if related, err = relatedWordChanges(s.wordRepo, s.revisions, word.Relations, toRelationModels(req.Relations), userID, isAdmin); err != nil {
+			return nil, err
+		}
+	}
+	before := s.revisions.Snapshot(models.RevisionEntityWord, id)
 
 	// Update word fields
 	if req.BaseWord != nil {
@@ -94,6 +141,9 @@ func (s *wordService) UpdateWord(id uint, req *dto.UpdateWordRequest, userID uin
 	if req.Notes != nil {
 		word.Notes = *req.Notes
 	}
+	if req.PartOfSpeech != nil {
+		word.PartOfSpeech = *req.PartOfSpeech
+	}
 
 	// Save word updates
 	if err := s.wordRepo.Update(word); err != nil {
@@ -134,11 +184,24 @@ func (s *wordService) UpdateWord(id uint, req *dto.UpdateWordRequest, userID uin
 				if translationInput.AudioURL != nil {
 					existing.AudioURL = *translationInput.AudioURL
 				}
+				if translationInput.MeasureWord != nil {
+					existing.MeasureWord = *translationInput.MeasureWord
+				}
+				if translationInput.Gender != nil {
+					existing.Gender = *translationInput.Gender
+				}
 
 				if err := s.wordRepo.UpdateTranslation(existing); err != nil {
 					return nil, fmt.Errorf("failed to update translation: %w", err)
 				}
 
+				// Examples are replaced as a list, and kept when omitted
+				if translationInput.Examples != nil {
+					if err := s.wordRepo.ReplaceExamples(existing.ID, toExampleModels(translationInput.Examples)); err != nil {
+						return nil, fmt.Errorf("failed to update examples: %w", err)
+					}
+				}
+
 				updatedIDs[*translationInput.ID] = true
 			} else {
 				// Create new translation
@@ -146,6 +209,7 @@ func (s *wordService) UpdateWord(id uint, req *dto.UpdateWordRequest, userID uin
 					WordID:      id,
 					LanguageID:  translationInput.LanguageID,
 					Translation: translationInput.Translation,
+					Examples:    toExampleModels(translationInput.Examples),
 				}
 
 				if translationInput.Romanization != nil {
@@ -154,6 +218,12 @@ func (s *wordService) UpdateWord(id uint, req *dto.UpdateWordRequest, userID uin
 				if translationInput.AudioURL != nil {
 					newTranslation.AudioURL = *translationInput.AudioURL
 				}
+				if translationInput.MeasureWord != nil {
+					newTranslation.MeasureWord = *translationInput.MeasureWord
+				}
+				if translationInput.Gender != nil {
+					newTranslation.Gender = *translationInput.Gender
+				}
 
 				if err := s.wordRepo.CreateTranslation(newTranslation); err != nil {
 					return nil, fmt.Errorf("failed to create translation: %w", err)
@@ -171,6 +241,16 @@ func (s *wordService) UpdateWord(id uint, req *dto.UpdateWordRequest, userID uin
 		}
 	}
 
+	// Related words are replaced as a list, and kept when omitted
+	if req.Relations != nil {
+		if err := s.wordRepo.ReplaceRelations(id, toRelationModels(req.Relations)); err != nil {
+			return nil, fmt.Errorf("failed to link related words: %w", err)
+		}
+	}
+
+	s.revisions.Record(models.RevisionEntityWord, id, models.RevisionActionUpdate, userID, before)
+	related.record(userID)
+
 	// Fetch updated word
 	updatedWord, err := s.wordRepo.GetByID(id)
 	if err != nil {
@@ -181,14 +261,26 @@ func (s *wordService) UpdateWord(id uint, req *dto.UpdateWordRequest, userID uin
 }
 
 // DeleteWord deletes a word
-func (s *wordService) DeleteWord(id uint, userID uint) error {
+func (s *wordService) DeleteWord(id uint, userID uint, isAdmin bool) error {
 	// Fetch existing word to check ownership
-	_, err := s.wordRepo.GetByID(id)
+	word, err := s.wordRepo.GetByID(id)
 	if err != nil {
 		return err
 	}
+	// Deleting the word removes its links from the related words too
+	related, err := relatedWordChanges(s.wordRepo, s.revisions, word.Relations, nil, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	before := s.revisions.Snapshot(models.RevisionEntityWord, id)
+
+	if err := s.wordRepo.Delete(id); err != nil {
+		return err
+	}
 
-	return s.wordRepo.Delete(id)
+	s.revisions.Record(models.RevisionEntityWord, id, models.RevisionActionDelete, userID, before)
+	related.record(userID)
+	return nil
 }
 
 // ListWords retrieves words with filtering and pagination
@@ -231,16 +323,139 @@ func (s *wordService) ListWords(params *dto.WordFilterParams) (*dto.WordListResp
 	}, nil
 }
 
+// DeriveTranslation converts a word's translation in one Chinese language into another,
+// with its measure word and example sentences. The languages' script settings pick the
+// conversion. Romanization is copied when both languages use the same scheme, and spelled
+// in pinyin for pinyin languages otherwise. Audio isn't copied, as it's in the other language.
+func (s *wordService) DeriveTranslation(id uint, req *dto.DeriveTranslationRequest, userID uint) (*dto.WordResponse, error) {
+	fromCode, toCode := req.FromLanguageCode, req.ToLanguageCode
+	if fromCode == "" {
+		fromCode = "zh-HK"
+	}
+	if toCode == "" {
+		toCode = "zh-CN"
+	}
+	fromLanguage, err := s.languageRepo.GetByCode(fromCode)
+	if err != nil {
+		return nil, fmt.Errorf("language %s not found", fromCode)
+	}
+	toLanguage, err := s.languageRepo.GetByCode(toCode)
+	if err != nil {
+		return nil, fmt.Errorf("language %s not found", toCode)
+	}
+	if fromLanguage.Script == "" || toLanguage.Script == "" || fromLanguage.ID == toLanguage.ID {
+		return nil, fmt.Errorf("can't derive a %s translation from %s", toCode, fromCode)
+	}
+	convert := func(text string) (string, error) {
+		if fromLanguage.Script == toLanguage.Script {
+			return text, nil
+		}
+		return s.scripts.ConvertText(text, fromLanguage.Script+"2"+toLanguage.Script)
+	}
+	sameRomanization := toLanguage.RomanizationScheme != "" && toLanguage.RomanizationScheme == fromLanguage.RomanizationScheme
+	pinyin := !sameRomanization && toLanguage.RomanizationScheme == "pinyin"
+
+	word, err := s.wordRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var source, target *models.WordTranslation
+	for i := range word.Translations {
+		translation := &word.Translations[i]
+		if translation.LanguageID == fromLanguage.ID && source == nil {
+			source = translation
+		}
+		if translation.LanguageID == toLanguage.ID && target == nil {
+			target = translation
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("word has no %s translation", fromCode)
+	}
+	if target != nil && !req.Overwrite {
+		return nil, ErrTranslationExists
+	}
+
+	derived := &models.WordTranslation{
+		WordID:     id,
+		LanguageID: toLanguage.ID,
+		Gender:     source.Gender,
+		Examples:   make([]models.WordExample, len(source.Examples)),
+	}
+	if derived.Translation, err = convert(source.Translation); err != nil {
+		return nil, err
+	}
+	if derived.MeasureWord, err = convert(source.MeasureWord); err != nil {
+		return nil, err
+	}
+	if sameRomanization {
+		derived.Romanization = source.Romanization
+	} else if pinyin {
+		if derived.Romanization, err = s.derivePinyin(derived.Translation, 255); err != nil {
+			return nil, err
+		}
+	}
+	for i, example := range source.Examples {
+		derived.Examples[i] = models.WordExample{
+			Meaning:       example.Meaning,
+			SequenceOrder: i + 1,
+		}
+		if derived.Examples[i].Sentence, err = convert(example.Sentence); err != nil {
+			return nil, err
+		}
+		if sameRomanization {
+			derived.Examples[i].Romanization = example.Romanization
+		} else if pinyin {
+			if derived.Examples[i].Romanization, err = s.derivePinyin(derived.Examples[i].Sentence, 500); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	before := s.revisions.Snapshot(models.RevisionEntityWord, id)
+	if target != nil {
+		derived.ID = target.ID
+		if err := s.wordRepo.ReplaceTranslation(derived); err != nil {
+			return nil, fmt.Errorf("failed to update translation: %w", err)
+		}
+	} else if err := s.wordRepo.CreateTranslation(derived); err != nil {
+		return nil, fmt.Errorf("failed to create translation: %w", err)
+	}
+	s.revisions.Record(models.RevisionEntityWord, id, models.RevisionActionUpdate, userID, before)
+
+	updatedWord, err := s.wordRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toWordResponse(updatedWord), nil
+}
+
+// derivePinyin spells text in pinyin, leaving the romanization empty when it wouldn't fit
+func (s *wordService) derivePinyin(text string, maxLength int) (string, error) {
+	pinyin, err := s.scripts.Pinyin(text)
+	if err != nil {
+		return "", err
+	}
+	if utf8.RuneCountInString(pinyin) > maxLength {
+		return "", nil
+	}
+	return pinyin, nil
+}
+
 // Helper: Convert model to response DTO
 func (s *wordService) toWordResponse(word *models.Word) *dto.WordResponse {
 	response := &dto.WordResponse{
-		ID:        word.ID,
-		BaseWord:  word.BaseWord,
-		ImageURL:  word.ImageURL,
-		Notes:     word.Notes,
-		CreatedBy: word.CreatedBy,
-		CreatedAt: word.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: word.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:           word.ID,
+		BaseWord:     word.BaseWord,
+		ImageURL:     word.ImageURL,
+		Notes:        word.Notes,
+		PartOfSpeech: word.PartOfSpeech,
+		CreatedBy:    word.CreatedBy,
+		CreatedAt:    word.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:    word.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Relations:    ToWordRelationResponses(word.Relations),
 	}
 
 	// Add creator info if loaded
@@ -262,6 +477,18 @@ func (s *wordService) toWordResponse(word *models.Word) *dto.WordResponse {
 			Translation:  trans.Translation,
 			Romanization: trans.Romanization,
 			AudioURL:     trans.AudioURL,
+			MeasureWord:  trans.MeasureWord,
+			Gender:       trans.Gender,
+			Examples:     make([]dto.ExampleResponse, len(trans.Examples)),
+		}
+		for j, example := range trans.Examples {
+			translationResp.Examples[j] = dto.ExampleResponse{
+				ID:           example.ID,
+				Sentence:     example.Sentence,
+				Romanization: example.Romanization,
+				Meaning:      example.Meaning,
+				AudioURL:     example.AudioURL,
+			}
 		}
 
 		// Add language info if loaded
@@ -279,3 +506,103 @@ func (s *wordService) toWordResponse(word *models.Word) *dto.WordResponse {
 
 	return response
 }
+
+// checkRelations verifies that every related word exists and isn't the word itself
+func (s *wordService) checkRelations(wordID uint, relations []dto.WordRelationInput) error {
+	seen := make(map[uint]bool, len(relations))
+	for _, relation := range relations {
+		if relation.RelatedWordID == wordID {
+			return fmt.Errorf("a word can't be related to itself")
+		}
+		if seen[relation.RelatedWordID] {
+			return fmt.Errorf("word %d is related more than once", relation.RelatedWordID)
+		}
+		seen[relation.RelatedWordID] = true
+		if _, err := s.wordRepo.GetByID(relation.RelatedWordID); err != nil {
+			return fmt.Errorf("related word %d not found", relation.RelatedWordID)
+		}
+	}
+	return nil
+}
+
+// relatedWordChanges checks that the user may edit every related word whose links change
+// when a word's links go from current to next, and snapshots them. Links are stored on both
+// words, so adding, removing or retyping one edits the related word as well. Record the
+// returned log once the links are saved.
+func relatedWordChanges(wordRepo repositories.WordRepository, revisions RevisionService, current, next []models.WordRelation, userID uint, isAdmin bool) (*revisionLog, error) {
+	types := make(map[uint]string, len(current))
+	for _, relation := range current {
+		types[relation.RelatedWordID] = relation.RelationType
+	}
+	var changed []uint
+	for _, relation := range next {
+		relationType, linked := types[relation.RelatedWordID]
+		if !linked || relationType != relation.RelationType {
+			changed = append(changed, relation.RelatedWordID)
+		}
+		delete(types, relation.RelatedWordID)
+	}
+	for relatedID := range types {
+		changed = append(changed, relatedID) // unlinked
+	}
+
+	log := newRevisionLog(revisions)
+	for _, relatedID := range changed {
+		if log.has(models.RevisionEntityWord, relatedID) {
+			continue
+		}
+		word, err := wordRepo.GetByID(relatedID)
+		if err != nil {
+			continue // deleted words have no links to change
+		}
+		if !isAdmin && word.CreatedBy != userID {
+			return nil, fmt.Errorf("%w: %q", ErrRelatedWordForbidden, word.BaseWord)
+		}
+		log.updated(models.RevisionEntityWord, relatedID, log.snapshot(models.RevisionEntityWord, relatedID))
+	}
+	return log, nil
+}
+
+func toExampleModels(examples []dto.ExampleInput) []models.WordExample {
+	if examples == nil {
+		return nil
+	}
+	result := make([]models.WordExample, len(examples))
+	for i, example := range examples {
+		result[i] = models.WordExample{
+			Sentence:      example.Sentence,
+			Romanization:  example.Romanization,
+			Meaning:       example.Meaning,
+			AudioURL:      example.AudioURL,
+			SequenceOrder: i + 1,
+		}
+	}
+	return result
+}
+
+func toRelationModels(relations []dto.WordRelationInput) []models.WordRelation {
+	result := make([]models.WordRelation, len(relations))
+	for i, relation := range relations {
+		result[i] = models.WordRelation{
+			RelatedWordID: relation.RelatedWordID,
+			RelationType:  relation.RelationType,
+		}
+	}
+	return result
+}
+
+// ToWordRelationResponses converts a word's preloaded links to related words
+func ToWordRelationResponses(relations []models.WordRelation) []dto.WordRelationResponse {
+	result := make([]dto.WordRelationResponse, 0, len(relations))
+	for _, relation := range relations {
+		response := dto.WordRelationResponse{
+			WordID:       relation.RelatedWordID,
+			RelationType: relation.RelationType,
+		}
+		if relation.RelatedWord != nil {
+			response.BaseWord = relation.RelatedWord.BaseWord
+		}
+		result = append(result, response)
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+#!/bin/bash
+
+# Download Chinese character data into hanzi/data, where go build embeds it:
+# Make Me a Hanzi (dictionary and stroke order) and OpenCC conversion tables.
+#
+# Files are fetched at the upstream commits pinned in hanzi-data.lock and checked against
+# the sha256 sums recorded there, so builds are reproducible and upstream changes only
+# ship once reviewed. To move to newer upstream data, review the changes, then run:
+#
+#   ./setup-hanzi-data.sh --pin
+#
+# which pins the current upstream commits, downloads the files and rewrites the lock file.
+
+set -e
+
+SCRIPT_DIR="$(cd -P -- "$(dirname -- "$0")" && pwd -P)"
+cd "$SCRIPT_DIR"
+
+DATA_DIR="$SCRIPT_DIR/hanzi/data"
+LOCK_FILE="$SCRIPT_DIR/hanzi-data.lock"
+MAKEMEAHANZI_REPO="skishore/makemeahanzi"
+OPENCC_REPO="BYVoid/OpenCC"
+
+MAKEMEAHANZI_FILES="dictionary.txt graphics.txt"
+OPENCC_FILES="STCharacters.txt TSCharacters.txt STPhrases.txt TSPhrases.txt HKVariants.txt HKVariantsRevPhrases.txt"
+
+# Colors
+GREEN='\033[0;32m'
+YELLOW='\033[1;33m'
+RED='\033[0;31m'
+NC='\033[0m'
+
+fail() {
+    echo -e "${RED}✗ $1${NC}" >&2
+    exit 1
+}
+
+# latest_commit prints the commit SHA of a GitHub repository's default branch
+latest_commit() {
+    curl -fsSL -H "Accept: application/vnd.github.sha" "https://api.github.com/repos/$1/commits/HEAD"
+}
+
+# locked_commit prints the commit pinned for a repository in the lock file
+locked_commit() {
+    awk -v repo="$1" '$1 == "commit" && $2 == repo { print $3 }' "$LOCK_FILE"
+}
+
+download() {
+    local url="$1" file="$DATA_DIR/$2"
+    if [ -s "$file" ]; then
+        echo -e "${GREEN}✓ $2 already downloaded${NC}"
+        return
+    fi
+    echo -e "${YELLOW}Downloading $2...${NC}"
+    curl -fsSL "$url" -o "$file.tmp"
+    mv "$file.tmp" "$file"
+    echo -e "${GREEN}✓ Downloaded $2${NC}"
+}
+
+download_all() {
+    local file
+    for file in $MAKEMEAHANZI_FILES; do
+        download "https://raw.githubusercontent.com/$MAKEMEAHANZI_REPO/$MAKEMEAHANZI_REV/$file" "$file"
+    done
+    for file in $OPENCC_FILES; do
+        download "https://raw.githubusercontent.com/$OPENCC_REPO/$OPENCC_REV/data/dictionary/$file" "$file"
+    done
+}
+
+mkdir -p "$DATA_DIR"
+
+if [ "$1" = "--pin" ]; then
+    MAKEMEAHANZI_REV="$(latest_commit "$MAKEMEAHANZI_REPO")"
+    OPENCC_REV="$(latest_commit "$OPENCC_REPO")"
+    [ -n "$MAKEMEAHANZI_REV" ] && [ -n "$OPENCC_REV" ] || fail "Could not resolve the upstream commits"
+
+    # Start from scratch so every file comes from the new commits
+    for file in $MAKEMEAHANZI_FILES $OPENCC_FILES; do
+        rm -f "$DATA_DIR/$file"
+    done
+    download_all
+
+    {
+        echo "# Upstream commits and sha256 sums of the character data setup-hanzi-data.sh downloads."
+        echo "# Regenerate with ./setup-hanzi-data.sh --pin after reviewing the upstream changes."
+        echo "commit $MAKEMEAHANZI_REPO $MAKEMEAHANZI_REV"
+        echo "commit $OPENCC_REPO $OPENCC_REV"
+        (cd "$DATA_DIR" && sha256sum $MAKEMEAHANZI_FILES $OPENCC_FILES) | sed 's/^/sha256 /'
+    } > "$LOCK_FILE"
+    echo -e "${GREEN}✓ Pinned $MAKEMEAHANZI_REPO@$MAKEMEAHANZI_REV and $OPENCC_REPO@$OPENCC_REV in $LOCK_FILE${NC}"
+    exit 0
+fi
+
+[ -f "$LOCK_FILE" ] || fail "$LOCK_FILE is missing: run ./setup-hanzi-data.sh --pin and commit it"
+MAKEMEAHANZI_REV="$(locked_commit "$MAKEMEAHANZI_REPO")"
+OPENCC_REV="$(locked_commit "$OPENCC_REPO")"
+[ -n "$MAKEMEAHANZI_REV" ] && [ -n "$OPENCC_REV" ] || fail "$LOCK_FILE does not pin both upstream commits"
+for file in $MAKEMEAHANZI_FILES $OPENCC_FILES; do
+    awk -v file="$file" '$1 == "sha256" && $3 == file { found = 1 } END { exit !found }' "$LOCK_FILE" ||
+        fail "$LOCK_FILE has no checksum for $file"
+done
+
+download_all
+
+echo -e "${YELLOW}Verifying checksums...${NC}"
+if ! (cd "$DATA_DIR" && awk '$1 == "sha256" { print $2 "  " $3 }' "$LOCK_FILE" | sha256sum -c -); then
+    fail "Character data does not match $LOCK_FILE; delete hanzi/data/*.txt and run again"
+fi
+
+echo ""
+echo -e "${GREEN}✓ Character data is in $DATA_DIR${NC}"
+echo "Rebuild the backend to embed it, or set HANZI_DATA_DIR=$DATA_DIR"
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/database"
+	"dannyswat/learnspeak/repositories"
+	"dannyswat/learnspeak/services"
+)
+
+// runRegenerateTTSCommand handles "learnspeak regenerate-tts --topic N"
+func runRegenerateTTSCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("regenerate-tts", flag.ContinueOnError)
+	topicID := fs.Uint("topic", 0, "topic ID (required)")
+	force := fs.Bool("force", false, "re-synthesize audio that already exists")
+	conversations := fs.Bool("conversations", false, "also regenerate lines of conversations linked to the topic")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *topicID == 0 {
+		return fmt.Errorf("--topic is required")
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	audioService := services.NewTopicAudioService(
+		repositories.NewTopicRepository(database.DB),
+		repositories.NewWordRepository(database.DB),
+		repositories.NewConversationRepository(database.DB),
+		services.NewTTSService(cfg, services.NewAIUsageService(repositories.NewAIUsageRepository(database.DB), cfg), repositories.NewAssetRepository(database.DB), repositories.NewLanguageRepository(database.DB)),
+	)
+
+	result, err := audioService.RegenerateTopicAudio(context.Background(), *topicID, services.TopicAudioOptions{
+		Force:                *force,
+		IncludeConversations: *conversations,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range result.Errors {
+		fmt.Printf("error: %s\n", msg)
+	}
+	fmt.Printf("Generated %d, skipped %d (already have audio), failed %d\n", result.Generated, result.Skipped, result.Failed)
+	if result.Failed > 0 {
+		return fmt.Errorf("%d item(s) failed", result.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"dannyswat/learnspeak/config"
+	"dannyswat/learnspeak/database"
+	"dannyswat/learnspeak/dto"
+	"dannyswat/learnspeak/repositories"
+	"dannyswat/learnspeak/services"
+	"dannyswat/learnspeak/utils"
+)
+
+// runSeedCommand handles "learnspeak seed"
+func runSeedCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := database.Seed(); err != nil {
+		return err
+	}
+	fmt.Println("Seed data is up to date")
+	return nil
+}
+
+// runCreateUserCommand handles "learnspeak create-user --username U --email E --name N --role R"
+func runCreateUserCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	username := fs.String("username", "", "login name (required)")
+	email := fs.String("email", "", "email address (required)")
+	name := fs.String("name", "", "display name (defaults to the username)")
+	role := fs.String("role", "learner", "comma-separated roles: learner, teacher, admin")
+	password := fs.String("password", "", "password (read from stdin when omitted)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		*name = *username
+	}
+	if *password == "" {
+		pw, err := readPassword()
+		if err != nil {
+			return err
+		}
+		*password = pw
+	}
+
+	req := &dto.CreateUserRequest{
+		Username: *username,
+		Password: *password,
+		Email:    *email,
+		Name:     *name,
+		Roles:    splitAndTrim(*role, ","),
+	}
+	if err := utils.NewValidator().Validate(req); err != nil {
+		return err
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	user, err := newUserService().CreateUser(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created user %s (id %d, roles %s)\n", user.Username, user.ID, strings.Join(user.Roles, ", "))
+	return nil
+}
+
+// runResetPasswordCommand handles "learnspeak reset-password --username U"
+func runResetPasswordCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	username := fs.String("username", "", "user whose password is reset (required)")
+	password := fs.String("password", "", "new password (read from stdin when omitted)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("--username is required")
+	}
+
+	if *password == "" {
+		pw, err := readPassword()
+		if err != nil {
+			return err
+		}
+		*password = pw
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	user, err := repositories.NewUserRepository(database.DB).GetByUsername(*username)
+	if err != nil {
+		return fmt.Errorf("user %q not found", *username)
+	}
+	if err := newUserService().ResetPassword(context.Background(), user.ID, *password); err != nil {
+		return err
+	}
+	fmt.Printf("Password reset for %s\n", user.Username)
+	return nil
+}
+
+func newUserService() services.UserService {
+	return services.NewUserService(
+		repositories.NewUserRepository(database.DB),
+		repositories.NewTopicRepository(database.DB),
+		repositories.NewUserProgressRepository(database.DB),
+		repositories.NewUserJourneyRepository(database.DB),
+		services.NewAuditService(repositories.NewAuditRepository(database.DB)),
+	)
+}
+
+// readPassword reads the password from the first line of stdin, so it stays out of shell history
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// BackgroundWorkers tracks goroutines that outlive the request that started them, so the
+// server can wait for them on shutdown instead of cutting them off
+type BackgroundWorkers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Workers is the process-wide background worker group, drained by the serve command on shutdown
+var Workers = NewBackgroundWorkers()
+
+// NewBackgroundWorkers creates an empty worker group
+func NewBackgroundWorkers() *BackgroundWorkers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BackgroundWorkers{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a tracked goroutine. fn's context is cancelled if shutdown runs out of
+// time, so long-running work should check it and stop at a safe point. Panics are logged.
+func (w *BackgroundWorkers) Go(name string, fn func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("Background worker panicked", "worker", name, "panic", r)
+			}
+		}()
+		fn(w.ctx)
+	}()
+}
+
+// Shutdown waits for running workers to finish. If ctx expires first, the workers'
+// context is cancelled and ctx's error returned.
+func (w *BackgroundWorkers) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		return ctx.Err()
+	}
+}
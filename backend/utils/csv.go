@@ -0,0 +1,23 @@
+package utils
+
+import "strings"
+
+// csvFormulaPrefixes are the leading characters spreadsheet apps treat as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// CSVCell stops spreadsheet apps from running an exported value as a formula by
+// prefixing it with an apostrophe
+func CSVCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// CSVValue undoes CSVCell, so exported files import back unchanged
+func CSVValue(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(cell[1])) {
+		return cell[1:]
+	}
+	return cell
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type requestIDKey struct{}
+
+type userIDKey struct{}
+
+type clientKey struct{}
+
+// Client identifies where a request came from, for the audit log
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// SetupLogger installs the default slog logger. Production logs are JSON, other
+// environments use the text format unless format says otherwise. Records logged with
+// a request context automatically carry its request_id.
+func SetupLogger(environment, level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLogLevel(level)}
+
+	if format == "" {
+		format = "text"
+		if environment == "production" {
+			format = "json"
+		}
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, "json") {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+
+	logger := slog.New(contextHandler{handler})
+	slog.SetDefault(logger)
+	return logger
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ContextWithUserID returns a copy of ctx carrying the authenticated user's ID
+func ContextWithUserID(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the authenticated user's ID stored in ctx
+func UserIDFromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	id, ok := ctx.Value(userIDKey{}).(uint)
+	return id, ok
+}
+
+// ContextWithClient returns a copy of ctx carrying the caller's IP and user agent
+func ContextWithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the caller stored in ctx, or a zero Client
+func ClientFromContext(ctx context.Context) Client {
+	if ctx == nil {
+		return Client{}
+	}
+	client, _ := ctx.Value(clientKey{}).(Client)
+	return client
+}
+
+func parseLogLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// contextHandler adds the request ID from the record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
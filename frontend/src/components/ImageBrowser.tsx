@@ -15,12 +15,14 @@ const ImageBrowser: React.FC<ImageBrowserProps> = ({ isOpen, onClose, onSelect }
   const [pageSize] = useState(20);
   const [totalPages, setTotalPages] = useState(0);
   const [error, setError] = useState<string | null>(null);
+  const [searchInput, setSearchInput] = useState('');
+  const [query, setQuery] = useState('');
 
   const loadImages = useCallback(async (pageNum: number) => {
     try {
       setLoading(true);
       setError(null);
-      const response = await imageGalleryService.getImages(pageNum, pageSize);
+      const response = await imageGalleryService.getImages(pageNum, pageSize, query);
       setImages(response.images);
       setTotalPages(response.pages);
       setPage(pageNum);
@@ -32,7 +34,7 @@ const ImageBrowser: React.FC<ImageBrowserProps> = ({ isOpen, onClose, onSelect }
     } finally {
       setLoading(false);
     }
-  }, [pageSize]);
+  }, [pageSize, query]);
 
   useEffect(() => {
     if (isOpen) {
@@ -40,6 +42,11 @@ const ImageBrowser: React.FC<ImageBrowserProps> = ({ isOpen, onClose, onSelect }
     }
   }, [isOpen, loadImages]);
 
+  const handleSearch = (e: React.FormEvent) => {
+    e.preventDefault();
+    setQuery(searchInput.trim());
+  };
+
   const handleImageClick = (url: string) => {
     onSelect(uploadService.addCacheBuster(url));
     onClose();
@@ -64,6 +71,34 @@ const ImageBrowser: React.FC<ImageBrowserProps> = ({ isOpen, onClose, onSelect }
 
         {/* Content */}
         <div className="p-6">
+          <form onSubmit={handleSearch} className="mb-4 flex gap-2">
+            <input
+              type="search"
+              value={searchInput}
+              onChange={(e) => setSearchInput(e.target.value)}
+              placeholder="Search by word, translation or tag, e.g. apple"
+              className="flex-1 px-3 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500"
+            />
+            <button
+              type="submit"
+              className="px-4 py-2 bg-blue-600 text-white rounded-lg hover:bg-blue-700"
+            >
+              Search
+            </button>
+            {query && (
+              <button
+                type="button"
+                onClick={() => {
+                  setSearchInput('');
+                  setQuery('');
+                }}
+                className="px-4 py-2 border border-gray-300 rounded-lg text-gray-700 hover:bg-gray-50"
+              >
+                Clear
+              </button>
+            )}
+          </form>
+
           {error && (
             <div className="mb-4 p-4 bg-red-50 border border-red-200 rounded-lg text-red-700">
               {error}
@@ -98,7 +133,9 @@ const ImageBrowser: React.FC<ImageBrowserProps> = ({ isOpen, onClose, onSelect }
             </div>
           ) : images.length === 0 ? (
             <div className="text-center py-12">
-              <p className="text-gray-500">No images found</p>
+              <p className="text-gray-500">
+                {query ? `No images found for "${query}"` : 'No images found'}
+              </p>
             </div>
           ) : (
             <>
@@ -112,8 +149,9 @@ const ImageBrowser: React.FC<ImageBrowserProps> = ({ isOpen, onClose, onSelect }
                     className={`relative group rounded-lg overflow-hidden aspect-square cursor-pointer transition-all hover:ring-2 hover:ring-gray-300`}
                   >
                     <img
-                      src={uploadService.getFileUrl(image.url)}
-                      alt={image.filename}
+                      src={uploadService.getFileUrl(image.thumbnailUrl || image.url)}
+                      alt={image.word || image.filename}
+                      title={image.word ? [image.word, image.translation].filter(Boolean).join(' · ') : undefined}
                       className="w-full h-full object-cover"
                       onError={(e) => {
                         (e.target as HTMLImageElement).src = '';
@@ -5,6 +5,16 @@ export interface ImageItem {
   url: string;
   size: number;
   modTime: number;
+  // Set for images recorded in the asset library
+  width?: number;
+  height?: number;
+  format?: string;
+  blurhash?: string;
+  thumbnailUrl?: string;
+  assetId?: number;
+  // Set for generated images: what they were generated for
+  word?: string;
+  translation?: string;
 }
 
 export interface ImageListResponse {
@@ -19,12 +29,14 @@ const imageGalleryService = {
    * Get paginated list of all uploaded images
    * @param page - Page number (1-indexed)
    * @param pageSize - Items per page (default: 20, max: 100)
+   * @param query - Optional search by word, translation, prompt, file name or tag (best match first)
    */
-  async getImages(page: number = 1, pageSize: number = 20): Promise<ImageListResponse> {
+  async getImages(page: number = 1, pageSize: number = 20, query: string = ''): Promise<ImageListResponse> {
     const response = await api.get<ImageListResponse>('/upload/images', {
       params: {
         page,
         pageSize: Math.min(pageSize, 100),
+        q: query.trim() || undefined,
       },
     });
     return response.data;